@@ -0,0 +1,186 @@
+// Command secrethub runs a command with the secret references in its
+// environment replaced by the values of the secrets they reference.
+//
+// Usage:
+//
+//	secrethub run [--no-masking] [--] <command> [<args>...]
+//
+// Environment variables with a value of the form
+// secrethub://<namespace>/<repo>[/<dir-path>]/<secret>[:<version>]
+// are replaced by the value of the referenced secret before the command is
+// run. Any secret value the command writes to its standard output or standard
+// error is masked, unless --no-masking is given.
+//
+// The credential is read from the SECRETHUB_CREDENTIAL environment variable or,
+// when that is not set, from the file $HOME/.secrethub/credential. A passphrase
+// for an encrypted credential is read from SECRETHUB_CREDENTIAL_PASSPHRASE.
+// Both variables are removed from the environment of the command.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/env"
+)
+
+const usage = `Usage: secrethub run [--no-masking] [--] <command> [<args>...]
+
+Run a command with the secret references in its environment
+replaced by the values of the secrets they reference.
+
+Flags:
+`
+
+func main() {
+	os.Exit(run(os.Args[1:]))
+}
+
+// run executes the CLI with the given arguments and returns the exit code.
+func run(args []string) int {
+	if len(args) == 0 || args[0] != "run" {
+		fmt.Fprint(os.Stderr, usage)
+		return 2
+	}
+
+	flags := flag.NewFlagSet("run", flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprint(os.Stderr, usage)
+		flags.PrintDefaults()
+	}
+	noMasking := flags.Bool("no-masking", false, "do not mask secret values in the output of the command")
+
+	err := flags.Parse(args[1:])
+	if err != nil {
+		return 2
+	}
+
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return 2
+	}
+
+	credential, err := loadCredential()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "secrethub: %s\n", err)
+		return 1
+	}
+
+	client := secrethub.NewClient(credential, nil)
+	defer client.Wipe()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Until the command is started, a termination request or an interrupt stops
+	// resolving the environment. After that, a termination request is passed on to
+	// the command, so that it can shut down gracefully. Interrupts are passed on too,
+	// unless the command is attached to a terminal: then it receives them from the
+	// terminal itself.
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(signals)
+
+	resolved := make(chan struct{})
+	forwarded := make(chan os.Signal, 1)
+	interactive := isTerminal(os.Stdin)
+	go func() {
+		for sig := range signals {
+			select {
+			case <-resolved:
+				if sig == os.Interrupt && interactive {
+					continue
+				}
+				forwarded <- sig
+			default:
+				cancel()
+			}
+		}
+	}()
+
+	environment, err := env.ResolveContext(ctx, client.Secrets().Versions(), withoutCredential(os.Environ()))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "secrethub: %s\n", err)
+		return 1
+	}
+	close(resolved)
+
+	err = environment.Run(ctx, flags.Arg(0), flags.Args()[1:], &env.RunOptions{
+		DisableMasking: *noMasking,
+		Signals:        forwarded,
+	})
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		if code := exitErr.ExitCode(); code > 0 {
+			return code
+		}
+		return 1
+	} else if err != nil {
+		fmt.Fprintf(os.Stderr, "secrethub: %s\n", err)
+		return 1
+	}
+
+	return 0
+}
+
+// isTerminal returns whether the file is a terminal.
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// credentialVars are the environment variables that configure the credential.
+// They are not passed on to the command, so that it cannot use the account.
+var credentialVars = []string{
+	"SECRETHUB_CREDENTIAL",
+	"SECRETHUB_CREDENTIAL_PASSPHRASE",
+}
+
+// withoutCredential returns the environment variables of the form KEY=value
+// without the variables that configure the credential.
+func withoutCredential(environ []string) []string {
+	filtered := make([]string, 0, len(environ))
+	for _, variable := range environ {
+		key := strings.SplitN(variable, "=", 2)[0]
+
+		isCredential := false
+		for _, credentialVar := range credentialVars {
+			if key == credentialVar {
+				isCredential = true
+				break
+			}
+		}
+		if !isCredential {
+			filtered = append(filtered, variable)
+		}
+	}
+	return filtered
+}
+
+// loadCredential reads the credential from the SECRETHUB_CREDENTIAL environment
+// variable or, when that is not set, from $HOME/.secrethub/credential.
+func loadCredential() (secrethub.Credential, error) {
+	raw := os.Getenv("SECRETHUB_CREDENTIAL")
+	if raw == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, err
+		}
+
+		contents, err := ioutil.ReadFile(filepath.Join(home, ".secrethub", "credential"))
+		if err != nil {
+			return nil, fmt.Errorf("cannot read credential: %s", err)
+		}
+		raw = strings.TrimSpace(string(contents))
+	}
+
+	return secrethub.NewCredential(raw, os.Getenv("SECRETHUB_CREDENTIAL_PASSPHRASE"))
+}
@@ -1,5 +1,7 @@
 module github.com/secrethub/secrethub-go
 
+go 1.16
+
 require (
 	bitbucket.org/zombiezen/cardcpx v0.0.0-20150417151802-902f68ff43ef
 	github.com/asaskevich/govalidator v0.0.0-20180720115003-f9ffefc3facf
@@ -72,6 +72,10 @@ type AuditSubject struct {
 	Secret                 *Secret                 `json:"secret,omitempty"`
 	EncryptedSecretVersion *EncryptedSecretVersion `json:"encrypted_secret_version,omitempty"` // This is converted to a SecretVersion by the Client.
 	SecretVersion          *SecretVersion          `json:"secret_version,omitempty"`
+	// SecretPath is the full path of the Secret, or of the SecretVersion including its version.
+	// This is set by the Client for the events of a secret and, when requested,
+	// resolved from the directory tree of the repository for the events of a repository.
+	SecretPath SecretPath `json:"secret_path,omitempty"`
 }
 
 // Join converts an AuditSubjectTypeList to a string where each AuditSubjectType is separated by separator.
@@ -2,6 +2,7 @@ package api
 
 import (
 	"net/http"
+	"sort"
 
 	"github.com/secrethub/secrethub-go/internals/api/uuid"
 	"github.com/secrethub/secrethub-go/internals/crypto"
@@ -127,6 +128,57 @@ func (t Tree) DirCount() int {
 	return len(t.Dirs) - 1
 }
 
+// SecretPaths returns the paths of all secrets in the tree, relative to the root dir,
+// e.g. parent/secret. The secrets of a directory come before the secrets of its
+// subdirectories and secrets and subdirectories are sorted by name.
+func (t Tree) SecretPaths() []string {
+	var paths []string
+	walkDir(t.RootDir, "", func(path string, isDir bool) {
+		if !isDir {
+			paths = append(paths, path)
+		}
+	})
+	return paths
+}
+
+// DirPaths returns the paths of all directories in the tree, relative to the root dir,
+// e.g. parent/dir. The root dir itself is not included. Every directory comes after
+// its parent and subdirectories are sorted by name.
+func (t Tree) DirPaths() []string {
+	var paths []string
+	walkDir(t.RootDir, "", func(path string, isDir bool) {
+		if isDir {
+			paths = append(paths, path)
+		}
+	})
+	return paths
+}
+
+// walkDir calls fn with the path of every secret in the dir, followed by every
+// subdirectory and its contents, sorted by name and prefixed with the given prefix.
+func walkDir(dir *Dir, prefix string, fn func(path string, isDir bool)) {
+	if dir == nil {
+		return
+	}
+
+	secrets := append([]*Secret{}, dir.Secrets...)
+	sort.Slice(secrets, func(i, j int) bool {
+		return secrets[i].Name < secrets[j].Name
+	})
+	for _, secret := range secrets {
+		fn(prefix+secret.Name, false)
+	}
+
+	subDirs := append([]*Dir{}, dir.SubDirs...)
+	sort.Slice(subDirs, func(i, j int) bool {
+		return subDirs[i].Name < subDirs[j].Name
+	})
+	for _, subDir := range subDirs {
+		fn(prefix+subDir.Name, true)
+		walkDir(subDir, prefix+subDir.Name+"/", fn)
+	}
+}
+
 // AbsSecretPath returns the full path of secret.
 // This function makes the assumption that every secret has a ParentDir.
 // If not, an error will occur.
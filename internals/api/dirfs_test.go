@@ -120,3 +120,39 @@ func TestAbsDirPath(t *testing.T) {
 		})
 	}
 }
+
+func TestTree_SecretPathsDirPaths(t *testing.T) {
+	// Tree:
+	// repo/
+	//	- b/
+	//		- secret
+	//	- a/
+	//		- sub/
+	//	- z
+	//	- y
+
+	tree := Tree{
+		RootDir: &Dir{
+			Name: "repo",
+			SubDirs: []*Dir{
+				{
+					Name:    "b",
+					Secrets: []*Secret{{Name: "secret"}},
+				},
+				{
+					Name:    "a",
+					SubDirs: []*Dir{{Name: "sub"}},
+				},
+			},
+			Secrets: []*Secret{{Name: "z"}, {Name: "y"}},
+		},
+	}
+
+	// Act
+	secretPaths := tree.SecretPaths()
+	dirPaths := tree.DirPaths()
+
+	// Assert
+	assert.Equal(t, secretPaths, []string{"y", "z", "b/secret"})
+	assert.Equal(t, dirPaths, []string{"a", "a/sub", "b"})
+}
@@ -12,6 +12,7 @@ import (
 	"encoding/pem"
 	"errors"
 	"fmt"
+	"math/big"
 
 	"github.com/secrethub/secrethub-go/internals/errio"
 )
@@ -264,6 +265,12 @@ func (prv RSAPrivateKey) Public() RSAPublicKey {
 	}
 }
 
+// Signer returns the private key as a crypto.Signer, e.g. to sign certificates.
+// Keep the result private.
+func (prv RSAPrivateKey) Signer() crypto.Signer {
+	return prv.private
+}
+
 // ReWrapBytes uses the private key to re-encrypt a small number of encrypted bytes for
 // the given public key. Note that this function will be deprecated. Directly use
 // Unwrap and Wrap when possible.
@@ -294,6 +301,41 @@ func (prv RSAPrivateKey) unwrap(encryptedData []byte) ([]byte, error) {
 	return output, nil
 }
 
+// Wipe overwrites the private exponent, the primes and the precomputed
+// values of the key with zeros, making it unusable. Use it to remove the
+// key from memory when it is no longer needed.
+func (prv RSAPrivateKey) Wipe() {
+	if prv.private == nil {
+		return
+	}
+
+	wipeInt(prv.private.D)
+	for _, prime := range prv.private.Primes {
+		wipeInt(prime)
+	}
+	wipeInt(prv.private.Precomputed.Dp)
+	wipeInt(prv.private.Precomputed.Dq)
+	wipeInt(prv.private.Precomputed.Qinv)
+	for _, crt := range prv.private.Precomputed.CRTValues {
+		wipeInt(crt.Exp)
+		wipeInt(crt.Coeff)
+		wipeInt(crt.R)
+	}
+}
+
+// wipeInt overwrites the memory of a big.Int with zeros.
+func wipeInt(i *big.Int) {
+	if i == nil {
+		return
+	}
+
+	bits := i.Bits()
+	for j := range bits {
+		bits[j] = 0
+	}
+	i.SetInt64(0)
+}
+
 // Export returns the private key in ASN.1 DER encoded format.
 func (prv RSAPrivateKey) Export() []byte {
 	return x509.MarshalPKCS1PrivateKey(prv.private)
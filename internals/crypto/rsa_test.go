@@ -245,3 +245,18 @@ func TestCiphertextRSA_MarshalJSON(t *testing.T) {
 		})
 	}
 }
+
+func TestRSAPrivateKey_Wipe(t *testing.T) {
+	key, err := GenerateRSAPrivateKey(1024)
+	assert.OK(t, err)
+
+	key.Wipe()
+
+	assert.Equal(t, key.private.D.Sign(), 0)
+	for _, prime := range key.private.Primes {
+		assert.Equal(t, prime.Sign(), 0)
+	}
+	assert.Equal(t, key.private.Precomputed.Dp.Sign(), 0)
+	assert.Equal(t, key.private.Precomputed.Dq.Sign(), 0)
+	assert.Equal(t, key.private.Precomputed.Qinv.Sign(), 0)
+}
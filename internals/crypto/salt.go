@@ -248,6 +248,7 @@ const (
 	SaltOperationNone                      = 0x00
 	SaltOperationLocalCredentialEncryption = 0x01
 	SaltOperationHTTPAuthentication        = 0x02
+	SaltOperationBackupEncryption          = 0x03
 )
 
 func (so SaltOperation) String() string {
@@ -256,6 +257,8 @@ func (so SaltOperation) String() string {
 		return "local_credential_encryption"
 	case SaltOperationHTTPAuthentication:
 		return "http_authentication"
+	case SaltOperationBackupEncryption:
+		return "backup_encryption"
 	default:
 		return ""
 	}
@@ -264,7 +267,7 @@ func (so SaltOperation) String() string {
 // Validate validates a salt operation.
 func (so SaltOperation) Validate() error {
 	switch so {
-	case SaltOperationLocalCredentialEncryption, SaltOperationHTTPAuthentication:
+	case SaltOperationLocalCredentialEncryption, SaltOperationHTTPAuthentication, SaltOperationBackupEncryption:
 		return nil
 	default:
 		return ErrInvalidSaltOperation
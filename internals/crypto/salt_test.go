@@ -27,6 +27,7 @@ func TestSaltOperationUniqueness(t *testing.T) {
 		SaltOperationNone:                      true,
 		SaltOperationLocalCredentialEncryption: true,
 		SaltOperationHTTPAuthentication:        true,
+		SaltOperationBackupEncryption:          true,
 	}
 
 	t.Logf("Each operation in the map is unique: %v", all)
@@ -87,6 +88,10 @@ func TestSaltOperationValidate(t *testing.T) {
 			operation: SaltOperationHTTPAuthentication,
 			expected:  nil,
 		},
+		"backup_encryption": {
+			operation: SaltOperationBackupEncryption,
+			expected:  nil,
+		},
 	}
 
 	for name, tc := range cases {
@@ -73,7 +73,14 @@ type ScryptKey struct {
 // GenerateScryptKey derives a key from a passphrase, using the default parameters
 // and a randomly generated salt for the key derivation function. To use other
 // parameters or to supply an elsewhere generated salt, use DeriveScryptKey.
+// The key can be used for local credential encryption.
 func GenerateScryptKey(passphrase []byte) (*ScryptKey, error) {
+	return GenerateScryptKeyForOperation(passphrase, SaltOperationLocalCredentialEncryption)
+}
+
+// GenerateScryptKeyForOperation is the same as GenerateScryptKey, but
+// generates a key that can be used for the given operation.
+func GenerateScryptKeyForOperation(passphrase []byte, operation SaltOperation) (*ScryptKey, error) {
 	keyLen := DefaultScryptKeyLength
 	saltLen := DefaultSaltLength
 	N := DefaultScryptN
@@ -81,7 +88,7 @@ func GenerateScryptKey(passphrase []byte) (*ScryptKey, error) {
 	p := DefaultScryptP
 
 	algo := saltAlgoForKeyLen(keyLen)
-	salt, err := generateSalt(saltLen, algo, SaltOperationLocalCredentialEncryption)
+	salt, err := generateSalt(saltLen, algo, operation)
 	if err != nil {
 		return nil, errio.Error(err)
 	}
@@ -201,6 +201,26 @@ func TestGenerateScryptKey(t *testing.T) {
 
 }
 
+func TestGenerateScryptKeyForOperation(t *testing.T) {
+	// Arrange
+	key, err := GenerateScryptKeyForOperation([]byte("foo"), SaltOperationBackupEncryption)
+	assert.OK(t, err)
+
+	// Act
+	ciphertext, err := key.Encrypt([]byte("data"), SaltOperationBackupEncryption)
+	assert.OK(t, err)
+
+	_, errOperation := key.Encrypt([]byte("data"), SaltOperationLocalCredentialEncryption)
+
+	plaintext, err := key.Decrypt(ciphertext, SaltOperationBackupEncryption)
+	assert.OK(t, err)
+
+	// Assert
+	assert.Equal(t, key.Salt.Purpose().Operation, SaltOperation(SaltOperationBackupEncryption))
+	assert.Equal(t, errOperation, ErrInvalidSaltOperation)
+	assert.Equal(t, plaintext, []byte("data"))
+}
+
 func TestDeriveScryptKey(t *testing.T) {
 
 	// Arrange
@@ -131,6 +131,14 @@ func (k *SymmetricKey) Export() []byte {
 	return k.key
 }
 
+// Wipe overwrites the key with zeros, making it unusable.
+// Use it to remove the key from memory when it is no longer needed.
+func (k *SymmetricKey) Wipe() {
+	for i := range k.key {
+		k.key[i] = 0
+	}
+}
+
 // IsWrongKey returns true when the error can be
 // the result of a wrong key being used for decryption.
 func IsWrongKey(err error) bool {
@@ -128,3 +128,12 @@ func Test_generateNonce(t *testing.T) {
 		t.Fatal("Same Salt generated.")
 	}
 }
+
+func TestSymmetricKey_Wipe(t *testing.T) {
+	key, err := GenerateSymmetricKey()
+	assert.OK(t, err)
+
+	key.Wipe()
+
+	assert.Equal(t, key.Export(), make([]byte, SymmetricKeyLength))
+}
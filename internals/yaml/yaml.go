@@ -0,0 +1,260 @@
+// Package yaml decodes the subset of YAML that is used for flat configuration files:
+// a mapping of keys to plain, single quoted and double quoted scalars on a single line
+// and literal block scalars (|). Nested mappings, sequences, flow collections, folded
+// block scalars, anchors, aliases and tags are not supported.
+package yaml
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// SyntaxError is returned when a line cannot be decoded.
+type SyntaxError struct {
+	// Line is the number of the line, starting at 1.
+	Line int
+	// Msg describes the problem.
+	Msg string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("line %d: %s", e.Line, e.Msg)
+}
+
+func syntaxError(line int, msg string) error {
+	return &SyntaxError{Line: line, Msg: msg}
+}
+
+// DuplicateKeyError is returned when a key occurs more than once.
+type DuplicateKeyError struct {
+	Key string
+}
+
+func (e *DuplicateKeyError) Error() string {
+	return fmt.Sprintf("key %s occurs more than once", e.Key)
+}
+
+// Decode decodes a YAML mapping of keys to scalar values. All values are read as
+// strings. A *SyntaxError is returned when the data is not supported and a
+// *DuplicateKeyError when a key occurs more than once.
+func Decode(data []byte) (map[string]string, error) {
+	values := make(map[string]string)
+	lines := strings.Split(strings.Replace(string(data), "\r\n", "\n", -1), "\n")
+	for i := 0; i < len(lines); i++ {
+		lineNumber := i + 1
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") || trimmed == "---" || trimmed == "..." {
+			continue
+		}
+		if line[0] == ' ' || line[0] == '\t' {
+			return nil, syntaxError(lineNumber, "nested values are not supported")
+		}
+		if line[0] == '-' || line[0] == '[' || line[0] == '{' {
+			return nil, syntaxError(lineNumber, "expected a mapping of keys to values")
+		}
+
+		key, rest, err := parseYAMLKey(line)
+		if err != nil {
+			return nil, syntaxError(lineNumber, err.Error())
+		}
+
+		var value string
+		switch {
+		case rest == "" || strings.HasPrefix(rest, "#"):
+			if i+1 < len(lines) && isIndented(lines[i+1]) {
+				return nil, syntaxError(lineNumber, "nested values are not supported")
+			}
+		case rest[0] == '"':
+			value, rest, err = parseYAMLDoubleQuoted(rest)
+		case rest[0] == '\'':
+			value, rest, err = parseYAMLSingleQuoted(rest)
+		case rest[0] == '|':
+			value, i, err = parseYAMLLiteral(rest, lines, i)
+			rest = ""
+		case strings.ContainsRune(">[{&*!%@`", rune(rest[0])):
+			return nil, syntaxError(lineNumber, "folded block scalars, flow collections, anchors, aliases and tags are not supported")
+		default:
+			value = rest
+			if index := strings.Index(value, " #"); index >= 0 {
+				value = value[:index]
+			}
+			value = strings.TrimSpace(value)
+			rest = ""
+		}
+		if err != nil {
+			return nil, syntaxError(lineNumber, err.Error())
+		}
+
+		rest = strings.TrimSpace(rest)
+		if rest != "" && !strings.HasPrefix(rest, "#") {
+			return nil, syntaxError(lineNumber, "unexpected characters after quoted value")
+		}
+
+		if _, exists := values[key]; exists {
+			return nil, &DuplicateKeyError{Key: key}
+		}
+		values[key] = value
+	}
+	return values, nil
+}
+
+// parseYAMLKey returns the key of a mapping entry and the trimmed rest of the line after the colon.
+func parseYAMLKey(line string) (string, string, error) {
+	var key, rest string
+	var err error
+	switch line[0] {
+	case '"':
+		key, rest, err = parseYAMLDoubleQuoted(line)
+	case '\'':
+		key, rest, err = parseYAMLSingleQuoted(line)
+	default:
+		index := strings.Index(line+" ", ": ")
+		if index < 0 {
+			return "", "", errors.New("expected key: value")
+		}
+		key, rest = strings.TrimSpace(line[:index]), line[index:]
+	}
+	if err != nil {
+		return "", "", err
+	}
+
+	rest = strings.TrimLeft(rest, " \t")
+	if !strings.HasPrefix(rest, ":") || (len(rest) > 1 && rest[1] != ' ' && rest[1] != '\t') {
+		return "", "", errors.New("expected key: value")
+	}
+	return key, strings.TrimSpace(rest[1:]), nil
+}
+
+// parseYAMLDoubleQuoted returns the value of the double quoted scalar at the start of s and the rest of s.
+func parseYAMLDoubleQuoted(s string) (string, string, error) {
+	var buf strings.Builder
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '"':
+			return buf.String(), s[i+1:], nil
+		case '\\':
+			if i == len(s)-1 {
+				return "", "", errors.New("unterminated double quoted value")
+			}
+			i++
+
+			if simple, ok := yamlEscapes[s[i]]; ok {
+				buf.WriteString(simple)
+				continue
+			}
+
+			size, ok := yamlUnicodeEscapes[s[i]]
+			if !ok || i+size >= len(s) {
+				return "", "", errors.New("invalid escape sequence")
+			}
+			code, err := strconv.ParseUint(s[i+1:i+1+size], 16, 32)
+			if err != nil || !utf8.ValidRune(rune(code)) {
+				return "", "", errors.New("invalid escape sequence")
+			}
+			buf.WriteRune(rune(code))
+			i += size
+		default:
+			buf.WriteByte(s[i])
+		}
+	}
+	return "", "", errors.New("unterminated double quoted value")
+}
+
+// yamlEscapes contains the escape sequences of YAML double quoted scalars that map to a fixed string.
+var yamlEscapes = map[byte]string{
+	'0': "\x00", 'a': "\a", 'b': "\b", 't': "\t", '\t': "\t", 'n': "\n", 'v': "\v", 'f': "\f",
+	'r': "\r", 'e': "\x1b", ' ': " ", '"': `"`, '/': "/", '\\': `\`,
+	'N': "\u0085", '_': "\u00a0", 'L': "\u2028", 'P': "\u2029",
+}
+
+// yamlUnicodeEscapes contains the number of hexadecimal digits of the escape
+// sequences of YAML double quoted scalars that map to a unicode character.
+var yamlUnicodeEscapes = map[byte]int{
+	'x': 2,
+	'u': 4,
+	'U': 8,
+}
+
+// parseYAMLSingleQuoted returns the value of the single quoted scalar at the start of s and the rest of s.
+func parseYAMLSingleQuoted(s string) (string, string, error) {
+	var buf strings.Builder
+	for i := 1; i < len(s); i++ {
+		if s[i] != '\'' {
+			buf.WriteByte(s[i])
+			continue
+		}
+		if i+1 < len(s) && s[i+1] == '\'' {
+			buf.WriteByte('\'')
+			i++
+			continue
+		}
+		return buf.String(), s[i+1:], nil
+	}
+	return "", "", errors.New("unterminated single quoted value")
+}
+
+// parseYAMLLiteral returns the value of the literal block scalar of which the header is
+// on line i and the index of the last line of the scalar.
+func parseYAMLLiteral(header string, lines []string, i int) (string, int, error) {
+	chomping := ""
+	indicator := strings.TrimSpace(header[1:])
+	if index := strings.Index(indicator, "#"); index >= 0 {
+		indicator = strings.TrimSpace(indicator[:index])
+	}
+	switch indicator {
+	case "", "-", "+":
+		chomping = indicator
+	default:
+		return "", i, errors.New("unsupported block scalar indicator")
+	}
+
+	indent := -1
+	var content []string
+	for i+1 < len(lines) {
+		line := lines[i+1]
+		if strings.TrimSpace(line) == "" {
+			content = append(content, "")
+			i++
+			continue
+		}
+
+		lineIndent := len(line) - len(strings.TrimLeft(line, " "))
+		if indent < 0 {
+			indent = lineIndent
+		}
+		if lineIndent < indent || indent == 0 {
+			break
+		}
+
+		content = append(content, line[indent:])
+		i++
+	}
+
+	// Trailing blank lines belong to the block scalar only when its chomping is keep.
+	trailing := 0
+	for trailing < len(content) && content[len(content)-1-trailing] == "" {
+		trailing++
+	}
+	body := content[:len(content)-trailing]
+	if len(body) == 0 {
+		return "", i, nil
+	}
+
+	value := strings.Join(body, "\n")
+	switch chomping {
+	case "":
+		value += "\n"
+	case "+":
+		value += strings.Repeat("\n", trailing+1)
+	}
+	return value, i, nil
+}
+
+// isIndented returns whether the line starts with whitespace and is not blank.
+func isIndented(line string) bool {
+	return strings.TrimSpace(line) != "" && (line[0] == ' ' || line[0] == '\t')
+}
@@ -0,0 +1,128 @@
+package keygen
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+
+	"github.com/secrethub/secrethub-go/internals/errio"
+)
+
+// JWTAlgorithm is a JSON Web Signature algorithm, as defined in RFC 7518.
+type JWTAlgorithm string
+
+// Supported JWT signing algorithms.
+const (
+	HS256 JWTAlgorithm = "HS256"
+	HS384 JWTAlgorithm = "HS384"
+	HS512 JWTAlgorithm = "HS512"
+	RS256 JWTAlgorithm = "RS256"
+	RS384 JWTAlgorithm = "RS384"
+	RS512 JWTAlgorithm = "RS512"
+	ES256 JWTAlgorithm = "ES256"
+	ES384 JWTAlgorithm = "ES384"
+	ES512 JWTAlgorithm = "ES512"
+)
+
+const (
+	// DefaultJWTRSABits is the size of generated RSA keys for JWTs when none is set.
+	DefaultJWTRSABits = 2048
+)
+
+var (
+	hmacKeySizes = map[JWTAlgorithm]int{
+		HS256: 32,
+		HS384: 48,
+		HS512: 64,
+	}
+	ecCurves = map[JWTAlgorithm]elliptic.Curve{
+		ES256: elliptic.P256(),
+		ES384: elliptic.P384(),
+		ES512: elliptic.P521(),
+	}
+)
+
+// JWT generates keys to sign JSON Web Tokens with.
+//
+// HMAC keys consist of as many random bytes as the output of the hash function,
+// encoded with unpadded base64url like the k parameter of a JSON Web Key.
+// They have no public part. RSA and EC keys are PEM encoded and their
+// public part is a PEM encoded public key.
+type JWT struct {
+	// Algorithm is the algorithm the key is used with, e.g. HS256 or ES256.
+	Algorithm JWTAlgorithm
+	// Bits is the size of RSA keys. When it is 0, DefaultJWTRSABits is used.
+	Bits int
+}
+
+// GenerateKey generates a key to sign JWTs with using the algorithm.
+func (g JWT) GenerateKey() (*Key, error) {
+	if size, ok := hmacKeySizes[g.Algorithm]; ok {
+		secret := make([]byte, size)
+		_, err := rand.Read(secret)
+		if err != nil {
+			return nil, errio.Error(err)
+		}
+
+		return &Key{
+			Private: []byte(base64.RawURLEncoding.EncodeToString(secret)),
+		}, nil
+	}
+
+	if curve, ok := ecCurves[g.Algorithm]; ok {
+		key, err := ecdsa.GenerateKey(curve, rand.Reader)
+		if err != nil {
+			return nil, errio.Error(err)
+		}
+
+		private, err := marshalECPrivateKey(key)
+		if err != nil {
+			return nil, err
+		}
+
+		public, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+		if err != nil {
+			return nil, errio.Error(err)
+		}
+
+		return &Key{
+			Private:      private,
+			Public:       encodePEM("PUBLIC KEY", public),
+			PublicSuffix: ".pub",
+		}, nil
+	}
+
+	switch g.Algorithm {
+	case RS256, RS384, RS512:
+		bits := g.Bits
+		if bits == 0 {
+			bits = DefaultJWTRSABits
+		}
+
+		key, err := generateRSAKey(bits)
+		if err != nil {
+			return nil, err
+		}
+		defer key.Wipe()
+
+		private, err := key.ExportPEM()
+		if err != nil {
+			return nil, errio.Error(err)
+		}
+
+		public, err := x509.MarshalPKIXPublicKey(key.Signer().Public())
+		if err != nil {
+			return nil, errio.Error(err)
+		}
+
+		return &Key{
+			Private:      private,
+			Public:       encodePEM("PUBLIC KEY", public),
+			PublicSuffix: ".pub",
+		}, nil
+	}
+
+	return nil, ErrUnsupportedAlgorithm
+}
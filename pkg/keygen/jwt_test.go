@@ -0,0 +1,95 @@
+package keygen
+
+import (
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"testing"
+
+	"github.com/secrethub/secrethub-go/internals/assert"
+)
+
+func TestJWT_GenerateKey(t *testing.T) {
+	cases := map[string]struct {
+		generator JWT
+		hmacSize  int
+		curveBits int
+		rsaBits   int
+		err       error
+	}{
+		"HS256": {
+			generator: JWT{Algorithm: HS256},
+			hmacSize:  32,
+		},
+		"HS512": {
+			generator: JWT{Algorithm: HS512},
+			hmacSize:  64,
+		},
+		"RS256": {
+			generator: JWT{Algorithm: RS256},
+			rsaBits:   DefaultJWTRSABits,
+		},
+		"ES256": {
+			generator: JWT{Algorithm: ES256},
+			curveBits: 256,
+		},
+		"ES512": {
+			generator: JWT{Algorithm: ES512},
+			curveBits: 521,
+		},
+		"RS256 too small": {
+			generator: JWT{Algorithm: RS256, Bits: 1024},
+			err:       ErrRSAKeyTooSmall(MinRSABits),
+		},
+		"none": {
+			generator: JWT{Algorithm: "none"},
+			err:       ErrUnsupportedAlgorithm,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			// Act
+			key, err := tc.generator.GenerateKey()
+
+			// Assert
+			assert.Equal(t, err, tc.err)
+			if err != nil {
+				return
+			}
+
+			if tc.hmacSize > 0 {
+				secret, err := base64.RawURLEncoding.DecodeString(string(key.Private))
+				assert.OK(t, err)
+				assert.Equal(t, len(secret), tc.hmacSize)
+				assert.Equal(t, len(key.Public), 0)
+				return
+			}
+
+			assert.Equal(t, key.PublicSuffix, ".pub")
+
+			block, _ := pem.Decode(key.Public)
+			assert.Equal(t, block.Type, "PUBLIC KEY")
+			public, err := x509.ParsePKIXPublicKey(block.Bytes)
+			assert.OK(t, err)
+
+			block, _ = pem.Decode(key.Private)
+			switch public := public.(type) {
+			case *rsa.PublicKey:
+				private, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+				assert.OK(t, err)
+				assert.Equal(t, private.N.BitLen(), tc.rsaBits)
+				assert.Equal(t, private.PublicKey.Equal(public), true)
+			case *ecdsa.PublicKey:
+				private, err := x509.ParseECPrivateKey(block.Bytes)
+				assert.OK(t, err)
+				assert.Equal(t, private.Curve.Params().BitSize, tc.curveBits)
+				assert.Equal(t, private.PublicKey.Equal(public), true)
+			default:
+				t.Fatalf("unexpected public key type %T", public)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+// Package keygen provides generators of key material, such as SSH keys,
+// TLS keys and certificates and JWT signing keys, that can be stored as secrets.
+package keygen
+
+import (
+	"encoding/pem"
+
+	"github.com/secrethub/secrethub-go/internals/crypto"
+	"github.com/secrethub/secrethub-go/internals/errio"
+)
+
+const (
+	// MinRSABits is the minimum size of generated RSA keys.
+	MinRSABits = 2048
+)
+
+// Errors
+var (
+	errKeygen = errio.Namespace("keygen")
+
+	ErrUnsupportedKeyType   = errKeygen.Code("unsupported_key_type").Error("the key type is not supported by the generator")
+	ErrUnsupportedAlgorithm = errKeygen.Code("unsupported_algorithm").Error("the signing algorithm is not supported")
+	ErrRSAKeyTooSmall       = errKeygen.Code("rsa_key_too_small").ErrorPref("RSA keys must be at least %d bits")
+	ErrNoSubject            = errKeygen.Code("no_subject").Error("a certificate needs a common name or at least one DNS name")
+	ErrInvalidValidity      = errKeygen.Code("invalid_validity").Error("the validity period of a certificate must be positive")
+)
+
+// KeyType is the type of a generated key pair.
+type KeyType string
+
+// Supported key types.
+const (
+	KeyTypeRSA     KeyType = "rsa"
+	KeyTypeECDSA   KeyType = "ecdsa"
+	KeyTypeEd25519 KeyType = "ed25519"
+)
+
+// Generator generates key material.
+type Generator interface {
+	GenerateKey() (*Key, error)
+}
+
+// Key is generated key material.
+type Key struct {
+	// Private is the private part of the key, which must be kept secret.
+	Private []byte
+	// Public is the public part of the key, e.g. a public key or a certificate.
+	// It is empty for symmetric keys.
+	Public []byte
+	// PublicSuffix is the conventional suffix of the file name of the public part,
+	// relative to the file name of the private part, e.g. .pub for SSH keys.
+	PublicSuffix string
+}
+
+// generateRSAKey generates an RSA private key of the given number of bits.
+func generateRSAKey(bits int) (crypto.RSAPrivateKey, error) {
+	if bits < MinRSABits {
+		return crypto.RSAPrivateKey{}, ErrRSAKeyTooSmall(MinRSABits)
+	}
+	return crypto.GenerateRSAPrivateKey(bits)
+}
+
+// encodePEM returns the data PEM encoded in a block of the given type.
+func encodePEM(blockType string, data []byte) []byte {
+	return pem.EncodeToMemory(&pem.Block{
+		Type:  blockType,
+		Bytes: data,
+	})
+}
@@ -0,0 +1,157 @@
+package keygen
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/binary"
+	"math/big"
+
+	"golang.org/x/crypto/ed25519"
+	"golang.org/x/crypto/ssh"
+
+	"github.com/secrethub/secrethub-go/internals/errio"
+)
+
+const (
+	// DefaultSSHRSABits is the size of generated RSA SSH keys when none is set.
+	DefaultSSHRSABits = 4096
+
+	opensshMagic     = "openssh-key-v1\x00"
+	opensshBlockSize = 8
+)
+
+// SSH generates SSH key pairs. The private key is encoded in the OpenSSH
+// format and the public key in the format of an authorized_keys file.
+// The zero value generates Ed25519 keys.
+type SSH struct {
+	// Type is the type of the key pair, either KeyTypeEd25519 or KeyTypeRSA.
+	// When it is empty, KeyTypeEd25519 is used.
+	Type KeyType
+	// Bits is the size of RSA keys. When it is 0, DefaultSSHRSABits is used.
+	Bits int
+	// Comment is added to the public and private key, e.g. user@host.
+	Comment string
+}
+
+// GenerateKey generates an SSH key pair.
+func (g SSH) GenerateKey() (*Key, error) {
+	var public ssh.PublicKey
+	var private []byte
+	switch g.Type {
+	case KeyTypeEd25519, "":
+		publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, errio.Error(err)
+		}
+
+		public, err = ssh.NewPublicKey(publicKey)
+		if err != nil {
+			return nil, errio.Error(err)
+		}
+
+		private = ssh.Marshal(struct {
+			Public  []byte
+			Private []byte
+		}{
+			Public:  publicKey,
+			Private: privateKey,
+		})
+	case KeyTypeRSA:
+		bits := g.Bits
+		if bits == 0 {
+			bits = DefaultSSHRSABits
+		}
+
+		key, err := generateRSAKey(bits)
+		if err != nil {
+			return nil, err
+		}
+		defer key.Wipe()
+
+		rsaKey := key.Signer().(*rsa.PrivateKey)
+		public, err = ssh.NewPublicKey(&rsaKey.PublicKey)
+		if err != nil {
+			return nil, errio.Error(err)
+		}
+
+		private = ssh.Marshal(struct {
+			N    *big.Int
+			E    *big.Int
+			D    *big.Int
+			Iqmp *big.Int
+			P    *big.Int
+			Q    *big.Int
+		}{
+			N:    rsaKey.N,
+			E:    big.NewInt(int64(rsaKey.E)),
+			D:    rsaKey.D,
+			Iqmp: rsaKey.Precomputed.Qinv,
+			P:    rsaKey.Primes[0],
+			Q:    rsaKey.Primes[1],
+		})
+	default:
+		return nil, ErrUnsupportedKeyType
+	}
+
+	encoded, err := marshalOpenSSHPrivateKey(public, private, g.Comment)
+	if err != nil {
+		return nil, err
+	}
+
+	authorizedKey := bytes.TrimSuffix(ssh.MarshalAuthorizedKey(public), []byte("\n"))
+	if g.Comment != "" {
+		authorizedKey = append(append(authorizedKey, ' '), g.Comment...)
+	}
+
+	return &Key{
+		Private:      encoded,
+		Public:       append(authorizedKey, '\n'),
+		PublicSuffix: ".pub",
+	}, nil
+}
+
+// marshalOpenSSHPrivateKey encodes an unencrypted private key in the OpenSSH format,
+// as described in https://github.com/openssh/openssh-portable/blob/master/PROTOCOL.key.
+// The private key contains the key type specific fields of the private key.
+func marshalOpenSSHPrivateKey(public ssh.PublicKey, private []byte, comment string) ([]byte, error) {
+	var check [4]byte
+	_, err := rand.Read(check[:])
+	if err != nil {
+		return nil, errio.Error(err)
+	}
+	checkInt := binary.BigEndian.Uint32(check[:])
+
+	section := ssh.Marshal(struct {
+		Check1  uint32
+		Check2  uint32
+		Type    string
+		Private []byte `ssh:"rest"`
+	}{
+		Check1:  checkInt,
+		Check2:  checkInt,
+		Type:    public.Type(),
+		Private: private,
+	})
+	section = append(section, ssh.Marshal(struct{ Comment string }{comment})...)
+	for i := byte(1); len(section)%opensshBlockSize != 0; i++ {
+		section = append(section, i)
+	}
+
+	data := append([]byte(opensshMagic), ssh.Marshal(struct {
+		CipherName  string
+		KDFName     string
+		KDFOptions  string
+		NumKeys     uint32
+		PublicKey   []byte
+		PrivateKeys []byte
+	}{
+		CipherName:  "none",
+		KDFName:     "none",
+		NumKeys:     1,
+		PublicKey:   public.Marshal(),
+		PrivateKeys: section,
+	})...)
+
+	return encodePEM("OPENSSH PRIVATE KEY", data), nil
+}
@@ -0,0 +1,88 @@
+package keygen
+
+import (
+	"bytes"
+	"crypto/rsa"
+	"testing"
+
+	"golang.org/x/crypto/ed25519"
+	"golang.org/x/crypto/ssh"
+
+	"github.com/secrethub/secrethub-go/internals/assert"
+)
+
+func TestSSH_GenerateKey(t *testing.T) {
+	cases := map[string]struct {
+		generator SSH
+		keyType   string
+		err       error
+	}{
+		"default": {
+			generator: SSH{},
+			keyType:   ssh.KeyAlgoED25519,
+		},
+		"ed25519 with comment": {
+			generator: SSH{
+				Type:    KeyTypeEd25519,
+				Comment: "deploy@example.com",
+			},
+			keyType: ssh.KeyAlgoED25519,
+		},
+		"rsa": {
+			generator: SSH{
+				Type: KeyTypeRSA,
+				Bits: 2048,
+			},
+			keyType: ssh.KeyAlgoRSA,
+		},
+		"rsa too small": {
+			generator: SSH{
+				Type: KeyTypeRSA,
+				Bits: 1024,
+			},
+			err: ErrRSAKeyTooSmall(MinRSABits),
+		},
+		"ecdsa": {
+			generator: SSH{
+				Type: KeyTypeECDSA,
+			},
+			err: ErrUnsupportedKeyType,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			// Act
+			key, err := tc.generator.GenerateKey()
+
+			// Assert
+			assert.Equal(t, err, tc.err)
+			if err != nil {
+				return
+			}
+
+			assert.Equal(t, key.PublicSuffix, ".pub")
+
+			public, comment, _, _, err := ssh.ParseAuthorizedKey(key.Public)
+			assert.OK(t, err)
+			assert.Equal(t, public.Type(), tc.keyType)
+			assert.Equal(t, comment, tc.generator.Comment)
+
+			private, err := ssh.ParseRawPrivateKey(key.Private)
+			assert.OK(t, err)
+
+			var privatePublic ssh.PublicKey
+			switch k := private.(type) {
+			case *ed25519.PrivateKey:
+				privatePublic, err = ssh.NewPublicKey(k.Public())
+			case *rsa.PrivateKey:
+				assert.OK(t, k.Validate())
+				privatePublic, err = ssh.NewPublicKey(&k.PublicKey)
+			default:
+				t.Fatalf("unexpected private key type %T", private)
+			}
+			assert.OK(t, err)
+			assert.Equal(t, bytes.Equal(privatePublic.Marshal(), public.Marshal()), true)
+		})
+	}
+}
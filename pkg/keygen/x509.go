@@ -0,0 +1,159 @@
+package keygen
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"time"
+
+	"github.com/secrethub/secrethub-go/internals/errio"
+)
+
+const (
+	// DefaultX509RSABits is the size of generated RSA keys for certificates when none is set.
+	DefaultX509RSABits = 2048
+	// DefaultValidity is the validity period of self-signed certificates when none is set.
+	DefaultValidity = 365 * 24 * time.Hour
+)
+
+// X509 generates private keys for X.509 certificates, e.g. for TLS.
+// The public part is either a self-signed certificate or a certificate
+// signing request (CSR) that can be sent to a certificate authority.
+// The private key and the certificate or CSR are PEM encoded.
+type X509 struct {
+	// Type is the type of the key, either KeyTypeRSA or KeyTypeECDSA.
+	// When it is empty, KeyTypeRSA is used. ECDSA keys use the P-256 curve.
+	Type KeyType
+	// Bits is the size of RSA keys. When it is 0, DefaultX509RSABits is used.
+	Bits int
+	// CommonName is the common name of the subject of the certificate.
+	CommonName string
+	// Organization is the organization of the subject of the certificate.
+	Organization string
+	// DNSNames are the host names the certificate is valid for.
+	DNSNames []string
+	// SelfSigned generates a self-signed certificate instead of a CSR.
+	SelfSigned bool
+	// Validity is the validity period of a self-signed certificate.
+	// When it is 0, DefaultValidity is used.
+	Validity time.Duration
+}
+
+// GenerateKey generates a private key and a self-signed certificate or a CSR for it.
+func (g X509) GenerateKey() (*Key, error) {
+	if g.CommonName == "" && len(g.DNSNames) == 0 {
+		return nil, ErrNoSubject
+	}
+
+	validity := g.Validity
+	if validity == 0 {
+		validity = DefaultValidity
+	}
+	if validity < 0 {
+		return nil, ErrInvalidValidity
+	}
+
+	// Only RSA keys can be used for key encipherment, e.g. in RSA key exchange.
+	// ECDSA keys can only sign.
+	keyUsage := x509.KeyUsageDigitalSignature
+
+	var signer crypto.Signer
+	var private []byte
+	switch g.Type {
+	case KeyTypeRSA, "":
+		bits := g.Bits
+		if bits == 0 {
+			bits = DefaultX509RSABits
+		}
+
+		key, err := generateRSAKey(bits)
+		if err != nil {
+			return nil, err
+		}
+		defer key.Wipe()
+
+		private, err = key.ExportPEM()
+		if err != nil {
+			return nil, errio.Error(err)
+		}
+		signer = key.Signer()
+		keyUsage |= x509.KeyUsageKeyEncipherment
+	case KeyTypeECDSA:
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			return nil, errio.Error(err)
+		}
+
+		private, err = marshalECPrivateKey(key)
+		if err != nil {
+			return nil, err
+		}
+		signer = key
+	default:
+		return nil, ErrUnsupportedKeyType
+	}
+
+	subject := pkix.Name{
+		CommonName: g.CommonName,
+	}
+	if g.Organization != "" {
+		subject.Organization = []string{g.Organization}
+	}
+
+	if !g.SelfSigned {
+		csr, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+			Subject:  subject,
+			DNSNames: g.DNSNames,
+		}, signer)
+		if err != nil {
+			return nil, errio.Error(err)
+		}
+
+		return &Key{
+			Private:      private,
+			Public:       encodePEM("CERTIFICATE REQUEST", csr),
+			PublicSuffix: ".csr",
+		}, nil
+	}
+
+	serialNumber, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, errio.Error(err)
+	}
+
+	notBefore := time.Now().UTC()
+	template := &x509.Certificate{
+		SerialNumber:          serialNumber,
+		Subject:               subject,
+		DNSNames:              g.DNSNames,
+		NotBefore:             notBefore,
+		NotAfter:              notBefore.Add(validity),
+		KeyUsage:              keyUsage,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+	}
+
+	cert, err := x509.CreateCertificate(rand.Reader, template, template, signer.Public(), signer)
+	if err != nil {
+		return nil, errio.Error(err)
+	}
+
+	return &Key{
+		Private:      private,
+		Public:       encodePEM("CERTIFICATE", cert),
+		PublicSuffix: ".crt",
+	}, nil
+}
+
+// marshalECPrivateKey returns the PEM encoded EC private key.
+func marshalECPrivateKey(key *ecdsa.PrivateKey) ([]byte, error) {
+	der, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, errio.Error(err)
+	}
+	return encodePEM("EC PRIVATE KEY", der), nil
+}
@@ -0,0 +1,118 @@
+package keygen
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+	"time"
+
+	"github.com/secrethub/secrethub-go/internals/assert"
+)
+
+func TestX509_GenerateKey(t *testing.T) {
+	cases := map[string]struct {
+		generator X509
+		suffix    string
+		keyUsage  x509.KeyUsage
+		err       error
+	}{
+		"rsa csr": {
+			generator: X509{
+				CommonName: "example.com",
+				DNSNames:   []string{"example.com", "www.example.com"},
+			},
+			suffix: ".csr",
+		},
+		"ecdsa csr": {
+			generator: X509{
+				Type:     KeyTypeECDSA,
+				DNSNames: []string{"example.com"},
+			},
+			suffix: ".csr",
+		},
+		"rsa self-signed": {
+			generator: X509{
+				CommonName:   "example.com",
+				Organization: "Example",
+				DNSNames:     []string{"example.com"},
+				SelfSigned:   true,
+			},
+			suffix:   ".crt",
+			keyUsage: x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		},
+		"ecdsa self-signed": {
+			generator: X509{
+				Type:       KeyTypeECDSA,
+				CommonName: "example.com",
+				SelfSigned: true,
+				Validity:   time.Hour,
+			},
+			suffix:   ".crt",
+			keyUsage: x509.KeyUsageDigitalSignature,
+		},
+		"no subject": {
+			generator: X509{},
+			err:       ErrNoSubject,
+		},
+		"negative validity": {
+			generator: X509{
+				CommonName: "example.com",
+				SelfSigned: true,
+				Validity:   -time.Hour,
+			},
+			err: ErrInvalidValidity,
+		},
+		"ed25519": {
+			generator: X509{
+				Type:       KeyTypeEd25519,
+				CommonName: "example.com",
+			},
+			err: ErrUnsupportedKeyType,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			// Act
+			key, err := tc.generator.GenerateKey()
+
+			// Assert
+			assert.Equal(t, err, tc.err)
+			if err != nil {
+				return
+			}
+
+			assert.Equal(t, key.PublicSuffix, tc.suffix)
+
+			block, rest := pem.Decode(key.Public)
+			assert.Equal(t, len(rest), 0)
+
+			if tc.generator.SelfSigned {
+				_, err = tls.X509KeyPair(key.Public, key.Private)
+				assert.OK(t, err)
+
+				cert, err := x509.ParseCertificate(block.Bytes)
+				assert.OK(t, err)
+				assert.OK(t, cert.CheckSignature(cert.SignatureAlgorithm, cert.RawTBSCertificate, cert.Signature))
+				assert.Equal(t, cert.Subject.CommonName, tc.generator.CommonName)
+				assert.Equal(t, cert.DNSNames, tc.generator.DNSNames)
+				assert.Equal(t, cert.KeyUsage, tc.keyUsage)
+
+				validity := tc.generator.Validity
+				if validity == 0 {
+					validity = DefaultValidity
+				}
+				assert.Equal(t, cert.NotAfter.Sub(cert.NotBefore), validity)
+			} else {
+				assert.Equal(t, block.Type, "CERTIFICATE REQUEST")
+
+				csr, err := x509.ParseCertificateRequest(block.Bytes)
+				assert.OK(t, err)
+				assert.OK(t, csr.CheckSignature())
+				assert.Equal(t, csr.Subject.CommonName, tc.generator.CommonName)
+				assert.Equal(t, csr.DNSNames, tc.generator.DNSNames)
+			}
+		})
+	}
+}
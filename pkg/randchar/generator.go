@@ -1,19 +1,53 @@
 package randchar
 
 import (
+	"bytes"
 	"crypto/rand"
 	"math/big"
 
 	"github.com/secrethub/secrethub-go/internals/errio"
 )
 
+// Errors
 var (
-	// charsetAlphanumeric is the default pattern of characters used to generate random secrets.
-	charsetAlphanumeric = []byte(`0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ`)
-	// charsetSymbols is added to the pattern when generator.useSymbols is true.
-	charsetSymbols = []byte(`!@#$%^*-_+=.,?`)
+	errRandchar = errio.Namespace("randchar")
+
+	ErrEmptyCharset = errRandchar.Code("empty_charset").Error("cannot generate random data from an empty charset")
 )
 
+// Charset is a set of characters random data can be generated from.
+type Charset []byte
+
+var (
+	// Numeric contains the digits 0 to 9.
+	Numeric = Charset(`0123456789`)
+	// Lowercase contains the lowercase letters a to z.
+	Lowercase = Charset(`abcdefghijklmnopqrstuvwxyz`)
+	// Uppercase contains the uppercase letters A to Z.
+	Uppercase = Charset(`ABCDEFGHIJKLMNOPQRSTUVWXYZ`)
+	// Alphanumeric is the default charset used to generate random secrets.
+	Alphanumeric = Numeric.Add(Lowercase).Add(Uppercase)
+	// Symbols is added to the default charset when symbols are used.
+	Symbols = Charset(`!@#$%^*-_+=.,?`)
+)
+
+// Add returns a charset with the characters of both charsets.
+// Characters that are in both charsets are included only once.
+func (c Charset) Add(other Charset) Charset {
+	result := make(Charset, 0, len(c)+len(other))
+	for _, char := range append(append(Charset{}, c...), other...) {
+		if !result.Contains(char) {
+			result = append(result, char)
+		}
+	}
+	return result
+}
+
+// Contains returns whether the charset contains the given character.
+func (c Charset) Contains(char byte) bool {
+	return bytes.IndexByte(c, char) >= 0
+}
+
 // Generator generates random byte arrays.
 type Generator interface {
 	Generate(length int) ([]byte, error)
@@ -21,22 +55,30 @@ type Generator interface {
 
 // NewGenerator creates a new random generator.
 func NewGenerator(useSymbols bool) Generator {
+	charset := Alphanumeric
+	if useSymbols {
+		charset = charset.Add(Symbols)
+	}
+	return NewCustomGenerator(charset)
+}
+
+// NewCustomGenerator creates a new random generator that uses the characters of the given charset.
+func NewCustomGenerator(charset Charset) Generator {
 	return &generator{
-		useSymbols: useSymbols,
+		charset: charset,
 	}
 }
 
 type generator struct {
-	useSymbols bool
+	charset Charset
 }
 
 // Generate returns a random byte array of given length.
 func (generator generator) Generate(length int) ([]byte, error) {
-	charset := charsetAlphanumeric
-	if generator.useSymbols {
-		charset = append(charset, charsetSymbols...)
+	if len(generator.charset) == 0 {
+		return nil, ErrEmptyCharset
 	}
-	return randFromCharset(charset, length)
+	return randFromCharset(generator.charset, length)
 }
 
 func randFromCharset(charset []byte, length int) ([]byte, error) {
@@ -0,0 +1,86 @@
+package randchar
+
+import (
+	"testing"
+
+	"github.com/secrethub/secrethub-go/internals/assert"
+)
+
+func TestCharset_Add(t *testing.T) {
+	cases := map[string]struct {
+		charset  Charset
+		other    Charset
+		expected Charset
+	}{
+		"disjoint": {
+			charset:  Charset("abc"),
+			other:    Charset("123"),
+			expected: Charset("abc123"),
+		},
+		"overlapping": {
+			charset:  Charset("abc"),
+			other:    Charset("bcd"),
+			expected: Charset("abcd"),
+		},
+		"duplicates": {
+			charset:  Charset("aab"),
+			other:    Charset(""),
+			expected: Charset("ab"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			// Act
+			actual := tc.charset.Add(tc.other)
+
+			// Assert
+			assert.Equal(t, actual, tc.expected)
+		})
+	}
+}
+
+func TestGenerator_Generate(t *testing.T) {
+	cases := map[string]struct {
+		generator Generator
+		charset   Charset
+		err       error
+	}{
+		"alphanumeric": {
+			generator: NewGenerator(false),
+			charset:   Alphanumeric,
+		},
+		"symbols": {
+			generator: NewGenerator(true),
+			charset:   Alphanumeric.Add(Symbols),
+		},
+		"custom": {
+			generator: NewCustomGenerator(Numeric),
+			charset:   Numeric,
+		},
+		"empty charset": {
+			generator: NewCustomGenerator(nil),
+			err:       ErrEmptyCharset,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			// Act
+			actual, err := tc.generator.Generate(50)
+
+			// Assert
+			assert.Equal(t, err, tc.err)
+			if err != nil {
+				return
+			}
+
+			assert.Equal(t, len(actual), 50)
+			for _, char := range actual {
+				if !tc.charset.Contains(char) {
+					t.Errorf("unexpected character %q", char)
+				}
+			}
+		})
+	}
+}
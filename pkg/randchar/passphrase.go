@@ -0,0 +1,217 @@
+package randchar
+
+import (
+	"crypto/rand"
+	_ "embed" // Used to embed the EFF long wordlist.
+	"math"
+	"math/big"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/secrethub/secrethub-go/internals/errio"
+)
+
+const (
+	// DefaultSeparator is the separator between the words of a passphrase when none is set.
+	DefaultSeparator = "-"
+)
+
+// Errors
+var (
+	ErrEmptyWordlist    = errRandchar.Code("empty_wordlist").Error("cannot generate a passphrase from a wordlist with less than two words")
+	ErrInvalidWordCount = errRandchar.Code("invalid_word_count").Error("a passphrase must contain at least one word")
+	ErrDigitInWordlist  = errRandchar.Code("digit_in_wordlist").Error("cannot insert a digit in passphrases of which the words or separator contain digits")
+)
+
+// effLongWordlist is the EFF long wordlist, as published on
+// https://www.eff.org/files/2016/07/18/eff_large_wordlist.txt.
+// Every line contains the five dice rolls that select a word, a tab and the word.
+//
+//go:embed eff_large_wordlist.txt
+var effLongWordlist string
+
+// effLongWords contains the words of the EFF long wordlist.
+var effLongWords = parseWordlist(effLongWordlist)
+
+// EFFLongWordlist returns the 7776 words of the EFF long wordlist, which is
+// designed for passphrases that are easy to type and to remember.
+// See https://www.eff.org/deeplinks/2016/07/new-wordlists-random-passphrases.
+func EFFLongWordlist() []string {
+	return append([]string{}, effLongWords...)
+}
+
+// parseWordlist returns the words of a wordlist in the format of the EFF wordlists.
+func parseWordlist(wordlist string) []string {
+	lines := strings.Split(strings.TrimSpace(wordlist), "\n")
+	words := make([]string, len(lines))
+	for i, line := range lines {
+		fields := strings.Fields(line)
+		words[i] = fields[len(fields)-1]
+	}
+	return words
+}
+
+// Passphrase describes the passphrases a PassphraseGenerator generates.
+// The zero value generates lowercase words of the EFF long wordlist,
+// separated by DefaultSeparator.
+type Passphrase struct {
+	// Wordlist contains the words to generate passphrases from.
+	// When it is empty, the EFF long wordlist is used.
+	Wordlist []string
+	// Separator is put between the words of a passphrase.
+	// When it is empty, DefaultSeparator is used.
+	Separator string
+	// Capitalize makes the first letter of every word uppercase.
+	Capitalize bool
+	// InsertDigit inserts a random digit at a random position of the passphrase.
+	// The words and the separator cannot contain digits, so that every position
+	// and digit results in another passphrase.
+	InsertDigit bool
+}
+
+// PassphraseGenerator generates passphrases of randomly chosen words.
+// It implements Generator, where the length is the number of words.
+type PassphraseGenerator struct {
+	words       []string
+	separator   string
+	insertDigit bool
+}
+
+// NewPassphraseGenerator returns a generator of passphrases as described by the given options.
+// Words that occur more than once in the wordlist, also after capitalization, are only used once.
+func NewPassphraseGenerator(passphrase Passphrase) (*PassphraseGenerator, error) {
+	wordlist := effLongWords
+	if len(passphrase.Wordlist) > 0 {
+		wordlist = passphrase.Wordlist
+	}
+
+	separator := passphrase.Separator
+	if separator == "" {
+		separator = DefaultSeparator
+	}
+
+	words := make([]string, 0, len(wordlist))
+	seen := make(map[string]bool, len(wordlist))
+	for _, word := range wordlist {
+		if word == "" {
+			continue
+		}
+		if passphrase.Capitalize {
+			first, size := utf8.DecodeRuneInString(word)
+			word = string(unicode.ToUpper(first)) + word[size:]
+		}
+		if !seen[word] {
+			seen[word] = true
+			words = append(words, word)
+		}
+	}
+	if len(words) < 2 {
+		return nil, ErrEmptyWordlist
+	}
+
+	if passphrase.InsertDigit {
+		for _, word := range append(words, separator) {
+			if strings.ContainsAny(word, string(Numeric)) {
+				return nil, ErrDigitInWordlist
+			}
+		}
+	}
+
+	return &PassphraseGenerator{
+		words:       words,
+		separator:   separator,
+		insertDigit: passphrase.InsertDigit,
+	}, nil
+}
+
+// Generate returns a passphrase of the given number of words.
+// Every word is chosen uniformly at random from the wordlist.
+func (g *PassphraseGenerator) Generate(words int) ([]byte, error) {
+	if words < 1 {
+		return nil, ErrInvalidWordCount
+	}
+
+	chosen := make([]string, words)
+	for i := range chosen {
+		n, err := randInt(len(g.words))
+		if err != nil {
+			return nil, err
+		}
+		chosen[i] = g.words[n]
+	}
+	passphrase := strings.Join(chosen, g.separator)
+
+	if g.insertDigit {
+		runes := []rune(passphrase)
+		i, err := randInt(len(runes) + 1)
+		if err != nil {
+			return nil, err
+		}
+
+		digit, err := randInt(len(Numeric))
+		if err != nil {
+			return nil, err
+		}
+
+		passphrase = string(runes[:i]) + string(Numeric[digit]) + string(runes[i:])
+	}
+
+	return []byte(passphrase), nil
+}
+
+// Entropy returns the number of bits of entropy of a passphrase of the given number of words.
+// Capitalization and the separator do not add entropy, as they are the same for every passphrase.
+// An inserted digit adds the entropy of its position and value. As the number of positions
+// depends on the length of the chosen words, this is averaged over all possible passphrases.
+func (g *PassphraseGenerator) Entropy(words int) (float64, error) {
+	if words < 1 {
+		return 0, ErrInvalidWordCount
+	}
+
+	entropy := float64(words) * math.Log2(float64(len(g.words)))
+	if g.insertDigit {
+		separators := (words - 1) * utf8.RuneCountInString(g.separator)
+		for length, p := range g.lengthProbabilities(words) {
+			if p > 0 {
+				positions := length + separators + 1
+				entropy += p * math.Log2(float64(positions*len(Numeric)))
+			}
+		}
+	}
+	return entropy, nil
+}
+
+// lengthProbabilities returns the probability of every total number of
+// runes of the given number of words, excluding the separators.
+func (g *PassphraseGenerator) lengthProbabilities(words int) []float64 {
+	var wordLengths []float64
+	for _, word := range g.words {
+		length := utf8.RuneCountInString(word)
+		for len(wordLengths) <= length {
+			wordLengths = append(wordLengths, 0)
+		}
+		wordLengths[length] += 1 / float64(len(g.words))
+	}
+
+	probabilities := []float64{1}
+	for i := 0; i < words; i++ {
+		next := make([]float64, len(probabilities)+len(wordLengths)-1)
+		for total, p := range probabilities {
+			for length, q := range wordLengths {
+				next[total+length] += p * q
+			}
+		}
+		probabilities = next
+	}
+	return probabilities
+}
+
+// randInt returns a uniformly random number in [0, n).
+func randInt(n int) (int, error) {
+	x, err := rand.Int(rand.Reader, big.NewInt(int64(n)))
+	if err != nil {
+		return 0, errio.Error(err)
+	}
+	return int(x.Int64()), nil
+}
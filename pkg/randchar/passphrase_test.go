@@ -0,0 +1,267 @@
+package randchar
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/secrethub/secrethub-go/internals/assert"
+)
+
+func TestEFFLongWordlist(t *testing.T) {
+	// Act
+	words := EFFLongWordlist()
+
+	// Assert
+	assert.Equal(t, len(words), 7776)
+	assert.Equal(t, words[0], "abacus")
+	assert.Equal(t, words[len(words)-1], "zoom")
+
+	seen := make(map[string]bool, len(words))
+	for _, word := range words {
+		if seen[word] {
+			t.Errorf("duplicate word %s", word)
+		}
+		seen[word] = true
+	}
+}
+
+func TestPassphraseGenerator_Generate(t *testing.T) {
+	wordlist := []string{"correct", "horse", "battery", "staple"}
+
+	cases := map[string]struct {
+		passphrase Passphrase
+		words      int
+		expected   func(t *testing.T, passphrase string)
+		err        error
+	}{
+		"default": {
+			passphrase: Passphrase{},
+			words:      6,
+			expected: func(t *testing.T, passphrase string) {
+				words := strings.Split(passphrase, DefaultSeparator)
+				assert.Equal(t, len(words) >= 6, true)
+			},
+		},
+		"custom wordlist": {
+			passphrase: Passphrase{
+				Wordlist:  wordlist,
+				Separator: " ",
+			},
+			words: 4,
+			expected: func(t *testing.T, passphrase string) {
+				words := strings.Split(passphrase, " ")
+				assert.Equal(t, len(words), 4)
+				for _, word := range words {
+					assert.Equal(t, contains(wordlist, word), true)
+				}
+			},
+		},
+		"capitalize": {
+			passphrase: Passphrase{
+				Wordlist:   wordlist,
+				Capitalize: true,
+			},
+			words: 4,
+			expected: func(t *testing.T, passphrase string) {
+				for _, word := range strings.Split(passphrase, DefaultSeparator) {
+					assert.Equal(t, contains(wordlist, strings.ToLower(word)), true)
+					assert.Equal(t, strings.ToUpper(word[:1]), word[:1])
+				}
+			},
+		},
+		"insert digit": {
+			passphrase: Passphrase{
+				Wordlist:    wordlist,
+				InsertDigit: true,
+			},
+			words: 3,
+			expected: func(t *testing.T, passphrase string) {
+				digits := 0
+				withoutDigits := strings.Map(func(r rune) rune {
+					if r < 128 && Numeric.Contains(byte(r)) {
+						digits++
+						return -1
+					}
+					return r
+				}, passphrase)
+				assert.Equal(t, digits, 1)
+				for _, word := range strings.Split(withoutDigits, DefaultSeparator) {
+					assert.Equal(t, contains(wordlist, word), true)
+				}
+			},
+		},
+		"zero words": {
+			passphrase: Passphrase{},
+			words:      0,
+			err:        ErrInvalidWordCount,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			// Arrange
+			generator, err := NewPassphraseGenerator(tc.passphrase)
+			assert.OK(t, err)
+
+			// Act
+			actual, err := generator.Generate(tc.words)
+
+			// Assert
+			assert.Equal(t, err, tc.err)
+			if err == nil {
+				tc.expected(t, string(actual))
+			}
+		})
+	}
+}
+
+func TestPassphraseGenerator_InsertDigitPosition(t *testing.T) {
+	// Arrange
+	generator, err := NewPassphraseGenerator(Passphrase{
+		Wordlist:    []string{"abc", "def"},
+		InsertDigit: true,
+	})
+	assert.OK(t, err)
+
+	// Act
+	positions := make(map[int]bool)
+	for i := 0; i < 500; i++ {
+		passphrase, err := generator.Generate(1)
+		assert.OK(t, err)
+
+		for j, char := range passphrase {
+			if Numeric.Contains(char) {
+				positions[j] = true
+			}
+		}
+	}
+
+	// Assert
+	assert.Equal(t, positions, map[int]bool{0: true, 1: true, 2: true, 3: true})
+}
+
+func TestPassphraseGenerator_Entropy(t *testing.T) {
+	cases := map[string]struct {
+		passphrase Passphrase
+		words      int
+		expected   float64
+		err        error
+	}{
+		"default": {
+			passphrase: Passphrase{},
+			words:      6,
+			expected:   6 * math.Log2(7776),
+		},
+		"capitalize": {
+			passphrase: Passphrase{
+				Capitalize: true,
+			},
+			words:    6,
+			expected: 6 * math.Log2(7776),
+		},
+		"insert digit": {
+			passphrase: Passphrase{
+				Wordlist:    []string{"a", "b", "c", "d"},
+				InsertDigit: true,
+			},
+			words: 2,
+			// 3 runes, including the separator, give 4 positions for 10 digits.
+			expected: math.Log2(4*4) + math.Log2(4*10),
+		},
+		"insert digit in words of different lengths": {
+			passphrase: Passphrase{
+				Wordlist:    []string{"a", "bb"},
+				InsertDigit: true,
+			},
+			words:    1,
+			expected: 1 + 0.5*math.Log2(2*10) + 0.5*math.Log2(3*10),
+		},
+		"capitalized duplicates": {
+			passphrase: Passphrase{
+				Wordlist:   []string{"a", "A", "b"},
+				Capitalize: true,
+			},
+			words:    2,
+			expected: 2,
+		},
+		"duplicate words": {
+			passphrase: Passphrase{
+				Wordlist: []string{"a", "b", "a", "b"},
+			},
+			words:    3,
+			expected: 3,
+		},
+		"negative words": {
+			passphrase: Passphrase{},
+			words:      -1,
+			err:        ErrInvalidWordCount,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			// Arrange
+			generator, err := NewPassphraseGenerator(tc.passphrase)
+			assert.OK(t, err)
+
+			// Act
+			actual, err := generator.Entropy(tc.words)
+
+			// Assert
+			assert.Equal(t, err, tc.err)
+			if math.Abs(actual-tc.expected) > 1e-9 {
+				t.Errorf("unexpected entropy: %v (actual) != %v (expected)", actual, tc.expected)
+			}
+		})
+	}
+}
+
+func TestNewPassphraseGenerator(t *testing.T) {
+	cases := map[string]struct {
+		wordlist    []string
+		insertDigit bool
+		err         error
+	}{
+		"one word": {
+			wordlist: []string{"a", "a"},
+			err:      ErrEmptyWordlist,
+		},
+		"empty words": {
+			wordlist: []string{"", ""},
+			err:      ErrEmptyWordlist,
+		},
+		"two words": {
+			wordlist: []string{"a", "b"},
+		},
+		"digits without inserting a digit": {
+			wordlist: []string{"a1", "b2"},
+		},
+		"digits when inserting a digit": {
+			wordlist:    []string{"a1", "b"},
+			insertDigit: true,
+			err:         ErrDigitInWordlist,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			// Act
+			_, err := NewPassphraseGenerator(Passphrase{Wordlist: tc.wordlist, InsertDigit: tc.insertDigit})
+
+			// Assert
+			assert.Equal(t, err, tc.err)
+		})
+	}
+}
+
+func contains(words []string, word string) bool {
+	for _, w := range words {
+		if w == word {
+			return true
+		}
+	}
+	return false
+}
+
+var _ Generator = (*PassphraseGenerator)(nil)
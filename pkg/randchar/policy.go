@@ -0,0 +1,259 @@
+package randchar
+
+import (
+	"crypto/rand"
+	"math"
+	"math/big"
+
+	"github.com/secrethub/secrethub-go/internals/errio"
+)
+
+// maxPolicyStates is the maximum number of combinations of remaining minimum
+// counts a policy can have, which bounds the memory used to generate data.
+const maxPolicyStates = 1 << 12
+
+// Errors
+var (
+	ErrInvalidLength       = errRandchar.Code("invalid_length").Error("cannot generate random data of a negative length")
+	ErrInvalidMinCount     = errRandchar.Code("invalid_min_count").Error("the minimum count of a requirement cannot be negative")
+	ErrPolicyUnsatisfiable = errRandchar.Code("policy_unsatisfiable").Error("no random data of the given length meets all requirements of the policy")
+	ErrPolicyTooComplex    = errRandchar.Code("policy_too_complex").Error("the minimum counts of the requirements of the policy are too high")
+)
+
+// Ambiguous contains characters that are easily confused with each other when read,
+// such as 0 and O or 1, l and I.
+var Ambiguous = Charset(`0Oo1lI|`)
+
+// Subtract returns a charset with the characters of the charset that are not in the other charset.
+func (c Charset) Subtract(other Charset) Charset {
+	result := Charset{}
+	for _, char := range c {
+		if !other.Contains(char) && !result.Contains(char) {
+			result = append(result, char)
+		}
+	}
+	return result
+}
+
+// Requirement requires random data to contain at least Min characters of Charset.
+type Requirement struct {
+	Charset Charset
+	Min     int
+}
+
+// Policy describes the random data a PolicyGenerator generates.
+type Policy struct {
+	// Charset contains the characters to generate data from.
+	// When it is empty, Alphanumeric is used.
+	Charset Charset
+	// ExcludeAmbiguous removes the Ambiguous characters from the charset.
+	ExcludeAmbiguous bool
+	// Requirements contains the minimum number of characters of character
+	// classes, e.g. at least 2 characters of Numeric.
+	Requirements []Requirement
+}
+
+// PolicyGenerator generates random data according to a policy.
+//
+// Every piece of data of a given length that meets the requirements of the policy is
+// equally likely to be generated. Instead of discarding data that does not meet the
+// requirements, the number of valid completions of the data is counted exactly for
+// every position, so generating data always takes the same number of random draws.
+type PolicyGenerator struct {
+	charset Charset
+	mins    []int
+	// cells groups the characters of the charset by the requirements they count for.
+	cells []cell
+}
+
+// cell is a group of characters that count for the same requirements.
+type cell struct {
+	// requirements contains a bit for every requirement the characters count for.
+	requirements uint
+	chars        Charset
+}
+
+// NewPolicyGenerator returns a generator of random data according to the policy.
+func NewPolicyGenerator(policy Policy) (*PolicyGenerator, error) {
+	charset := policy.Charset
+	if len(charset) == 0 {
+		charset = Alphanumeric
+	}
+	charset = charset.Add(nil)
+	if policy.ExcludeAmbiguous {
+		charset = charset.Subtract(Ambiguous)
+	}
+	if len(charset) == 0 {
+		return nil, ErrEmptyCharset
+	}
+
+	states := 1
+	mins := make([]int, len(policy.Requirements))
+	for i, requirement := range policy.Requirements {
+		if requirement.Min < 0 {
+			return nil, ErrInvalidMinCount
+		}
+		mins[i] = requirement.Min
+
+		states *= requirement.Min + 1
+		if states > maxPolicyStates {
+			return nil, ErrPolicyTooComplex
+		}
+	}
+
+	var cells []cell
+	index := make(map[uint]int)
+	for _, char := range charset {
+		var requirements uint
+		for i, requirement := range policy.Requirements {
+			if requirement.Charset.Contains(char) {
+				requirements |= 1 << uint(i)
+			}
+		}
+
+		i, ok := index[requirements]
+		if !ok {
+			i = len(cells)
+			index[requirements] = i
+			cells = append(cells, cell{requirements: requirements})
+		}
+		cells[i].chars = append(cells[i].chars, char)
+	}
+
+	return &PolicyGenerator{
+		charset: charset,
+		mins:    mins,
+		cells:   cells,
+	}, nil
+}
+
+// Charset returns the characters the generator uses.
+func (g *PolicyGenerator) Charset() Charset {
+	return append(Charset{}, g.charset...)
+}
+
+// Generate returns random data of the given length that meets the requirements of the policy.
+func (g *PolicyGenerator) Generate(length int) ([]byte, error) {
+	counts, err := g.count(length)
+	if err != nil {
+		return nil, err
+	}
+
+	data := make([]byte, length)
+	state := g.initialState()
+	for pos := 0; pos < length; pos++ {
+		remaining := length - pos
+
+		// Draw one of the valid completions of the data and
+		// select the character that completion starts with.
+		x, err := rand.Int(rand.Reader, counts[remaining][state])
+		if err != nil {
+			return nil, errio.Error(err)
+		}
+
+		for _, c := range g.cells {
+			next := g.nextState(state, c.requirements)
+			completions := counts[remaining-1][next]
+			if completions.Sign() == 0 {
+				continue
+			}
+
+			weight := new(big.Int).Mul(completions, big.NewInt(int64(len(c.chars))))
+			if x.Cmp(weight) < 0 {
+				i := new(big.Int).Div(x, completions)
+				data[pos] = c.chars[i.Int64()]
+				state = next
+				break
+			}
+			x.Sub(x, weight)
+		}
+	}
+
+	return data, nil
+}
+
+// Entropy returns the number of bits of entropy of random data of the given length,
+// which is the base 2 logarithm of the number of different pieces of data of that
+// length that meet the requirements of the policy. The result is computed exactly,
+// so it is the same every time.
+func (g *PolicyGenerator) Entropy(length int) (float64, error) {
+	counts, err := g.count(length)
+	if err != nil {
+		return 0, err
+	}
+	return log2(counts[length][g.initialState()]), nil
+}
+
+// count returns for every remaining length up to the given length and every state
+// the number of pieces of data of that length that meet the remaining minimum counts.
+// A state is the combination of the remaining minimum counts of all requirements.
+func (g *PolicyGenerator) count(length int) ([][]*big.Int, error) {
+	if length < 0 {
+		return nil, ErrInvalidLength
+	}
+
+	states := 1
+	for _, min := range g.mins {
+		states *= min + 1
+	}
+
+	counts := make([][]*big.Int, length+1)
+	counts[0] = make([]*big.Int, states)
+	for state := range counts[0] {
+		counts[0][state] = big.NewInt(0)
+	}
+	counts[0][0].SetInt64(1)
+
+	for remaining := 1; remaining <= length; remaining++ {
+		counts[remaining] = make([]*big.Int, states)
+		for state := range counts[remaining] {
+			total := big.NewInt(0)
+			for _, c := range g.cells {
+				next := g.nextState(state, c.requirements)
+				ways := new(big.Int).Mul(counts[remaining-1][next], big.NewInt(int64(len(c.chars))))
+				total.Add(total, ways)
+			}
+			counts[remaining][state] = total
+		}
+	}
+
+	if counts[length][g.initialState()].Sign() == 0 {
+		return nil, ErrPolicyUnsatisfiable
+	}
+	return counts, nil
+}
+
+// initialState returns the state in which none of the minimum counts have been met.
+func (g *PolicyGenerator) initialState() int {
+	state := 0
+	for i := len(g.mins) - 1; i >= 0; i-- {
+		state = state*(g.mins[i]+1) + g.mins[i]
+	}
+	return state
+}
+
+// nextState returns the state after adding a character that counts for the given requirements.
+func (g *PolicyGenerator) nextState(state int, requirements uint) int {
+	next := 0
+	radix := 1
+	for i, min := range g.mins {
+		remaining := state % (min + 1)
+		state /= min + 1
+
+		if requirements&(1<<uint(i)) != 0 && remaining > 0 {
+			remaining--
+		}
+
+		next += remaining * radix
+		radix *= min + 1
+	}
+	return next
+}
+
+// log2 returns the base 2 logarithm of a positive number.
+func log2(x *big.Int) float64 {
+	mantissa := new(big.Float)
+	exp := new(big.Float).SetInt(x).MantExp(mantissa)
+	m, _ := mantissa.Float64()
+	return float64(exp) + math.Log2(m)
+}
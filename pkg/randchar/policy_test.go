@@ -0,0 +1,196 @@
+package randchar
+
+import (
+	"math"
+	"testing"
+
+	"github.com/secrethub/secrethub-go/internals/assert"
+)
+
+func TestPolicyGenerator_Entropy(t *testing.T) {
+	cases := map[string]struct {
+		policy   Policy
+		length   int
+		expected float64
+		err      error
+	}{
+		"no requirements": {
+			policy: Policy{
+				Charset: Numeric,
+			},
+			length:   4,
+			expected: math.Log2(10000),
+		},
+		"requirement": {
+			// aaa, aab, aba and baa.
+			policy: Policy{
+				Charset:      Charset("ab"),
+				Requirements: []Requirement{{Charset: Charset("a"), Min: 2}},
+			},
+			length:   3,
+			expected: 2,
+		},
+		"overlapping requirements": {
+			// ab, ac, ba, bb, bc, ca and cb.
+			policy: Policy{
+				Charset: Charset("abc"),
+				Requirements: []Requirement{
+					{Charset: Charset("ab"), Min: 1},
+					{Charset: Charset("bc"), Min: 1},
+				},
+			},
+			length:   2,
+			expected: math.Log2(7),
+		},
+		"exclude ambiguous": {
+			policy: Policy{
+				Charset:          Numeric,
+				ExcludeAmbiguous: true,
+			},
+			length:   1,
+			expected: math.Log2(8),
+		},
+		"unsatisfiable": {
+			policy: Policy{
+				Requirements: []Requirement{
+					{Charset: Numeric, Min: 1},
+					{Charset: Lowercase, Min: 1},
+				},
+			},
+			length: 1,
+			err:    ErrPolicyUnsatisfiable,
+		},
+		"negative length": {
+			policy: Policy{},
+			length: -1,
+			err:    ErrInvalidLength,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			// Arrange
+			generator, err := NewPolicyGenerator(tc.policy)
+			assert.OK(t, err)
+
+			// Act
+			actual, err := generator.Entropy(tc.length)
+
+			// Assert
+			assert.Equal(t, err, tc.err)
+			if math.Abs(actual-tc.expected) > 1e-9 {
+				t.Errorf("unexpected entropy: %f (actual) != %f (expected)", actual, tc.expected)
+			}
+		})
+	}
+}
+
+func TestNewPolicyGenerator(t *testing.T) {
+	cases := map[string]struct {
+		policy Policy
+		err    error
+	}{
+		"default": {
+			policy: Policy{},
+		},
+		"empty charset": {
+			policy: Policy{
+				Charset:          Charset("0O"),
+				ExcludeAmbiguous: true,
+			},
+			err: ErrEmptyCharset,
+		},
+		"negative min": {
+			policy: Policy{
+				Requirements: []Requirement{{Charset: Numeric, Min: -1}},
+			},
+			err: ErrInvalidMinCount,
+		},
+		"too complex": {
+			policy: Policy{
+				Requirements: []Requirement{
+					{Charset: Numeric, Min: 100},
+					{Charset: Lowercase, Min: 100},
+				},
+			},
+			err: ErrPolicyTooComplex,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			// Act
+			_, err := NewPolicyGenerator(tc.policy)
+
+			// Assert
+			assert.Equal(t, err, tc.err)
+		})
+	}
+}
+
+func TestPolicyGenerator_Generate(t *testing.T) {
+	// Arrange
+	policy := Policy{
+		Charset:      Charset("ab"),
+		Requirements: []Requirement{{Charset: Charset("a"), Min: 2}},
+	}
+
+	generator, err := NewPolicyGenerator(policy)
+	assert.OK(t, err)
+
+	// Act
+	generated := make(map[string]int)
+	for i := 0; i < 400; i++ {
+		data, err := generator.Generate(3)
+		assert.OK(t, err)
+		generated[string(data)]++
+	}
+
+	// Assert
+	expected := []string{"aaa", "aab", "aba", "baa"}
+	assert.Equal(t, len(generated), len(expected))
+	for _, data := range expected {
+		if generated[data] == 0 {
+			t.Errorf("%s was never generated", data)
+		}
+	}
+}
+
+func TestPolicyGenerator_Generate_Requirements(t *testing.T) {
+	// Arrange
+	policy := Policy{
+		Charset:          Alphanumeric.Add(Symbols),
+		ExcludeAmbiguous: true,
+		Requirements: []Requirement{
+			{Charset: Numeric, Min: 2},
+			{Charset: Uppercase, Min: 1},
+			{Charset: Symbols, Min: 3},
+		},
+	}
+
+	generator, err := NewPolicyGenerator(policy)
+	assert.OK(t, err)
+
+	for i := 0; i < 100; i++ {
+		// Act
+		data, err := generator.Generate(8)
+		assert.OK(t, err)
+
+		// Assert
+		assert.Equal(t, len(data), 8)
+		for _, requirement := range policy.Requirements {
+			count := 0
+			for _, char := range data {
+				if requirement.Charset.Contains(char) {
+					count++
+				}
+				if Ambiguous.Contains(char) {
+					t.Errorf("%s contains ambiguous character %q", data, char)
+				}
+			}
+			if count < requirement.Min {
+				t.Errorf("%s contains %d characters of %s, expected at least %d", data, count, requirement.Charset, requirement.Min)
+			}
+		}
+	}
+}
@@ -1,6 +1,8 @@
 package secrethub
 
 import (
+	"context"
+
 	"github.com/secrethub/secrethub-go/internals/api"
 	"github.com/secrethub/secrethub-go/internals/crypto"
 	"github.com/secrethub/secrethub-go/internals/errio"
@@ -10,28 +12,35 @@ import (
 type AccountService interface {
 	// Get retrieves an account by name.
 	Get(name string) (*api.Account, error)
+	// GetContext is the same as Get, but uses the given context for all requests.
+	GetContext(ctx context.Context, name string) (*api.Account, error)
 	// Keys returns an account key service.
 	Keys() AccountKeyService
 }
 
-func newAccountService(client client) AccountService {
+func newAccountService(client *client) AccountService {
 	return &accountService{
 		client: client,
 	}
 }
 
 type accountService struct {
-	client client
+	client *client
 }
 
 // Get retrieves an account by name.
 func (s accountService) Get(name string) (*api.Account, error) {
+	return s.GetContext(context.Background(), name)
+}
+
+// GetContext is the same as Get, but uses the given context for all requests.
+func (s accountService) GetContext(ctx context.Context, name string) (*api.Account, error) {
 	accountName, err := api.NewAccountName(name)
 	if err != nil {
 		return nil, errio.Error(err)
 	}
 
-	return s.client.httpClient.GetAccount(accountName)
+	return s.client.httpClient.GetAccount(ctx, accountName)
 }
 
 // Keys returns an account key service.
@@ -85,9 +94,12 @@ func (c *client) createCredentialRequest(credential Credential) (*api.CreateCred
 
 // getAccountKey attempts to get the account key from the cache,
 // getting it from the API if not found in the cache.
-func (c *client) getAccountKey() (*crypto.RSAPrivateKey, error) {
+func (c *client) getAccountKey(ctx context.Context) (*crypto.RSAPrivateKey, error) {
+	c.accountMutex.Lock()
+	defer c.accountMutex.Unlock()
+
 	if c.accountKey == nil {
-		err := c.fetchAccountDetails()
+		err := c.fetchAccountDetails(ctx)
 		if err != nil {
 			return nil, errio.Error(err)
 		}
@@ -97,13 +109,16 @@ func (c *client) getAccountKey() (*crypto.RSAPrivateKey, error) {
 }
 
 // getMyAccount returns the account of the client itself.
-func (c *client) getMyAccount() (*api.Account, error) {
+func (c *client) getMyAccount(ctx context.Context) (*api.Account, error) {
+	c.accountMutex.Lock()
+	defer c.accountMutex.Unlock()
+
 	// retrieve the account from cache
 	if c.account != nil {
 		return c.account, nil
 	}
 
-	err := c.fetchAccountDetails()
+	err := c.fetchAccountDetails(ctx)
 	if err != nil {
 		return nil, errio.Error(err)
 	}
@@ -114,9 +129,11 @@ func (c *client) getMyAccount() (*api.Account, error) {
 // fetchAccountDetails is a helper function that fetches the account and account key from the API.
 // These are cached in the client.
 // This function should only be called from client.getAccountKey or client.getMyAccount
+// and the caller must hold the client's accountMutex, so the account key is only fetched
+// and decrypted once, even when the client is used from multiple goroutines.
 // Don't use this unless you know what you're doing. Use client.getAccountKey instead.
-func (c *client) fetchAccountDetails() error {
-	resp, err := c.httpClient.GetAccountKey()
+func (c *client) fetchAccountDetails(ctx context.Context) error {
+	resp, err := c.fetchEncryptedAccountKey(ctx)
 	if err != nil {
 		return errio.Error(err)
 	}
@@ -1,6 +1,8 @@
 package secrethub
 
 import (
+	"context"
+
 	"github.com/secrethub/secrethub-go/internals/api"
 	"github.com/secrethub/secrethub-go/internals/crypto"
 )
@@ -16,16 +18,20 @@ func generateAccountKey() (crypto.RSAPrivateKey, error) {
 type AccountKeyService interface {
 	// Create creates an account key for the client's credential.
 	Create() (*api.EncryptedAccountKey, error)
+	// CreateContext is the same as Create, but uses the given context for all requests.
+	CreateContext(ctx context.Context) (*api.EncryptedAccountKey, error)
 	// Exists returns whether an account key exists for the client's credential.
 	Exists() (bool, error)
+	// ExistsContext is the same as Exists, but uses the given context for all requests.
+	ExistsContext(ctx context.Context) (bool, error)
 }
 
 type accountKeyService struct {
-	client client
+	client *client
 }
 
 // newAccountKeyService creates a new accountKeyService
-func newAccountKeyService(client client) accountKeyService {
+func newAccountKeyService(client *client) accountKeyService {
 	return accountKeyService{
 		client: client,
 	}
@@ -33,16 +39,26 @@ func newAccountKeyService(client client) accountKeyService {
 
 // Create creates an account key for the clients credential.
 func (s accountKeyService) Create() (*api.EncryptedAccountKey, error) {
+	return s.CreateContext(context.Background())
+}
+
+// CreateContext is the same as Create, but uses the given context for all requests.
+func (s accountKeyService) CreateContext(ctx context.Context) (*api.EncryptedAccountKey, error) {
 	key, err := generateAccountKey()
 	if err != nil {
 		return nil, err
 	}
-	return s.client.createAccountKey(key)
+	return s.client.createAccountKey(ctx, key)
 }
 
 // Exists returns whether an account key exists for the client's credential.
 func (s accountKeyService) Exists() (bool, error) {
-	_, err := s.client.getAccountKey()
+	return s.ExistsContext(context.Background())
+}
+
+// ExistsContext is the same as Exists, but uses the given context for all requests.
+func (s accountKeyService) ExistsContext(ctx context.Context) (bool, error) {
+	_, err := s.client.getAccountKey(ctx)
 	if err == api.ErrAccountKeyNotFound || err == api.ErrCredentialNotKeyed {
 		return false, nil
 	}
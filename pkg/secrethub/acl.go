@@ -1,6 +1,8 @@
 package secrethub
 
 import (
+	"context"
+
 	"github.com/secrethub/secrethub-go/internals/api"
 	"github.com/secrethub/secrethub-go/internals/api/uuid"
 	"github.com/secrethub/secrethub-go/internals/errio"
@@ -10,20 +12,30 @@ import (
 type AccessRuleService interface {
 	// Delete removes the accessrule for the given directory and account.
 	Delete(path string, accountName string) error
+	// DeleteContext is the same as Delete, but uses the given context for all requests.
+	DeleteContext(ctx context.Context, path string, accountName string) error
 	// Get retrieves the access rule for the given account on the given directory.
 	Get(path string, accountName string) (*api.AccessRule, error)
+	// GetContext is the same as Get, but uses the given context for all requests.
+	GetContext(ctx context.Context, path string, accountName string) (*api.AccessRule, error)
 	// List etrieves all access rules that apply to a directory, including
 	// rules that apply to its children up to a specified depth. When ancestors is set
 	// to true, it also includes rules for any parent directories. When the depth is
 	// set to -1, all children are retrieved without limit.
 	List(path string, depth int, ancestors bool) ([]*api.AccessRule, error)
+	// ListContext is the same as List, but uses the given context for all requests.
+	ListContext(ctx context.Context, path string, depth int, ancestors bool) ([]*api.AccessRule, error)
 	// ListLevels lists the access levels on the given directory.
 	ListLevels(path string) ([]*api.AccessLevel, error)
+	// ListLevelsContext is the same as ListLevels, but uses the given context for all requests.
+	ListLevelsContext(ctx context.Context, path string) ([]*api.AccessLevel, error)
 	// Set sets an access rule with a certain permission level for an account to a path.
 	Set(path string, permission api.Permission, accountName string) (*api.AccessRule, error)
+	// SetContext is the same as Set, but uses the given context for all requests.
+	SetContext(ctx context.Context, path string, permission api.Permission, accountName string) (*api.AccessRule, error)
 }
 
-func newAccessRuleService(client client) AccessRuleService {
+func newAccessRuleService(client *client) AccessRuleService {
 	return accessRuleService{
 		client:         client,
 		accountService: newAccountService(client),
@@ -32,13 +44,18 @@ func newAccessRuleService(client client) AccessRuleService {
 }
 
 type accessRuleService struct {
-	client         client
+	client         *client
 	accountService AccountService
 	dirService     DirService
 }
 
 // Delete removes the accessrule for the given directory and account.
 func (s accessRuleService) Delete(path string, accountName string) error {
+	return s.DeleteContext(context.Background(), path, accountName)
+}
+
+// DeleteContext is the same as Delete, but uses the given context for all requests.
+func (s accessRuleService) DeleteContext(ctx context.Context, path string, accountName string) error {
 	p, err := api.NewDirPath(path)
 	if err != nil {
 		return errio.Error(err)
@@ -49,12 +66,12 @@ func (s accessRuleService) Delete(path string, accountName string) error {
 		return errio.Error(err)
 	}
 
-	blindName, err := s.client.convertPathToBlindName(p)
+	blindName, err := s.client.convertPathToBlindName(ctx, p)
 	if err != nil {
 		return errio.Error(err)
 	}
 
-	err = s.client.httpClient.DeleteAccessRule(blindName, an)
+	err = s.client.httpClient.DeleteAccessRule(ctx, blindName, an)
 	if err != nil {
 		return errio.Error(err)
 	}
@@ -64,6 +81,11 @@ func (s accessRuleService) Delete(path string, accountName string) error {
 
 // Get retrieves the access rule for the given account on the given directory.
 func (s accessRuleService) Get(path string, accountName string) (*api.AccessRule, error) {
+	return s.GetContext(context.Background(), path, accountName)
+}
+
+// GetContext is the same as Get, but uses the given context for all requests.
+func (s accessRuleService) GetContext(ctx context.Context, path string, accountName string) (*api.AccessRule, error) {
 	p, err := api.NewDirPath(path)
 	if err != nil {
 		return nil, errio.Error(err)
@@ -74,12 +96,12 @@ func (s accessRuleService) Get(path string, accountName string) (*api.AccessRule
 		return nil, errio.Error(err)
 	}
 
-	blindName, err := s.client.convertPathToBlindName(p)
+	blindName, err := s.client.convertPathToBlindName(ctx, p)
 	if err != nil {
 		return nil, errio.Error(err)
 	}
 
-	accessRule, err := s.client.httpClient.GetAccessRule(blindName, an)
+	accessRule, err := s.client.httpClient.GetAccessRule(ctx, blindName, an)
 	if err != nil {
 		return nil, errio.Error(err)
 	}
@@ -92,17 +114,22 @@ func (s accessRuleService) Get(path string, accountName string) (*api.AccessRule
 // to true, it also includes rules for any parent directories. When the depth is
 // set to -1, all children are retrieved without limit.
 func (s accessRuleService) List(path string, depth int, ancestors bool) ([]*api.AccessRule, error) {
+	return s.ListContext(context.Background(), path, depth, ancestors)
+}
+
+// ListContext is the same as List, but uses the given context for all requests.
+func (s accessRuleService) ListContext(ctx context.Context, path string, depth int, ancestors bool) ([]*api.AccessRule, error) {
 	p, err := api.NewDirPath(path)
 	if err != nil {
 		return nil, errio.Error(err)
 	}
 
-	blindName, err := s.client.convertPathToBlindName(p)
+	blindName, err := s.client.convertPathToBlindName(ctx, p)
 	if err != nil {
 		return nil, errio.Error(err)
 	}
 
-	rules, err := s.client.httpClient.ListAccessRules(blindName, depth, ancestors)
+	rules, err := s.client.httpClient.ListAccessRules(ctx, blindName, depth, ancestors)
 	if err != nil {
 		return nil, errio.Error(err)
 	}
@@ -112,22 +139,27 @@ func (s accessRuleService) List(path string, depth int, ancestors bool) ([]*api.
 
 // List lists the access rules on the given directory.
 func (s accessRuleService) ListLevels(path string) ([]*api.AccessLevel, error) {
+	return s.ListLevelsContext(context.Background(), path)
+}
+
+// ListLevelsContext is the same as ListLevels, but uses the given context for all requests.
+func (s accessRuleService) ListLevelsContext(ctx context.Context, path string) ([]*api.AccessLevel, error) {
 	p, err := api.NewDirPath(path)
 	if err != nil {
 		return nil, errio.Error(err)
 	}
 
-	blindName, err := s.client.convertPathToBlindName(p)
+	blindName, err := s.client.convertPathToBlindName(ctx, p)
 	if err != nil {
 		return nil, errio.Error(err)
 	}
 
-	rules, err := s.client.httpClient.ListAccessRules(blindName, 0, true)
+	rules, err := s.client.httpClient.ListAccessRules(ctx, blindName, 0, true)
 	if err != nil {
 		return nil, errio.Error(err)
 	}
 
-	dir, err := s.dirService.GetTree(path, 0, false)
+	dir, err := s.dirService.GetTreeContext(ctx, path, 0, false)
 	if err != nil {
 		return nil, errio.Error(err)
 	}
@@ -164,6 +196,11 @@ func (s accessRuleService) ListLevels(path string) ([]*api.AccessLevel, error) {
 
 // Set sets an access rule with a certain permission level for an account to a path.
 func (s accessRuleService) Set(path string, permission api.Permission, accountName string) (*api.AccessRule, error) {
+	return s.SetContext(context.Background(), path, permission, accountName)
+}
+
+// SetContext is the same as Set, but uses the given context for all requests.
+func (s accessRuleService) SetContext(ctx context.Context, path string, permission api.Permission, accountName string) (*api.AccessRule, error) {
 	p, err := api.NewDirPath(path)
 	if err != nil {
 		return nil, errio.Error(err)
@@ -174,28 +211,28 @@ func (s accessRuleService) Set(path string, permission api.Permission, accountNa
 		return nil, errio.Error(err)
 	}
 
-	_, err = s.Get(path, accountName)
+	_, err = s.GetContext(ctx, path, accountName)
 	if err != nil && err != api.ErrAccessRuleNotFound {
 		return nil, errio.Error(err)
 	} else if err == api.ErrAccessRuleNotFound {
-		return s.create(p, permission, an)
+		return s.create(ctx, p, permission, an)
 	}
-	return s.update(p, permission, an)
+	return s.update(ctx, p, permission, an)
 }
 
 // CreateAccessRule creates a new AccessRule for an account with a certain permission level.
-func (s accessRuleService) create(path api.BlindNamePath, permission api.Permission, accountName api.AccountName) (*api.AccessRule, error) {
-	blindName, err := s.client.convertPathToBlindName(path)
+func (s accessRuleService) create(ctx context.Context, path api.BlindNamePath, permission api.Permission, accountName api.AccountName) (*api.AccessRule, error) {
+	blindName, err := s.client.convertPathToBlindName(ctx, path)
 	if err != nil {
 		return nil, errio.Error(err)
 	}
 
-	account, err := s.accountService.Get(accountName.String())
+	account, err := s.accountService.GetContext(ctx, accountName.String())
 	if err != nil {
 		return nil, errio.Error(err)
 	}
 
-	currentAccessLevel, err := s.client.getAccessLevel(path, accountName)
+	currentAccessLevel, err := s.client.getAccessLevel(ctx, path, accountName)
 	if err != nil {
 		return nil, errio.Error(err)
 	}
@@ -205,12 +242,12 @@ func (s accessRuleService) create(path api.BlindNamePath, permission api.Permiss
 	}
 
 	if currentAccessLevel.Permission < api.PermissionRead {
-		encryptedTree, err := s.client.httpClient.GetTree(blindName, -1, true)
+		encryptedTree, err := s.client.httpClient.GetTree(ctx, blindName, -1, true)
 		if err != nil {
 			return nil, errio.Error(err)
 		}
 
-		accountKey, err := s.client.getAccountKey()
+		accountKey, err := s.client.getAccountKey(ctx)
 		if err != nil {
 			return nil, errio.Error(err)
 		}
@@ -231,7 +268,7 @@ func (s accessRuleService) create(path api.BlindNamePath, permission api.Permiss
 
 		in.EncryptedSecrets = make([]api.SecretAccessRequest, 0, len(secrets))
 		for _, secret := range secrets {
-			encryptedSecrets, err := s.client.encryptSecretFor(secret, account)
+			encryptedSecrets, err := s.client.encryptSecretFor(ctx, secret, account)
 			if err != nil {
 				return nil, errio.Error(err)
 			}
@@ -245,15 +282,15 @@ func (s accessRuleService) create(path api.BlindNamePath, permission api.Permiss
 		return nil, err
 	}
 
-	accessRule, err := s.client.httpClient.CreateAccessRule(blindName, accountName, in)
+	accessRule, err := s.client.httpClient.CreateAccessRule(ctx, blindName, accountName, in)
 	return accessRule, errio.Error(err)
 
 }
 
 // UpdateAccessRule updates an AccessRule for an account with a certain permission level.
 // It fails if the AccessRule does not already exist.
-func (s accessRuleService) update(path api.BlindNamePath, permission api.Permission, name api.AccountName) (*api.AccessRule, error) {
-	blindName, err := s.client.convertPathToBlindName(path)
+func (s accessRuleService) update(ctx context.Context, path api.BlindNamePath, permission api.Permission, name api.AccountName) (*api.AccessRule, error) {
+	blindName, err := s.client.convertPathToBlindName(ctx, path)
 	if err != nil {
 		return nil, errio.Error(err)
 	}
@@ -261,19 +298,19 @@ func (s accessRuleService) update(path api.BlindNamePath, permission api.Permiss
 	in := &api.UpdateAccessRuleRequest{
 		Permission: permission,
 	}
-	accessRule, err := s.client.httpClient.UpdateAccessRule(blindName, name, in)
+	accessRule, err := s.client.httpClient.UpdateAccessRule(ctx, blindName, name, in)
 	return accessRule, errio.Error(err)
 }
 
 // GetAccessLevel retrieves the permissions of an account on a directory, defined by
 // one or more access rules on the directory itself or its parent(s).
-func (c *client) getAccessLevel(path api.BlindNamePath, accountName api.AccountName) (*api.AccessLevel, error) {
-	blindName, err := c.convertPathToBlindName(path)
+func (c *client) getAccessLevel(ctx context.Context, path api.BlindNamePath, accountName api.AccountName) (*api.AccessLevel, error) {
+	blindName, err := c.convertPathToBlindName(ctx, path)
 	if err != nil {
 		return nil, errio.Error(err)
 	}
 
-	accessLevel, err := c.httpClient.GetAccessLevel(blindName, accountName)
+	accessLevel, err := c.httpClient.GetAccessLevel(ctx, blindName, accountName)
 	if err != nil {
 		return nil, errio.Error(err)
 	}
@@ -1,12 +1,18 @@
 package secrethub
 
 import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
 	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/api/uuid"
 	"github.com/secrethub/secrethub-go/internals/errio"
 )
 
-func (c *client) decryptAuditEvents(events ...*api.Audit) error {
-	accountKey, err := c.getAccountKey()
+func (c *client) decryptAuditEvents(ctx context.Context, paths *auditPathResolver, events ...*api.Audit) error {
+	accountKey, err := c.getAccountKey(ctx)
 	if err != nil {
 		return errio.Error(err)
 	}
@@ -30,5 +36,352 @@ func (c *client) decryptAuditEvents(events ...*api.Audit) error {
 		}
 	}
 
+	return paths.resolve(ctx, events...)
+}
+
+// auditPathResolver resolves the full paths of the secrets that are the subjects of
+// audit events. When the events are of a single secret, its path is known. Otherwise,
+// the directory tree of the repository is retrieved once, when the first path is
+// resolved. Directories can only be retrieved by path, so the tree of the whole
+// repository is needed to find the directory of a secret. A nil resolver leaves
+// the paths empty.
+type auditPathResolver struct {
+	// secretPath is the path of the secret of which the events are resolved,
+	// without a version. It is empty when the paths are resolved from the tree.
+	secretPath api.SecretPath
+	fetchTree  func(ctx context.Context) (*api.Tree, error)
+	tree       *api.Tree
+	fetched    bool
+}
+
+// newAuditPathResolver returns a resolver that resolves the paths of the secrets
+// in the repository from its directory tree.
+func newAuditPathResolver(client *client, repoPath api.RepoPath) *auditPathResolver {
+	return &auditPathResolver{
+		fetchTree: func(ctx context.Context) (*api.Tree, error) {
+			return newDirService(client).GetTreeContext(ctx, repoPath.Value(), -1, false)
+		},
+	}
+}
+
+// newSecretAuditPathResolver returns a resolver for the events of the secret at the
+// given path, which sets the path of every secret subject to that path.
+func newSecretAuditPathResolver(secretPath api.SecretPath) *auditPathResolver {
+	return &auditPathResolver{
+		secretPath: api.SecretPath(strings.SplitN(secretPath.Value(), ":", 2)[0]),
+	}
+}
+
+// resolve sets the SecretPath of the decrypted secret and secret version subjects of the events.
+// Secrets that are no longer in the tree of the repository are left without a path. When the
+// tree cannot be retrieved because it does not exist or the account is not allowed to read it,
+// all secrets are left without a path.
+func (r *auditPathResolver) resolve(ctx context.Context, events ...*api.Audit) error {
+	if r == nil {
+		return nil
+	}
+
+	for _, event := range events {
+		if event.Subject.Deleted {
+			continue
+		}
+
+		secret := event.Subject.Secret
+		version := 0
+		if event.Subject.SecretVersion != nil {
+			secret = event.Subject.SecretVersion.Secret
+			version = event.Subject.SecretVersion.Version
+		}
+		if secret == nil || secret.SecretID == nil {
+			continue
+		}
+
+		secretPath, ok, err := r.secretPathOf(ctx, secret)
+		if err != nil {
+			return errio.Error(err)
+		}
+		if !ok {
+			continue
+		}
+
+		if version > 0 {
+			secretPath, err = secretPath.AddVersion(version)
+			if err != nil {
+				return errio.Error(err)
+			}
+		}
+		event.Subject.SecretPath = secretPath
+	}
+
 	return nil
 }
+
+// secretPathOf returns the path of the secret without a version and false when it cannot be resolved.
+func (r *auditPathResolver) secretPathOf(ctx context.Context, secret *api.Secret) (api.SecretPath, bool, error) {
+	if r.secretPath != "" {
+		return r.secretPath, true, nil
+	}
+
+	if !r.fetched {
+		tree, err := r.fetchTree(ctx)
+		if err != nil && !isForbiddenOrNotFound(err) {
+			return "", false, errio.Error(err)
+		}
+		r.tree = tree
+		r.fetched = true
+	}
+	if r.tree == nil {
+		return "", false, nil
+	}
+
+	secretPath, err := r.tree.AbsSecretPath(secret.SecretID)
+	if err == api.ErrSecretNotFound || err == api.ErrDirNotFound {
+		return "", false, nil
+	} else if err != nil {
+		return "", false, errio.Error(err)
+	}
+	return *secretPath, true, nil
+}
+
+// isForbiddenOrNotFound returns whether the error is returned by the server
+// because the resource does not exist or the account is not allowed to access it.
+func isForbiddenOrNotFound(err error) bool {
+	statusErr, ok := err.(errio.PublicStatusError)
+	return ok && (statusErr.StatusCode == http.StatusForbidden || statusErr.StatusCode == http.StatusNotFound)
+}
+
+const (
+	// DefaultAuditPageSize is the number of audit events that is retrieved per request
+	// when iterating over audit events and no page size is given.
+	DefaultAuditPageSize = 100
+)
+
+// Errors
+var (
+	ErrIteratorDone = errClient.Code("iterator_done").Error("there are no more items to iterate over")
+)
+
+// AuditFilter selects the audit events that are returned by an AuditEventIterator.
+// An event is returned when it matches all rules of the filter. Rules that have
+// their zero value are not used.
+//
+// Only SubjectTypes and After are sent to the server. From, Until, Actions, Actors
+// and IPAddresses are applied on the client side, so an iterator with these rules
+// retrieves the pages of events starting at the oldest event or at After. As events
+// are returned oldest first, the iterator stops at the first event logged at or after Until.
+type AuditFilter struct {
+	// From only returns the events that have been logged at or after this time.
+	From time.Time
+	// Until only returns the events that have been logged before this time.
+	Until time.Time
+	// Actions only returns the events with one of these actions.
+	Actions []api.AuditAction
+	// Actors only returns the events of which the actor is one of these accounts,
+	// identified by the username of a user or the ID of a service.
+	Actors []string
+	// SubjectTypes only returns the events with one of these subject types.
+	// When empty, the server's default is used.
+	SubjectTypes api.AuditSubjectTypeList
+	// IPAddresses only returns the events that have been logged from one of these IP addresses.
+	IPAddresses []string
+	// After only returns the events that have been logged after the event with this ID,
+	// e.g. to continue where a previous iteration stopped. Events are returned oldest first.
+	After *uuid.UUID
+	// PageSize is the number of events that is retrieved per request.
+	// Defaults to DefaultAuditPageSize.
+	PageSize int
+	// ResolvePaths sets the SecretPath of the secret subjects of the events of a repository.
+	// This retrieves and decrypts the directory tree of the whole repository once, so it
+	// is not done by default. The paths of the events of a secret are always set.
+	ResolvePaths bool
+}
+
+// Matches returns whether the event matches all rules of the filter.
+func (f AuditFilter) Matches(event *api.Audit) bool {
+	if !f.From.IsZero() && event.LoggedAt.Before(f.From) {
+		return false
+	}
+
+	if !f.Until.IsZero() && !event.LoggedAt.Before(f.Until) {
+		return false
+	}
+
+	if len(f.Actions) > 0 && !containsAuditAction(f.Actions, event.Action) {
+		return false
+	}
+
+	if len(f.Actors) > 0 {
+		name := auditActorName(event.Actor)
+		found := false
+		for _, actor := range f.Actors {
+			if name != "" && strings.EqualFold(actor, name) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if len(f.SubjectTypes) > 0 && !containsAuditSubjectType(f.SubjectTypes, event.Subject.Type) {
+		return false
+	}
+
+	if len(f.IPAddresses) > 0 && !containsString(f.IPAddresses, event.IPAddress) {
+		return false
+	}
+
+	return true
+}
+
+// auditActorName returns the name of the account of the actor,
+// or an empty string when the account has been deleted.
+func auditActorName(actor api.AuditActor) string {
+	switch {
+	case actor.User != nil:
+		return actor.User.Username
+	case actor.Service != nil:
+		return actor.Service.ServiceID
+	}
+	return ""
+}
+
+func containsAuditAction(actions []api.AuditAction, action api.AuditAction) bool {
+	for _, a := range actions {
+		if a == action {
+			return true
+		}
+	}
+	return false
+}
+
+func containsAuditSubjectType(subjectTypes api.AuditSubjectTypeList, subjectType api.AuditSubjectType) bool {
+	for _, t := range subjectTypes {
+		if t == subjectType {
+			return true
+		}
+	}
+	return false
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// AuditEventIterator iterates over audit events.
+type AuditEventIterator interface {
+	// Next returns the next audit event. It returns ErrIteratorDone
+	// when there are no more events.
+	Next() (*api.Audit, error)
+}
+
+// auditPageFunc retrieves a page of at most perPage audit events, starting
+// after the event with the given ID, or at the first event when it is nil.
+type auditPageFunc func(ctx context.Context, subjectTypes api.AuditSubjectTypeList, startingAfter *uuid.UUID, perPage int) ([]*api.Audit, error)
+
+// auditEventIterator retrieves audit events one page at a time and only
+// decrypts the subjects of the events that match the filter when they are returned.
+type auditEventIterator struct {
+	ctx      context.Context
+	client   *client
+	paths    *auditPathResolver
+	fetch    auditPageFunc
+	filter   AuditFilter
+	pageSize int
+
+	page          []*api.Audit
+	index         int
+	startingAfter *uuid.UUID
+	done          bool
+	err           error
+}
+
+func newAuditEventIterator(ctx context.Context, client *client, paths *auditPathResolver, fetch auditPageFunc, filter AuditFilter) *auditEventIterator {
+	pageSize := filter.PageSize
+	if pageSize <= 0 {
+		pageSize = DefaultAuditPageSize
+	}
+
+	return &auditEventIterator{
+		ctx:           ctx,
+		client:        client,
+		paths:         paths,
+		fetch:         fetch,
+		filter:        filter,
+		pageSize:      pageSize,
+		startingAfter: filter.After,
+	}
+}
+
+// Next returns the next audit event that matches the filter, retrieving
+// the next page when all events of the current page have been returned.
+// ErrIteratorDone is returned when there are no more events or when an
+// event logged at or after the Until of the filter has been reached.
+// Once an error has been returned, the same error is returned on every call.
+func (it *auditEventIterator) Next() (*api.Audit, error) {
+	if it.err != nil {
+		return nil, it.err
+	}
+
+	for {
+		for it.index < len(it.page) {
+			event := it.page[it.index]
+			it.index++
+
+			// Events are returned oldest first, so no later event can match.
+			if !it.filter.Until.IsZero() && !event.LoggedAt.Before(it.filter.Until) {
+				it.page = nil
+				it.index = 0
+				it.done = true
+				return nil, ErrIteratorDone
+			}
+
+			if !it.filter.Matches(event) {
+				continue
+			}
+
+			err := it.client.decryptAuditEvents(it.ctx, it.paths, event)
+			if err != nil {
+				it.err = errio.Error(err)
+				return nil, it.err
+			}
+			return event, nil
+		}
+
+		if it.done {
+			return nil, ErrIteratorDone
+		}
+
+		page, err := it.fetch(it.ctx, it.filter.SubjectTypes, it.startingAfter, it.pageSize)
+		if err != nil {
+			it.err = errio.Error(err)
+			return nil, it.err
+		}
+
+		// The server can return fewer events than requested before the last page,
+		// so only an empty page marks the end of the events.
+		it.page = page
+		it.index = 0
+		if len(page) == 0 {
+			it.done = true
+		} else {
+			it.startingAfter = page[len(page)-1].EventID
+		}
+	}
+}
+
+// errAuditEventIterator is an AuditEventIterator that only returns an error.
+type errAuditEventIterator struct {
+	err error
+}
+
+// Next returns the error of the iterator.
+func (it errAuditEventIterator) Next() (*api.Audit, error) {
+	return nil, it.err
+}
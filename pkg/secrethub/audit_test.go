@@ -0,0 +1,469 @@
+package secrethub
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/api/uuid"
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/internals/crypto"
+)
+
+func TestAuditFilter_Matches(t *testing.T) {
+	loggedAt := time.Date(2019, 1, 1, 12, 0, 0, 0, time.UTC)
+	event := &api.Audit{
+		Action:    api.AuditActionRead,
+		IPAddress: "10.0.0.1",
+		LoggedAt:  loggedAt,
+		Actor: api.AuditActor{
+			Type: "user",
+			User: &api.User{Username: "dev1"},
+		},
+		Subject: api.AuditSubject{
+			Type: api.AuditSubjectSecretVersion,
+		},
+	}
+
+	cases := map[string]struct {
+		filter   AuditFilter
+		expected bool
+	}{
+		"empty filter": {
+			filter:   AuditFilter{},
+			expected: true,
+		},
+		"from": {
+			filter:   AuditFilter{From: loggedAt},
+			expected: true,
+		},
+		"from after": {
+			filter:   AuditFilter{From: loggedAt.Add(time.Second)},
+			expected: false,
+		},
+		"until": {
+			filter:   AuditFilter{Until: loggedAt.Add(time.Second)},
+			expected: true,
+		},
+		"until is exclusive": {
+			filter:   AuditFilter{Until: loggedAt},
+			expected: false,
+		},
+		"action": {
+			filter:   AuditFilter{Actions: []api.AuditAction{api.AuditActionCreate, api.AuditActionRead}},
+			expected: true,
+		},
+		"other action": {
+			filter:   AuditFilter{Actions: []api.AuditAction{api.AuditActionDelete}},
+			expected: false,
+		},
+		"actor case insensitive": {
+			filter:   AuditFilter{Actors: []string{"Dev1"}},
+			expected: true,
+		},
+		"other actor": {
+			filter:   AuditFilter{Actors: []string{"dev2"}},
+			expected: false,
+		},
+		"subject type": {
+			filter:   AuditFilter{SubjectTypes: api.AuditSubjectTypeList{api.AuditSubjectSecretVersion}},
+			expected: true,
+		},
+		"other subject type": {
+			filter:   AuditFilter{SubjectTypes: api.AuditSubjectTypeList{api.AuditSubjectRepo}},
+			expected: false,
+		},
+		"ip address": {
+			filter:   AuditFilter{IPAddresses: []string{"10.0.0.1"}},
+			expected: true,
+		},
+		"other ip address": {
+			filter:   AuditFilter{IPAddresses: []string{"10.0.0.2"}},
+			expected: false,
+		},
+		"all rules must match": {
+			filter:   AuditFilter{Actions: []api.AuditAction{api.AuditActionRead}, Actors: []string{"dev2"}},
+			expected: false,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			// Act
+			actual := tc.filter.Matches(event)
+
+			// Assert
+			assert.Equal(t, actual, tc.expected)
+		})
+	}
+}
+
+func TestAuditFilter_Matches_DeletedActor(t *testing.T) {
+	// Arrange
+	event := &api.Audit{
+		Actor: api.AuditActor{
+			Deleted: true,
+			Type:    api.AuditSubjectAccount,
+		},
+	}
+
+	// Act
+	actual := AuditFilter{Actors: []string{""}}.Matches(event)
+
+	// Assert
+	assert.Equal(t, actual, false)
+}
+
+func TestAuditEventIterator(t *testing.T) {
+	// Arrange
+	accountKey, err := crypto.GenerateRSAPrivateKey(1024)
+	assert.OK(t, err)
+
+	events := make([]*api.Audit, 5)
+	for i := range events {
+		events[i] = &api.Audit{
+			EventID: uuid.New(),
+			Action:  api.AuditActionRead,
+			Subject: api.AuditSubject{Type: api.AuditSubjectRepo},
+		}
+	}
+	events[1].Action = api.AuditActionCreate
+
+	var calls []*uuid.UUID
+	fetch := func(ctx context.Context, subjectTypes api.AuditSubjectTypeList, startingAfter *uuid.UUID, perPage int) ([]*api.Audit, error) {
+		calls = append(calls, startingAfter)
+
+		start := 0
+		if startingAfter != nil {
+			for i, event := range events {
+				if uuid.Equal(event.EventID, startingAfter) {
+					start = i + 1
+				}
+			}
+		}
+
+		end := start + perPage
+		if end > len(events) {
+			end = len(events)
+		}
+		return events[start:end], nil
+	}
+
+	c := &client{accountKey: &accountKey}
+	it := newAuditEventIterator(context.Background(), c, nil, fetch, AuditFilter{
+		Actions:  []api.AuditAction{api.AuditActionRead},
+		PageSize: 2,
+	})
+
+	// Act
+	var actual []*api.Audit
+	for {
+		event, err := it.Next()
+		if err == ErrIteratorDone {
+			break
+		}
+		assert.OK(t, err)
+		actual = append(actual, event)
+	}
+	_, errDone := it.Next()
+
+	// Assert
+	assert.Equal(t, actual, []*api.Audit{events[0], events[2], events[3], events[4]})
+	assert.Equal(t, calls, []*uuid.UUID{nil, events[1].EventID, events[3].EventID, events[4].EventID})
+	assert.Equal(t, errDone, ErrIteratorDone)
+}
+
+func TestAuditEventIterator_ShortPages(t *testing.T) {
+	// Arrange
+	accountKey, err := crypto.GenerateRSAPrivateKey(1024)
+	assert.OK(t, err)
+
+	events := make([]*api.Audit, 5)
+	for i := range events {
+		events[i] = &api.Audit{
+			EventID: uuid.New(),
+			Subject: api.AuditSubject{Type: api.AuditSubjectRepo},
+		}
+	}
+
+	// The server returns at most 2 events per page, fewer than requested.
+	fetch := func(ctx context.Context, subjectTypes api.AuditSubjectTypeList, startingAfter *uuid.UUID, perPage int) ([]*api.Audit, error) {
+		start := 0
+		if startingAfter != nil {
+			for i, event := range events {
+				if uuid.Equal(event.EventID, startingAfter) {
+					start = i + 1
+				}
+			}
+		}
+
+		end := start + 2
+		if end > len(events) {
+			end = len(events)
+		}
+		return events[start:end], nil
+	}
+
+	c := &client{accountKey: &accountKey}
+	it := newAuditEventIterator(context.Background(), c, nil, fetch, AuditFilter{PageSize: 3})
+
+	// Act
+	var actual []*api.Audit
+	for {
+		event, err := it.Next()
+		if err == ErrIteratorDone {
+			break
+		}
+		assert.OK(t, err)
+		actual = append(actual, event)
+	}
+
+	// Assert
+	assert.Equal(t, actual, events)
+}
+
+func TestAuditEventIterator_Until(t *testing.T) {
+	// Arrange
+	accountKey, err := crypto.GenerateRSAPrivateKey(1024)
+	assert.OK(t, err)
+
+	loggedAt := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	events := make([]*api.Audit, 5)
+	for i := range events {
+		events[i] = &api.Audit{
+			EventID:  uuid.New(),
+			LoggedAt: loggedAt.Add(time.Duration(i) * time.Hour),
+			Subject:  api.AuditSubject{Type: api.AuditSubjectRepo},
+		}
+	}
+
+	var calls []*uuid.UUID
+	fetch := func(ctx context.Context, subjectTypes api.AuditSubjectTypeList, startingAfter *uuid.UUID, perPage int) ([]*api.Audit, error) {
+		calls = append(calls, startingAfter)
+
+		start := 0
+		if startingAfter != nil {
+			for i, event := range events {
+				if uuid.Equal(event.EventID, startingAfter) {
+					start = i + 1
+				}
+			}
+		}
+
+		end := start + perPage
+		if end > len(events) {
+			end = len(events)
+		}
+		return events[start:end], nil
+	}
+
+	c := &client{accountKey: &accountKey}
+	it := newAuditEventIterator(context.Background(), c, nil, fetch, AuditFilter{
+		Until:    events[2].LoggedAt,
+		PageSize: 2,
+	})
+
+	// Act
+	var actual []*api.Audit
+	for {
+		event, err := it.Next()
+		if err == ErrIteratorDone {
+			break
+		}
+		assert.OK(t, err)
+		actual = append(actual, event)
+	}
+	_, errDone := it.Next()
+
+	// Assert
+	assert.Equal(t, actual, events[:2])
+	assert.Equal(t, calls, []*uuid.UUID{nil, events[1].EventID})
+	assert.Equal(t, errDone, ErrIteratorDone)
+}
+
+func TestAuditEventIterator_Error(t *testing.T) {
+	// Arrange
+	calls := 0
+	fetch := func(ctx context.Context, subjectTypes api.AuditSubjectTypeList, startingAfter *uuid.UUID, perPage int) ([]*api.Audit, error) {
+		calls++
+		return nil, api.ErrForbidden
+	}
+
+	it := newAuditEventIterator(context.Background(), &client{}, nil, fetch, AuditFilter{})
+
+	// Act
+	_, err1 := it.Next()
+	_, err2 := it.Next()
+
+	// Assert
+	assert.Equal(t, err1, api.ErrForbidden)
+	assert.Equal(t, err2, api.ErrForbidden)
+	assert.Equal(t, calls, 1)
+}
+
+func TestAuditEventIterator_After(t *testing.T) {
+	// Arrange
+	after := uuid.New()
+
+	var calls []*uuid.UUID
+	fetch := func(ctx context.Context, subjectTypes api.AuditSubjectTypeList, startingAfter *uuid.UUID, perPage int) ([]*api.Audit, error) {
+		calls = append(calls, startingAfter)
+		return nil, nil
+	}
+
+	it := newAuditEventIterator(context.Background(), &client{}, nil, fetch, AuditFilter{After: after})
+
+	// Act
+	_, err := it.Next()
+
+	// Assert
+	assert.Equal(t, err, ErrIteratorDone)
+	assert.Equal(t, calls, []*uuid.UUID{after})
+}
+
+func TestAuditPathResolver(t *testing.T) {
+	// Arrange
+	root := &api.Dir{DirID: uuid.New(), Name: "repo"}
+	dir := &api.Dir{DirID: uuid.New(), Name: "dir", ParentID: root.DirID}
+
+	secret := &api.Secret{SecretID: uuid.New(), DirID: dir.DirID, Name: "db_password"}
+	rootSecret := &api.Secret{SecretID: uuid.New(), DirID: root.DirID, Name: "api_key"}
+	removedSecret := &api.Secret{SecretID: uuid.New(), DirID: uuid.New(), Name: "removed"}
+
+	tree := &api.Tree{
+		ParentPath: "dev1",
+		RootDir:    root,
+		Dirs: map[uuid.UUID]*api.Dir{
+			*dir.DirID: dir,
+		},
+		Secrets: map[uuid.UUID]*api.Secret{
+			*secret.SecretID:     secret,
+			*rootSecret.SecretID: rootSecret,
+		},
+	}
+
+	cases := map[string]struct {
+		subject  api.AuditSubject
+		fetchErr error
+		expected api.SecretPath
+		err      error
+	}{
+		"secret": {
+			subject:  api.AuditSubject{Type: api.AuditSubjectSecret, Secret: secret},
+			expected: "dev1/repo/dir/db_password",
+		},
+		"secret in root dir": {
+			subject:  api.AuditSubject{Type: api.AuditSubjectSecret, Secret: rootSecret},
+			expected: "dev1/repo/api_key",
+		},
+		"secret version": {
+			subject: api.AuditSubject{
+				Type:          api.AuditSubjectSecretVersion,
+				SecretVersion: &api.SecretVersion{Secret: secret, Version: 3},
+			},
+			expected: "dev1/repo/dir/db_password:3",
+		},
+		"secret not in tree": {
+			subject:  api.AuditSubject{Type: api.AuditSubjectSecret, Secret: removedSecret},
+			expected: "",
+		},
+		"deleted": {
+			subject:  api.AuditSubject{Type: api.AuditSubjectSecret, Deleted: true, Secret: secret},
+			expected: "",
+		},
+		"repo": {
+			subject:  api.AuditSubject{Type: api.AuditSubjectRepo, Repo: &api.Repo{Owner: "dev1", Name: "repo"}},
+			expected: "",
+		},
+		"tree forbidden": {
+			subject:  api.AuditSubject{Type: api.AuditSubjectSecret, Secret: secret},
+			fetchErr: api.ErrForbidden,
+			expected: "",
+		},
+		"tree not found": {
+			subject:  api.AuditSubject{Type: api.AuditSubjectSecret, Secret: secret},
+			fetchErr: api.ErrDirNotFound,
+			expected: "",
+		},
+		"tree error": {
+			subject:  api.AuditSubject{Type: api.AuditSubjectSecret, Secret: secret},
+			fetchErr: api.ErrTimeout,
+			expected: "",
+			err:      api.ErrTimeout,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			// Arrange
+			paths := newAuditPathResolver(&client{}, "dev1/repo")
+			paths.fetchTree = func(ctx context.Context) (*api.Tree, error) {
+				if tc.fetchErr != nil {
+					return nil, tc.fetchErr
+				}
+				return tree, nil
+			}
+			event := &api.Audit{Subject: tc.subject}
+
+			// Act
+			err := paths.resolve(context.Background(), event)
+
+			// Assert
+			assert.Equal(t, err, tc.err)
+			assert.Equal(t, event.Subject.SecretPath, tc.expected)
+		})
+	}
+}
+
+func TestSecretAuditPathResolver(t *testing.T) {
+	secret := &api.Secret{SecretID: uuid.New(), DirID: uuid.New(), Name: "db_password"}
+
+	cases := map[string]struct {
+		path     api.SecretPath
+		subject  api.AuditSubject
+		expected api.SecretPath
+	}{
+		"secret": {
+			path:     "dev1/repo/dir/db_password",
+			subject:  api.AuditSubject{Type: api.AuditSubjectSecret, Secret: secret},
+			expected: "dev1/repo/dir/db_password",
+		},
+		"secret version": {
+			path: "dev1/repo/dir/db_password",
+			subject: api.AuditSubject{
+				Type:          api.AuditSubjectSecretVersion,
+				SecretVersion: &api.SecretVersion{Secret: secret, Version: 3},
+			},
+			expected: "dev1/repo/dir/db_password:3",
+		},
+		"path with version": {
+			path: "dev1/repo/dir/db_password:2",
+			subject: api.AuditSubject{
+				Type:          api.AuditSubjectSecretVersion,
+				SecretVersion: &api.SecretVersion{Secret: secret, Version: 3},
+			},
+			expected: "dev1/repo/dir/db_password:3",
+		},
+		"deleted": {
+			path:     "dev1/repo/dir/db_password",
+			subject:  api.AuditSubject{Type: api.AuditSubjectSecret, Deleted: true, Secret: secret},
+			expected: "",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			// Arrange
+			paths := newSecretAuditPathResolver(tc.path)
+			event := &api.Audit{Subject: tc.subject}
+
+			// Act
+			err := paths.resolve(context.Background(), event)
+
+			// Assert
+			assert.OK(t, err)
+			assert.Equal(t, event.Subject.SecretPath, tc.expected)
+		})
+	}
+}
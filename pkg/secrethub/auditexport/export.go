@@ -0,0 +1,128 @@
+// Package auditexport exports the audit events of repositories to a SIEM or log pipeline,
+// as JSON Lines, CSV, ArcSight CEF or RFC 5424 syslog messages.
+//
+// An export can be incremental: a State remembers the ID of the last exported event of
+// every repository, so that a periodic job only exports the events logged since its
+// previous run.
+package auditexport
+
+import (
+	"context"
+	"io"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/errio"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+)
+
+// Errors
+var (
+	errExport = errio.Namespace("auditexport")
+
+	ErrUnknownFormat    = errExport.Code("unknown_format").ErrorPref("unknown format %s: use jsonl, csv, cef or syslog")
+	ErrUnknownLastEvent = errExport.Code("unknown_last_event").ErrorPref("the last exported event %s of %s does not exist: remove it from the state to export all events again")
+)
+
+// Format is the format in which events are exported.
+type Format string
+
+// Supported formats.
+const (
+	// FormatJSONL writes every event as a JSON object on its own line.
+	FormatJSONL Format = "jsonl"
+	// FormatCSV writes every event as a row of comma separated values, after a header row.
+	FormatCSV Format = "csv"
+	// FormatCEF writes every event as an ArcSight Common Event Format message on its own line.
+	FormatCEF Format = "cef"
+	// FormatSyslog writes every event as an RFC 5424 syslog message on its own line,
+	// with the CEF message of the event as its message.
+	FormatSyslog Format = "syslog"
+)
+
+// Options configure a Writer.
+type Options struct {
+	// Hostname is the HOSTNAME of syslog messages. Defaults to the hostname of the machine.
+	Hostname string
+}
+
+// Writer writes audit events in an export format.
+type Writer interface {
+	// Write writes the event. The subject of the event must have been decrypted.
+	Write(event *api.Audit) error
+	// Flush writes any buffered data to the underlying writer.
+	Flush() error
+}
+
+// NewWriter returns a Writer that writes events to w in the given format.
+func NewWriter(w io.Writer, format Format, options Options) (Writer, error) {
+	switch format {
+	case FormatJSONL:
+		return newJSONLWriter(w), nil
+	case FormatCSV:
+		return newCSVWriter(w), nil
+	case FormatCEF:
+		return newCEFWriter(w), nil
+	case FormatSyslog:
+		return newSyslogWriter(w, options.Hostname), nil
+	default:
+		return nil, ErrUnknownFormat(format)
+	}
+}
+
+// ExportRepo writes the audit events of the repository at the given path that match the
+// filter to the writer, oldest first, and returns the number of exported events. When a
+// state is given, only the events logged after the last exported event of the repository
+// are exported. When that event does not exist, ErrUnknownLastEvent is returned instead of
+// treating the export as up to date. The state is only updated when all events have been
+// written and flushed, so events of a failed export are exported again by the next export.
+// The caller is responsible for saving the state. Set filter.ResolvePaths to identify
+// secrets by their full paths instead of their names.
+func ExportRepo(client secrethub.Client, path string, filter secrethub.AuditFilter, w Writer, state *State) (int, error) {
+	return ExportRepoContext(context.Background(), client, path, filter, w, state)
+}
+
+// ExportRepoContext is the same as ExportRepo, but uses the given context for all requests.
+func ExportRepoContext(ctx context.Context, client secrethub.Client, path string, filter secrethub.AuditFilter, w Writer, state *State) (int, error) {
+	repoPath, err := api.NewRepoPath(path)
+	if err != nil {
+		return 0, errio.Error(err)
+	}
+
+	if state != nil {
+		filter.After, err = state.LastEventID(repoPath.Value())
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	exported := 0
+	var last *api.Audit
+	it := client.Repos().EventsContext(ctx, repoPath.Value(), filter)
+	for {
+		event, err := it.Next()
+		if err == secrethub.ErrIteratorDone {
+			break
+		} else if err == api.ErrNotFound && filter.After != nil {
+			return exported, ErrUnknownLastEvent(filter.After, repoPath.Value())
+		} else if err != nil {
+			return exported, errio.Error(err)
+		}
+
+		err = w.Write(event)
+		if err != nil {
+			return exported, err
+		}
+		exported++
+		last = event
+	}
+
+	err = w.Flush()
+	if err != nil {
+		return exported, err
+	}
+
+	if state != nil && last != nil {
+		state.SetLastEventID(repoPath.Value(), last.EventID)
+	}
+	return exported, nil
+}
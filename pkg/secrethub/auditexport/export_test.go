@@ -0,0 +1,171 @@
+package auditexport
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/api/uuid"
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/internals/errio"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/memclient"
+)
+
+// newRepo returns a client of a new store, signed up as dev1, with the repository dev1/repo.
+func newRepo(t *testing.T) *memclient.Client {
+	client, err := memclient.New("dev1")
+	assert.OK(t, err)
+
+	_, err = client.Repos().Create("dev1/repo")
+	assert.OK(t, err)
+
+	return client
+}
+
+// decodeRecords decodes the records in a JSON Lines export.
+func decodeRecords(t *testing.T, data []byte) []Record {
+	var records []Record
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		if line == "" {
+			continue
+		}
+
+		var record Record
+		err := json.Unmarshal([]byte(line), &record)
+		assert.OK(t, err)
+		records = append(records, record)
+	}
+	return records
+}
+
+func TestExportRepo_Incremental(t *testing.T) {
+	// Arrange
+	client := newRepo(t)
+
+	dir, err := ioutil.TempDir("", "secrethub-auditexport")
+	assert.OK(t, err)
+	defer os.RemoveAll(dir)
+	statePath := filepath.Join(dir, "state.json")
+
+	_, err = client.Secrets().Write("dev1/repo/secret", []byte("value"))
+	assert.OK(t, err)
+
+	export := func() []Record {
+		state, err := LoadState(statePath)
+		assert.OK(t, err)
+
+		buf := &bytes.Buffer{}
+		w, err := NewWriter(buf, FormatJSONL, Options{})
+		assert.OK(t, err)
+
+		n, err := ExportRepo(client, "dev1/repo", secrethub.AuditFilter{ResolvePaths: true}, w, state)
+		assert.OK(t, err)
+
+		err = state.Save(statePath)
+		assert.OK(t, err)
+
+		records := decodeRecords(t, buf.Bytes())
+		assert.Equal(t, n, len(records))
+		return records
+	}
+
+	// Act
+	first := export()
+
+	_, err = client.Secrets().Versions().GetWithData("dev1/repo/secret")
+	assert.OK(t, err)
+
+	second := export()
+	third := export()
+
+	// Assert
+	assert.Equal(t, len(first) > 0, true)
+	assert.Equal(t, first[0].SubjectType, api.AuditSubjectRepo)
+	assert.Equal(t, len(second), 1)
+	assert.Equal(t, second[0].Action, string(api.AuditActionRead))
+	assert.Equal(t, second[0].Subject, "dev1/repo/secret:1")
+	assert.Equal(t, second[0].Actor, "dev1")
+	assert.Equal(t, len(third), 0)
+}
+
+func TestExportRepo_UnknownLastEvent(t *testing.T) {
+	// Arrange
+	client := newRepo(t)
+
+	unknown := uuid.New()
+	state := &State{}
+	state.SetLastEventID("dev1/repo", unknown)
+
+	buf := &bytes.Buffer{}
+	w, err := NewWriter(buf, FormatJSONL, Options{})
+	assert.OK(t, err)
+
+	// Act
+	n, err := ExportRepo(client, "dev1/repo", secrethub.AuditFilter{}, w, state)
+
+	// Assert
+	assert.Equal(t, err, ErrUnknownLastEvent(unknown, "dev1/repo"))
+	assert.Equal(t, n, 0)
+	assert.Equal(t, buf.Len(), 0)
+	last, err := state.LastEventID("dev1/repo")
+	assert.OK(t, err)
+	assert.Equal(t, last, unknown)
+}
+
+func TestExportRepo_InvalidState(t *testing.T) {
+	// Arrange
+	client := newRepo(t)
+
+	dir, err := ioutil.TempDir("", "secrethub-auditexport")
+	assert.OK(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "state.json")
+	err = ioutil.WriteFile(path, []byte(`{"last_event_ids": {"dev1/repo": "corrupt"}}`), 0600)
+	assert.OK(t, err)
+
+	state, err := LoadState(path)
+	assert.OK(t, err)
+
+	buf := &bytes.Buffer{}
+	w, err := NewWriter(buf, FormatJSONL, Options{})
+	assert.OK(t, err)
+
+	// Act
+	n, err := ExportRepo(client, "dev1/repo", secrethub.AuditFilter{}, w, state)
+
+	// Assert
+	assert.Equal(t, err != nil, true)
+	assert.Equal(t, err.(errio.PublicError).Code, "invalid_state")
+	assert.Equal(t, n, 0)
+	assert.Equal(t, buf.Len(), 0)
+	assert.Equal(t, state.LastEventIDs["dev1/repo"], "corrupt")
+}
+
+func TestLoadState(t *testing.T) {
+	// Arrange
+	dir, err := ioutil.TempDir("", "secrethub-auditexport")
+	assert.OK(t, err)
+	defer os.RemoveAll(dir)
+
+	invalidPath := filepath.Join(dir, "invalid.json")
+	err = ioutil.WriteFile(invalidPath, []byte("{"), 0600)
+	assert.OK(t, err)
+
+	// Act
+	missing, errMissing := LoadState(filepath.Join(dir, "missing.json"))
+	_, errInvalid := LoadState(invalidPath)
+
+	// Assert
+	assert.OK(t, errMissing)
+	last, err := missing.LastEventID("dev1/repo")
+	assert.OK(t, err)
+	assert.Equal(t, last == nil, true)
+	assert.Equal(t, errInvalid != nil, true)
+}
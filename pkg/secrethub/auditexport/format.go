@@ -0,0 +1,315 @@
+package auditexport
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/api/uuid"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+)
+
+// Record is the flat representation of an audit event that is exported.
+type Record struct {
+	EventID     string    `json:"event_id"`
+	LoggedAt    time.Time `json:"logged_at"`
+	Action      string    `json:"action"`
+	ActorType   string    `json:"actor_type"`
+	Actor       string    `json:"actor"`
+	SubjectType string    `json:"subject_type"`
+	Subject     string    `json:"subject"`
+	Repo        string    `json:"repo"`
+	IPAddress   string    `json:"ip_address"`
+}
+
+// csvHeader contains the names of the columns of a CSV export, in the order of the fields of a Record.
+var csvHeader = []string{"event_id", "logged_at", "action", "actor_type", "actor", "subject_type", "subject", "repo", "ip_address"}
+
+// NewRecord returns the record of a decrypted audit event. Accounts are identified by their
+// usernames or service IDs, repositories by their paths, secrets by their full paths and secret
+// versions by the full paths of their secrets followed by a colon and the version number.
+// Secrets of which the path is unknown are identified by their names instead.
+// Subjects and actors that have been deleted, or that have no name, are identified by their IDs.
+func NewRecord(event *api.Audit) Record {
+	return Record{
+		EventID:     idString(event.EventID),
+		LoggedAt:    event.LoggedAt.UTC(),
+		Action:      string(event.Action),
+		ActorType:   event.Actor.Type,
+		Actor:       actorName(event.Actor),
+		SubjectType: string(event.Subject.Type),
+		Subject:     subjectName(event.Subject),
+		Repo:        repoName(event.Repo),
+		IPAddress:   event.IPAddress,
+	}
+}
+
+// values returns the fields of the record, in the order of the CSV header.
+func (r Record) values() []string {
+	return []string{
+		r.EventID,
+		r.LoggedAt.Format(time.RFC3339Nano),
+		r.Action,
+		r.ActorType,
+		r.Actor,
+		r.SubjectType,
+		r.Subject,
+		r.Repo,
+		r.IPAddress,
+	}
+}
+
+func actorName(actor api.AuditActor) string {
+	switch {
+	case actor.User != nil:
+		return actor.User.Username
+	case actor.Service != nil:
+		return actor.Service.ServiceID
+	}
+	return idString(actor.ActorID)
+}
+
+func subjectName(subject api.AuditSubject) string {
+	switch {
+	case subject.User != nil:
+		return subject.User.Username
+	case subject.Service != nil:
+		return subject.Service.ServiceID
+	case subject.Repo != nil:
+		return repoName(*subject.Repo)
+	case subject.SecretPath != "":
+		return subject.SecretPath.String()
+	case subject.SecretVersion != nil && subject.SecretVersion.Secret != nil:
+		return subject.SecretVersion.Secret.Name + ":" + strconv.Itoa(subject.SecretVersion.Version)
+	case subject.Secret != nil:
+		return subject.Secret.Name
+	}
+	return idString(subject.SubjectID)
+}
+
+func repoName(repo api.Repo) string {
+	if repo.Owner == "" && repo.Name == "" {
+		return ""
+	}
+	return repo.Owner + "/" + repo.Name
+}
+
+func idString(id *uuid.UUID) string {
+	if id == nil {
+		return ""
+	}
+	return id.ToString()
+}
+
+// jsonlWriter writes every record as a JSON object on its own line.
+type jsonlWriter struct {
+	w       *bufio.Writer
+	encoder *json.Encoder
+}
+
+func newJSONLWriter(w io.Writer) *jsonlWriter {
+	buf := bufio.NewWriter(w)
+	return &jsonlWriter{
+		w:       buf,
+		encoder: json.NewEncoder(buf),
+	}
+}
+
+// Write writes the record of the event as a line of JSON.
+func (w *jsonlWriter) Write(event *api.Audit) error {
+	return w.encoder.Encode(NewRecord(event))
+}
+
+// Flush writes the buffered lines.
+func (w *jsonlWriter) Flush() error {
+	return w.w.Flush()
+}
+
+// csvWriter writes every record as a CSV row, after a header row.
+type csvWriter struct {
+	w             *csv.Writer
+	headerWritten bool
+}
+
+func newCSVWriter(w io.Writer) *csvWriter {
+	return &csvWriter{
+		w: csv.NewWriter(w),
+	}
+}
+
+// Write writes the record of the event as a row, preceded by
+// the header row when it is the first event that is written.
+func (w *csvWriter) Write(event *api.Audit) error {
+	if !w.headerWritten {
+		err := w.w.Write(csvHeader)
+		if err != nil {
+			return err
+		}
+		w.headerWritten = true
+	}
+	return w.w.Write(NewRecord(event).values())
+}
+
+// Flush writes the buffered rows.
+func (w *csvWriter) Flush() error {
+	w.w.Flush()
+	return w.w.Error()
+}
+
+var (
+	cefHeaderEscaper    = strings.NewReplacer(`\`, `\\`, `|`, `\|`, "\n", " ", "\r", " ")
+	cefExtensionEscaper = strings.NewReplacer(`\`, `\\`, `=`, `\=`, "\n", `\n`, "\r", `\r`)
+)
+
+// cefSeverity returns the CEF severity, from 0 to 10, of an event with the given action.
+func cefSeverity(action string) int {
+	switch api.AuditAction(action) {
+	case api.AuditActionDelete:
+		return 7
+	case api.AuditActionCreate, api.AuditActionUpdate:
+		return 5
+	default:
+		return 3
+	}
+}
+
+// formatCEF returns the record as an ArcSight Common Event Format message. The signature
+// of the event is its subject type and action, e.g. secret_version.read. The subject type,
+// subject and repository are custom string extensions.
+func formatCEF(r Record) string {
+	header := []string{
+		"CEF:0",
+		"SecretHub",
+		"SecretHub",
+		secrethub.ClientVersion,
+		r.SubjectType + "." + r.Action,
+		r.Action + " " + r.SubjectType,
+		strconv.Itoa(cefSeverity(r.Action)),
+	}
+	for i := 1; i < len(header); i++ {
+		header[i] = cefHeaderEscaper.Replace(header[i])
+	}
+
+	extensions := [][2]string{
+		{"rt", strconv.FormatInt(r.LoggedAt.UnixNano()/int64(time.Millisecond), 10)},
+		{"externalId", r.EventID},
+		{"act", r.Action},
+		{"suser", r.Actor},
+		{"src", r.IPAddress},
+		{"cs1Label", "subjectType"},
+		{"cs1", r.SubjectType},
+		{"cs2Label", "subject"},
+		{"cs2", r.Subject},
+		{"cs3Label", "repo"},
+		{"cs3", r.Repo},
+	}
+
+	var extension []string
+	for _, e := range extensions {
+		if e[1] != "" {
+			extension = append(extension, e[0]+"="+cefExtensionEscaper.Replace(e[1]))
+		}
+	}
+
+	return strings.Join(header, "|") + "|" + strings.Join(extension, " ")
+}
+
+// cefWriter writes every record as a CEF message on its own line.
+type cefWriter struct {
+	w *bufio.Writer
+}
+
+func newCEFWriter(w io.Writer) *cefWriter {
+	return &cefWriter{
+		w: bufio.NewWriter(w),
+	}
+}
+
+// Write writes the record of the event as a line containing a CEF message.
+func (w *cefWriter) Write(event *api.Audit) error {
+	_, err := fmt.Fprintln(w.w, formatCEF(NewRecord(event)))
+	return err
+}
+
+// Flush writes the buffered lines.
+func (w *cefWriter) Flush() error {
+	return w.w.Flush()
+}
+
+const (
+	// syslogPriority is the PRI of syslog messages: facility 13 (log audit) and severity 6 (informational).
+	syslogPriority = 13*8 + 6
+	// syslogAppName is the APP-NAME of syslog messages.
+	syslogAppName = "secrethub"
+	// syslogNil is the NILVALUE of syslog header fields.
+	syslogNil = "-"
+)
+
+// syslogField returns the value as a syslog header field, which consists
+// of at most maxLength printable ASCII characters and cannot be empty.
+func syslogField(value string, maxLength int) string {
+	field := strings.Map(func(r rune) rune {
+		if r < 33 || r > 126 {
+			return '_'
+		}
+		return r
+	}, value)
+
+	if len(field) > maxLength {
+		field = field[:maxLength]
+	}
+	if field == "" {
+		return syslogNil
+	}
+	return field
+}
+
+// syslogWriter writes every record as an RFC 5424 syslog message on its own line.
+type syslogWriter struct {
+	w        *bufio.Writer
+	hostname string
+}
+
+func newSyslogWriter(w io.Writer, hostname string) *syslogWriter {
+	if hostname == "" {
+		hostname, _ = os.Hostname()
+	}
+
+	return &syslogWriter{
+		w:        bufio.NewWriter(w),
+		hostname: syslogField(hostname, 255),
+	}
+}
+
+// formatSyslog returns the record as an RFC 5424 syslog message, with the
+// action as its MSGID and the CEF message of the record as its MSG.
+func formatSyslog(r Record, hostname string) string {
+	return fmt.Sprintf("<%d>1 %s %s %s %s %s %s %s",
+		syslogPriority,
+		r.LoggedAt.Format("2006-01-02T15:04:05.000000Z07:00"),
+		hostname,
+		syslogAppName,
+		syslogNil,
+		syslogField(r.Action, 32),
+		syslogNil,
+		formatCEF(r),
+	)
+}
+
+// Write writes the record of the event as a line containing a syslog message.
+func (w *syslogWriter) Write(event *api.Audit) error {
+	_, err := fmt.Fprintln(w.w, formatSyslog(NewRecord(event), w.hostname))
+	return err
+}
+
+// Flush writes the buffered lines.
+func (w *syslogWriter) Flush() error {
+	return w.w.Flush()
+}
@@ -0,0 +1,116 @@
+package auditexport
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/api/uuid"
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+)
+
+func TestNewWriter(t *testing.T) {
+	eventID, err := uuid.FromString("9d6a1d4e-8f1b-4b4e-9c39-3a8c1e0f2b7d")
+	assert.OK(t, err)
+
+	event := &api.Audit{
+		EventID:   eventID,
+		Action:    api.AuditActionRead,
+		IPAddress: "10.0.0.1",
+		LoggedAt:  time.Date(2019, 1, 2, 3, 4, 5, 6000000, time.UTC),
+		Repo:      api.Repo{Owner: "dev1", Name: "repo"},
+		Actor: api.AuditActor{
+			Type: "user",
+			User: &api.User{Username: "dev1"},
+		},
+		Subject: api.AuditSubject{
+			Type: api.AuditSubjectSecretVersion,
+			SecretVersion: &api.SecretVersion{
+				Secret:  &api.Secret{Name: "db=pass|word"},
+				Version: 2,
+			},
+		},
+	}
+
+	cef := "CEF:0|SecretHub|SecretHub|" + secrethub.ClientVersion + "|secret_version.read|read secret_version|3|" +
+		"rt=1546398245006 externalId=9d6a1d4e-8f1b-4b4e-9c39-3a8c1e0f2b7d act=read suser=dev1 src=10.0.0.1 " +
+		`cs1Label=subjectType cs1=secret_version cs2Label=subject cs2=db\=pass|word:2 cs3Label=repo cs3=dev1/repo`
+
+	cases := map[string]struct {
+		format   Format
+		expected string
+		err      error
+	}{
+		"jsonl": {
+			format: FormatJSONL,
+			expected: `{"event_id":"9d6a1d4e-8f1b-4b4e-9c39-3a8c1e0f2b7d","logged_at":"2019-01-02T03:04:05.006Z","action":"read",` +
+				`"actor_type":"user","actor":"dev1","subject_type":"secret_version","subject":"db=pass|word:2","repo":"dev1/repo","ip_address":"10.0.0.1"}` + "\n",
+		},
+		"csv": {
+			format: FormatCSV,
+			expected: "event_id,logged_at,action,actor_type,actor,subject_type,subject,repo,ip_address\n" +
+				"9d6a1d4e-8f1b-4b4e-9c39-3a8c1e0f2b7d,2019-01-02T03:04:05.006Z,read,user,dev1,secret_version,db=pass|word:2,dev1/repo,10.0.0.1\n",
+		},
+		"cef": {
+			format:   FormatCEF,
+			expected: cef + "\n",
+		},
+		"syslog": {
+			format:   FormatSyslog,
+			expected: "<110>1 2019-01-02T03:04:05.006000Z host.example.com secrethub - read - " + cef + "\n",
+		},
+		"unknown": {
+			format: "xml",
+			err:    ErrUnknownFormat("xml"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			// Arrange
+			buf := &bytes.Buffer{}
+
+			// Act
+			w, err := NewWriter(buf, tc.format, Options{Hostname: "host.example.com"})
+			if err == nil {
+				err = w.Write(event)
+				assert.OK(t, err)
+				err = w.Flush()
+			}
+
+			// Assert
+			assert.Equal(t, err, tc.err)
+			assert.Equal(t, buf.String(), tc.expected)
+		})
+	}
+}
+
+func TestNewRecord_Deleted(t *testing.T) {
+	// Arrange
+	actorID := uuid.New()
+	subjectID := uuid.New()
+	event := &api.Audit{
+		Action: api.AuditActionDelete,
+		Actor: api.AuditActor{
+			ActorID: actorID,
+			Deleted: true,
+			Type:    api.AuditSubjectAccount,
+		},
+		Subject: api.AuditSubject{
+			SubjectID: subjectID,
+			Deleted:   true,
+			Type:      api.AuditSubjectSecret,
+		},
+	}
+
+	// Act
+	record := NewRecord(event)
+
+	// Assert
+	assert.Equal(t, record.Actor, actorID.ToString())
+	assert.Equal(t, record.Subject, subjectID.ToString())
+	assert.Equal(t, record.Repo, "")
+	assert.Equal(t, record.EventID, "")
+}
@@ -0,0 +1,110 @@
+package auditexport
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/secrethub/secrethub-go/internals/api/uuid"
+	"github.com/secrethub/secrethub-go/internals/errio"
+)
+
+// Errors
+var (
+	ErrInvalidState = errExport.Code("invalid_state").ErrorPref("cannot read state file %s: %s")
+)
+
+// State remembers the ID of the last exported event of every repository,
+// so that incremental exports only export the events logged since.
+type State struct {
+	// LastEventIDs contains the IDs of the last exported events by lowercase repository paths.
+	LastEventIDs map[string]string `json:"last_event_ids"`
+
+	// path is the file the state has been loaded from, used in error messages.
+	path string
+}
+
+// LoadState reads the state from the file at the given path. When the file
+// does not exist, an empty state is returned, so that the first incremental
+// export exports all events.
+func LoadState(path string) (*State, error) {
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return &State{
+			LastEventIDs: make(map[string]string),
+			path:         path,
+		}, nil
+	} else if err != nil {
+		return nil, errio.Error(err)
+	}
+
+	state := &State{path: path}
+	err = json.Unmarshal(data, state)
+	if err != nil {
+		return nil, ErrInvalidState(path, err)
+	}
+	if state.LastEventIDs == nil {
+		state.LastEventIDs = make(map[string]string)
+	}
+	return state, nil
+}
+
+// Save writes the state to the file at the given path. The file is replaced
+// atomically, so the state is never lost when saving it is interrupted.
+func (s *State) Save(path string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return errio.Error(err)
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".tmp-")
+	if err != nil {
+		return errio.Error(err)
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = tmp.Write(data)
+	if err != nil {
+		_ = tmp.Close()
+		return errio.Error(err)
+	}
+
+	err = tmp.Close()
+	if err != nil {
+		return errio.Error(err)
+	}
+
+	err = os.Rename(tmp.Name(), path)
+	if err != nil {
+		return errio.Error(err)
+	}
+	return nil
+}
+
+// LastEventID returns the ID of the last exported event of the repository at the
+// given path, or nil when no events of the repository have been exported yet.
+// ErrInvalidState is returned when the recorded ID is not a valid UUID, so that
+// a corrupt state does not silently cause all events to be exported again.
+func (s *State) LastEventID(repoPath string) (*uuid.UUID, error) {
+	value := s.LastEventIDs[strings.ToLower(repoPath)]
+	if value == "" {
+		return nil, nil
+	}
+
+	id, err := uuid.FromString(value)
+	if err != nil {
+		return nil, ErrInvalidState(s.path, fmt.Sprintf("invalid last event ID of %s: %s", repoPath, err))
+	}
+	return id, nil
+}
+
+// SetLastEventID records the ID of the last exported event of the repository at the given path.
+func (s *State) SetLastEventID(repoPath string, id *uuid.UUID) {
+	if s.LastEventIDs == nil {
+		s.LastEventIDs = make(map[string]string)
+	}
+	s.LastEventIDs[strings.ToLower(repoPath)] = id.ToString()
+}
@@ -0,0 +1,298 @@
+package secrethub
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"sort"
+	"time"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/crypto"
+	"github.com/secrethub/secrethub-go/internals/errio"
+)
+
+const (
+	// BackupFormat identifies a repository backup.
+	BackupFormat = "secrethub-repo-backup"
+	// BackupFormatVersion is the version of the format of the backups that are written.
+	// Restore supports all versions up to and including this version.
+	BackupFormatVersion = 1
+)
+
+// Errors
+var (
+	ErrInvalidBackup            = errClient.Code("invalid_backup").Error("the data is not a repository backup")
+	ErrUnsupportedBackupVersion = errClient.Code("unsupported_backup_version").ErrorPref("backups of format version %d are not supported; upgrade the client to restore this backup")
+	ErrBackupDecryptionFailed   = errClient.Code("backup_decryption_failed").Error("cannot decrypt the backup: the passphrase is incorrect or the backup is corrupted")
+)
+
+// backupEnvelope is the encrypted form of a backup, as it is written.
+// It contains the parameters needed to derive the key from the passphrase.
+type backupEnvelope struct {
+	Format  string      `json:"format"`
+	Version int         `json:"version"`
+	KeyLen  int         `json:"key_len"`
+	Salt    crypto.Salt `json:"salt"`
+	N       int         `json:"n"`
+	R       int         `json:"r"`
+	P       int         `json:"p"`
+	Nonce   []byte      `json:"nonce"`
+	Data    []byte      `json:"data"`
+}
+
+// backupArchive contains the directories and secrets of a repository.
+// All paths are relative to the repository.
+type backupArchive struct {
+	Repo      string         `json:"repo"`
+	CreatedAt time.Time      `json:"created_at"`
+	Dirs      []string       `json:"dirs"`
+	Secrets   []backupSecret `json:"secrets"`
+}
+
+type backupSecret struct {
+	Path     string          `json:"path"`
+	Versions []backupVersion `json:"versions"`
+}
+
+type backupVersion struct {
+	Version   int       `json:"version"`
+	Data      []byte    `json:"data"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// RestoreResult describes what a restore has written.
+type RestoreResult struct {
+	// RepoCreated is true when the repository did not exist and has been created.
+	RepoCreated bool
+	// Dirs is the number of directories that have been created.
+	Dirs int
+	// Secrets is the number of secrets of which versions have been written.
+	Secrets int
+	// Versions is the number of secret versions that have been written.
+	Versions int
+}
+
+// Backup writes all versions of all secrets in the repository at the given path
+// to w, encrypted with a key derived from the passphrase.
+func (s repoService) Backup(path string, w io.Writer, passphrase []byte) error {
+	return s.BackupContext(context.Background(), path, w, passphrase)
+}
+
+// BackupContext is the same as Backup, but uses the given context for all requests.
+func (s repoService) BackupContext(ctx context.Context, path string, w io.Writer, passphrase []byte) error {
+	return backupRepo(ctx, clientAdapter{client: s.client}, path, w, passphrase)
+}
+
+// Restore recreates the directories and secrets of the backup in r, which is
+// encrypted with a key derived from the passphrase, in the repository at the given path.
+func (s repoService) Restore(path string, r io.Reader, passphrase []byte) (*RestoreResult, error) {
+	return s.RestoreContext(context.Background(), path, r, passphrase)
+}
+
+// RestoreContext is the same as Restore, but uses the given context for all requests.
+func (s repoService) RestoreContext(ctx context.Context, path string, r io.Reader, passphrase []byte) (*RestoreResult, error) {
+	return restoreRepo(ctx, clientAdapter{client: s.client}, path, r, passphrase)
+}
+
+// backupRepo writes all versions of all secrets in the repository at the given path
+// to w, using the client to read them. The backup is encrypted with AES-GCM using a
+// key that is derived from the passphrase with scrypt.
+func backupRepo(ctx context.Context, client Client, path string, w io.Writer, passphrase []byte) error {
+	repoPath, err := api.NewRepoPath(path)
+	if err != nil {
+		return errio.Error(err)
+	}
+
+	tree, err := client.Dirs().GetTreeContext(ctx, repoPath.Value(), -1, false)
+	if err != nil {
+		return errio.Error(err)
+	}
+
+	archive := backupArchive{
+		Repo:      repoPath.Value(),
+		CreatedAt: time.Now().UTC(),
+		Dirs:      []string{},
+		Secrets:   []backupSecret{},
+	}
+
+	archive.Dirs = append(archive.Dirs, tree.DirPaths()...)
+
+	for _, secretPath := range tree.SecretPaths() {
+		versions, err := client.Secrets().Versions().ListWithDataContext(ctx, repoPath.Value()+"/"+secretPath)
+		if err != nil {
+			return errio.Error(err)
+		}
+
+		sort.Slice(versions, func(i, j int) bool {
+			return versions[i].Version < versions[j].Version
+		})
+
+		backup := backupSecret{
+			Path:     secretPath,
+			Versions: make([]backupVersion, len(versions)),
+		}
+		for i, version := range versions {
+			backup.Versions[i] = backupVersion{
+				Version:   version.Version,
+				Data:      version.Data,
+				CreatedAt: version.CreatedAt,
+			}
+		}
+		archive.Secrets = append(archive.Secrets, backup)
+	}
+
+	payload, err := json.Marshal(archive)
+	if err != nil {
+		return errio.Error(err)
+	}
+
+	key, err := crypto.GenerateScryptKeyForOperation(passphrase, crypto.SaltOperationBackupEncryption)
+	if err != nil {
+		return errio.Error(err)
+	}
+
+	ciphertext, err := key.Encrypt(payload, crypto.SaltOperationBackupEncryption)
+	if err != nil {
+		return errio.Error(err)
+	}
+
+	return json.NewEncoder(w).Encode(backupEnvelope{
+		Format:  BackupFormat,
+		Version: BackupFormatVersion,
+		KeyLen:  key.KeyLen,
+		Salt:    key.Salt,
+		N:       key.N,
+		R:       key.R,
+		P:       key.P,
+		Nonce:   ciphertext.Nonce,
+		Data:    ciphertext.Data,
+	})
+}
+
+// restoreRepo recreates the directories and secrets of the backup in r in the repository
+// at the given path, using the client to write them. The repository is created when it does
+// not exist. Directories that already exist are reused and the versions of every secret are
+// written in order as new versions, so version numbers can differ from the backup when the
+// secret already exists or versions were deleted before the backup was made.
+func restoreRepo(ctx context.Context, client Client, path string, r io.Reader, passphrase []byte) (*RestoreResult, error) {
+	repoPath, err := api.NewRepoPath(path)
+	if err != nil {
+		return nil, errio.Error(err)
+	}
+
+	archive, err := decryptBackup(r, passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, dir := range archive.Dirs {
+		err = api.ValidateDirPath(repoPath.Value() + "/" + dir)
+		if err != nil {
+			return nil, ErrInvalidBackup
+		}
+	}
+	for _, secret := range archive.Secrets {
+		err = api.ValidateSecretPath(repoPath.Value() + "/" + secret.Path)
+		if err != nil {
+			return nil, ErrInvalidBackup
+		}
+	}
+
+	result := &RestoreResult{}
+
+	existing := make(map[string]bool)
+	_, err = client.Repos().GetContext(ctx, repoPath.Value())
+	if err == api.ErrRepoNotFound {
+		_, err = client.Repos().CreateContext(ctx, repoPath.Value())
+		if err != nil {
+			return nil, errio.Error(err)
+		}
+		result.RepoCreated = true
+	} else if err != nil {
+		return nil, errio.Error(err)
+	} else {
+		tree, err := client.Dirs().GetTreeContext(ctx, repoPath.Value(), -1, false)
+		if err != nil {
+			return nil, errio.Error(err)
+		}
+
+		for _, dirPath := range tree.DirPaths() {
+			existing[repoPath.Value()+"/"+dirPath] = true
+		}
+	}
+
+	for _, dir := range archive.Dirs {
+		dirPath := repoPath.Value() + "/" + dir
+		if existing[dirPath] {
+			continue
+		}
+
+		_, err = client.Dirs().CreateContext(ctx, dirPath)
+		if err != nil {
+			return result, errio.Error(err)
+		}
+		result.Dirs++
+	}
+
+	for _, secret := range archive.Secrets {
+		for _, version := range secret.Versions {
+			_, err = client.Secrets().WriteContext(ctx, repoPath.Value()+"/"+secret.Path, version.Data)
+			if err != nil {
+				return result, errio.Error(err)
+			}
+			result.Versions++
+		}
+		result.Secrets++
+	}
+
+	return result, nil
+}
+
+// decryptBackup reads the encrypted backup from r and decrypts it with a key derived from the passphrase.
+func decryptBackup(r io.Reader, passphrase []byte) (*backupArchive, error) {
+	raw, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, errio.Error(err)
+	}
+
+	var envelope backupEnvelope
+	err = json.Unmarshal(raw, &envelope)
+	if err != nil || envelope.Format != BackupFormat {
+		return nil, ErrInvalidBackup
+	}
+
+	if envelope.Version < 1 || envelope.Version > BackupFormatVersion {
+		return nil, ErrUnsupportedBackupVersion(envelope.Version)
+	}
+
+	// backupRepo derives keys with the default parameters. Larger values are refused,
+	// so that a crafted backup cannot make the key derivation use unbounded memory or time.
+	if envelope.N > crypto.DefaultScryptN || envelope.R > crypto.DefaultScryptR ||
+		envelope.P > crypto.DefaultScryptP || envelope.KeyLen > crypto.DefaultScryptKeyLength {
+		return nil, ErrInvalidBackup
+	}
+
+	key, err := crypto.DeriveScryptKey(passphrase, envelope.Salt, envelope.N, envelope.R, envelope.P, envelope.KeyLen)
+	if err == crypto.ErrEmptyPassphrase {
+		return nil, err
+	} else if err != nil {
+		return nil, ErrInvalidBackup
+	}
+
+	payload, err := key.Decrypt(crypto.CiphertextAES{
+		Data:  envelope.Data,
+		Nonce: envelope.Nonce,
+	}, crypto.SaltOperationBackupEncryption)
+	if err != nil {
+		return nil, ErrBackupDecryptionFailed
+	}
+
+	var archive backupArchive
+	err = json.Unmarshal(payload, &archive)
+	if err != nil {
+		return nil, ErrInvalidBackup
+	}
+	return &archive, nil
+}
@@ -0,0 +1,69 @@
+package secrethub
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/internals/crypto"
+)
+
+func TestDecryptBackup_ScryptParameters(t *testing.T) {
+	cases := map[string]struct {
+		n      int
+		r      int
+		p      int
+		keyLen int
+	}{
+		"N too large": {
+			n:      1 << 30,
+			r:      crypto.DefaultScryptR,
+			p:      crypto.DefaultScryptP,
+			keyLen: crypto.DefaultScryptKeyLength,
+		},
+		"r too large": {
+			n:      crypto.DefaultScryptN,
+			r:      1 << 20,
+			p:      crypto.DefaultScryptP,
+			keyLen: crypto.DefaultScryptKeyLength,
+		},
+		"p too large": {
+			n:      crypto.DefaultScryptN,
+			r:      crypto.DefaultScryptR,
+			p:      1 << 20,
+			keyLen: crypto.DefaultScryptKeyLength,
+		},
+		"key length too large": {
+			n:      crypto.DefaultScryptN,
+			r:      crypto.DefaultScryptR,
+			p:      crypto.DefaultScryptP,
+			keyLen: 1 << 30,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			// Arrange
+			key, err := crypto.GenerateScryptKeyForOperation([]byte("passphrase"), crypto.SaltOperationBackupEncryption)
+			assert.OK(t, err)
+
+			envelope, err := json.Marshal(backupEnvelope{
+				Format:  BackupFormat,
+				Version: BackupFormatVersion,
+				KeyLen:  tc.keyLen,
+				Salt:    key.Salt,
+				N:       tc.n,
+				R:       tc.r,
+				P:       tc.p,
+			})
+			assert.OK(t, err)
+
+			// Act
+			_, err = decryptBackup(bytes.NewReader(envelope), []byte("passphrase"))
+
+			// Assert
+			assert.Equal(t, err, ErrInvalidBackup)
+		})
+	}
+}
@@ -0,0 +1,340 @@
+// Package bulk exports all secrets in a directory to a .env, JSON or YAML file
+// and imports the values in such a file as secrets, e.g. to migrate from dotenv files.
+//
+// Every secret corresponds to a key in the file. A Naming converts between the
+// path of a secret relative to the directory and its key.
+package bulk
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/errio"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+)
+
+// Errors
+var (
+	errBulk = errio.Namespace("bulk")
+
+	ErrUnknownFormat = errBulk.Code("unknown_format").ErrorPref("unknown format %s: use env, json or yaml")
+	ErrInvalidKey    = errBulk.Code("invalid_key").ErrorPref("key %s cannot be used as the path of a secret: %s")
+	ErrDuplicateKey  = errBulk.Code("duplicate_key").ErrorPref("key %s occurs more than once")
+	ErrDuplicatePath = errBulk.Code("duplicate_path").ErrorPref("keys %s and %s refer to the same secret")
+	ErrSyntax        = errBulk.Code("syntax_error").ErrorPref("line %d: %s")
+	ErrInvalidSecret = errBulk.Code("invalid_secret").ErrorPref("the value of key %s cannot be written as a secret: %s")
+)
+
+// Format is the format of an exported file.
+type Format string
+
+// Supported formats.
+const (
+	FormatEnv  Format = "env"
+	FormatJSON Format = "json"
+	FormatYAML Format = "yaml"
+)
+
+// Naming converts between the paths of secrets relative to a directory and keys.
+type Naming interface {
+	// Key returns the key of the secret at the given relative path.
+	Key(path string) string
+	// Path returns the relative path of the secret of the given key.
+	Path(key string) string
+}
+
+var (
+	// PathNaming uses the relative path of a secret as its key, e.g. dir/db_password.
+	PathNaming Naming = pathNaming{}
+	// UpperSnakeNaming converts the relative path of a secret to UPPER_SNAKE_CASE,
+	// separating directories by a double underscore, e.g. dir/db-password becomes
+	// DIR__DB_PASSWORD. Keys are converted to lowercase paths, e.g. DIR__DB_PASSWORD
+	// becomes dir/db_password.
+	UpperSnakeNaming Naming = upperSnakeNaming{}
+)
+
+type pathNaming struct{}
+
+// Key returns the path.
+func (pathNaming) Key(path string) string {
+	return path
+}
+
+// Path returns the key.
+func (pathNaming) Path(key string) string {
+	return key
+}
+
+type upperSnakeNaming struct{}
+
+var upperSnakeReplacer = strings.NewReplacer("/", "__", "-", "_", ".", "_")
+
+// Key returns the path in UPPER_SNAKE_CASE.
+func (upperSnakeNaming) Key(path string) string {
+	return strings.ToUpper(upperSnakeReplacer.Replace(path))
+}
+
+// Path returns the key in lowercase, with double underscores replaced by slashes.
+func (upperSnakeNaming) Path(key string) string {
+	return strings.Replace(strings.ToLower(key), "__", "/", -1)
+}
+
+// Options configure an export or import.
+type Options struct {
+	// Format is the format of the file. When it is empty, FormatEnv is used.
+	Format Format
+	// Naming converts between paths and keys. When it is nil, UpperSnakeNaming is used.
+	Naming Naming
+	// DryRun only computes the changes of an import, without making them.
+	DryRun bool
+}
+
+func (o Options) format() Format {
+	if o.Format == "" {
+		return FormatEnv
+	}
+	return o.Format
+}
+
+func (o Options) naming() Naming {
+	if o.Naming == nil {
+		return UpperSnakeNaming
+	}
+	return o.Naming
+}
+
+// Export returns the latest versions of all secrets in the directory at the given path
+// and its subdirectories, encoded in the format of the options.
+func Export(client secrethub.Client, path string, options Options) ([]byte, error) {
+	return ExportContext(context.Background(), client, path, options)
+}
+
+// ExportContext is the same as Export, but uses the given context for all requests.
+func ExportContext(ctx context.Context, client secrethub.Client, path string, options Options) ([]byte, error) {
+	values, err := ReadContext(ctx, client, path, options.naming())
+	if err != nil {
+		return nil, err
+	}
+	return Encode(values, options.format())
+}
+
+// Read returns the values of the latest versions of all secrets in the directory at the
+// given path and its subdirectories by their keys. All secrets are retrieved in parallel.
+func Read(client secrethub.Client, path string, naming Naming) (map[string]string, error) {
+	return ReadContext(context.Background(), client, path, naming)
+}
+
+// ReadContext is the same as Read, but uses the given context for all requests.
+func ReadContext(ctx context.Context, client secrethub.Client, path string, naming Naming) (map[string]string, error) {
+	dirPath, err := api.NewDirPath(path)
+	if err != nil {
+		return nil, errio.Error(err)
+	}
+
+	tree, err := client.Dirs().GetTreeContext(ctx, dirPath.Value(), -1, false)
+	if err != nil {
+		return nil, errio.Error(err)
+	}
+
+	relativePaths := tree.SecretPaths()
+	paths := make([]string, len(relativePaths))
+	for i, relativePath := range relativePaths {
+		paths[i] = dirPath.Value() + "/" + relativePath
+	}
+
+	results := client.Secrets().Versions().GetManyWithDataContext(ctx, paths)
+
+	values := make(map[string]string, len(results))
+	for i, result := range results {
+		if result.Err != nil {
+			return nil, errio.Error(result.Err)
+		}
+
+		key := naming.Key(relativePaths[i])
+		if _, exists := values[key]; exists {
+			return nil, ErrDuplicateKey(key)
+		}
+		values[key] = string(result.Version.Data)
+	}
+	return values, nil
+}
+
+// Diff describes the changes an import makes. All paths are absolute.
+type Diff struct {
+	// Dirs contains the paths of the directories that are created, parents first.
+	Dirs []string
+	// Created contains the paths of the secrets that are created.
+	Created []string
+	// Updated contains the paths of the secrets that get a new version with a different value.
+	Updated []string
+	// Unchanged contains the paths of the secrets that already have the imported value.
+	Unchanged []string
+}
+
+// Import writes the values in the data, encoded in the format of the options, as secrets
+// in the directory at the given path. The path of every secret is given by the naming of the
+// options. Missing directories are created and secrets that already have the imported value
+// are not written again. The returned diff describes the changes, which are only computed
+// and not made when the options are a dry run.
+//
+// Import fails before making any changes when a key does not convert to a valid path
+// or when a value is empty or larger than secrethub.MaxSecretSize.
+func Import(client secrethub.Client, path string, data []byte, options Options) (*Diff, error) {
+	return ImportContext(context.Background(), client, path, data, options)
+}
+
+// ImportContext is the same as Import, but uses the given context for all requests.
+func ImportContext(ctx context.Context, client secrethub.Client, path string, data []byte, options Options) (*Diff, error) {
+	dirPath, err := api.NewDirPath(path)
+	if err != nil {
+		return nil, errio.Error(err)
+	}
+
+	values, err := Decode(data, options.format())
+	if err != nil {
+		return nil, err
+	}
+
+	diff, paths, err := diffImport(ctx, client, dirPath, values, options.naming())
+	if err != nil {
+		return nil, err
+	}
+
+	if options.DryRun {
+		return diff, nil
+	}
+
+	for _, dir := range diff.Dirs {
+		_, err = client.Dirs().CreateContext(ctx, dir)
+		if err != nil {
+			return nil, errio.Error(err)
+		}
+	}
+
+	for _, secretPath := range append(append([]string{}, diff.Created...), diff.Updated...) {
+		_, err = client.Secrets().WriteContext(ctx, secretPath, []byte(values[paths[secretPath]]))
+		if err != nil {
+			return nil, errio.Error(err)
+		}
+	}
+
+	return diff, nil
+}
+
+// diffImport returns the changes of importing the values into the directory
+// and the key of the value of every secret path.
+func diffImport(ctx context.Context, client secrethub.Client, dirPath api.DirPath, values map[string]string, naming Naming) (*Diff, map[string]string, error) {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	// Secret names are case insensitive, so paths are compared in lowercase.
+	paths := make(map[string]string, len(keys))
+	lowerPaths := make(map[string]string, len(keys))
+	dirs := make(map[string]bool)
+	for _, key := range keys {
+		relativePath := naming.Path(key)
+		err := validatePath(relativePath)
+		if err != nil {
+			return nil, nil, ErrInvalidKey(key, err)
+		}
+
+		lower := strings.ToLower(relativePath)
+		if other, exists := lowerPaths[lower]; exists {
+			return nil, nil, ErrDuplicatePath(other, key)
+		}
+		lowerPaths[lower] = key
+
+		err = validateValue(values[key])
+		if err != nil {
+			return nil, nil, ErrInvalidSecret(key, err)
+		}
+
+		paths[dirPath.Value()+"/"+relativePath] = key
+
+		elements := strings.Split(relativePath, "/")
+		for i := 1; i < len(elements); i++ {
+			dirs[strings.Join(elements[:i], "/")] = true
+		}
+	}
+
+	existingSecrets := make(map[string]bool)
+	existingDirs := make(map[string]bool)
+	tree, err := client.Dirs().GetTreeContext(ctx, dirPath.Value(), -1, false)
+	if err == nil {
+		for _, secretPath := range tree.SecretPaths() {
+			existingSecrets[strings.ToLower(secretPath)] = true
+		}
+		existingDirs[""] = true
+		for _, existingDir := range tree.DirPaths() {
+			existingDirs[strings.ToLower(existingDir)] = true
+		}
+	} else if err != api.ErrDirNotFound {
+		return nil, nil, errio.Error(err)
+	}
+
+	diff := &Diff{}
+	if !existingDirs[""] {
+		diff.Dirs = append(diff.Dirs, dirPath.Value())
+	}
+	for dir := range dirs {
+		if !existingDirs[strings.ToLower(dir)] {
+			diff.Dirs = append(diff.Dirs, dirPath.Value()+"/"+dir)
+		}
+	}
+	// Sorting puts every directory after its parent.
+	sort.Strings(diff.Dirs)
+
+	var existing []string
+	for secretPath := range paths {
+		relativePath := strings.TrimPrefix(secretPath, dirPath.Value()+"/")
+		if existingSecrets[strings.ToLower(relativePath)] {
+			existing = append(existing, secretPath)
+		} else {
+			diff.Created = append(diff.Created, secretPath)
+		}
+	}
+	sort.Strings(diff.Created)
+	sort.Strings(existing)
+
+	results := client.Secrets().Versions().GetManyWithDataContext(ctx, existing)
+	for _, result := range results {
+		if result.Err != nil {
+			return nil, nil, errio.Error(result.Err)
+		}
+
+		if string(result.Version.Data) == values[paths[result.Path]] {
+			diff.Unchanged = append(diff.Unchanged, result.Path)
+		} else {
+			diff.Updated = append(diff.Updated, result.Path)
+		}
+	}
+
+	return diff, paths, nil
+}
+
+// validateValue returns an error when the value cannot be written as a secret.
+func validateValue(value string) error {
+	if len(value) == 0 {
+		return secrethub.ErrEmptySecret
+	}
+	if len(value) > secrethub.MaxSecretSize {
+		return secrethub.ErrSecretTooBig
+	}
+	return nil
+}
+
+// validatePath returns an error when not all elements of the relative path are valid names.
+func validatePath(relativePath string) error {
+	for _, name := range strings.Split(relativePath, "/") {
+		err := api.ValidateSecretName(name)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,263 @@
+package bulk
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/memclient"
+)
+
+// newRepo returns a client of a new store, signed up as dev1, with the repository dev1/repo.
+func newRepo(t *testing.T) *memclient.Client {
+	client, err := memclient.New("dev1")
+	assert.OK(t, err)
+
+	_, err = client.Repos().Create("dev1/repo")
+	assert.OK(t, err)
+
+	return client
+}
+
+func TestNaming(t *testing.T) {
+	cases := map[string]struct {
+		naming       Naming
+		path         string
+		expectedKey  string
+		expectedPath string
+	}{
+		"path": {
+			naming:       PathNaming,
+			path:         "dir/db-password",
+			expectedKey:  "dir/db-password",
+			expectedPath: "dir/db-password",
+		},
+		"upper snake": {
+			naming:       UpperSnakeNaming,
+			path:         "dir/db_password",
+			expectedKey:  "DIR__DB_PASSWORD",
+			expectedPath: "dir/db_password",
+		},
+		"upper snake with dashes and dots": {
+			naming:       UpperSnakeNaming,
+			path:         "app.config/api-key",
+			expectedKey:  "APP_CONFIG__API_KEY",
+			expectedPath: "app_config/api_key",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			// Act
+			key := tc.naming.Key(tc.path)
+			path := tc.naming.Path(key)
+
+			// Assert
+			assert.Equal(t, key, tc.expectedKey)
+			assert.Equal(t, path, tc.expectedPath)
+		})
+	}
+}
+
+func TestExport(t *testing.T) {
+	// Arrange
+	client := newRepo(t)
+
+	_, err := client.Dirs().Create("dev1/repo/db")
+	assert.OK(t, err)
+
+	_, err = client.Secrets().Write("dev1/repo/api_key", []byte("key"))
+	assert.OK(t, err)
+	_, err = client.Secrets().Write("dev1/repo/db/password", []byte("old"))
+	assert.OK(t, err)
+	_, err = client.Secrets().Write("dev1/repo/db/password", []byte("pass word"))
+	assert.OK(t, err)
+
+	// Act
+	env, err := Export(client, "dev1/repo", Options{})
+	assert.OK(t, err)
+
+	json, err := Export(client, "dev1/repo/db", Options{Format: FormatJSON, Naming: PathNaming})
+	assert.OK(t, err)
+
+	_, errNotFound := Export(client, "dev1/repo/missing", Options{})
+
+	// Assert
+	assert.Equal(t, string(env), "API_KEY=key\nDB__PASSWORD=\"pass word\"\n")
+	assert.Equal(t, string(json), "{\n  \"password\": \"pass word\"\n}\n")
+	assert.Equal(t, errNotFound, api.ErrDirNotFound)
+}
+
+func TestImport(t *testing.T) {
+	// Arrange
+	client := newRepo(t)
+
+	_, err := client.Dirs().Create("dev1/repo/app")
+	assert.OK(t, err)
+	_, err = client.Secrets().Write("dev1/repo/app/api_key", []byte("key"))
+	assert.OK(t, err)
+	_, err = client.Secrets().Write("dev1/repo/app/token", []byte("old"))
+	assert.OK(t, err)
+
+	data := []byte("API_KEY=key\nTOKEN=new\nDB__PASSWORD=secret\nDB__CACHE__URL=redis://cache\n")
+
+	expectedDiff := &Diff{
+		Dirs:      []string{"dev1/repo/app/db", "dev1/repo/app/db/cache"},
+		Created:   []string{"dev1/repo/app/db/cache/url", "dev1/repo/app/db/password"},
+		Updated:   []string{"dev1/repo/app/token"},
+		Unchanged: []string{"dev1/repo/app/api_key"},
+	}
+
+	// Act
+	dryRun, err := Import(client, "dev1/repo/app", data, Options{DryRun: true})
+	assert.OK(t, err)
+
+	_, errDryRun := client.Dirs().GetTree("dev1/repo/app/db", -1, false)
+
+	diff, err := Import(client, "dev1/repo/app", data, Options{})
+	assert.OK(t, err)
+
+	imported, err := Read(client, "dev1/repo/app", UpperSnakeNaming)
+	assert.OK(t, err)
+
+	apiKey, err := client.Secrets().Get("dev1/repo/app/api_key")
+	assert.OK(t, err)
+
+	again, err := Import(client, "dev1/repo/app", data, Options{})
+	assert.OK(t, err)
+
+	// Assert
+	assert.Equal(t, dryRun, expectedDiff)
+	assert.Equal(t, errDryRun, api.ErrDirNotFound)
+	assert.Equal(t, diff, expectedDiff)
+	assert.Equal(t, imported, map[string]string{
+		"API_KEY":        "key",
+		"TOKEN":          "new",
+		"DB__PASSWORD":   "secret",
+		"DB__CACHE__URL": "redis://cache",
+	})
+	assert.Equal(t, apiKey.LatestVersion, 1)
+	assert.Equal(t, again.Created, []string(nil))
+	assert.Equal(t, again.Updated, []string(nil))
+	assert.Equal(t, len(again.Unchanged), 4)
+}
+
+func TestExportImport_RoundTrip(t *testing.T) {
+	for _, format := range []Format{FormatEnv, FormatJSON, FormatYAML} {
+		t.Run(string(format), func(t *testing.T) {
+			// Arrange
+			client := newRepo(t)
+
+			_, err := client.Dirs().Create("dev1/repo/src")
+			assert.OK(t, err)
+			_, err = client.Dirs().Create("dev1/repo/src/db")
+			assert.OK(t, err)
+
+			_, err = client.Secrets().Write("dev1/repo/src/api_key", []byte("it's a \"key\" # not a comment"))
+			assert.OK(t, err)
+			_, err = client.Secrets().Write("dev1/repo/src/db/password", []byte("line 1\nline 2\n"))
+			assert.OK(t, err)
+
+			options := Options{Format: format}
+
+			// Act
+			data, err := Export(client, "dev1/repo/src", options)
+			assert.OK(t, err)
+
+			dryRun, err := Import(client, "dev1/repo/dst", data, Options{Format: format, DryRun: true})
+			assert.OK(t, err)
+
+			_, err = Import(client, "dev1/repo/dst", data, options)
+			assert.OK(t, err)
+
+			src, err := Read(client, "dev1/repo/src", UpperSnakeNaming)
+			assert.OK(t, err)
+
+			dst, err := Read(client, "dev1/repo/dst", UpperSnakeNaming)
+			assert.OK(t, err)
+
+			// Assert
+			assert.Equal(t, dryRun.Created, []string{"dev1/repo/dst/api_key", "dev1/repo/dst/db/password"})
+			assert.Equal(t, dst, src)
+		})
+	}
+}
+
+func TestImport_NewDir(t *testing.T) {
+	// Arrange
+	client := newRepo(t)
+
+	// Act
+	diff, err := Import(client, "dev1/repo/new", []byte(`{"secret": "value"}`), Options{Format: FormatJSON})
+	assert.OK(t, err)
+
+	version, err := client.Secrets().Versions().GetWithData("dev1/repo/new/secret")
+	assert.OK(t, err)
+
+	// Assert
+	assert.Equal(t, diff.Dirs, []string{"dev1/repo/new"})
+	assert.Equal(t, diff.Created, []string{"dev1/repo/new/secret"})
+	assert.Equal(t, version.Data, []byte("value"))
+}
+
+func TestImport_Invalid(t *testing.T) {
+	cases := map[string]struct {
+		format Format
+		data   string
+		err    error
+	}{
+		"invalid name": {
+			data: "API_KEY=key\nINVALID$NAME=value\n",
+			err:  ErrInvalidKey("INVALID$NAME", api.ErrInvalidSecretName),
+		},
+		"empty dir name": {
+			data: "A____B=value\n",
+			err:  ErrInvalidKey("A____B", api.ErrInvalidSecretName),
+		},
+		"duplicate path": {
+			data: "api_key=1\nAPI_KEY=2\n",
+			err:  ErrDuplicatePath("API_KEY", "api_key"),
+		},
+		"syntax error": {
+			data: "API_KEY\n",
+			err:  ErrSyntax(1, "expected KEY=value"),
+		},
+		"empty value": {
+			data: "API_KEY=key\nEMPTY=\n",
+			err:  ErrInvalidSecret("EMPTY", secrethub.ErrEmptySecret),
+		},
+		"yaml invalid name": {
+			format: FormatYAML,
+			data:   "API_KEY: key\n\"INVALID$NAME\": value\n",
+			err:    ErrInvalidKey("INVALID$NAME", api.ErrInvalidSecretName),
+		},
+		"yaml empty value": {
+			format: FormatYAML,
+			data:   "API_KEY: key\nEMPTY:\n",
+			err:    ErrInvalidSecret("EMPTY", secrethub.ErrEmptySecret),
+		},
+		"too big value": {
+			data: "API_KEY=key\nBIG=" + strings.Repeat("a", secrethub.MaxSecretSize+1) + "\n",
+			err:  ErrInvalidSecret("BIG", secrethub.ErrSecretTooBig),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			// Arrange
+			client := newRepo(t)
+
+			// Act
+			_, err := Import(client, "dev1/repo", []byte(tc.data), Options{Format: tc.format})
+
+			secrets, errTree := client.Dirs().GetTree("dev1/repo", -1, false)
+			assert.OK(t, errTree)
+
+			// Assert
+			assert.Equal(t, err, tc.err)
+			assert.Equal(t, secrets.SecretCount(), 0)
+		})
+	}
+}
@@ -0,0 +1,230 @@
+package bulk
+
+import (
+	"bytes"
+	"encoding/json"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/secrethub/secrethub-go/internals/errio"
+)
+
+// Errors
+var (
+	ErrInvalidValue = errBulk.Code("invalid_value").ErrorPref("the value of key %s is not a string")
+)
+
+var (
+	envKeyPattern   = regexp.MustCompile(`^[^\s=#"']+$`)
+	envPlainPattern = regexp.MustCompile(`^[A-Za-z0-9_./:@+,%-]*$`)
+	envEscaper      = strings.NewReplacer(`\`, `\\`, `"`, `\"`, `$`, `\$`, "\n", `\n`, "\r", `\r`, "\t", `\t`)
+)
+
+// Encode returns the values by their keys encoded in the given format, sorted by key.
+func Encode(values map[string]string, format Format) ([]byte, error) {
+	switch format {
+	case FormatEnv:
+		return encodeEnv(values)
+	case FormatJSON:
+		return encodeJSON(values)
+	case FormatYAML:
+		return encodeYAML(values)
+	default:
+		return nil, ErrUnknownFormat(format)
+	}
+}
+
+// Decode returns the values by their keys in the data, which is encoded in the given format.
+func Decode(data []byte, format Format) (map[string]string, error) {
+	switch format {
+	case FormatEnv:
+		return decodeEnv(data)
+	case FormatJSON:
+		return decodeJSON(data)
+	case FormatYAML:
+		return decodeYAML(data)
+	default:
+		return nil, ErrUnknownFormat(format)
+	}
+}
+
+// sortedKeys returns the keys of the values in sorted order.
+func sortedKeys(values map[string]string) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// encodeEnv encodes the values as lines of the form KEY=value. Values that contain
+// characters other than letters, digits and common punctuation are double quoted.
+func encodeEnv(values map[string]string) ([]byte, error) {
+	var buf bytes.Buffer
+	for _, key := range sortedKeys(values) {
+		if !envKeyPattern.MatchString(key) {
+			return nil, ErrInvalidKey(key, "keys of .env files cannot contain whitespace, quotes, = or #")
+		}
+
+		value := values[key]
+		if !envPlainPattern.MatchString(value) {
+			value = `"` + envEscaper.Replace(value) + `"`
+		}
+
+		buf.WriteString(key + "=" + value + "\n")
+	}
+	return buf.Bytes(), nil
+}
+
+// decodeEnv decodes lines of the form KEY=value, optionally prefixed by export.
+// Blank lines and lines starting with # are ignored. Unquoted values end at a
+// # preceded by whitespace. Double quoted values can contain the escape sequences
+// \n, \r, \t, \", \$ and \\, single quoted values are taken literally and both
+// can span multiple lines.
+func decodeEnv(data []byte) (map[string]string, error) {
+	values := make(map[string]string)
+	lines := strings.Split(strings.Replace(string(data), "\r\n", "\n", -1), "\n")
+	for i := 0; i < len(lines); i++ {
+		lineNumber := i + 1
+		line := strings.TrimLeft(lines[i], " \t")
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+
+		split := strings.SplitN(line, "=", 2)
+		if len(split) != 2 {
+			return nil, ErrSyntax(lineNumber, "expected KEY=value")
+		}
+
+		key := strings.TrimSpace(split[0])
+		if !envKeyPattern.MatchString(key) {
+			return nil, ErrSyntax(lineNumber, "invalid key "+key)
+		}
+
+		rest := strings.TrimLeft(split[1], " \t")
+		var value string
+		if strings.HasPrefix(rest, `"`) || strings.HasPrefix(rest, `'`) {
+			quote := rest[0]
+			raw := rest[1:]
+			end := closingQuote(raw, quote)
+			for end < 0 {
+				i++
+				if i == len(lines) {
+					return nil, ErrSyntax(lineNumber, "unterminated quoted value")
+				}
+				raw += "\n" + lines[i]
+				end = closingQuote(raw, quote)
+			}
+
+			after := strings.TrimSpace(raw[end+1:])
+			if after != "" && !strings.HasPrefix(after, "#") {
+				return nil, ErrSyntax(lineNumber, "unexpected characters after quoted value")
+			}
+
+			value = raw[:end]
+			if quote == '"' {
+				value = unescapeEnv(value)
+			}
+		} else {
+			value = rest
+			if index := strings.Index(value, " #"); index >= 0 {
+				value = value[:index]
+			}
+			value = strings.TrimSpace(value)
+		}
+
+		if _, exists := values[key]; exists {
+			return nil, ErrDuplicateKey(key)
+		}
+		values[key] = value
+	}
+	return values, nil
+}
+
+// closingQuote returns the index of the first unescaped quote in s, or -1 when there is none.
+// Only double quotes can be escaped.
+func closingQuote(s string, quote byte) int {
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && quote == '"' {
+			i++
+			continue
+		}
+		if s[i] == quote {
+			return i
+		}
+	}
+	return -1
+}
+
+// unescapeEnv replaces the escape sequences in a double quoted value.
+// Unknown escape sequences are kept as is.
+func unescapeEnv(s string) string {
+	var buf strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i == len(s)-1 {
+			buf.WriteByte(s[i])
+			continue
+		}
+
+		i++
+		switch s[i] {
+		case 'n':
+			buf.WriteByte('\n')
+		case 'r':
+			buf.WriteByte('\r')
+		case 't':
+			buf.WriteByte('\t')
+		case '"', '$', '\\':
+			buf.WriteByte(s[i])
+		default:
+			buf.WriteByte('\\')
+			buf.WriteByte(s[i])
+		}
+	}
+	return buf.String()
+}
+
+// encodeJSON encodes the values as an indented JSON object.
+func encodeJSON(values map[string]string) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	err := encoder.Encode(values)
+	if err != nil {
+		return nil, errio.Error(err)
+	}
+	return buf.Bytes(), nil
+}
+
+// decodeJSON decodes a JSON object of which all values are strings.
+func decodeJSON(data []byte) (map[string]string, error) {
+	var raw map[string]json.RawMessage
+	err := json.Unmarshal(data, &raw)
+	if err != nil {
+		return nil, errio.Error(err)
+	}
+
+	values := make(map[string]string, len(raw))
+	for key, rawValue := range raw {
+		var value string
+		err = json.Unmarshal(rawValue, &value)
+		if err != nil {
+			return nil, ErrInvalidValue(key)
+		}
+		values[key] = value
+	}
+	return values, nil
+}
+
+// quoteJSON returns the string as a double quoted JSON string.
+func quoteJSON(s string) string {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	_ = encoder.Encode(s)
+	return strings.TrimSuffix(buf.String(), "\n")
+}
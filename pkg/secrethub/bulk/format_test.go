@@ -0,0 +1,207 @@
+package bulk
+
+import (
+	"testing"
+
+	"github.com/secrethub/secrethub-go/internals/assert"
+)
+
+func TestEncodeDecode(t *testing.T) {
+	values := map[string]string{
+		"PLAIN":      "value",
+		"EMPTY":      "",
+		"SPACES":     "hello world",
+		"QUOTES":     `it's "quoted"`,
+		"MULTILINE":  "line 1\nline 2\n",
+		"SPECIAL":    `$HOME \ # = : - [x]`,
+		"UNICODE":    "h\u00e9llo \u2028",
+		"dir/secret": "nested",
+		"yes":        "reserved",
+	}
+
+	for _, format := range []Format{FormatEnv, FormatJSON, FormatYAML} {
+		t.Run(string(format), func(t *testing.T) {
+			// Act
+			encoded, err := Encode(values, format)
+			assert.OK(t, err)
+
+			actual, err := Decode(encoded, format)
+
+			// Assert
+			assert.OK(t, err)
+			assert.Equal(t, actual, values)
+		})
+	}
+}
+
+func TestEncode(t *testing.T) {
+	values := map[string]string{
+		"B": "two words",
+		"A": "plain",
+	}
+
+	cases := map[string]struct {
+		format   Format
+		expected string
+		err      error
+	}{
+		"env": {
+			format:   FormatEnv,
+			expected: "A=plain\nB=\"two words\"\n",
+		},
+		"json": {
+			format:   FormatJSON,
+			expected: "{\n  \"A\": \"plain\",\n  \"B\": \"two words\"\n}\n",
+		},
+		"yaml": {
+			format:   FormatYAML,
+			expected: "A: \"plain\"\nB: \"two words\"\n",
+		},
+		"unknown": {
+			format: "toml",
+			err:    ErrUnknownFormat("toml"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			// Act
+			actual, err := Encode(values, tc.format)
+
+			// Assert
+			assert.Equal(t, err, tc.err)
+			assert.Equal(t, string(actual), tc.expected)
+		})
+	}
+}
+
+func TestDecode(t *testing.T) {
+	cases := map[string]struct {
+		format   Format
+		data     string
+		expected map[string]string
+		err      error
+	}{
+		"env": {
+			format: FormatEnv,
+			data: "# comment\n" +
+				"\n" +
+				"export PLAIN=value # comment\n" +
+				"SPACED = value with spaces \n" +
+				"DOUBLE=\"a\\nb \\\"c\\\" \\$d\"\n" +
+				"SINGLE='a\\nb # c'\n" +
+				"MULTILINE=\"line 1\n" +
+				"line 2\"\n" +
+				"URL=https://example.com/#anchor\r\n" +
+				"EMPTY=\n",
+			expected: map[string]string{
+				"PLAIN":     "value",
+				"SPACED":    "value with spaces",
+				"DOUBLE":    "a\nb \"c\" $d",
+				"SINGLE":    `a\nb # c`,
+				"MULTILINE": "line 1\nline 2",
+				"URL":       "https://example.com/#anchor",
+				"EMPTY":     "",
+			},
+		},
+		"env without equals sign": {
+			format: FormatEnv,
+			data:   "A=1\nB\n",
+			err:    ErrSyntax(2, "expected KEY=value"),
+		},
+		"env unterminated quote": {
+			format: FormatEnv,
+			data:   "A=\"value\n",
+			err:    ErrSyntax(1, "unterminated quoted value"),
+		},
+		"env duplicate key": {
+			format: FormatEnv,
+			data:   "A=1\nA=2\n",
+			err:    ErrDuplicateKey("A"),
+		},
+		"json": {
+			format:   FormatJSON,
+			data:     `{"A": "1", "B": "two"}`,
+			expected: map[string]string{"A": "1", "B": "two"},
+		},
+		"json number": {
+			format: FormatJSON,
+			data:   `{"A": 1}`,
+			err:    ErrInvalidValue("A"),
+		},
+		"yaml": {
+			format: FormatYAML,
+			data: "---\n" +
+				"# comment\n" +
+				"plain: value # comment\n" +
+				"number: 42\n" +
+				"url: https://example.com\n" +
+				"double: \"a\\tb\\x41\\u00e9\"\n" +
+				"single: 'it''s'\n" +
+				"\"quoted key\": value\n" +
+				"empty:\n" +
+				"literal: |\n" +
+				"  line 1\n" +
+				"\n" +
+				"    indented\n" +
+				"\n" +
+				"strip: |-\n" +
+				"  stripped\n" +
+				"keep: |+\n" +
+				"  kept\n" +
+				"\n" +
+				"last: value\n",
+			expected: map[string]string{
+				"plain":      "value",
+				"number":     "42",
+				"url":        "https://example.com",
+				"double":     "a\tbA\u00e9",
+				"single":     "it's",
+				"quoted key": "value",
+				"empty":      "",
+				"literal":    "line 1\n\n  indented\n",
+				"strip":      "stripped",
+				"keep":       "kept\n\n",
+				"last":       "value",
+			},
+		},
+		"yaml nested mapping": {
+			format: FormatYAML,
+			data:   "parent:\n  child: value\n",
+			err:    ErrSyntax(1, "nested values are not supported"),
+		},
+		"yaml sequence": {
+			format: FormatYAML,
+			data:   "- value\n",
+			err:    ErrSyntax(1, "expected a mapping of keys to values"),
+		},
+		"yaml flow mapping": {
+			format: FormatYAML,
+			data:   "key: {a: b}\n",
+			err:    ErrSyntax(1, "folded block scalars, flow collections, anchors, aliases and tags are not supported"),
+		},
+		"yaml unterminated quote": {
+			format: FormatYAML,
+			data:   "key: \"value\n",
+			err:    ErrSyntax(1, "unterminated double quoted value"),
+		},
+		"yaml duplicate key": {
+			format: FormatYAML,
+			data:   "a: 1\na: 2\n",
+			err:    ErrDuplicateKey("a"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			// Act
+			actual, err := Decode([]byte(tc.data), tc.format)
+
+			// Assert
+			assert.Equal(t, err, tc.err)
+			if err == nil {
+				assert.Equal(t, actual, tc.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package bulk
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+
+	"github.com/secrethub/secrethub-go/internals/yaml"
+)
+
+var (
+	yamlPlainKeyPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_./-]*$`)
+	// yamlReservedKeys are plain scalars that YAML parsers may not read as strings.
+	yamlReservedKeys = map[string]bool{
+		"y": true, "n": true, "yes": true, "no": true, "on": true, "off": true,
+		"true": true, "false": true, "null": true,
+	}
+)
+
+// encodeYAML encodes the values as a YAML mapping. Values are always double quoted,
+// so that every YAML parser reads them as strings.
+func encodeYAML(values map[string]string) ([]byte, error) {
+	var buf bytes.Buffer
+	for _, key := range sortedKeys(values) {
+		encodedKey := key
+		if !yamlPlainKeyPattern.MatchString(key) || yamlReservedKeys[strings.ToLower(key)] {
+			encodedKey = quoteJSON(key)
+		}
+		buf.WriteString(encodedKey + ": " + quoteJSON(values[key]) + "\n")
+	}
+	return buf.Bytes(), nil
+}
+
+// decodeYAML decodes a YAML mapping of keys to scalar values. All values are read
+// as strings. See the yaml package for the supported subset of YAML.
+func decodeYAML(data []byte) (map[string]string, error) {
+	values, err := yaml.Decode(data)
+	switch e := err.(type) {
+	case *yaml.SyntaxError:
+		return nil, ErrSyntax(e.Line, e.Msg)
+	case *yaml.DuplicateKeyError:
+		return nil, ErrDuplicateKey(e.Key)
+	}
+	return values, err
+}
@@ -0,0 +1,292 @@
+package secrethub
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/errio"
+)
+
+const (
+	// DefaultCacheMaxStaleness is the maximum age of a cached response that is
+	// served when the API cannot be reached and no MaxStaleness is configured.
+	DefaultCacheMaxStaleness = 24 * time.Hour
+)
+
+// CacheOptions configure a read-through cache of the encrypted responses of the API,
+// which is used when the API cannot be reached. Only responses that are encrypted for
+// the account key or the credential are cached: the account key, the repository keys
+// and the versions of secrets, including their data. They are stored on disk exactly
+// as they are returned by the API, so the cache never contains plaintext secrets and
+// can only be used by a client that has the credential of the account.
+type CacheOptions struct {
+	// Dir is the directory in which the responses are stored. It is created
+	// when it does not exist. Clients with different credentials can share
+	// a directory, as every credential has its own subdirectory.
+	Dir string
+	// MaxStaleness is the maximum age of a cached response that is served
+	// when the API cannot be reached. Defaults to DefaultCacheMaxStaleness.
+	MaxStaleness time.Duration
+	// OnFallback is an optional callback that is called for every secret
+	// version that is served from the cache, e.g. to log a warning.
+	OnFallback func(fallback CacheFallback)
+}
+
+// CacheFallback describes a secret version that is served from the cache.
+type CacheFallback struct {
+	// Path is the path of the secret version, as it was requested.
+	Path string
+	// Version is the number of the version that is served.
+	Version int
+	// StoredAt is the time at which the version was retrieved from the API.
+	StoredAt time.Time
+	// Err is the error of the request to the API.
+	Err error
+}
+
+// cacheEntry is the format in which a response is stored on disk.
+type cacheEntry struct {
+	StoredAt time.Time       `json:"stored_at"`
+	Response json.RawMessage `json:"response"`
+}
+
+// diskCache stores encrypted API responses as files in a directory.
+type diskCache struct {
+	dir          string
+	maxStaleness time.Duration
+	onFallback   func(CacheFallback)
+	now          func() time.Time
+}
+
+// newDiskCache returns a cache in the subdirectory of the configured directory
+// for the given credential, or nil when the options are nil.
+func newDiskCache(opts *CacheOptions, credential Credential) *diskCache {
+	if opts == nil || credential == nil {
+		return nil
+	}
+
+	fingerprint, err := credential.Fingerprint()
+	if err != nil {
+		log.Debugf("cannot use the cache: %v", err)
+		return nil
+	}
+
+	maxStaleness := opts.MaxStaleness
+	if maxStaleness <= 0 {
+		maxStaleness = DefaultCacheMaxStaleness
+	}
+
+	return &diskCache{
+		dir:          filepath.Join(opts.Dir, cacheFileName(fingerprint)),
+		maxStaleness: maxStaleness,
+		onFallback:   opts.OnFallback,
+		now:          time.Now,
+	}
+}
+
+// cacheFileName returns a name for the key that is safe to use on any file system.
+func cacheFileName(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// store writes the response to the cache under the given key. Failing to write
+// to the cache does not fail the request, so errors are only logged.
+func (c *diskCache) store(key string, response interface{}) {
+	err := c.write(key, response)
+	if err != nil {
+		log.Debugf("cannot write %s to the cache: %v", key, err)
+	}
+}
+
+func (c *diskCache) write(key string, response interface{}) error {
+	raw, err := json.Marshal(response)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(cacheEntry{
+		StoredAt: c.now(),
+		Response: raw,
+	})
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(c.dir, 0700)
+	if err != nil {
+		return err
+	}
+
+	// Write to a temporary file first, so that concurrent readers
+	// never see a partially written entry.
+	tmp, err := ioutil.TempFile(c.dir, ".tmp-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = tmp.Write(data)
+	if err != nil {
+		_ = tmp.Close()
+		return err
+	}
+
+	err = tmp.Close()
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), filepath.Join(c.dir, cacheFileName(key)))
+}
+
+// fallback decodes the response cached under the given key into out when the
+// request failed with err because the API could not be reached. It returns the
+// time at which the response was stored and false when no response is served,
+// because the error is not caused by the API being unavailable or because no
+// response is cached that is younger than the maximum staleness.
+func (c *diskCache) fallback(err error, key string, out interface{}) (time.Time, bool) {
+	if !isUnavailable(err) {
+		return time.Time{}, false
+	}
+
+	data, errRead := ioutil.ReadFile(filepath.Join(c.dir, cacheFileName(key)))
+	if errRead != nil {
+		if !os.IsNotExist(errRead) {
+			log.Debugf("cannot read %s from the cache: %v", key, errRead)
+		}
+		return time.Time{}, false
+	}
+
+	var entry cacheEntry
+	errRead = json.Unmarshal(data, &entry)
+	if errRead == nil {
+		errRead = json.Unmarshal(entry.Response, out)
+	}
+	if errRead != nil {
+		log.Debugf("cannot read %s from the cache: %v", key, errRead)
+		return time.Time{}, false
+	}
+
+	if c.now().Sub(entry.StoredAt) > c.maxStaleness {
+		return time.Time{}, false
+	}
+
+	return entry.StoredAt, true
+}
+
+// isUnavailable returns whether the error is caused by the API being unreachable,
+// i.e. the request failed with ErrCannotReachServer or the server responded with
+// a 5xx status code.
+// Errors returned by the API itself, such as a missing secret or permission,
+// and canceled requests never fall back to the cache.
+func isUnavailable(err error) bool {
+	switch e := err.(type) {
+	case errio.PublicStatusError:
+		return e.StatusCode >= 500
+	case errio.PublicError:
+		return e.Namespace == errClient && e.Code == "cannot_reach_server"
+	}
+	return false
+}
+
+const (
+	cacheKeyAccountKey    = "account_key"
+	cacheKeyRepoKeys      = "repo_keys/"
+	cacheKeySecretVersion = "secret_version/"
+)
+
+// fetchEncryptedAccountKey gets the account key, encrypted for the credential,
+// from the API or, when the API cannot be reached, from the cache.
+func (c *client) fetchEncryptedAccountKey(ctx context.Context) (*api.EncryptedAccountKey, error) {
+	resp, err := c.httpClient.GetAccountKey(ctx)
+	if c.cache == nil {
+		return resp, err
+	}
+
+	if err == nil {
+		c.cache.store(cacheKeyAccountKey, resp)
+		return resp, nil
+	}
+
+	cached := &api.EncryptedAccountKey{}
+	_, ok := c.cache.fallback(err, cacheKeyAccountKey, cached)
+	if !ok {
+		return nil, errio.Error(err)
+	}
+	return cached, nil
+}
+
+// fetchRepoKeys gets the keys of a repository, encrypted for the account key,
+// from the API or, when the API cannot be reached, from the cache.
+func (c *client) fetchRepoKeys(ctx context.Context, repoPath api.RepoPath) (*api.RepoKeys, error) {
+	resp, err := c.httpClient.GetRepoKeys(ctx, repoPath.GetNamespace(), repoPath.GetRepo())
+	if c.cache == nil {
+		return resp, err
+	}
+
+	key := cacheKeyRepoKeys + strings.ToLower(repoPath.Value())
+	if err == nil {
+		c.cache.store(key, resp)
+		return resp, nil
+	}
+
+	cached := &api.RepoKeys{}
+	_, ok := c.cache.fallback(err, key, cached)
+	if !ok {
+		return nil, errio.Error(err)
+	}
+	return cached, nil
+}
+
+// fetchSecretVersion gets a secret version, encrypted for the account key, from the API
+// or, when the API cannot be reached, from the cache. Versions are cached by blind name
+// and version when they are retrieved with their data, so a cached version can be served
+// with and without data. The latest version is also cached under its version number.
+// The returned bool is true when the version has been served from the cache.
+func (c *client) fetchSecretVersion(ctx context.Context, path api.SecretPath, blindName string, version string, withData bool) (*api.EncryptedSecretVersion, bool, error) {
+	resp, err := c.httpClient.GetSecretVersion(ctx, blindName, version, withData)
+	if c.cache == nil {
+		return resp, false, err
+	}
+
+	key := cacheKeySecretVersion + blindName + "/" + version
+	if err == nil {
+		if withData {
+			c.cache.store(key, resp)
+			if version == "latest" {
+				c.cache.store(cacheKeySecretVersion+blindName+"/"+strconv.Itoa(resp.Version), resp)
+			}
+		}
+		return resp, false, nil
+	}
+
+	cached := &api.EncryptedSecretVersion{}
+	storedAt, ok := c.cache.fallback(err, key, cached)
+	if !ok {
+		return nil, false, errio.Error(err)
+	}
+
+	if !withData {
+		cached.EncryptedData = nil
+	}
+
+	if c.cache.onFallback != nil {
+		c.cache.onFallback(CacheFallback{
+			Path:     path.Value(),
+			Version:  cached.Version,
+			StoredAt: storedAt,
+			Err:      err,
+		})
+	}
+	return cached, true, nil
+}
@@ -0,0 +1,136 @@
+package secrethub
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/internals/errio"
+)
+
+func TestIsUnavailable(t *testing.T) {
+	cases := map[string]struct {
+		err      error
+		expected bool
+	}{
+		"connection failed": {
+			err:      ErrCannotReachServer("dial tcp: connection refused"),
+			expected: true,
+		},
+		"unexpected error": {
+			err:      errio.UnexpectedError(errors.New("cannot decrypt")),
+			expected: false,
+		},
+		"server error": {
+			err:      errio.Namespace("server").Code("unavailable").StatusError("service unavailable", http.StatusServiceUnavailable),
+			expected: true,
+		},
+		"not found": {
+			err:      api.ErrSecretNotFound,
+			expected: false,
+		},
+		"forbidden": {
+			err:      api.ErrForbidden,
+			expected: false,
+		},
+		"canceled": {
+			err:      ErrCanceled,
+			expected: false,
+		},
+		"deadline exceeded": {
+			err:      ErrDeadlineExceeded,
+			expected: false,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			// Act
+			actual := isUnavailable(tc.err)
+
+			// Assert
+			assert.Equal(t, actual, tc.expected)
+		})
+	}
+}
+
+func TestDiskCache_Fallback(t *testing.T) {
+	unavailable := ErrCannotReachServer("dial tcp: connection refused")
+	storedAt := time.Date(2019, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	cases := map[string]struct {
+		key      string
+		err      error
+		age      time.Duration
+		expected bool
+	}{
+		"fresh": {
+			key:      "secret_version/blind/latest",
+			err:      unavailable,
+			age:      time.Hour,
+			expected: true,
+		},
+		"max staleness": {
+			key:      "secret_version/blind/latest",
+			err:      unavailable,
+			age:      2 * time.Hour,
+			expected: true,
+		},
+		"stale": {
+			key:      "secret_version/blind/latest",
+			err:      unavailable,
+			age:      2*time.Hour + time.Second,
+			expected: false,
+		},
+		"not cached": {
+			key:      "secret_version/blind/1",
+			err:      unavailable,
+			age:      time.Hour,
+			expected: false,
+		},
+		"api error": {
+			key:      "secret_version/blind/latest",
+			err:      api.ErrSecretNotFound,
+			age:      time.Hour,
+			expected: false,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			// Arrange
+			dir, err := ioutil.TempDir("", "secrethub-cache")
+			assert.OK(t, err)
+			defer os.RemoveAll(dir)
+
+			now := storedAt
+			cache := &diskCache{
+				dir:          dir,
+				maxStaleness: 2 * time.Hour,
+				now: func() time.Time {
+					return now
+				},
+			}
+
+			stored := &api.EncryptedSecretVersion{Version: 2, Status: api.StatusOK}
+			cache.store("secret_version/blind/latest", stored)
+			now = storedAt.Add(tc.age)
+
+			// Act
+			actual := &api.EncryptedSecretVersion{}
+			actualStoredAt, ok := cache.fallback(tc.err, tc.key, actual)
+
+			// Assert
+			assert.Equal(t, ok, tc.expected)
+			if tc.expected {
+				assert.Equal(t, actual, stored)
+				assert.Equal(t, actualStoredAt.Equal(storedAt), true)
+			}
+		})
+	}
+}
@@ -1,6 +1,8 @@
 package secrethub
 
 import (
+	"sync"
+
 	"github.com/secrethub/secrethub-go/internals/api"
 	"github.com/secrethub/secrethub-go/internals/crypto"
 	"github.com/secrethub/secrethub-go/internals/errio"
@@ -16,10 +18,16 @@ type Client interface {
 	Secrets() SecretService
 	Services() ServiceService
 	Users() UserService
+
+	// Wipe removes the account key and repository keys cached by the client
+	// from memory, overwriting them with zeros. The keys are fetched again
+	// when they are needed by subsequent calls. Wipe should not be called
+	// while other calls on the client are still in progress.
+	Wipe()
 }
 
 type clientAdapter struct {
-	client client
+	client *client
 }
 
 // NewClient creates a new SecretHub client.
@@ -70,18 +78,33 @@ func (c clientAdapter) Users() UserService {
 	return newUserService(c.client)
 }
 
+// Wipe removes the keys cached by the client from memory.
+func (c clientAdapter) Wipe() {
+	c.client.wipe()
+}
+
 var (
 	errClient = errio.Namespace("client")
 )
 
 // Client is a client for the SecretHub HTTP API.
+// It is shared by all services of a Client and is safe for concurrent use.
 type client struct {
 	httpClient *httpClient
 
+	// concurrency is the maximum number of parallel requests of bulk operations.
+	concurrency int
+
+	// cache serves encrypted responses when the API cannot be reached. It is nil when not configured.
+	cache *diskCache
+
 	// credential is the key used by a client to decrypt the account key and authenticate the requests.
 	// It is passed to the httpClient to provide authentication.
 	credential Credential
 
+	// accountMutex guards account and accountKey.
+	accountMutex sync.Mutex
+
 	// account is the api.Account for this SecretHub account.
 	// Do not use this field directly, but use client.getMyAccount() instead.
 	account *api.Account
@@ -90,18 +113,59 @@ type client struct {
 	// Do not use this field directly, but use client.getAccountKey() instead.
 	accountKey *crypto.RSAPrivateKey
 
+	// repoIndexKeysMutex guards repoIndexKeys and repoIndexKeyFetches.
+	repoIndexKeysMutex sync.Mutex
+
 	// repoindexKeys are the keys used to generate blind names in the repo.
 	// These are cached
 	repoIndexKeys map[api.RepoPath]*crypto.SymmetricKey
+
+	// repoIndexKeyFetches are the repo index keys that are being fetched,
+	// so that concurrent callers wait for the same fetch.
+	repoIndexKeyFetches map[api.RepoPath]*repoIndexKeyFetch
 }
 
 // newClient configures a new client, overriding defaults with options when given.
-func newClient(credential Credential, opts *ClientOptions) client {
+func newClient(credential Credential, opts *ClientOptions) *client {
 	httpClient := newHTTPClient(credential, opts)
 
-	return client{
-		httpClient:    httpClient,
-		credential:    credential,
-		repoIndexKeys: make(map[api.RepoPath]*crypto.SymmetricKey),
+	concurrency := DefaultConcurrency
+	var cache *diskCache
+	if opts != nil {
+		if opts.Concurrency > 0 {
+			concurrency = opts.Concurrency
+		}
+		cache = newDiskCache(opts.Cache, credential)
+	}
+
+	return &client{
+		httpClient:          httpClient,
+		concurrency:         concurrency,
+		cache:               cache,
+		credential:          credential,
+		repoIndexKeys:       make(map[api.RepoPath]*crypto.SymmetricKey),
+		repoIndexKeyFetches: make(map[api.RepoPath]*repoIndexKeyFetch),
+	}
+}
+
+// wipe overwrites the cached account key and repo index keys with zeros
+// and removes them from the cache.
+func (c *client) wipe() {
+	c.accountMutex.Lock()
+	if c.accountKey != nil {
+		c.accountKey.Wipe()
+	}
+	c.account = nil
+	c.accountKey = nil
+	c.accountMutex.Unlock()
+
+	c.repoIndexKeysMutex.Lock()
+	for repoPath, key := range c.repoIndexKeys {
+		key.Wipe()
+		delete(c.repoIndexKeys, repoPath)
+	}
+	for repoPath := range c.repoIndexKeyFetches {
+		delete(c.repoIndexKeyFetches, repoPath)
 	}
+	c.repoIndexKeysMutex.Unlock()
 }
@@ -0,0 +1,232 @@
+package secrethub
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/go-chi/chi"
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/internals/crypto"
+)
+
+func TestClient_AccountKeyIsFetchedOnce(t *testing.T) {
+	// Arrange
+	router, opts, cleanup := setup()
+	defer cleanup()
+
+	accountKey, err := crypto.GenerateRSAPrivateKey(1024)
+	assert.OK(t, err)
+
+	privateKey, err := accountKey.ExportPEM()
+	assert.OK(t, err)
+
+	encryptedPrivateKey, err := cred1.Wrap(privateKey)
+	assert.OK(t, err)
+
+	var calls int32
+	router.Get("/me/key", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(&api.EncryptedAccountKey{
+			Account:             &api.Account{Name: "dev1"},
+			EncryptedPrivateKey: encryptedPrivateKey,
+		})
+	})
+
+	c := NewClient(cred1, opts).(*clientAdapter)
+
+	// Act
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := c.client.getAccountKey(context.Background())
+			assert.OK(t, err)
+		}()
+	}
+	wg.Wait()
+
+	account, err := c.client.getMyAccount(context.Background())
+
+	// Assert
+	assert.OK(t, err)
+	assert.Equal(t, account.Name, api.AccountName("dev1"))
+	assert.Equal(t, atomic.LoadInt32(&calls), int32(1))
+}
+
+func TestClient_RepoIndexKeyIsFetchedOnce(t *testing.T) {
+	// Arrange
+	router, opts, cleanup := setup()
+	defer cleanup()
+
+	accountKey, err := crypto.GenerateRSAPrivateKey(1024)
+	assert.OK(t, err)
+
+	privateKey, err := accountKey.ExportPEM()
+	assert.OK(t, err)
+
+	encryptedPrivateKey, err := cred1.Wrap(privateKey)
+	assert.OK(t, err)
+
+	repoIndexKey, err := crypto.GenerateSymmetricKey()
+	assert.OK(t, err)
+
+	wrappedRepoIndexKey, err := accountKey.Public().WrapBytes(repoIndexKey.Export())
+	assert.OK(t, err)
+
+	router.Get("/me/key", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(&api.EncryptedAccountKey{
+			Account:             &api.Account{Name: "dev1"},
+			EncryptedPrivateKey: encryptedPrivateKey,
+		})
+	})
+
+	var calls int32
+	router.Get("/namespaces/dev1/repos/repo/keys", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(&api.RepoKeys{
+			RepoIndexKey: wrappedRepoIndexKey,
+		})
+	})
+
+	c := NewClient(cred1, opts).(*clientAdapter)
+
+	// Act
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			key, err := c.client.getRepoIndexKey(context.Background(), "dev1/repo")
+			assert.OK(t, err)
+			assert.Equal(t, key.Export(), repoIndexKey.Export())
+		}()
+	}
+	wg.Wait()
+
+	// Assert
+	assert.Equal(t, atomic.LoadInt32(&calls), int32(1))
+}
+
+func TestClient_RepoIndexKeyFetchesDoNotBlockOtherRepos(t *testing.T) {
+	// Arrange
+	router, opts, cleanup := setup()
+	defer cleanup()
+
+	accountKey, err := crypto.GenerateRSAPrivateKey(1024)
+	assert.OK(t, err)
+
+	privateKey, err := accountKey.ExportPEM()
+	assert.OK(t, err)
+
+	encryptedPrivateKey, err := cred1.Wrap(privateKey)
+	assert.OK(t, err)
+
+	repoIndexKey, err := crypto.GenerateSymmetricKey()
+	assert.OK(t, err)
+
+	wrappedRepoIndexKey, err := accountKey.Public().WrapBytes(repoIndexKey.Export())
+	assert.OK(t, err)
+
+	router.Get("/me/key", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(&api.EncryptedAccountKey{
+			Account:             &api.Account{Name: "dev1"},
+			EncryptedPrivateKey: encryptedPrivateKey,
+		})
+	})
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	router.Get("/namespaces/dev1/repos/{repo}/keys", func(w http.ResponseWriter, r *http.Request) {
+		if chi.URLParam(r, "repo") == "slow" {
+			close(started)
+			<-release
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(&api.RepoKeys{
+			RepoIndexKey: wrappedRepoIndexKey,
+		})
+	})
+
+	c := NewClient(cred1, opts).(*clientAdapter)
+
+	_, err = c.client.getRepoIndexKey(context.Background(), "dev1/cached")
+	assert.OK(t, err)
+
+	slow := make(chan error)
+	go func() {
+		_, err := c.client.getRepoIndexKey(context.Background(), "dev1/slow")
+		slow <- err
+	}()
+	<-started
+
+	// Act
+	_, errCached := c.client.getRepoIndexKey(context.Background(), "dev1/cached")
+	_, errOther := c.client.getRepoIndexKey(context.Background(), "dev1/other")
+
+	close(release)
+	errSlow := <-slow
+
+	// Assert
+	assert.OK(t, errCached)
+	assert.OK(t, errOther)
+	assert.OK(t, errSlow)
+}
+
+func TestClient_Wipe(t *testing.T) {
+	// Arrange
+	router, opts, cleanup := setup()
+	defer cleanup()
+
+	accountKey, err := crypto.GenerateRSAPrivateKey(1024)
+	assert.OK(t, err)
+
+	privateKey, err := accountKey.ExportPEM()
+	assert.OK(t, err)
+
+	encryptedPrivateKey, err := cred1.Wrap(privateKey)
+	assert.OK(t, err)
+
+	calls := 0
+	router.Get("/me/key", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(&api.EncryptedAccountKey{
+			Account:             &api.Account{Name: "dev1"},
+			EncryptedPrivateKey: encryptedPrivateKey,
+		})
+	})
+
+	c := NewClient(cred1, opts).(*clientAdapter)
+
+	_, err = c.client.getAccountKey(context.Background())
+	assert.OK(t, err)
+
+	repoIndexKey, err := crypto.GenerateSymmetricKey()
+	assert.OK(t, err)
+	c.client.repoIndexKeys["dev1/repo"] = repoIndexKey
+
+	// Act
+	c.Wipe()
+
+	// Assert
+	assert.Equal(t, repoIndexKey.Export(), make([]byte, crypto.SymmetricKeyLength))
+	assert.Equal(t, len(c.client.repoIndexKeys), 0)
+
+	_, err = c.client.getAccountKey(context.Background())
+	assert.OK(t, err)
+	assert.Equal(t, calls, 2)
+}
@@ -0,0 +1,182 @@
+package secrethub
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+)
+
+// ClientSide returns a Client that performs the operations that are implemented on
+// the client side, such as rotating secrets, with the requests of the given client.
+// All other calls are passed on to the given client. This allows other implementations
+// of Client, such as fakes, to perform these operations in the same way as the client
+// that is returned by NewClient.
+//
+// The client-side operations are:
+//   - DirService: Copy and Move
+//   - RepoService: Backup and Restore
+//   - SecretService: Move, PruneVersions, Rotate and Watch
+//   - SecretVersionService: Diff and Rollback
+func ClientSide(client Client) Client {
+	return clientSide{
+		Client: client,
+	}
+}
+
+// clientSide overrides the client-side operations of a Client.
+type clientSide struct {
+	Client
+}
+
+// Dirs returns a DirService of which the client-side operations use the client.
+func (c clientSide) Dirs() DirService {
+	return clientSideDirService{
+		DirService: c.Client.Dirs(),
+		client:     c.Client,
+	}
+}
+
+// Repos returns a RepoService of which the client-side operations use the client.
+func (c clientSide) Repos() RepoService {
+	return clientSideRepoService{
+		RepoService: c.Client.Repos(),
+		client:      c.Client,
+	}
+}
+
+// Secrets returns a SecretService of which the client-side operations use the client.
+func (c clientSide) Secrets() SecretService {
+	return clientSideSecretService{
+		SecretService: c.Client.Secrets(),
+		client:        c.Client,
+	}
+}
+
+// clientSideDirService overrides the client-side operations of a DirService.
+type clientSideDirService struct {
+	DirService
+	client Client
+}
+
+// Copy copies the directory at path src to path dst. See DirService.Copy.
+func (s clientSideDirService) Copy(src, dst string, options CopyOptions) (*CopyResult, error) {
+	return s.CopyContext(context.Background(), src, dst, options)
+}
+
+// CopyContext is the same as Copy, but uses the given context for all requests.
+func (s clientSideDirService) CopyContext(ctx context.Context, src, dst string, options CopyOptions) (*CopyResult, error) {
+	return copyDir(ctx, s.client, src, dst, options)
+}
+
+// Move moves the directory at path src to path dst. See DirService.Move.
+func (s clientSideDirService) Move(src, dst string, options MoveOptions) (*MoveResult, error) {
+	return s.MoveContext(context.Background(), src, dst, options)
+}
+
+// MoveContext is the same as Move, but uses the given context for all requests.
+func (s clientSideDirService) MoveContext(ctx context.Context, src, dst string, options MoveOptions) (*MoveResult, error) {
+	return moveDir(ctx, s.client, src, dst, options)
+}
+
+// clientSideRepoService overrides the client-side operations of a RepoService.
+type clientSideRepoService struct {
+	RepoService
+	client Client
+}
+
+// Backup writes a backup of the repository at the given path to w. See RepoService.Backup.
+func (s clientSideRepoService) Backup(path string, w io.Writer, passphrase []byte) error {
+	return s.BackupContext(context.Background(), path, w, passphrase)
+}
+
+// BackupContext is the same as Backup, but uses the given context for all requests.
+func (s clientSideRepoService) BackupContext(ctx context.Context, path string, w io.Writer, passphrase []byte) error {
+	return backupRepo(ctx, s.client, path, w, passphrase)
+}
+
+// Restore restores the backup in r in the repository at the given path. See RepoService.Restore.
+func (s clientSideRepoService) Restore(path string, r io.Reader, passphrase []byte) (*RestoreResult, error) {
+	return s.RestoreContext(context.Background(), path, r, passphrase)
+}
+
+// RestoreContext is the same as Restore, but uses the given context for all requests.
+func (s clientSideRepoService) RestoreContext(ctx context.Context, path string, r io.Reader, passphrase []byte) (*RestoreResult, error) {
+	return restoreRepo(ctx, s.client, path, r, passphrase)
+}
+
+// clientSideSecretService overrides the client-side operations of a SecretService.
+type clientSideSecretService struct {
+	SecretService
+	client Client
+}
+
+// Move moves the secret at path src to path dst. See SecretService.Move.
+func (s clientSideSecretService) Move(src, dst string, options MoveOptions) (*MoveResult, error) {
+	return s.MoveContext(context.Background(), src, dst, options)
+}
+
+// MoveContext is the same as Move, but uses the given context for all requests.
+func (s clientSideSecretService) MoveContext(ctx context.Context, src, dst string, options MoveOptions) (*MoveResult, error) {
+	return moveSecret(ctx, s.client, src, dst, options)
+}
+
+// PruneVersions deletes the versions that are not kept according to the policy. See SecretService.PruneVersions.
+func (s clientSideSecretService) PruneVersions(path string, policy RetentionPolicy) (*PruneReport, error) {
+	return s.PruneVersionsContext(context.Background(), path, policy)
+}
+
+// PruneVersionsContext is the same as PruneVersions, but uses the given context for all requests.
+func (s clientSideSecretService) PruneVersionsContext(ctx context.Context, path string, policy RetentionPolicy) (*PruneReport, error) {
+	return pruneVersions(ctx, s.client, path, policy)
+}
+
+// Rotate rotates the secret at the given path. See SecretService.Rotate.
+func (s clientSideSecretService) Rotate(path string, policy RotatePolicy) (*RotateResult, error) {
+	return s.RotateContext(context.Background(), path, policy)
+}
+
+// RotateContext is the same as Rotate, but uses the given context for all requests.
+func (s clientSideSecretService) RotateContext(ctx context.Context, path string, policy RotatePolicy) (*RotateResult, error) {
+	return rotateSecret(ctx, s.client, path, policy)
+}
+
+// Watch polls the latest versions of the secrets at the given paths. See SecretService.Watch.
+func (s clientSideSecretService) Watch(ctx context.Context, paths []string, interval time.Duration, withData bool) (<-chan WatchEvent, error) {
+	return watchSecrets(ctx, s.client, paths, interval, withData)
+}
+
+// Versions returns a SecretVersionService of which the client-side operations use the client.
+func (s clientSideSecretService) Versions() SecretVersionService {
+	return clientSideSecretVersionService{
+		SecretVersionService: s.SecretService.Versions(),
+		client:               s.client,
+	}
+}
+
+// clientSideSecretVersionService overrides the client-side operations of a SecretVersionService.
+type clientSideSecretVersionService struct {
+	SecretVersionService
+	client Client
+}
+
+// Diff compares two versions of the secret at the given path. See SecretVersionService.Diff.
+func (s clientSideSecretVersionService) Diff(path string, from, to int, redact bool) (*VersionDiff, error) {
+	return s.DiffContext(context.Background(), path, from, to, redact)
+}
+
+// DiffContext is the same as Diff, but uses the given context for all requests.
+func (s clientSideSecretVersionService) DiffContext(ctx context.Context, path string, from, to int, redact bool) (*VersionDiff, error) {
+	return diffVersions(ctx, s.client, path, from, to, redact)
+}
+
+// Rollback writes the value of the given version as a new version. See SecretVersionService.Rollback.
+func (s clientSideSecretVersionService) Rollback(path string, version int) (*api.SecretVersion, error) {
+	return s.RollbackContext(context.Background(), path, version)
+}
+
+// RollbackContext is the same as Rollback, but uses the given context for all requests.
+func (s clientSideSecretVersionService) RollbackContext(ctx context.Context, path string, version int) (*api.SecretVersion, error) {
+	return rollbackVersion(ctx, s.client, path, version)
+}
@@ -0,0 +1,603 @@
+package secrethub_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/memclient"
+)
+
+// newRepo returns a client of a new in-memory store, signed up as dev1, with the repository dev1/repo.
+func newRepo(t *testing.T) *memclient.Client {
+	client, err := memclient.New("dev1")
+	assert.OK(t, err)
+
+	_, err = client.Repos().Create("dev1/repo")
+	assert.OK(t, err)
+
+	return client
+}
+
+func TestClientSide_BackupRestore(t *testing.T) {
+	// Arrange
+	client := secrethub.ClientSide(newRepo(t))
+
+	_, err := client.Dirs().Create("dev1/repo/db")
+	assert.OK(t, err)
+	_, err = client.Dirs().Create("dev1/repo/db/prod")
+	assert.OK(t, err)
+
+	_, err = client.Secrets().Write("dev1/repo/api_key", []byte("key"))
+	assert.OK(t, err)
+	_, err = client.Secrets().Write("dev1/repo/db/prod/password", []byte("old"))
+	assert.OK(t, err)
+	_, err = client.Secrets().Write("dev1/repo/db/prod/password", []byte("new"))
+	assert.OK(t, err)
+
+	passphrase := []byte("correct horse battery staple")
+
+	// Act
+	var backup bytes.Buffer
+	err = client.Repos().Backup("dev1/repo", &backup, passphrase)
+	assert.OK(t, err)
+
+	result, err := client.Repos().Restore("dev1/restored", bytes.NewReader(backup.Bytes()), passphrase)
+	assert.OK(t, err)
+
+	versions, err := client.Secrets().Versions().ListWithData("dev1/restored/db/prod/password")
+	assert.OK(t, err)
+
+	apiKey, err := client.Secrets().Versions().GetWithData("dev1/restored/api_key")
+	assert.OK(t, err)
+
+	_, errPassphrase := client.Repos().Restore("dev1/restored", bytes.NewReader(backup.Bytes()), []byte("wrong"))
+	_, errInvalid := client.Repos().Restore("dev1/restored", bytes.NewReader([]byte("{}")), passphrase)
+
+	// Assert
+	assert.Equal(t, result, &secrethub.RestoreResult{
+		RepoCreated: true,
+		Dirs:        2,
+		Secrets:     2,
+		Versions:    3,
+	})
+	assert.Equal(t, len(versions), 2)
+	assert.Equal(t, versions[0].Data, []byte("old"))
+	assert.Equal(t, versions[1].Data, []byte("new"))
+	assert.Equal(t, apiKey.Data, []byte("key"))
+	assert.Equal(t, errPassphrase, secrethub.ErrBackupDecryptionFailed)
+	assert.Equal(t, errInvalid, secrethub.ErrInvalidBackup)
+}
+
+func TestClientSide_SecretMove(t *testing.T) {
+	// Arrange
+	client := secrethub.ClientSide(newRepo(t))
+
+	_, err := client.Dirs().Create("dev1/repo/dir")
+	assert.OK(t, err)
+	_, err = client.Secrets().Write("dev1/repo/secret", []byte("v1"))
+	assert.OK(t, err)
+	_, err = client.Secrets().Write("dev1/repo/secret", []byte("v2"))
+	assert.OK(t, err)
+	_, err = client.Secrets().Write("dev1/repo/other", []byte("other"))
+	assert.OK(t, err)
+
+	// Act
+	dryRun, err := client.Secrets().Move("dev1/repo/secret", "dev1/repo/dir/moved", secrethub.MoveOptions{DryRun: true})
+	assert.OK(t, err)
+
+	existsAfterDryRun, err := client.Secrets().Exists("dev1/repo/dir/moved")
+	assert.OK(t, err)
+
+	result, err := client.Secrets().Move("dev1/repo/secret", "dev1/repo/dir/moved", secrethub.MoveOptions{})
+	assert.OK(t, err)
+
+	versions, err := client.Secrets().Versions().ListWithData("dev1/repo/dir/moved")
+	assert.OK(t, err)
+
+	srcExists, err := client.Secrets().Exists("dev1/repo/secret")
+	assert.OK(t, err)
+
+	latestOnly, err := client.Secrets().Move("dev1/repo/dir/moved", "dev1/repo/latest", secrethub.MoveOptions{LatestOnly: true})
+	assert.OK(t, err)
+
+	latest, err := client.Secrets().Versions().ListWithData("dev1/repo/latest")
+	assert.OK(t, err)
+
+	_, errExists := client.Secrets().Move("dev1/repo/latest", "dev1/repo/other", secrethub.MoveOptions{})
+	_, errDirExists := client.Secrets().Move("dev1/repo/latest", "dev1/repo/dir", secrethub.MoveOptions{})
+	_, errSame := client.Secrets().Move("dev1/repo/latest", "dev1/repo/LATEST", secrethub.MoveOptions{})
+	_, errNoParent := client.Secrets().Move("dev1/repo/latest", "dev1/repo/missing/latest", secrethub.MoveOptions{})
+	_, errNotFound := client.Secrets().Move("dev1/repo/missing", "dev1/repo/new", secrethub.MoveOptions{})
+
+	// Assert
+	expected := &secrethub.MoveResult{
+		Secrets: []secrethub.MovedSecret{{Src: "dev1/repo/secret", Dst: "dev1/repo/dir/moved", Versions: 2}},
+	}
+	assert.Equal(t, dryRun, expected)
+	assert.Equal(t, existsAfterDryRun, false)
+	assert.Equal(t, result, expected)
+	assert.Equal(t, len(versions), 2)
+	assert.Equal(t, versions[0].Data, []byte("v1"))
+	assert.Equal(t, versions[1].Data, []byte("v2"))
+	assert.Equal(t, srcExists, false)
+	assert.Equal(t, latestOnly.Secrets[0].Versions, 1)
+	assert.Equal(t, len(latest), 1)
+	assert.Equal(t, latest[0].Data, []byte("v2"))
+	assert.Equal(t, errExists, secrethub.ErrMoveDestinationExists("dev1/repo/other"))
+	assert.Equal(t, errDirExists, secrethub.ErrMoveDestinationExists("dev1/repo/dir"))
+	assert.Equal(t, errSame, secrethub.ErrMoveSameLocation)
+	assert.Equal(t, errNoParent, api.ErrDirNotFound)
+	assert.Equal(t, errNotFound, api.ErrSecretNotFound)
+}
+
+func TestClientSide_DirMove(t *testing.T) {
+	// Arrange
+	client := secrethub.ClientSide(newRepo(t))
+
+	_, err := client.Dirs().Create("dev1/repo/app")
+	assert.OK(t, err)
+	_, err = client.Dirs().Create("dev1/repo/app/db")
+	assert.OK(t, err)
+	_, err = client.Dirs().Create("dev1/repo/archive")
+	assert.OK(t, err)
+	_, err = client.Secrets().Write("dev1/repo/app/key", []byte("key"))
+	assert.OK(t, err)
+	_, err = client.Secrets().Write("dev1/repo/app/db/password", []byte("old"))
+	assert.OK(t, err)
+	_, err = client.Secrets().Write("dev1/repo/app/db/password", []byte("new"))
+	assert.OK(t, err)
+
+	// Act
+	dryRun, err := client.Dirs().Move("dev1/repo/app", "dev1/repo/archive/app", secrethub.MoveOptions{DryRun: true})
+	assert.OK(t, err)
+
+	_, errDryRun := client.Dirs().GetTree("dev1/repo/archive/app", -1, false)
+
+	result, err := client.Dirs().Move("dev1/repo/app", "dev1/repo/archive/app", secrethub.MoveOptions{})
+	assert.OK(t, err)
+
+	versions, err := client.Secrets().Versions().ListWithData("dev1/repo/archive/app/db/password")
+	assert.OK(t, err)
+
+	_, errSrc := client.Dirs().GetTree("dev1/repo/app", -1, false)
+
+	_, errIntoItself := client.Dirs().Move("dev1/repo/archive", "dev1/repo/archive/app/archive", secrethub.MoveOptions{})
+	_, errRoot := client.Dirs().Move("dev1/repo", "dev1/repo/archive/repo", secrethub.MoveOptions{})
+	_, errExists := client.Dirs().Move("dev1/repo/archive/app/db", "dev1/repo/archive", secrethub.MoveOptions{})
+
+	// Assert
+	expected := &secrethub.MoveResult{
+		Dirs: []string{"dev1/repo/archive/app", "dev1/repo/archive/app/db"},
+		Secrets: []secrethub.MovedSecret{
+			{Src: "dev1/repo/app/key", Dst: "dev1/repo/archive/app/key", Versions: 1},
+			{Src: "dev1/repo/app/db/password", Dst: "dev1/repo/archive/app/db/password", Versions: 2},
+		},
+	}
+	assert.Equal(t, dryRun, expected)
+	assert.Equal(t, errDryRun, api.ErrDirNotFound)
+	assert.Equal(t, result, expected)
+	assert.Equal(t, len(versions), 2)
+	assert.Equal(t, versions[1].Data, []byte("new"))
+	assert.Equal(t, errSrc, api.ErrDirNotFound)
+	assert.Equal(t, errIntoItself, secrethub.ErrMoveIntoItself("dev1/repo/archive"))
+	assert.Equal(t, errRoot, api.ErrCannotRemoveRootDir)
+	assert.Equal(t, errExists, secrethub.ErrMoveDestinationExists("dev1/repo/archive"))
+}
+
+func TestClientSide_DirMove_AccessRules(t *testing.T) {
+	// Arrange
+	dev1 := newRepo(t)
+	client := secrethub.ClientSide(dev1)
+
+	dev2 := dev1.Store().NewClient()
+	_, err := dev2.Users().Create("dev2", "dev2@example.com", "Test User")
+	assert.OK(t, err)
+	_, err = dev1.Repos().Users().Invite("dev1/repo", "dev2")
+	assert.OK(t, err)
+
+	_, err = client.Dirs().Create("dev1/repo/app")
+	assert.OK(t, err)
+	_, err = client.Dirs().Create("dev1/repo/app/db")
+	assert.OK(t, err)
+	_, err = client.Secrets().Write("dev1/repo/app/db/password", []byte("password"))
+	assert.OK(t, err)
+	_, err = client.AccessRules().Set("dev1/repo/app/db", api.PermissionRead, "dev2")
+	assert.OK(t, err)
+
+	// Act
+	result, err := client.Dirs().Move("dev1/repo/app", "dev1/repo/moved", secrethub.MoveOptions{})
+	assert.OK(t, err)
+
+	rule, err := client.AccessRules().Get("dev1/repo/moved/db", "dev2")
+	assert.OK(t, err)
+
+	version, err := dev2.Secrets().Versions().GetWithData("dev1/repo/moved/db/password")
+	assert.OK(t, err)
+
+	// Assert
+	assert.Equal(t, result.AccessRules, []secrethub.MovedAccessRule{
+		{Path: "dev1/repo/moved/db", AccountName: "dev2", Permission: api.PermissionRead},
+	})
+	assert.Equal(t, rule.Permission, api.PermissionRead)
+	assert.Equal(t, version.Data, []byte("password"))
+}
+
+// failingWriteClient is a client of which writing a secret fails after a number of writes.
+type failingWriteClient struct {
+	*memclient.Client
+	writes int
+	err    error
+}
+
+func (c *failingWriteClient) Secrets() secrethub.SecretService {
+	return failingWriteSecretService{SecretService: c.Client.Secrets(), client: c}
+}
+
+type failingWriteSecretService struct {
+	secrethub.SecretService
+	client *failingWriteClient
+}
+
+func (s failingWriteSecretService) WriteContext(ctx context.Context, path string, data []byte) (*api.SecretVersion, error) {
+	if s.client.writes == 0 {
+		return nil, s.client.err
+	}
+	s.client.writes--
+	return s.SecretService.WriteContext(ctx, path, data)
+}
+
+func TestClientSide_DirMove_Rollback(t *testing.T) {
+	// Arrange
+	client := newRepo(t)
+
+	_, err := client.Dirs().Create("dev1/repo/app")
+	assert.OK(t, err)
+	_, err = client.Secrets().Write("dev1/repo/app/a", []byte("a"))
+	assert.OK(t, err)
+	_, err = client.Secrets().Write("dev1/repo/app/b", []byte("b"))
+	assert.OK(t, err)
+
+	failing := &failingWriteClient{Client: client, writes: 1, err: api.ErrForbidden}
+
+	// Act
+	_, errMove := secrethub.ClientSide(failing).Dirs().Move("dev1/repo/app", "dev1/repo/moved", secrethub.MoveOptions{})
+
+	_, errDst := client.Dirs().GetTree("dev1/repo/moved", -1, false)
+
+	src, err := client.Dirs().GetTree("dev1/repo/app", -1, false)
+	assert.OK(t, err)
+
+	// Assert
+	assert.Equal(t, errMove, api.ErrForbidden)
+	assert.Equal(t, errDst, api.ErrDirNotFound)
+	assert.Equal(t, src.SecretCount(), 2)
+}
+
+// racingDirClient is a client of which a directory is created by someone else
+// right before it creates the same directory itself.
+type racingDirClient struct {
+	*memclient.Client
+}
+
+func (c racingDirClient) Dirs() secrethub.DirService {
+	return racingDirService{DirService: c.Client.Dirs()}
+}
+
+type racingDirService struct {
+	secrethub.DirService
+}
+
+func (s racingDirService) CreateContext(ctx context.Context, path string) (*api.Dir, error) {
+	_, err := s.DirService.CreateContext(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	return s.DirService.CreateContext(ctx, path)
+}
+
+func TestClientSide_DirMove_ConcurrentDestination(t *testing.T) {
+	// Arrange
+	client := newRepo(t)
+
+	_, err := client.Dirs().Create("dev1/repo/app")
+	assert.OK(t, err)
+	_, err = client.Secrets().Write("dev1/repo/app/a", []byte("a"))
+	assert.OK(t, err)
+
+	// Act
+	_, errMove := secrethub.ClientSide(racingDirClient{Client: client}).Dirs().Move("dev1/repo/app", "dev1/repo/moved", secrethub.MoveOptions{})
+
+	_, errDst := client.Dirs().GetTree("dev1/repo/moved", -1, false)
+
+	src, err := client.Dirs().GetTree("dev1/repo/app", -1, false)
+	assert.OK(t, err)
+
+	// Assert
+	assert.Equal(t, errMove, api.ErrDirAlreadyExists)
+	assert.OK(t, errDst)
+	assert.Equal(t, src.SecretCount(), 1)
+}
+
+func TestClientSide_Copy(t *testing.T) {
+	cases := map[string]struct {
+		options          secrethub.CopyOptions
+		expected         *secrethub.CopyResult
+		expectedVersions []string
+		expectedExisting []string
+		expectedSteps    []secrethub.CopyAction
+	}{
+		"default": {
+			expected: &secrethub.CopyResult{
+				Dirs:    []string{"dev1/prod/app/db"},
+				Created: []string{"dev1/prod/app/db/password"},
+				Skipped: []string{"dev1/prod/app/key"},
+			},
+			expectedVersions: []string{"old", "new"},
+			expectedExisting: []string{"existing"},
+			expectedSteps: []secrethub.CopyAction{
+				secrethub.CopyActionCreateDir,
+				secrethub.CopyActionSkip,
+				secrethub.CopyActionCreate,
+			},
+		},
+		"latest only, overwrite and empty dirs": {
+			options: secrethub.CopyOptions{
+				LatestOnly:       true,
+				Overwrite:        true,
+				IncludeEmptyDirs: true,
+			},
+			expected: &secrethub.CopyResult{
+				Dirs:        []string{"dev1/prod/app/db", "dev1/prod/app/empty"},
+				Created:     []string{"dev1/prod/app/db/password"},
+				Overwritten: []string{"dev1/prod/app/key"},
+			},
+			expectedVersions: []string{"new"},
+			expectedExisting: []string{"existing", "key"},
+			expectedSteps: []secrethub.CopyAction{
+				secrethub.CopyActionCreateDir,
+				secrethub.CopyActionCreateDir,
+				secrethub.CopyActionOverwrite,
+				secrethub.CopyActionCreate,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			// Arrange
+			client := secrethub.ClientSide(newRepo(t))
+
+			_, err := client.Repos().Create("dev1/prod")
+			assert.OK(t, err)
+
+			for _, dir := range []string{"dev1/repo/app", "dev1/repo/app/db", "dev1/repo/app/empty"} {
+				_, err = client.Dirs().Create(dir)
+				assert.OK(t, err)
+			}
+			_, err = client.Secrets().Write("dev1/repo/app/key", []byte("key"))
+			assert.OK(t, err)
+			_, err = client.Secrets().Write("dev1/repo/app/db/password", []byte("old"))
+			assert.OK(t, err)
+			_, err = client.Secrets().Write("dev1/repo/app/db/password", []byte("new"))
+			assert.OK(t, err)
+
+			// The destination exists, but only partially.
+			_, err = client.Dirs().Create("dev1/prod/app")
+			assert.OK(t, err)
+			_, err = client.Secrets().Write("dev1/prod/app/KEY", []byte("existing"))
+			assert.OK(t, err)
+
+			var steps []secrethub.CopyAction
+			tc.options.Progress = func(progress secrethub.CopyProgress) {
+				assert.Equal(t, progress.Done, len(steps)+1)
+				assert.Equal(t, progress.Total, len(tc.expectedSteps))
+				steps = append(steps, progress.Action)
+			}
+
+			// Act
+			result, err := client.Dirs().Copy("dev1/repo/app", "dev1/prod/app", tc.options)
+			assert.OK(t, err)
+
+			versions, err := client.Secrets().Versions().ListWithData("dev1/prod/app/db/password")
+			assert.OK(t, err)
+
+			existing, err := client.Secrets().Versions().ListWithData("dev1/prod/app/key")
+			assert.OK(t, err)
+
+			// Assert
+			assert.Equal(t, result, tc.expected)
+			assert.Equal(t, steps, tc.expectedSteps)
+
+			actualVersions := make([]string, len(versions))
+			for i, version := range versions {
+				actualVersions[i] = string(version.Data)
+			}
+			assert.Equal(t, actualVersions, tc.expectedVersions)
+
+			actualExisting := make([]string, len(existing))
+			for i, version := range existing {
+				actualExisting[i] = string(version.Data)
+			}
+			assert.Equal(t, actualExisting, tc.expectedExisting)
+		})
+	}
+}
+
+func TestClientSide_Copy_IntoItself(t *testing.T) {
+	// Arrange
+	client := secrethub.ClientSide(newRepo(t))
+
+	_, err := client.Dirs().Create("dev1/repo/app")
+	assert.OK(t, err)
+
+	// Act
+	_, errSame := client.Dirs().Copy("dev1/repo/app", "dev1/repo/APP", secrethub.CopyOptions{})
+	_, errSub := client.Dirs().Copy("dev1/repo/app", "dev1/repo/app/copy", secrethub.CopyOptions{})
+	_, errNotFound := client.Dirs().Copy("dev1/repo/missing", "dev1/repo/copy", secrethub.CopyOptions{})
+
+	// Assert
+	assert.Equal(t, errSame, secrethub.ErrCopyIntoItself("dev1/repo/app"))
+	assert.Equal(t, errSub, secrethub.ErrCopyIntoItself("dev1/repo/app"))
+	assert.Equal(t, errNotFound, api.ErrDirNotFound)
+}
+
+func TestClientSide_DiffRollback(t *testing.T) {
+	// Arrange
+	client := secrethub.ClientSide(newRepo(t))
+
+	_, err := client.Secrets().Write("dev1/repo/config", []byte(`{"user": "admin", "password": "good"}`))
+	assert.OK(t, err)
+	_, err = client.Secrets().Write("dev1/repo/config", []byte(`{"user": "admin", "password": "bad"}`))
+	assert.OK(t, err)
+
+	// Act
+	diff, err := client.Secrets().Versions().Diff("dev1/repo/config", 1, 2, true)
+	assert.OK(t, err)
+
+	rolledBack, err := client.Secrets().Versions().Rollback("dev1/repo/config", 1)
+	assert.OK(t, err)
+
+	latest, err := client.Secrets().Versions().GetWithData("dev1/repo/config")
+	assert.OK(t, err)
+
+	after, err := client.Secrets().Versions().Diff("dev1/repo/config", 1, 3, false)
+	assert.OK(t, err)
+
+	_, errLatest := client.Secrets().Versions().Rollback("dev1/repo/config", 3)
+	_, errNotFound := client.Secrets().Versions().Rollback("dev1/repo/config", 9)
+	_, errDiffNotFound := client.Secrets().Versions().Diff("dev1/repo/config", 1, 9, true)
+
+	// Assert
+	assert.Equal(t, diff, &secrethub.VersionDiff{
+		Path:     "dev1/repo/config",
+		From:     1,
+		To:       2,
+		Format:   secrethub.DiffFormatJSON,
+		Redacted: true,
+		Changes: []secrethub.DiffChange{
+			{Op: secrethub.DiffOpChange, Key: "password", Old: secrethub.RedactedValue, New: secrethub.RedactedValue},
+		},
+	})
+	assert.Equal(t, rolledBack.Version, 3)
+	assert.Equal(t, latest.Data, []byte(`{"user": "admin", "password": "good"}`))
+	assert.Equal(t, after.Equal(), true)
+	assert.Equal(t, errLatest, secrethub.ErrRollbackToLatest(3))
+	assert.Equal(t, errNotFound, api.ErrSecretVersionNotFound)
+	assert.Equal(t, errDiffNotFound, api.ErrSecretVersionNotFound)
+}
+
+func TestClientSide_PruneVersions(t *testing.T) {
+	// Arrange
+	client := secrethub.ClientSide(newRepo(t))
+
+	_, err := client.Dirs().Create("dev1/repo/app")
+	assert.OK(t, err)
+	for i := 0; i < 4; i++ {
+		_, err = client.Secrets().Write("dev1/repo/app/key", []byte("key"))
+		assert.OK(t, err)
+		_, err = client.Secrets().Write("dev1/repo/password", []byte("password"))
+		assert.OK(t, err)
+	}
+	_, err = client.Secrets().Write("dev1/repo/app/single", []byte("single"))
+	assert.OK(t, err)
+
+	// Act
+	dryRun, err := client.Secrets().PruneVersions("dev1/repo", secrethub.RetentionPolicy{KeepLast: 2, DryRun: true})
+	assert.OK(t, err)
+
+	afterDryRun, err := client.Secrets().Versions().ListWithoutData("dev1/repo/password")
+	assert.OK(t, err)
+
+	secret, err := client.Secrets().PruneVersions("dev1/repo/password", secrethub.RetentionPolicy{KeepLast: 3})
+	assert.OK(t, err)
+
+	dir, err := client.Secrets().PruneVersions("dev1/repo", secrethub.RetentionPolicy{KeepLatest: true})
+	assert.OK(t, err)
+
+	password, err := client.Secrets().Versions().ListWithoutData("dev1/repo/password")
+	assert.OK(t, err)
+
+	_, errEmpty := client.Secrets().PruneVersions("dev1/repo", secrethub.RetentionPolicy{})
+	_, errNotFound := client.Secrets().PruneVersions("dev1/repo/missing", secrethub.RetentionPolicy{KeepLatest: true})
+
+	// Assert
+	assert.Equal(t, dryRun, &secrethub.PruneReport{
+		DryRun: true,
+		Secrets: []secrethub.PrunedSecret{
+			{Path: "dev1/repo/password", Kept: []int{3, 4}, Deleted: []int{1, 2}},
+			{Path: "dev1/repo/app/key", Kept: []int{3, 4}, Deleted: []int{1, 2}},
+			{Path: "dev1/repo/app/single", Kept: []int{1}},
+		},
+	})
+	assert.Equal(t, dryRun.Deleted(), 4)
+	assert.Equal(t, len(afterDryRun), 4)
+	assert.Equal(t, secret, &secrethub.PruneReport{
+		Secrets: []secrethub.PrunedSecret{
+			{Path: "dev1/repo/password", Kept: []int{2, 3, 4}, Deleted: []int{1}},
+		},
+	})
+	assert.Equal(t, dir.Deleted(), 5)
+	assert.Equal(t, len(password), 1)
+	assert.Equal(t, password[0].Version, 4)
+	assert.Equal(t, errEmpty, secrethub.ErrEmptyRetentionPolicy)
+	assert.Equal(t, errNotFound, api.ErrDirNotFound)
+}
+
+func TestClientSide_Watch(t *testing.T) {
+	// Arrange
+	client := secrethub.ClientSide(newRepo(t))
+
+	_, err := client.Secrets().Write("dev1/repo/secret", []byte("v1"))
+	assert.OK(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Act
+	events, err := client.Secrets().Watch(ctx, []string{"dev1/repo/secret", "dev1/repo/SECRET", "dev1/repo/missing"}, 10*time.Millisecond, true)
+	assert.OK(t, err)
+
+	initial := []secrethub.WatchEvent{<-events, <-events, <-events}
+
+	_, err = client.Secrets().Write("dev1/repo/secret", []byte("v2"))
+	assert.OK(t, err)
+
+	// Polling the missing secret fails again in the meantime.
+	var changed []secrethub.WatchEvent
+	for len(changed) < 2 {
+		event := <-events
+		if event.Err == nil {
+			changed = append(changed, event)
+		}
+	}
+
+	cancel()
+	for range events {
+	}
+
+	_, errNoPaths := client.Secrets().Watch(ctx, nil, time.Second, false)
+	_, errVersion := client.Secrets().Watch(ctx, []string{"dev1/repo/secret:1"}, time.Second, false)
+
+	// Assert
+	assert.Equal(t, initial[0].Path, "dev1/repo/secret")
+	assert.Equal(t, initial[0].Previous, 0)
+	assert.Equal(t, initial[0].Version.Data, []byte("v1"))
+	assert.Equal(t, initial[1].Path, "dev1/repo/SECRET")
+	assert.Equal(t, initial[1].Version.Version, 1)
+	assert.Equal(t, initial[2].Path, "dev1/repo/missing")
+	assert.Equal(t, initial[2].Err, api.ErrSecretNotFound)
+
+	assert.Equal(t, changed[0].Path, "dev1/repo/secret")
+	assert.Equal(t, changed[0].Previous, 1)
+	assert.Equal(t, changed[0].Version.Version, 2)
+	assert.Equal(t, changed[0].Version.Data, []byte("v2"))
+	assert.Equal(t, changed[1].Path, "dev1/repo/SECRET")
+
+	assert.Equal(t, errNoPaths, secrethub.ErrNoWatchPaths)
+	assert.Equal(t, errVersion, secrethub.ErrCannotWatchVersion)
+}
@@ -0,0 +1,46 @@
+package secrethub
+
+import (
+	"context"
+	"sync"
+)
+
+const (
+	// DefaultConcurrency defines the default number of requests
+	// that bulk operations make in parallel.
+	DefaultConcurrency = 8
+)
+
+// forEachConcurrently calls fn for every index in [0,n) using at most the given
+// number of goroutines. It returns when all calls have returned. When the context
+// is done, the remaining indices are not started.
+func forEachConcurrently(ctx context.Context, n int, concurrency int, fn func(i int)) {
+	if concurrency <= 0 {
+		concurrency = DefaultConcurrency
+	}
+	if concurrency > n {
+		concurrency = n
+	}
+
+	indices := make(chan int)
+	var wg sync.WaitGroup
+	wg.Add(concurrency)
+	for w := 0; w < concurrency; w++ {
+		go func() {
+			defer wg.Done()
+			for i := range indices {
+				fn(i)
+			}
+		}()
+	}
+
+	defer wg.Wait()
+	defer close(indices)
+	for i := 0; i < n; i++ {
+		select {
+		case indices <- i:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,258 @@
+package secrethub
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/errio"
+)
+
+// Errors
+var (
+	ErrCopyIntoItself = errClient.Code("copy_into_itself").ErrorPref("cannot copy directory %s into itself")
+)
+
+// CopyOptions configure a copy of a directory.
+type CopyOptions struct {
+	// LatestOnly only copies the latest version of every secret instead of all versions.
+	LatestOnly bool
+	// Overwrite writes the copied versions as new versions of secrets that already
+	// exist at the destination. By default, such secrets are skipped.
+	Overwrite bool
+	// IncludeEmptyDirs also creates directories that do not contain any secrets,
+	// directly or in one of their subdirectories. By default, they are not created.
+	IncludeEmptyDirs bool
+	// Progress is an optional callback that is called after every step of the copy.
+	Progress CopyProgressFunc
+}
+
+// CopyAction is a step of a copy.
+type CopyAction string
+
+// Copy actions.
+const (
+	CopyActionCreateDir CopyAction = "create_dir"
+	CopyActionCreate    CopyAction = "create"
+	CopyActionOverwrite CopyAction = "overwrite"
+	CopyActionSkip      CopyAction = "skip"
+)
+
+// CopyProgress describes a step of a copy that has been completed.
+type CopyProgress struct {
+	// Action is the step that has been completed.
+	Action CopyAction
+	// Src is the path of the copied directory or secret.
+	Src string
+	// Dst is the path of the copy.
+	Dst string
+	// Done is the number of completed steps, including this step.
+	Done int
+	// Total is the number of steps of the copy.
+	Total int
+}
+
+// CopyProgressFunc is called after every step of a copy.
+type CopyProgressFunc func(progress CopyProgress)
+
+// CopyResult describes the changes of a copy. All paths are absolute.
+type CopyResult struct {
+	// Dirs contains the paths of the directories that have been created, parents first.
+	Dirs []string
+	// Created contains the paths of the secrets that have been created.
+	Created []string
+	// Overwritten contains the paths of existing secrets to which the copied versions have been written.
+	Overwritten []string
+	// Skipped contains the paths of existing secrets that have not been written.
+	Skipped []string
+}
+
+// Copy copies the directory at path src with all of its subdirectories and secrets to path dst,
+// which can be in another repository. When the directory at dst already exists, the copied
+// contents are merged into it. The copied versions are encrypted for the accounts that have
+// access to the destination.
+func (s dirService) Copy(src, dst string, options CopyOptions) (*CopyResult, error) {
+	return s.CopyContext(context.Background(), src, dst, options)
+}
+
+// CopyContext is the same as Copy, but uses the given context for all requests.
+func (s dirService) CopyContext(ctx context.Context, src, dst string, options CopyOptions) (*CopyResult, error) {
+	return copyDir(ctx, clientAdapter{client: s.client}, src, dst, options)
+}
+
+// copyStep is a step of a copy.
+type copyStep struct {
+	action CopyAction
+	src    string
+	dst    string
+}
+
+// copyDir copies the directory at path src to path dst, using the client to read the tree
+// and the secrets and to create the directories and write the secrets. The latest versions
+// of the secrets are read in parallel. When the copy fails, the changes made before the
+// failure are kept and the returned result describes them.
+func copyDir(ctx context.Context, client Client, src, dst string, options CopyOptions) (*CopyResult, error) {
+	srcPath, err := api.NewDirPath(src)
+	if err != nil {
+		return nil, errio.Error(err)
+	}
+
+	dstPath, err := api.NewDirPath(dst)
+	if err != nil {
+		return nil, errio.Error(err)
+	}
+
+	srcLower := strings.ToLower(srcPath.Value())
+	dstLower := strings.ToLower(dstPath.Value())
+	if dstLower == srcLower || strings.HasPrefix(dstLower, srcLower+"/") {
+		return nil, ErrCopyIntoItself(srcPath.Value())
+	}
+
+	tree, err := client.Dirs().GetTreeContext(ctx, srcPath.Value(), -1, false)
+	if err != nil {
+		return nil, errio.Error(err)
+	}
+
+	// Names are case insensitive, so existing paths are compared in lowercase.
+	existingDirs := make(map[string]bool)
+	existingSecrets := make(map[string]bool)
+	dstTree, err := client.Dirs().GetTreeContext(ctx, dstPath.Value(), -1, false)
+	if err == nil {
+		existingDirs[""] = true
+		for _, dir := range dstTree.DirPaths() {
+			existingDirs[strings.ToLower(dir)] = true
+		}
+		for _, secret := range dstTree.SecretPaths() {
+			existingSecrets[strings.ToLower(secret)] = true
+		}
+	} else if err != api.ErrDirNotFound {
+		return nil, errio.Error(err)
+	}
+
+	var steps []copyStep
+	if !existingDirs[""] {
+		steps = append(steps, copyStep{action: CopyActionCreateDir, src: srcPath.Value(), dst: dstPath.Value()})
+	}
+
+	secretPaths := tree.SecretPaths()
+	nonEmptyDirs := make(map[string]bool)
+	for _, secretPath := range secretPaths {
+		elements := strings.Split(secretPath, "/")
+		for i := 1; i < len(elements); i++ {
+			nonEmptyDirs[strings.Join(elements[:i], "/")] = true
+		}
+	}
+
+	// Directories are created first, so that every secret can be written in its directory.
+	// The paths of the tree put every directory after its parent.
+	for _, dirPath := range tree.DirPaths() {
+		if !options.IncludeEmptyDirs && !nonEmptyDirs[dirPath] {
+			continue
+		}
+		if !existingDirs[strings.ToLower(dirPath)] {
+			steps = append(steps, copyStep{
+				action: CopyActionCreateDir,
+				src:    srcPath.Value() + "/" + dirPath,
+				dst:    dstPath.Value() + "/" + dirPath,
+			})
+		}
+	}
+
+	for _, secretPath := range secretPaths {
+		step := copyStep{
+			action: CopyActionCreate,
+			src:    srcPath.Value() + "/" + secretPath,
+			dst:    dstPath.Value() + "/" + secretPath,
+		}
+		if existingSecrets[strings.ToLower(secretPath)] {
+			step.action = CopyActionSkip
+			if options.Overwrite {
+				step.action = CopyActionOverwrite
+			}
+		}
+		steps = append(steps, step)
+	}
+
+	data, err := readCopyData(ctx, client, steps, options.LatestOnly)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &CopyResult{}
+	for i, step := range steps {
+		switch step.action {
+		case CopyActionCreateDir:
+			_, err = client.Dirs().CreateContext(ctx, step.dst)
+			if err != nil {
+				return result, errio.Error(err)
+			}
+			result.Dirs = append(result.Dirs, step.dst)
+		case CopyActionCreate, CopyActionOverwrite:
+			for _, versionData := range data[step.src] {
+				_, err = client.Secrets().WriteContext(ctx, step.dst, versionData)
+				if err != nil {
+					return result, errio.Error(err)
+				}
+			}
+			if step.action == CopyActionCreate {
+				result.Created = append(result.Created, step.dst)
+			} else {
+				result.Overwritten = append(result.Overwritten, step.dst)
+			}
+		case CopyActionSkip:
+			result.Skipped = append(result.Skipped, step.dst)
+		}
+
+		if options.Progress != nil {
+			options.Progress(CopyProgress{
+				Action: step.action,
+				Src:    step.src,
+				Dst:    step.dst,
+				Done:   i + 1,
+				Total:  len(steps),
+			})
+		}
+	}
+
+	return result, nil
+}
+
+// readCopyData returns the data of the versions, oldest first, of the secrets that are
+// written by the steps by their source paths. When latestOnly is set, only the latest
+// versions are returned and they are all read in parallel.
+func readCopyData(ctx context.Context, client Client, steps []copyStep, latestOnly bool) (map[string][][]byte, error) {
+	var paths []string
+	for _, step := range steps {
+		if step.action == CopyActionCreate || step.action == CopyActionOverwrite {
+			paths = append(paths, step.src)
+		}
+	}
+
+	data := make(map[string][][]byte, len(paths))
+	if latestOnly {
+		for _, result := range client.Secrets().Versions().GetManyWithDataContext(ctx, paths) {
+			if result.Err != nil {
+				return nil, errio.Error(result.Err)
+			}
+			data[result.Path] = [][]byte{result.Version.Data}
+		}
+		return data, nil
+	}
+
+	for _, path := range paths {
+		versions, err := client.Secrets().Versions().ListWithDataContext(ctx, path)
+		if err != nil {
+			return nil, errio.Error(err)
+		}
+
+		sort.Slice(versions, func(i, j int) bool {
+			return versions[i].Version < versions[j].Version
+		})
+
+		for _, version := range versions {
+			data[path] = append(data[path], version.Data)
+		}
+	}
+	return data, nil
+}
@@ -1,6 +1,8 @@
 package secrethub
 
 import (
+	"context"
+
 	"github.com/secrethub/secrethub-go/internals/api"
 	"github.com/secrethub/secrethub-go/internals/api/uuid"
 	"github.com/secrethub/secrethub-go/internals/crypto"
@@ -15,15 +17,15 @@ func (c *client) encryptDirFor(dir *api.Dir, accounts ...*api.Account) ([]api.En
 // encryptSecretFor encrypts the secret for every account.
 // The SecretKeys are retrieved from the API.
 // The keys are decrypted, then for every account this key is encrypted.
-func (c *client) encryptSecretFor(secret *api.Secret, accounts ...*api.Account) ([]api.SecretAccessRequest, error) {
+func (c *client) encryptSecretFor(ctx context.Context, secret *api.Secret, accounts ...*api.Account) ([]api.SecretAccessRequest, error) {
 	results := make([]api.SecretAccessRequest, len(accounts))
 
-	secretKeys, err := c.httpClient.ListSecretKeys(secret.BlindName)
+	secretKeys, err := c.httpClient.ListSecretKeys(ctx, secret.BlindName)
 	if err != nil {
 		return nil, errio.Error(err)
 	}
 
-	myKey, err := c.getAccountKey()
+	myKey, err := c.getAccountKey(ctx)
 	if err != nil {
 		return nil, errio.Error(err)
 	}
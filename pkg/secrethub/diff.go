@@ -0,0 +1,358 @@
+package secrethub
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/errio"
+	"github.com/secrethub/secrethub-go/internals/yaml"
+)
+
+const (
+	// RedactedValue replaces the values of lines and keys in a redacted diff.
+	RedactedValue = "<redacted>"
+
+	// maxLineDiffCells limits the size of the table that is used to find the longest
+	// common subsequence of the lines of two versions. When the changed parts of the
+	// versions are larger, all of their lines are reported as removed and added.
+	maxLineDiffCells = 4 * 1024 * 1024
+)
+
+// DiffFormat is the format of the values of the compared secret versions.
+type DiffFormat string
+
+// Diff formats. Text values are compared line by line and the values of
+// JSON and YAML objects are compared key by key. Binary values are only
+// compared as a whole.
+const (
+	DiffFormatText   DiffFormat = "text"
+	DiffFormatJSON   DiffFormat = "json"
+	DiffFormatYAML   DiffFormat = "yaml"
+	DiffFormatBinary DiffFormat = "binary"
+)
+
+// DiffOp is the kind of a change between two secret versions.
+type DiffOp string
+
+// Diff operations. Lines are only added or removed, keys are also changed.
+const (
+	DiffOpAdd    DiffOp = "add"
+	DiffOpRemove DiffOp = "remove"
+	DiffOpChange DiffOp = "change"
+)
+
+// VersionDiff describes the changes between two versions of a secret.
+type VersionDiff struct {
+	// Path is the path of the secret.
+	Path string
+	// From is the version the changes are made to.
+	From int
+	// To is the version that results from the changes.
+	To int
+	// Format is the format in which the values of the versions have been compared.
+	Format DiffFormat
+	// Redacted is true when the values of the changes are replaced by RedactedValue.
+	Redacted bool
+	// Changes contains the changes from version From to version To, in order.
+	Changes []DiffChange
+}
+
+// DiffChange is a changed line, key or binary value.
+type DiffChange struct {
+	// Op is the kind of change.
+	Op DiffOp
+	// Key is the key of a changed value of a JSON or YAML object.
+	// The keys of nested JSON objects are joined by dots.
+	Key string
+	// Line is the number of a changed line of a text value, in version From
+	// for removed lines and in version To for added lines.
+	Line int
+	// Old is the removed or changed line or value.
+	Old string
+	// New is the added or changed line or value.
+	New string
+}
+
+// Equal returns whether the compared versions have the same value.
+func (d *VersionDiff) Equal() bool {
+	return len(d.Changes) == 0
+}
+
+// String returns the diff in a human readable form. Removed lines and values
+// are prefixed with -, added lines and values with + and changed values with ~.
+func (d *VersionDiff) String() string {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "--- %s:%d\n+++ %s:%d\n", d.Path, d.From, d.Path, d.To)
+	for _, change := range d.Changes {
+		switch {
+		case d.Format == DiffFormatBinary:
+			buf.WriteString("binary values differ\n")
+		case d.Format == DiffFormatText && change.Op == DiffOpRemove:
+			fmt.Fprintf(&buf, "-%d: %s\n", change.Line, change.Old)
+		case d.Format == DiffFormatText:
+			fmt.Fprintf(&buf, "+%d: %s\n", change.Line, change.New)
+		case change.Op == DiffOpRemove:
+			fmt.Fprintf(&buf, "- %s: %s\n", change.Key, change.Old)
+		case change.Op == DiffOpAdd:
+			fmt.Fprintf(&buf, "+ %s: %s\n", change.Key, change.New)
+		default:
+			fmt.Fprintf(&buf, "~ %s: %s -> %s\n", change.Key, change.Old, change.New)
+		}
+	}
+	return buf.String()
+}
+
+// Diff compares two versions of the secret at the given path. When redact is set,
+// the diff only contains which lines or keys have changed and their values are
+// replaced by RedactedValue.
+func (s secretVersionService) Diff(path string, from, to int, redact bool) (*VersionDiff, error) {
+	return s.DiffContext(context.Background(), path, from, to, redact)
+}
+
+// DiffContext is the same as Diff, but uses the given context for all requests.
+func (s secretVersionService) DiffContext(ctx context.Context, path string, from, to int, redact bool) (*VersionDiff, error) {
+	return diffVersions(ctx, clientAdapter{client: s.client}, path, from, to, redact)
+}
+
+// diffVersions compares two versions of the secret at the given path, using the client
+// to read them.
+func diffVersions(ctx context.Context, client Client, path string, from, to int, redact bool) (*VersionDiff, error) {
+	secretPath, err := api.NewSecretPath(path)
+	if err != nil {
+		return nil, errio.Error(err)
+	}
+
+	fromPath, err := secretPath.AddVersion(from)
+	if err != nil {
+		return nil, errio.Error(err)
+	}
+
+	toPath, err := secretPath.AddVersion(to)
+	if err != nil {
+		return nil, errio.Error(err)
+	}
+
+	results := client.Secrets().Versions().GetManyWithDataContext(ctx, []string{fromPath.Value(), toPath.Value()})
+	for _, result := range results {
+		if result.Err != nil {
+			return nil, errio.Error(result.Err)
+		}
+	}
+
+	diff := Diff(results[0].Version.Data, results[1].Version.Data, redact)
+	diff.Path = secretPath.Value()
+	diff.From = from
+	diff.To = to
+	return diff, nil
+}
+
+// Diff compares two values of a secret. Values that are JSON objects or YAML mappings of
+// keys to scalar values are compared key by key, other text values line by line. When
+// redact is set, the values of the changes are replaced by RedactedValue. The Path, From
+// and To of the returned diff are not set.
+func Diff(from, to []byte, redact bool) *VersionDiff {
+	diff := &VersionDiff{
+		Redacted: redact,
+	}
+
+	if isBinary(from) || isBinary(to) {
+		diff.Format = DiffFormatBinary
+		if !bytes.Equal(from, to) {
+			diff.Changes = []DiffChange{{Op: DiffOpChange}}
+		}
+		return diff
+	}
+
+	fromJSON, errFrom := flattenJSON(from)
+	toJSON, errTo := flattenJSON(to)
+	if errFrom == nil && errTo == nil {
+		diff.Format = DiffFormatJSON
+		diff.Changes = diffKeys(fromJSON, toJSON)
+	} else if fromYAML, toYAML := decodeYAML(from), decodeYAML(to); fromYAML != nil && toYAML != nil {
+		diff.Format = DiffFormatYAML
+		diff.Changes = diffKeys(fromYAML, toYAML)
+	} else {
+		diff.Format = DiffFormatText
+		diff.Changes = diffLines(strings.Split(string(from), "\n"), strings.Split(string(to), "\n"))
+	}
+
+	if redact {
+		for i := range diff.Changes {
+			if diff.Changes[i].Op != DiffOpAdd {
+				diff.Changes[i].Old = RedactedValue
+			}
+			if diff.Changes[i].Op != DiffOpRemove {
+				diff.Changes[i].New = RedactedValue
+			}
+		}
+	}
+	return diff
+}
+
+// isBinary returns whether the value is not valid UTF-8 or contains a null byte.
+func isBinary(value []byte) bool {
+	return !utf8.Valid(value) || bytes.IndexByte(value, 0) >= 0
+}
+
+// diffKeys returns the changes between two sets of values, ordered by key.
+func diffKeys(from, to map[string]string) []DiffChange {
+	keys := make([]string, 0, len(from)+len(to))
+	for key := range from {
+		keys = append(keys, key)
+	}
+	for key := range to {
+		if _, ok := from[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	var changes []DiffChange
+	for _, key := range keys {
+		oldValue, inFrom := from[key]
+		newValue, inTo := to[key]
+		switch {
+		case !inTo:
+			changes = append(changes, DiffChange{Op: DiffOpRemove, Key: key, Old: oldValue})
+		case !inFrom:
+			changes = append(changes, DiffChange{Op: DiffOpAdd, Key: key, New: newValue})
+		case oldValue != newValue:
+			changes = append(changes, DiffChange{Op: DiffOpChange, Key: key, Old: oldValue, New: newValue})
+		}
+	}
+	return changes
+}
+
+// diffLines returns the lines that are removed from and added to the lines of from
+// to get the lines of to. It uses the longest common subsequence of the lines.
+func diffLines(from, to []string) []DiffChange {
+	// Lines that both start or end with do not change.
+	prefix := 0
+	for prefix < len(from) && prefix < len(to) && from[prefix] == to[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(from)-prefix && suffix < len(to)-prefix && from[len(from)-1-suffix] == to[len(to)-1-suffix] {
+		suffix++
+	}
+	a := from[prefix : len(from)-suffix]
+	b := to[prefix : len(to)-suffix]
+
+	var changes []DiffChange
+	remove := func(i int) {
+		changes = append(changes, DiffChange{Op: DiffOpRemove, Line: prefix + i + 1, Old: a[i]})
+	}
+	add := func(j int) {
+		changes = append(changes, DiffChange{Op: DiffOpAdd, Line: prefix + j + 1, New: b[j]})
+	}
+
+	if len(a)*len(b) > maxLineDiffCells {
+		for i := range a {
+			remove(i)
+		}
+		for j := range b {
+			add(j)
+		}
+		return changes
+	}
+
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:].
+	lcs := make([][]int32, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int32, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			remove(i)
+			i++
+		default:
+			add(j)
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		remove(i)
+	}
+	for ; j < len(b); j++ {
+		add(j)
+	}
+	return changes
+}
+
+// flattenJSON returns the values of a JSON object by their keys. The values of nested
+// objects are included with their keys joined by dots. All other values are encoded
+// as JSON. An error is returned when the value is not a JSON object.
+func flattenJSON(value []byte) (map[string]string, error) {
+	decoder := json.NewDecoder(bytes.NewReader(value))
+	decoder.UseNumber()
+
+	var object map[string]interface{}
+	err := decoder.Decode(&object)
+	if err != nil {
+		return nil, err
+	}
+	if object == nil || decoder.More() {
+		return nil, errors.New("not a JSON object")
+	}
+
+	values := make(map[string]string)
+	err = flattenJSONObject(values, object, "")
+	if err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+func flattenJSONObject(values map[string]string, object map[string]interface{}, prefix string) error {
+	for key, value := range object {
+		nested, ok := value.(map[string]interface{})
+		if ok && len(nested) > 0 {
+			err := flattenJSONObject(values, nested, prefix+key+".")
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		values[prefix+key] = string(encoded)
+	}
+	return nil
+}
+
+// decodeYAML returns the values of a YAML mapping of keys to scalar values by their keys.
+// It returns nil when the value is not such a mapping or contains no keys.
+func decodeYAML(value []byte) map[string]string {
+	values, err := yaml.Decode(value)
+	if err != nil || len(values) == 0 {
+		return nil
+	}
+	return values
+}
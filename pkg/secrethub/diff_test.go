@@ -0,0 +1,178 @@
+package secrethub
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/secrethub/secrethub-go/internals/assert"
+)
+
+func TestDiff(t *testing.T) {
+	cases := map[string]struct {
+		from     string
+		to       string
+		redact   bool
+		expected *VersionDiff
+	}{
+		"equal text": {
+			from: "value",
+			to:   "value",
+			expected: &VersionDiff{
+				Format: DiffFormatText,
+			},
+		},
+		"text": {
+			from: "line 1\nline 2\nline 3\nline 4",
+			to:   "line 1\nline 2b\nline 3\nline 4\nline 5",
+			expected: &VersionDiff{
+				Format: DiffFormatText,
+				Changes: []DiffChange{
+					{Op: DiffOpRemove, Line: 2, Old: "line 2"},
+					{Op: DiffOpAdd, Line: 2, New: "line 2b"},
+					{Op: DiffOpAdd, Line: 5, New: "line 5"},
+				},
+			},
+		},
+		"redacted text": {
+			from:   "password1",
+			to:     "password2",
+			redact: true,
+			expected: &VersionDiff{
+				Format:   DiffFormatText,
+				Redacted: true,
+				Changes: []DiffChange{
+					{Op: DiffOpRemove, Line: 1, Old: RedactedValue},
+					{Op: DiffOpAdd, Line: 1, New: RedactedValue},
+				},
+			},
+		},
+		"json": {
+			from: `{"user": "admin", "password": "old", "db": {"host": "localhost", "port": 5432}, "removed": true}`,
+			to:   `{"user": "admin", "password": "new", "db": {"host": "localhost", "port": 5433}, "added": [1, 2]}`,
+			expected: &VersionDiff{
+				Format: DiffFormatJSON,
+				Changes: []DiffChange{
+					{Op: DiffOpAdd, Key: "added", New: "[1,2]"},
+					{Op: DiffOpChange, Key: "db.port", Old: "5432", New: "5433"},
+					{Op: DiffOpChange, Key: "password", Old: `"old"`, New: `"new"`},
+					{Op: DiffOpRemove, Key: "removed", Old: "true"},
+				},
+			},
+		},
+		"redacted json": {
+			from:   `{"password": "old", "removed": 1}`,
+			to:     `{"password": "new"}`,
+			redact: true,
+			expected: &VersionDiff{
+				Format:   DiffFormatJSON,
+				Redacted: true,
+				Changes: []DiffChange{
+					{Op: DiffOpChange, Key: "password", Old: RedactedValue, New: RedactedValue},
+					{Op: DiffOpRemove, Key: "removed", Old: RedactedValue},
+				},
+			},
+		},
+		"yaml": {
+			from: "---\n# credentials\nuser: admin\npassword: old\nhost: a\n",
+			to:   "user: \"admin\"\npassword: new\nport: 5432\n",
+			expected: &VersionDiff{
+				Format: DiffFormatYAML,
+				Changes: []DiffChange{
+					{Op: DiffOpRemove, Key: "host", Old: "a"},
+					{Op: DiffOpChange, Key: "password", Old: "old", New: "new"},
+					{Op: DiffOpAdd, Key: "port", New: "5432"},
+				},
+			},
+		},
+		"nested yaml is text": {
+			from: "hosts:\n  - a\n",
+			to:   "hosts:\n  - b\n",
+			expected: &VersionDiff{
+				Format: DiffFormatText,
+				Changes: []DiffChange{
+					{Op: DiffOpRemove, Line: 2, Old: "  - a"},
+					{Op: DiffOpAdd, Line: 2, New: "  - b"},
+				},
+			},
+		},
+		"json and text": {
+			from: `{"key": "value"}`,
+			to:   "value",
+			expected: &VersionDiff{
+				Format: DiffFormatText,
+				Changes: []DiffChange{
+					{Op: DiffOpRemove, Line: 1, Old: `{"key": "value"}`},
+					{Op: DiffOpAdd, Line: 1, New: "value"},
+				},
+			},
+		},
+		"url is text": {
+			from: "https://example.com",
+			to:   "https://example.org",
+			expected: &VersionDiff{
+				Format: DiffFormatText,
+				Changes: []DiffChange{
+					{Op: DiffOpRemove, Line: 1, Old: "https://example.com"},
+					{Op: DiffOpAdd, Line: 1, New: "https://example.org"},
+				},
+			},
+		},
+		"binary": {
+			from: "\xff\x00",
+			to:   "\xff\x01",
+			expected: &VersionDiff{
+				Format:  DiffFormatBinary,
+				Changes: []DiffChange{{Op: DiffOpChange}},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			// Act
+			actual := Diff([]byte(tc.from), []byte(tc.to), tc.redact)
+
+			// Assert
+			assert.Equal(t, actual, tc.expected)
+		})
+	}
+}
+
+func TestDiff_LargeText(t *testing.T) {
+	// Arrange
+	from := make([]string, 3000)
+	to := make([]string, 3000)
+	for i := range from {
+		from[i] = "a"
+		to[i] = "b"
+	}
+	to[0] = "a"
+
+	// Act
+	actual := Diff([]byte(strings.Join(from, "\n")), []byte(strings.Join(to, "\n")), true)
+
+	// Assert
+	assert.Equal(t, len(actual.Changes), 2*2999)
+	assert.Equal(t, actual.Changes[0], DiffChange{Op: DiffOpRemove, Line: 2, Old: RedactedValue})
+}
+
+func TestVersionDiff_String(t *testing.T) {
+	// Arrange
+	diff := &VersionDiff{
+		Path:   "namespace/repo/secret",
+		From:   1,
+		To:     2,
+		Format: DiffFormatJSON,
+		Changes: []DiffChange{
+			{Op: DiffOpAdd, Key: "added", New: "1"},
+			{Op: DiffOpChange, Key: "changed", Old: "1", New: "2"},
+			{Op: DiffOpRemove, Key: "removed", Old: "1"},
+		},
+	}
+
+	// Act
+	actual := diff.String()
+
+	// Assert
+	assert.Equal(t, actual, "--- namespace/repo/secret:1\n+++ namespace/repo/secret:2\n+ added: 1\n~ changed: 1 -> 2\n- removed: 1\n")
+}
@@ -1,30 +1,48 @@
 package secrethub
 
 import (
+	"context"
+
 	"github.com/secrethub/secrethub-go/internals/api"
 	"github.com/secrethub/secrethub-go/internals/errio"
 )
 
 // DirService handles operations on directories from SecretHub.
 type DirService interface {
+	// Copy copies the directory at path src with all of its contents to path dst,
+	// which can be in another repository.
+	Copy(src, dst string, options CopyOptions) (*CopyResult, error)
+	// CopyContext is the same as Copy, but uses the given context for all requests.
+	CopyContext(ctx context.Context, src, dst string, options CopyOptions) (*CopyResult, error)
 	// Create a directory at a given path.
 	Create(path string) (*api.Dir, error)
+	// CreateContext is the same as Create, but uses the given context for all requests.
+	CreateContext(ctx context.Context, path string) (*api.Dir, error)
 	// Delete removes the directory at the given path.
 	Delete(path string) error
+	// DeleteContext is the same as Delete, but uses the given context for all requests.
+	DeleteContext(ctx context.Context, path string) error
 	// GetTree retrieves a directory at a given path and all of its descendants up to a given depth.
 	// When the depth <= 0, all descendants are returned. When ancestors is true, the parent directories
 	// of the dir at the given path will also be included in the tree.
 	GetTree(path string, depth int, ancestors bool) (*api.Tree, error)
+	// GetTreeContext is the same as GetTree, but uses the given context for all requests.
+	GetTreeContext(ctx context.Context, path string, depth int, ancestors bool) (*api.Tree, error)
+	// Move copies the directory at path src with all of its contents and the access rules
+	// on its directories to path dst and deletes the source when everything has been copied.
+	Move(src, dst string, options MoveOptions) (*MoveResult, error)
+	// MoveContext is the same as Move, but uses the given context for all requests.
+	MoveContext(ctx context.Context, src, dst string, options MoveOptions) (*MoveResult, error)
 }
 
-func newDirService(client client) DirService {
+func newDirService(client *client) DirService {
 	return dirService{
 		client: client,
 	}
 }
 
 type dirService struct {
-	client client
+	client *client
 }
 
 // GetTree retrieves a directory tree at a given path. The contents to the given depth
@@ -32,22 +50,27 @@ type dirService struct {
 // When ancestors is true, the parent directories of the dir at the given path will also
 // be included in the tree.
 func (s dirService) GetTree(path string, depth int, ancestors bool) (*api.Tree, error) {
+	return s.GetTreeContext(context.Background(), path, depth, ancestors)
+}
+
+// GetTreeContext is the same as GetTree, but uses the given context for all requests.
+func (s dirService) GetTreeContext(ctx context.Context, path string, depth int, ancestors bool) (*api.Tree, error) {
 	p, err := api.NewDirPath(path)
 	if err != nil {
 		return nil, errio.Error(err)
 	}
 
-	blindName, err := s.client.convertPathToBlindName(p)
+	blindName, err := s.client.convertPathToBlindName(ctx, p)
 	if err != nil {
 		return nil, errio.Error(err)
 	}
 
-	encTree, err := s.client.httpClient.GetTree(blindName, depth, ancestors)
+	encTree, err := s.client.httpClient.GetTree(ctx, blindName, depth, ancestors)
 	if err != nil {
 		return nil, errio.Error(err)
 	}
 
-	accountKey, err := s.client.getAccountKey()
+	accountKey, err := s.client.getAccountKey(ctx)
 	if err != nil {
 		return nil, errio.Error(err)
 	}
@@ -75,6 +98,11 @@ func (s dirService) GetTree(path string, depth int, ancestors bool) (*api.Tree,
 
 // Create creates a directory at a given path.
 func (s dirService) Create(path string) (*api.Dir, error) {
+	return s.CreateContext(context.Background(), path)
+}
+
+// CreateContext is the same as Create, but uses the given context for all requests.
+func (s dirService) CreateContext(ctx context.Context, path string) (*api.Dir, error) {
 	p, err := api.NewDirPath(path)
 	if err != nil {
 		return nil, errio.Error(err)
@@ -85,7 +113,7 @@ func (s dirService) Create(path string) (*api.Dir, error) {
 		return nil, errio.Error(err)
 	}
 
-	accounts, err := s.client.ListDirAccounts(parentPath)
+	accounts, err := s.client.ListDirAccounts(ctx, parentPath)
 	if err != nil {
 		return nil, errio.Error(err)
 	}
@@ -95,12 +123,12 @@ func (s dirService) Create(path string) (*api.Dir, error) {
 		return nil, errio.Error(err)
 	}
 
-	blindName, err := s.client.convertPathToBlindName(p)
+	blindName, err := s.client.convertPathToBlindName(ctx, p)
 	if err != nil {
 		return nil, errio.Error(err)
 	}
 
-	parentBlindName, err := s.client.convertPathToBlindName(parentPath)
+	parentBlindName, err := s.client.convertPathToBlindName(ctx, parentPath)
 	if err != nil {
 		return nil, errio.Error(err)
 	}
@@ -112,12 +140,12 @@ func (s dirService) Create(path string) (*api.Dir, error) {
 		EncryptedNames: encryptedNames,
 	}
 
-	encryptedDir, err := s.client.httpClient.CreateDir(p.GetNamespace(), p.GetRepo(), request)
+	encryptedDir, err := s.client.httpClient.CreateDir(ctx, p.GetNamespace(), p.GetRepo(), request)
 	if err != nil {
 		return nil, errio.Error(err)
 	}
 
-	accountKey, err := s.client.getAccountKey()
+	accountKey, err := s.client.getAccountKey(ctx)
 	if err != nil {
 		return nil, errio.Error(err)
 	}
@@ -128,17 +156,22 @@ func (s dirService) Create(path string) (*api.Dir, error) {
 
 // Delete removes the directory at the given path.
 func (s dirService) Delete(path string) error {
+	return s.DeleteContext(context.Background(), path)
+}
+
+// DeleteContext is the same as Delete, but uses the given context for all requests.
+func (s dirService) DeleteContext(ctx context.Context, path string) error {
 	p, err := api.NewDirPath(path)
 	if err != nil {
 		return errio.Error(err)
 	}
 
-	dirBlindName, err := s.client.convertPathToBlindName(p)
+	dirBlindName, err := s.client.convertPathToBlindName(ctx, p)
 	if err != nil {
 		return errio.Error(err)
 	}
 
-	err = s.client.httpClient.DeleteDir(dirBlindName)
+	err = s.client.httpClient.DeleteDir(ctx, dirBlindName)
 	if err != nil {
 		return errio.Error(err)
 	}
@@ -147,12 +180,12 @@ func (s dirService) Delete(path string) error {
 }
 
 // ListDirAccounts list the accounts with read permission.
-func (c *client) ListDirAccounts(path api.BlindNamePath) ([]*api.Account, error) {
-	blindName, err := c.convertPathToBlindName(path)
+func (c *client) ListDirAccounts(ctx context.Context, path api.BlindNamePath) ([]*api.Account, error) {
+	blindName, err := c.convertPathToBlindName(ctx, path)
 	if err != nil {
 		return nil, errio.Error(err)
 	}
 
-	accounts, err := c.httpClient.ListDirAccounts(blindName)
+	accounts, err := c.httpClient.ListDirAccounts(ctx, blindName)
 	return accounts, errio.Error(err)
 }
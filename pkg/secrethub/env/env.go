@@ -0,0 +1,103 @@
+// Package env resolves references to secrets in environment variables,
+// so that applications can be configured with secrets without ever
+// storing them on disk.
+//
+// A reference is an environment variable value of the form
+//
+//	secrethub://<namespace>/<repo>[/<dir-path>]/<secret>[:<version>]
+//
+// When no version is given, the latest version of the secret is used.
+package env
+
+import (
+	"context"
+	"strings"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/errio"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+)
+
+// ReferencePrefix is the prefix of an environment variable value that references a secret.
+const ReferencePrefix = "secrethub://"
+
+// Errors
+var (
+	errEnv = errio.Namespace("env")
+
+	ErrInvalidReference   = errEnv.Code("invalid_reference").ErrorPref("environment variable %s contains an invalid secret reference: %s")
+	ErrInvalidEnvironment = errEnv.Code("invalid_environment").ErrorPref("environment variable is not of the form key=value: %s")
+)
+
+// IsReference returns whether the given value references a secret.
+func IsReference(value string) bool {
+	return strings.HasPrefix(value, ReferencePrefix)
+}
+
+// ParseReference returns the path of the secret the given value references.
+func ParseReference(value string) (api.SecretPath, error) {
+	if !IsReference(value) {
+		return "", api.ErrInvalidSecretPath(value)
+	}
+	return api.NewSecretPath(strings.TrimPrefix(value, ReferencePrefix))
+}
+
+// Environment is a set of environment variables of which
+// all secret references have been resolved.
+type Environment struct {
+	// Vars contains the environment variables in the form key=value,
+	// with every secret reference replaced by the secret's value.
+	Vars []string
+	// Secrets contains the values of all resolved secrets.
+	Secrets [][]byte
+}
+
+// Resolve replaces all secret references in the given environment variables,
+// in the form key=value as returned by os.Environ, by the values of the secrets
+// they reference. Every referenced secret is retrieved only once.
+func Resolve(versions secrethub.SecretVersionService, environ []string) (*Environment, error) {
+	return ResolveContext(context.Background(), versions, environ)
+}
+
+// ResolveContext is the same as Resolve, but uses the given context for all requests.
+func ResolveContext(ctx context.Context, versions secrethub.SecretVersionService, environ []string) (*Environment, error) {
+	env := &Environment{
+		Vars: make([]string, len(environ)),
+	}
+
+	resolved := make(map[api.SecretPath][]byte)
+	for i, variable := range environ {
+		env.Vars[i] = variable
+
+		split := strings.SplitN(variable, "=", 2)
+		if len(split) != 2 {
+			return nil, ErrInvalidEnvironment(variable)
+		}
+		key, value := split[0], split[1]
+
+		if !IsReference(value) {
+			continue
+		}
+
+		path, err := ParseReference(value)
+		if err != nil {
+			return nil, ErrInvalidReference(key, err)
+		}
+
+		data, ok := resolved[path]
+		if !ok {
+			version, err := versions.GetWithDataContext(ctx, path.Value())
+			if err != nil {
+				return nil, errio.Error(err)
+			}
+
+			data = version.Data
+			resolved[path] = data
+			env.Secrets = append(env.Secrets, data)
+		}
+
+		env.Vars[i] = key + "=" + string(data)
+	}
+
+	return env, nil
+}
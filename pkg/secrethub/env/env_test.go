@@ -0,0 +1,135 @@
+package env
+
+import (
+	"context"
+	"testing"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+)
+
+// versionService returns the secrets in a map and counts the number of calls per path.
+type versionService struct {
+	secrethub.SecretVersionService
+	secrets map[string]string
+	calls   map[string]int
+}
+
+func (s *versionService) GetWithDataContext(ctx context.Context, path string) (*api.SecretVersion, error) {
+	s.calls[path]++
+	data, ok := s.secrets[path]
+	if !ok {
+		return nil, api.ErrSecretNotFound
+	}
+	return &api.SecretVersion{Data: []byte(data)}, nil
+}
+
+func TestParseReference(t *testing.T) {
+	cases := map[string]struct {
+		value    string
+		expected api.SecretPath
+		err      bool
+	}{
+		"secret": {
+			value:    "secrethub://namespace/repo/secret",
+			expected: "namespace/repo/secret",
+		},
+		"secret in dir with version": {
+			value:    "secrethub://namespace/repo/dir/secret:3",
+			expected: "namespace/repo/dir/secret:3",
+		},
+		"no prefix": {
+			value: "namespace/repo/secret",
+			err:   true,
+		},
+		"repo path": {
+			value: "secrethub://namespace/repo",
+			err:   true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			// Act
+			actual, err := ParseReference(tc.value)
+
+			// Assert
+			assert.Equal(t, err != nil, tc.err)
+			if !tc.err {
+				assert.Equal(t, actual, tc.expected)
+			}
+		})
+	}
+}
+
+func TestResolve(t *testing.T) {
+	secrets := map[string]string{
+		"namespace/repo/db_password": "password123",
+		"namespace/repo/api_key:2":   "key456",
+	}
+
+	cases := map[string]struct {
+		environ         []string
+		expectedVars    []string
+		expectedSecrets [][]byte
+		expectedCalls   map[string]int
+		err             error
+	}{
+		"no references": {
+			environ:       []string{"HOME=/root", "EMPTY="},
+			expectedVars:  []string{"HOME=/root", "EMPTY="},
+			expectedCalls: map[string]int{},
+		},
+		"references": {
+			environ: []string{
+				"HOME=/root",
+				"DB_PASSWORD=secrethub://namespace/repo/db_password",
+				"API_KEY=secrethub://namespace/repo/api_key:2",
+				"PASSWORD=secrethub://namespace/repo/db_password",
+			},
+			expectedVars: []string{
+				"HOME=/root",
+				"DB_PASSWORD=password123",
+				"API_KEY=key456",
+				"PASSWORD=password123",
+			},
+			expectedSecrets: [][]byte{[]byte("password123"), []byte("key456")},
+			expectedCalls: map[string]int{
+				"namespace/repo/db_password": 1,
+				"namespace/repo/api_key:2":   1,
+			},
+		},
+		"secret not found": {
+			environ:       []string{"DB_PASSWORD=secrethub://namespace/repo/unknown"},
+			expectedCalls: map[string]int{"namespace/repo/unknown": 1},
+			err:           api.ErrSecretNotFound,
+		},
+		"invalid environment": {
+			environ:       []string{"INVALID"},
+			expectedCalls: map[string]int{},
+			err:           ErrInvalidEnvironment("INVALID"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			// Arrange
+			versions := &versionService{
+				secrets: secrets,
+				calls:   make(map[string]int),
+			}
+
+			// Act
+			actual, err := Resolve(versions, tc.environ)
+
+			// Assert
+			assert.Equal(t, err, tc.err)
+			assert.Equal(t, versions.calls, tc.expectedCalls)
+			if tc.err == nil {
+				assert.Equal(t, actual.Vars, tc.expectedVars)
+				assert.Equal(t, actual.Secrets, tc.expectedSecrets)
+			}
+		})
+	}
+}
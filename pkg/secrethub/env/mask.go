@@ -0,0 +1,123 @@
+package env
+
+import (
+	"bytes"
+	"io"
+	"sort"
+	"sync"
+)
+
+// DefaultMask is written instead of a secret by a MaskingWriter.
+var DefaultMask = []byte("<redacted by SecretHub>")
+
+// MaskingWriter is an io.Writer that replaces every occurrence
+// of a secret in the data written to it by a mask.
+//
+// Because a secret may be split over multiple calls to Write, data that
+// could be the start of a secret is held back until it is known whether
+// it is. Call Flush when done writing to write any data that is held back.
+type MaskingWriter struct {
+	w       io.Writer
+	mask    []byte
+	secrets [][]byte
+	buf     []byte
+	mutex   sync.Mutex
+}
+
+// NewMaskingWriter creates a MaskingWriter that writes to w
+// and replaces every occurrence of the given secrets by DefaultMask.
+// Empty secrets are ignored.
+func NewMaskingWriter(w io.Writer, secrets [][]byte) *MaskingWriter {
+	masked := make([][]byte, 0, len(secrets))
+	for _, secret := range secrets {
+		if len(secret) > 0 {
+			masked = append(masked, secret)
+		}
+	}
+
+	// Try the longest secrets first, so a secret that contains
+	// another secret is masked completely.
+	sort.SliceStable(masked, func(i, j int) bool {
+		return len(masked[i]) > len(masked[j])
+	})
+
+	return &MaskingWriter{
+		w:       w,
+		mask:    DefaultMask,
+		secrets: masked,
+	}
+}
+
+// Write implements the io.Writer interface.
+func (mw *MaskingWriter) Write(p []byte) (int, error) {
+	mw.mutex.Lock()
+	defer mw.mutex.Unlock()
+
+	mw.buf = append(mw.buf, p...)
+
+	_, err := mw.w.Write(mw.process(false))
+	if err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Flush masks and writes any data that is held back because
+// it could be the start of a secret to the underlying writer.
+func (mw *MaskingWriter) Flush() error {
+	mw.mutex.Lock()
+	defer mw.mutex.Unlock()
+
+	if len(mw.buf) == 0 {
+		return nil
+	}
+
+	_, err := mw.w.Write(mw.process(true))
+	return err
+}
+
+// process masks the secrets in the buffered data and returns the result.
+// Unless final is true, data that could be the start of a secret is kept
+// in the buffer instead of being returned.
+func (mw *MaskingWriter) process(final bool) []byte {
+	var out []byte
+	i := 0
+	for i < len(mw.buf) {
+		if !final && mw.isPartialMatch(mw.buf[i:]) {
+			break
+		}
+
+		n := mw.match(mw.buf[i:])
+		if n > 0 {
+			out = append(out, mw.mask...)
+			i += n
+			continue
+		}
+
+		out = append(out, mw.buf[i])
+		i++
+	}
+
+	mw.buf = append(mw.buf[:0], mw.buf[i:]...)
+	return out
+}
+
+// match returns the length of the secret that p starts with or 0 if p does not start with a secret.
+func (mw *MaskingWriter) match(p []byte) int {
+	for _, secret := range mw.secrets {
+		if bytes.HasPrefix(p, secret) {
+			return len(secret)
+		}
+	}
+	return 0
+}
+
+// isPartialMatch returns whether p is the start of a secret, but not a complete secret.
+func (mw *MaskingWriter) isPartialMatch(p []byte) bool {
+	for _, secret := range mw.secrets {
+		if len(p) < len(secret) && bytes.HasPrefix(secret, p) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,82 @@
+package env
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/secrethub/secrethub-go/internals/assert"
+)
+
+func TestMaskingWriter(t *testing.T) {
+	cases := map[string]struct {
+		secrets  []string
+		writes   []string
+		expected string
+	}{
+		"no secrets": {
+			secrets:  nil,
+			writes:   []string{"hello ", "world"},
+			expected: "hello world",
+		},
+		"secret in single write": {
+			secrets:  []string{"password123"},
+			writes:   []string{"the password is password123\n"},
+			expected: "the password is <redacted by SecretHub>\n",
+		},
+		"secret split over writes": {
+			secrets:  []string{"password123"},
+			writes:   []string{"the password is pass", "word", "123\n"},
+			expected: "the password is <redacted by SecretHub>\n",
+		},
+		"partial secret is flushed": {
+			secrets:  []string{"password123"},
+			writes:   []string{"the password is pass"},
+			expected: "the password is pass",
+		},
+		"partial secret followed by other data": {
+			secrets:  []string{"password123"},
+			writes:   []string{"pass", "port"},
+			expected: "passport",
+		},
+		"multiple occurrences": {
+			secrets:  []string{"abc"},
+			writes:   []string{"abcabc-ab", "c"},
+			expected: "<redacted by SecretHub><redacted by SecretHub>-<redacted by SecretHub>",
+		},
+		"longest secret first": {
+			secrets:  []string{"abc", "abcdef"},
+			writes:   []string{"abcdef abc"},
+			expected: "<redacted by SecretHub> <redacted by SecretHub>",
+		},
+		"empty secret is ignored": {
+			secrets:  []string{""},
+			writes:   []string{"hello"},
+			expected: "hello",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			// Arrange
+			secrets := make([][]byte, len(tc.secrets))
+			for i, secret := range tc.secrets {
+				secrets[i] = []byte(secret)
+			}
+
+			buf := bytes.Buffer{}
+			w := NewMaskingWriter(&buf, secrets)
+
+			// Act
+			for _, write := range tc.writes {
+				n, err := w.Write([]byte(write))
+				assert.OK(t, err)
+				assert.Equal(t, n, len(write))
+			}
+			err := w.Flush()
+
+			// Assert
+			assert.OK(t, err)
+			assert.Equal(t, buf.String(), tc.expected)
+		})
+	}
+}
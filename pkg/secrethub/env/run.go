@@ -0,0 +1,100 @@
+package env
+
+import (
+	"context"
+	"io"
+	"os"
+	"os/exec"
+)
+
+// RunOptions configure how a command is run with an Environment.
+type RunOptions struct {
+	// Stdin is the standard input of the command. Defaults to os.Stdin.
+	Stdin io.Reader
+	// Stdout is the standard output of the command. Defaults to os.Stdout.
+	Stdout io.Writer
+	// Stderr is the standard error of the command. Defaults to os.Stderr.
+	Stderr io.Writer
+	// DisableMasking disables replacing the values of the resolved
+	// secrets in the output of the command by a mask.
+	DisableMasking bool
+	// Signals are passed on to the command while it runs, so that it can
+	// shut down gracefully, e.g. on a termination request.
+	Signals <-chan os.Signal
+}
+
+// Run runs the named command with the given arguments and the resolved environment
+// variables as its only environment, and waits for it to finish. Unless masking is
+// disabled, every secret value the command writes to its standard output or standard
+// error is replaced by a mask.
+//
+// The command is killed when the context is done before the command finishes. To let
+// the command shut down gracefully instead, pass the signals on with opts.Signals.
+// When the command exits with a non-zero exit code, an *exec.ExitError is returned.
+func (e *Environment) Run(ctx context.Context, name string, args []string, opts *RunOptions) error {
+	if opts == nil {
+		opts = &RunOptions{}
+	}
+
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Env = e.Vars
+	cmd.Stdin = opts.Stdin
+	if cmd.Stdin == nil {
+		cmd.Stdin = os.Stdin
+	}
+
+	stdout := opts.Stdout
+	if stdout == nil {
+		stdout = os.Stdout
+	}
+	stderr := opts.Stderr
+	if stderr == nil {
+		stderr = os.Stderr
+	}
+
+	if opts.DisableMasking {
+		cmd.Stdout = stdout
+		cmd.Stderr = stderr
+		return runCommand(cmd, opts.Signals)
+	}
+
+	maskedStdout := NewMaskingWriter(stdout, e.Secrets)
+	maskedStderr := NewMaskingWriter(stderr, e.Secrets)
+	cmd.Stdout = maskedStdout
+	cmd.Stderr = maskedStderr
+
+	err := runCommand(cmd, opts.Signals)
+
+	flushErr := maskedStdout.Flush()
+	if flushErr == nil {
+		flushErr = maskedStderr.Flush()
+	}
+	if err != nil {
+		return err
+	}
+	return flushErr
+}
+
+// runCommand starts the command, passes on the signals to it until it exits
+// and waits for it to finish.
+func runCommand(cmd *exec.Cmd, signals <-chan os.Signal) error {
+	err := cmd.Start()
+	if err != nil {
+		return err
+	}
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		for {
+			select {
+			case sig := <-signals:
+				_ = cmd.Process.Signal(sig)
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return cmd.Wait()
+}
@@ -0,0 +1,68 @@
+package env
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+
+	"github.com/secrethub/secrethub-go/internals/assert"
+)
+
+// readyWriter buffers the output and closes ready when it contains "ready".
+type readyWriter struct {
+	mutex sync.Mutex
+	buf   bytes.Buffer
+	ready chan struct{}
+	once  sync.Once
+}
+
+func (w *readyWriter) Write(p []byte) (int, error) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	n, err := w.buf.Write(p)
+	if strings.Contains(w.buf.String(), "ready") {
+		w.once.Do(func() { close(w.ready) })
+	}
+	return n, err
+}
+
+func (w *readyWriter) String() string {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	return w.buf.String()
+}
+
+func TestEnvironment_Run_Signals(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("signals cannot be sent to processes on windows")
+	}
+
+	// Arrange
+	environment := &Environment{Secrets: [][]byte{[]byte("secret")}}
+	stdout := &readyWriter{ready: make(chan struct{})}
+	signals := make(chan os.Signal, 1)
+
+	go func() {
+		<-stdout.ready
+		signals <- syscall.SIGTERM
+	}()
+
+	// Act
+	err := environment.Run(context.Background(), "sh", []string{"-c", `trap 'echo stopped secret; exit 3' TERM; echo ready; while :; do sleep 0.1; done`}, &RunOptions{
+		Stdout:  stdout,
+		Signals: signals,
+	})
+
+	// Assert
+	exitErr, ok := err.(*exec.ExitError)
+	assert.Equal(t, ok, true)
+	assert.Equal(t, exitErr.ExitCode(), 3)
+	assert.Equal(t, stdout.String(), "ready\nstopped "+string(DefaultMask)+"\n")
+}
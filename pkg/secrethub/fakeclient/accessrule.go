@@ -2,7 +2,11 @@
 
 package fakeclient
 
-import "github.com/secrethub/secrethub-go/internals/api"
+import (
+	"context"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+)
 
 // AccessRuleService is a mock of the AccessRuleService interface.
 type AccessRuleService struct {
@@ -18,26 +22,51 @@ func (s *AccessRuleService) Delete(path string, accountName string) error {
 	return s.Deleter.Delete(path, accountName)
 }
 
+// DeleteContext implements the AccessRuleService interface DeleteContext function.
+func (s *AccessRuleService) DeleteContext(ctx context.Context, path string, accountName string) error {
+	return s.Delete(path, accountName)
+}
+
 // Get implements the AccessRuleService interface Get function.
 func (s *AccessRuleService) Get(path string, accountName string) (*api.AccessRule, error) {
 	return s.Getter.Get(path, accountName)
 }
 
+// GetContext implements the AccessRuleService interface GetContext function.
+func (s *AccessRuleService) GetContext(ctx context.Context, path string, accountName string) (*api.AccessRule, error) {
+	return s.Get(path, accountName)
+}
+
 // ListLevels implements the AccessRuleService interface ListLevels function.
 func (s *AccessRuleService) ListLevels(path string) ([]*api.AccessLevel, error) {
 	return s.LevelLister.ListLevels(path)
 }
 
+// ListLevelsContext implements the AccessRuleService interface ListLevelsContext function.
+func (s *AccessRuleService) ListLevelsContext(ctx context.Context, path string) ([]*api.AccessLevel, error) {
+	return s.ListLevels(path)
+}
+
 // List implements the AccessRuleService interface List function.
 func (s *AccessRuleService) List(path string, depth int, ancestors bool) ([]*api.AccessRule, error) {
 	return s.Lister.List(path, depth, ancestors)
 }
 
+// ListContext implements the AccessRuleService interface ListContext function.
+func (s *AccessRuleService) ListContext(ctx context.Context, path string, depth int, ancestors bool) ([]*api.AccessRule, error) {
+	return s.List(path, depth, ancestors)
+}
+
 // Set implements the AccessRuleService interface Set function.
 func (s *AccessRuleService) Set(path string, permission api.Permission, accountName string) (*api.AccessRule, error) {
 	return s.Setter.Set(path, permission, accountName)
 }
 
+// SetContext implements the AccessRuleService interface SetContext function.
+func (s *AccessRuleService) SetContext(ctx context.Context, path string, permission api.Permission, accountName string) (*api.AccessRule, error) {
+	return s.Set(path, permission, accountName)
+}
+
 // AccessRuleDeleter mocks the Delete function.
 type AccessRuleDeleter struct {
 	ArgPath        string
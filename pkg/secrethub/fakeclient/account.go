@@ -3,6 +3,8 @@
 package fakeclient
 
 import (
+	"context"
+
 	"github.com/secrethub/secrethub-go/internals/api"
 	"github.com/secrethub/secrethub-go/pkg/secrethub"
 )
@@ -17,6 +19,11 @@ func (s *AccountService) Get(name string) (*api.Account, error) {
 	return s.Getter.Get(name)
 }
 
+// GetContext implements the AccountService interface GetContext function.
+func (s *AccountService) GetContext(ctx context.Context, name string) (*api.Account, error) {
+	return s.Get(name)
+}
+
 // AccountGetter mocks the Get function.
 type AccountGetter struct {
 	ArgName        string
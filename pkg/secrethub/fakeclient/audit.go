@@ -0,0 +1,45 @@
+// +build !production
+
+package fakeclient
+
+import (
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+)
+
+// EventIterator mocks the Events function.
+type EventIterator struct {
+	ArgPath         string
+	ArgFilter       secrethub.AuditFilter
+	ReturnsIterator secrethub.AuditEventIterator
+}
+
+// Events saves the arguments it was called with and returns the mocked response.
+func (ei *EventIterator) Events(path string, filter secrethub.AuditFilter) secrethub.AuditEventIterator {
+	ei.ArgPath = path
+	ei.ArgFilter = filter
+	return ei.ReturnsIterator
+}
+
+// AuditEventIterator is a mock of the AuditEventIterator interface.
+// It returns the mocked events in order and then Err,
+// or secrethub.ErrIteratorDone when Err is nil.
+type AuditEventIterator struct {
+	Events []*api.Audit
+	Err    error
+	index  int
+}
+
+// Next returns the next mocked event.
+func (it *AuditEventIterator) Next() (*api.Audit, error) {
+	if it.index < len(it.Events) {
+		event := it.Events[it.index]
+		it.index++
+		return event, nil
+	}
+
+	if it.Err != nil {
+		return nil, it.Err
+	}
+	return nil, secrethub.ErrIteratorDone
+}
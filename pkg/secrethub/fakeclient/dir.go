@@ -3,31 +3,88 @@
 package fakeclient
 
 import (
+	"context"
+
 	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
 )
 
 // DirService is a mock of the DirService interface.
 type DirService struct {
+	Copier     DirCopier
 	Creater    DirCreater
 	Deleter    DirDeleter
+	Mover      Mover
 	TreeGetter TreeGetter
 }
 
+// Copy implements the DirService interface Copy function.
+func (s *DirService) Copy(src, dst string, options secrethub.CopyOptions) (*secrethub.CopyResult, error) {
+	return s.Copier.Copy(src, dst, options)
+}
+
+// CopyContext implements the DirService interface CopyContext function.
+func (s *DirService) CopyContext(ctx context.Context, src, dst string, options secrethub.CopyOptions) (*secrethub.CopyResult, error) {
+	return s.Copy(src, dst, options)
+}
+
 // Create implements the DirService interface Create function.
 func (s *DirService) Create(path string) (*api.Dir, error) {
 	return s.Creater.Create(path)
 }
 
+// CreateContext implements the DirService interface CreateContext function.
+func (s *DirService) CreateContext(ctx context.Context, path string) (*api.Dir, error) {
+	return s.Create(path)
+}
+
 // Delete implements the DirService interface Delete function.
 func (s *DirService) Delete(path string) error {
 	return s.Deleter.Delete(path)
 }
 
+// DeleteContext implements the DirService interface DeleteContext function.
+func (s *DirService) DeleteContext(ctx context.Context, path string) error {
+	return s.Delete(path)
+}
+
 // GetTree implements the DirService interface GetTree function.
 func (s *DirService) GetTree(path string, depth int, ancestors bool) (*api.Tree, error) {
 	return s.TreeGetter.GetTree(path, depth)
 }
 
+// GetTreeContext implements the DirService interface GetTreeContext function.
+func (s *DirService) GetTreeContext(ctx context.Context, path string, depth int, ancestors bool) (*api.Tree, error) {
+	return s.GetTree(path, depth, ancestors)
+}
+
+// Move implements the DirService interface Move function.
+func (s *DirService) Move(src, dst string, options secrethub.MoveOptions) (*secrethub.MoveResult, error) {
+	return s.Mover.Move(src, dst, options)
+}
+
+// MoveContext implements the DirService interface MoveContext function.
+func (s *DirService) MoveContext(ctx context.Context, src, dst string, options secrethub.MoveOptions) (*secrethub.MoveResult, error) {
+	return s.Move(src, dst, options)
+}
+
+// DirCopier mocks the Copy function.
+type DirCopier struct {
+	ArgSrc        string
+	ArgDst        string
+	ArgOptions    secrethub.CopyOptions
+	ReturnsResult *secrethub.CopyResult
+	Err           error
+}
+
+// Copy saves the arguments it was called with and returns the mocked response.
+func (c *DirCopier) Copy(src, dst string, options secrethub.CopyOptions) (*secrethub.CopyResult, error) {
+	c.ArgSrc = src
+	c.ArgDst = dst
+	c.ArgOptions = options
+	return c.ReturnsResult, c.Err
+}
+
 // DirCreater mocks the Create function.
 type DirCreater struct {
 	ArgPath    string
@@ -67,3 +124,20 @@ func (dg *TreeGetter) GetTree(path string, depth int) (*api.Tree, error) {
 	dg.ArgDepth = depth
 	return dg.ReturnsTree, dg.Err
 }
+
+// Mover mocks the Move function of the SecretService and DirService.
+type Mover struct {
+	ArgSrc        string
+	ArgDst        string
+	ArgOptions    secrethub.MoveOptions
+	ReturnsResult *secrethub.MoveResult
+	Err           error
+}
+
+// Move saves the arguments it was called with and returns the mocked response.
+func (m *Mover) Move(src, dst string, options secrethub.MoveOptions) (*secrethub.MoveResult, error) {
+	m.ArgSrc = src
+	m.ArgDst = dst
+	m.ArgOptions = options
+	return m.ReturnsResult, m.Err
+}
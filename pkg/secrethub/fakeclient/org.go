@@ -3,6 +3,8 @@
 package fakeclient
 
 import (
+	"context"
+
 	"github.com/secrethub/secrethub-go/internals/api"
 	"github.com/secrethub/secrethub-go/pkg/secrethub"
 )
@@ -21,16 +23,31 @@ func (s *OrgService) Create(name string, description string) (*api.Org, error) {
 	return s.Creater.Create(name, description)
 }
 
+// CreateContext implements the OrgService interface CreateContext function.
+func (s *OrgService) CreateContext(ctx context.Context, name string, description string) (*api.Org, error) {
+	return s.Create(name, description)
+}
+
 // Delete implements the RepoService interface Delete function.
 func (s *OrgService) Delete(name string) error {
 	return s.Deleter.Delete(name)
 }
 
+// DeleteContext implements the OrgService interface DeleteContext function.
+func (s *OrgService) DeleteContext(ctx context.Context, name string) error {
+	return s.Delete(name)
+}
+
 // Get implements the RepoService interface Get function.
 func (s *OrgService) Get(name string) (*api.Org, error) {
 	return s.Getter.Get(name)
 }
 
+// GetContext implements the OrgService interface GetContext function.
+func (s *OrgService) GetContext(ctx context.Context, name string) (*api.Org, error) {
+	return s.Get(name)
+}
+
 // Members returns a mock of the OrgMemberService interface.
 func (s *OrgService) Members() secrethub.OrgMemberService {
 	return s.MemberService
@@ -41,6 +58,11 @@ func (s *OrgService) ListMine() ([]*api.Org, error) {
 	return s.MineLister.ListMine()
 }
 
+// ListMineContext implements the OrgService interface ListMineContext function.
+func (s *OrgService) ListMineContext(ctx context.Context) ([]*api.Org, error) {
+	return s.ListMine()
+}
+
 // OrgCreater mocks the Create function.
 type OrgCreater struct {
 	ArgName        string
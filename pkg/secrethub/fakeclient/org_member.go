@@ -2,7 +2,11 @@
 
 package fakeclient
 
-import "github.com/secrethub/secrethub-go/internals/api"
+import (
+	"context"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+)
 
 // OrgMemberService is a mock of the OrgMemberService interface.
 type OrgMemberService struct {
@@ -17,26 +21,51 @@ func (s *OrgMemberService) Get(org string, username string) (*api.OrgMember, err
 	return nil, nil
 }
 
+// GetContext implements the OrgMemberService interface GetContext function.
+func (s *OrgMemberService) GetContext(ctx context.Context, org string, username string) (*api.OrgMember, error) {
+	return s.Get(org, username)
+}
+
 // Invite implements the OrgMemberService interface Invite function.
 func (s *OrgMemberService) Invite(org string, username string, role string) (*api.OrgMember, error) {
 	return s.Inviter.Invite(org, username, role)
 }
 
+// InviteContext implements the OrgMemberService interface InviteContext function.
+func (s *OrgMemberService) InviteContext(ctx context.Context, org string, username string, role string) (*api.OrgMember, error) {
+	return s.Invite(org, username, role)
+}
+
 // List implements the OrgMemberService interface List function.
 func (s *OrgMemberService) List(name string) ([]*api.OrgMember, error) {
 	return s.Lister.List(name)
 }
 
+// ListContext implements the OrgMemberService interface ListContext function.
+func (s *OrgMemberService) ListContext(ctx context.Context, name string) ([]*api.OrgMember, error) {
+	return s.List(name)
+}
+
 // Revoke implements the OrgMemberService interface Revoke function.
 func (s *OrgMemberService) Revoke(name string, username string, opts *api.RevokeOpts) (*api.RevokeOrgResponse, error) {
 	return s.Revoker.Revoke(name, username, opts)
 }
 
+// RevokeContext implements the OrgMemberService interface RevokeContext function.
+func (s *OrgMemberService) RevokeContext(ctx context.Context, name string, username string, opts *api.RevokeOpts) (*api.RevokeOrgResponse, error) {
+	return s.Revoke(name, username, opts)
+}
+
 // Update implements the OrgMemberService interface Update function.
 func (s *OrgMemberService) Update(orgName string, username string, role string) (*api.OrgMember, error) {
 	return s.Updater.Update(orgName, username, role)
 }
 
+// UpdateContext implements the OrgMemberService interface UpdateContext function.
+func (s *OrgMemberService) UpdateContext(ctx context.Context, orgName string, username string, role string) (*api.OrgMember, error) {
+	return s.Update(orgName, username, role)
+}
+
 // OrgInviter mocks the Invite function.
 type OrgInviter struct {
 	ArgOrg           string
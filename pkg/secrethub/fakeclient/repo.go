@@ -3,6 +3,10 @@
 package fakeclient
 
 import (
+	"context"
+	"io"
+	"io/ioutil"
+
 	"github.com/secrethub/secrethub-go/internals/api"
 	"github.com/secrethub/secrethub-go/pkg/secrethub"
 )
@@ -10,14 +14,17 @@ import (
 // RepoService is a mock of the RepoService interface.
 type RepoService struct {
 	AccountLister  RepoAccountLister
+	Backuper       RepoBackuper
 	Creater        RepoCreater
 	Deleter        RepoDeleter
 	Getter         RepoGetter
 	EventLister    RepoEventLister
+	EventIterator  EventIterator
 	Lister         RepoLister
 	UserService    *RepoUserService
 	ServiceService *RepoServiceService
 	MineLister     RepoMineLister
+	Restorer       RepoRestorer
 }
 
 // List implements the RepoService interface List function.
@@ -25,36 +32,101 @@ func (s *RepoService) List(namespace string) ([]*api.Repo, error) {
 	return s.Lister.List(namespace)
 }
 
+// ListContext implements the RepoService interface ListContext function.
+func (s *RepoService) ListContext(ctx context.Context, namespace string) ([]*api.Repo, error) {
+	return s.List(namespace)
+}
+
 // ListAccounts implements the RepoService interface ListAccounts function.
 func (s *RepoService) ListAccounts(path string) ([]*api.Account, error) {
 	return s.AccountLister.ListAccounts(path)
 }
 
+// ListAccountsContext implements the RepoService interface ListAccountsContext function.
+func (s *RepoService) ListAccountsContext(ctx context.Context, path string) ([]*api.Account, error) {
+	return s.ListAccounts(path)
+}
+
 // ListEvents implements the RepoService interface ListEvents function.
 func (s *RepoService) ListEvents(path string, subjectTypes api.AuditSubjectTypeList) ([]*api.Audit, error) {
 	return s.EventLister.ListEvents(path, subjectTypes)
 }
 
+// ListEventsContext implements the RepoService interface ListEventsContext function.
+func (s *RepoService) ListEventsContext(ctx context.Context, path string, subjectTypes api.AuditSubjectTypeList) ([]*api.Audit, error) {
+	return s.ListEvents(path, subjectTypes)
+}
+
+// Events implements the RepoService interface Events function.
+func (s *RepoService) Events(path string, filter secrethub.AuditFilter) secrethub.AuditEventIterator {
+	return s.EventIterator.Events(path, filter)
+}
+
+// EventsContext implements the RepoService interface EventsContext function.
+func (s *RepoService) EventsContext(ctx context.Context, path string, filter secrethub.AuditFilter) secrethub.AuditEventIterator {
+	return s.Events(path, filter)
+}
+
 // ListMine implements the RepoService interface ListMine function.
 func (s *RepoService) ListMine() ([]*api.Repo, error) {
 	return s.MineLister.ListMine()
 }
 
+// ListMineContext implements the RepoService interface ListMineContext function.
+func (s *RepoService) ListMineContext(ctx context.Context) ([]*api.Repo, error) {
+	return s.ListMine()
+}
+
+// Backup implements the RepoService interface Backup function.
+func (s *RepoService) Backup(path string, w io.Writer, passphrase []byte) error {
+	return s.Backuper.Backup(path, w, passphrase)
+}
+
+// BackupContext implements the RepoService interface BackupContext function.
+func (s *RepoService) BackupContext(ctx context.Context, path string, w io.Writer, passphrase []byte) error {
+	return s.Backup(path, w, passphrase)
+}
+
 // Create implements the RepoService interface Create function.
 func (s *RepoService) Create(path string) (*api.Repo, error) {
 	return s.Creater.Create(path)
 }
 
+// CreateContext implements the RepoService interface CreateContext function.
+func (s *RepoService) CreateContext(ctx context.Context, path string) (*api.Repo, error) {
+	return s.Create(path)
+}
+
 // Delete implements the RepoService interface Delete function.
 func (s *RepoService) Delete(path string) error {
 	return s.Deleter.Delete(path)
 }
 
+// DeleteContext implements the RepoService interface DeleteContext function.
+func (s *RepoService) DeleteContext(ctx context.Context, path string) error {
+	return s.Delete(path)
+}
+
 // Get implements the RepoService interface Get function.
 func (s *RepoService) Get(path string) (*api.Repo, error) {
 	return s.Getter.Get(path)
 }
 
+// GetContext implements the RepoService interface GetContext function.
+func (s *RepoService) GetContext(ctx context.Context, path string) (*api.Repo, error) {
+	return s.Get(path)
+}
+
+// Restore implements the RepoService interface Restore function.
+func (s *RepoService) Restore(path string, r io.Reader, passphrase []byte) (*secrethub.RestoreResult, error) {
+	return s.Restorer.Restore(path, r, passphrase)
+}
+
+// RestoreContext implements the RepoService interface RestoreContext function.
+func (s *RepoService) RestoreContext(ctx context.Context, path string, r io.Reader, passphrase []byte) (*secrethub.RestoreResult, error) {
+	return s.Restore(path, r, passphrase)
+}
+
 // Users returns the mocked UserService.
 func (s *RepoService) Users() secrethub.RepoUserService {
 	return s.UserService
@@ -154,3 +226,43 @@ type RepoMineLister struct {
 func (m *RepoMineLister) ListMine() ([]*api.Repo, error) {
 	return m.ReturnsRepos, m.Err
 }
+
+// RepoBackuper mocks the Backup function.
+type RepoBackuper struct {
+	ArgPath       string
+	ArgPassphrase []byte
+	ReturnsBackup []byte
+	Err           error
+}
+
+// Backup saves the arguments it was called with, writes the mocked backup to w and returns the mocked error.
+func (b *RepoBackuper) Backup(path string, w io.Writer, passphrase []byte) error {
+	b.ArgPath = path
+	b.ArgPassphrase = passphrase
+	if b.Err != nil {
+		return b.Err
+	}
+	_, err := w.Write(b.ReturnsBackup)
+	return err
+}
+
+// RepoRestorer mocks the Restore function.
+type RepoRestorer struct {
+	ArgPath       string
+	ArgBackup     []byte
+	ArgPassphrase []byte
+	ReturnsResult *secrethub.RestoreResult
+	Err           error
+}
+
+// Restore saves the arguments it was called with and returns the mocked response.
+func (r *RepoRestorer) Restore(path string, backup io.Reader, passphrase []byte) (*secrethub.RestoreResult, error) {
+	r.ArgPath = path
+	r.ArgPassphrase = passphrase
+	data, err := ioutil.ReadAll(backup)
+	if err != nil {
+		return nil, err
+	}
+	r.ArgBackup = data
+	return r.ReturnsResult, r.Err
+}
@@ -2,7 +2,11 @@
 
 package fakeclient
 
-import "github.com/secrethub/secrethub-go/internals/api"
+import (
+	"context"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+)
 
 // RepoServiceService is a mock of the RepoServiceService interface.
 type RepoServiceService struct {
@@ -14,6 +18,11 @@ func (s *RepoServiceService) List(path string) ([]*api.Service, error) {
 	return s.Lister.List(path)
 }
 
+// ListContext implements the RepoServiceService interface ListContext function.
+func (s *RepoServiceService) ListContext(ctx context.Context, path string) ([]*api.Service, error) {
+	return s.List(path)
+}
+
 // RepoServiceLister mocks the List function.
 type RepoServiceLister struct {
 	ArgPath         string
@@ -2,7 +2,11 @@
 
 package fakeclient
 
-import "github.com/secrethub/secrethub-go/internals/api"
+import (
+	"context"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+)
 
 // RepoUserService is a mock of the RepoUserService interface.
 type RepoUserService struct {
@@ -16,16 +20,31 @@ func (s *RepoUserService) Invite(path string, username string) (*api.RepoMember,
 	return s.RepoInviter.Invite(path, username)
 }
 
+// InviteContext implements the RepoUserService interface InviteContext function.
+func (s *RepoUserService) InviteContext(ctx context.Context, path string, username string) (*api.RepoMember, error) {
+	return s.Invite(path, username)
+}
+
 // List implements the RepoUserService interface List function.
 func (s *RepoUserService) List(path string) ([]*api.User, error) {
 	return s.Lister.List(path)
 }
 
+// ListContext implements the RepoUserService interface ListContext function.
+func (s *RepoUserService) ListContext(ctx context.Context, path string) ([]*api.User, error) {
+	return s.List(path)
+}
+
 // Revoke implements the RepoUserService interface Revoke function.
 func (s *RepoUserService) Revoke(path string, username string) (*api.RevokeRepoResponse, error) {
 	return s.Revoker.Revoke(path, username)
 }
 
+// RevokeContext implements the RepoUserService interface RevokeContext function.
+func (s *RepoUserService) RevokeContext(ctx context.Context, path string, username string) (*api.RevokeRepoResponse, error) {
+	return s.Revoke(path, username)
+}
+
 // RepoUserLister mocks the List function.
 type RepoUserLister struct {
 	ArgPath      string
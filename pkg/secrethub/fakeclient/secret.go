@@ -3,7 +3,11 @@
 package fakeclient
 
 import (
+	"context"
+	"time"
+
 	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/pkg/keygen"
 	"github.com/secrethub/secrethub-go/pkg/secrethub"
 )
 
@@ -11,10 +15,17 @@ import (
 type SecretService struct {
 	VersionService secrethub.SecretVersionService
 
-	Deleter     SecretDeleter
-	Getter      SecretGetter
-	EventLister SecretEventLister
-	Writer      Writer
+	Deleter        SecretDeleter
+	Getter         SecretGetter
+	EventLister    SecretEventLister
+	EventIterator  EventIterator
+	Writer         Writer
+	IfLatestWriter IfLatestWriter
+	Rotator        SecretRotator
+	KeyGenerator   KeyGenerator
+	Mover          Mover
+	VersionPruner  VersionPruner
+	Watcher        SecretWatcher
 }
 
 // Delete implements the SecretService interface Delete function.
@@ -22,26 +33,116 @@ func (s *SecretService) Delete(path string) error {
 	return s.Deleter.Delete(path)
 }
 
+// DeleteContext implements the SecretService interface DeleteContext function.
+func (s *SecretService) DeleteContext(ctx context.Context, path string) error {
+	return s.Delete(path)
+}
+
+// Events implements the SecretService interface Events function.
+func (s *SecretService) Events(path string, filter secrethub.AuditFilter) secrethub.AuditEventIterator {
+	return s.EventIterator.Events(path, filter)
+}
+
+// EventsContext implements the SecretService interface EventsContext function.
+func (s *SecretService) EventsContext(ctx context.Context, path string, filter secrethub.AuditFilter) secrethub.AuditEventIterator {
+	return s.Events(path, filter)
+}
+
 // Exists implements the SecretService interface Exists function.
 func (s *SecretService) Exists(path string) (bool, error) {
 	return false, nil
 }
 
+// ExistsContext implements the SecretService interface ExistsContext function.
+func (s *SecretService) ExistsContext(ctx context.Context, path string) (bool, error) {
+	return s.Exists(path)
+}
+
+// GenerateKey implements the SecretService interface GenerateKey function.
+func (s *SecretService) GenerateKey(path string, generator keygen.Generator, writePublic bool) (*secrethub.KeyResult, error) {
+	return s.KeyGenerator.GenerateKey(path, generator, writePublic)
+}
+
+// GenerateKeyContext implements the SecretService interface GenerateKeyContext function.
+func (s *SecretService) GenerateKeyContext(ctx context.Context, path string, generator keygen.Generator, writePublic bool) (*secrethub.KeyResult, error) {
+	return s.GenerateKey(path, generator, writePublic)
+}
+
+// Move implements the SecretService interface Move function.
+func (s *SecretService) Move(src, dst string, options secrethub.MoveOptions) (*secrethub.MoveResult, error) {
+	return s.Mover.Move(src, dst, options)
+}
+
+// MoveContext implements the SecretService interface MoveContext function.
+func (s *SecretService) MoveContext(ctx context.Context, src, dst string, options secrethub.MoveOptions) (*secrethub.MoveResult, error) {
+	return s.Move(src, dst, options)
+}
+
+// PruneVersions implements the SecretService interface PruneVersions function.
+func (s *SecretService) PruneVersions(path string, policy secrethub.RetentionPolicy) (*secrethub.PruneReport, error) {
+	return s.VersionPruner.PruneVersions(path, policy)
+}
+
+// PruneVersionsContext implements the SecretService interface PruneVersionsContext function.
+func (s *SecretService) PruneVersionsContext(ctx context.Context, path string, policy secrethub.RetentionPolicy) (*secrethub.PruneReport, error) {
+	return s.PruneVersions(path, policy)
+}
+
+// Watch implements the SecretService interface Watch function.
+func (s *SecretService) Watch(ctx context.Context, paths []string, interval time.Duration, withData bool) (<-chan secrethub.WatchEvent, error) {
+	return s.Watcher.Watch(ctx, paths, interval, withData)
+}
+
 // Get implements the SecretService interface Get function.
 func (s *SecretService) Get(path string) (*api.Secret, error) {
 	return s.Getter.Get(path)
 }
 
+// GetContext implements the SecretService interface GetContext function.
+func (s *SecretService) GetContext(ctx context.Context, path string) (*api.Secret, error) {
+	return s.Get(path)
+}
+
 // Write implements the SecretService interface Write function.
 func (s *SecretService) Write(path string, data []byte) (*api.SecretVersion, error) {
 	return s.Writer.Write(path, data)
 }
 
+// WriteContext implements the SecretService interface WriteContext function.
+func (s *SecretService) WriteContext(ctx context.Context, path string, data []byte) (*api.SecretVersion, error) {
+	return s.Write(path, data)
+}
+
+// WriteIfLatest implements the SecretService interface WriteIfLatest function.
+func (s *SecretService) WriteIfLatest(path string, expectedVersion int, data []byte) (*api.SecretVersion, error) {
+	return s.IfLatestWriter.WriteIfLatest(path, expectedVersion, data)
+}
+
+// WriteIfLatestContext implements the SecretService interface WriteIfLatestContext function.
+func (s *SecretService) WriteIfLatestContext(ctx context.Context, path string, expectedVersion int, data []byte) (*api.SecretVersion, error) {
+	return s.WriteIfLatest(path, expectedVersion, data)
+}
+
 // ListEvents implements the SecretService interface ListEvents function.
 func (s *SecretService) ListEvents(path string, subjectTypes api.AuditSubjectTypeList) ([]*api.Audit, error) {
 	return s.EventLister.ListEvents(path, subjectTypes)
 }
 
+// ListEventsContext implements the SecretService interface ListEventsContext function.
+func (s *SecretService) ListEventsContext(ctx context.Context, path string, subjectTypes api.AuditSubjectTypeList) ([]*api.Audit, error) {
+	return s.ListEvents(path, subjectTypes)
+}
+
+// Rotate implements the SecretService interface Rotate function.
+func (s *SecretService) Rotate(path string, policy secrethub.RotatePolicy) (*secrethub.RotateResult, error) {
+	return s.Rotator.Rotate(path, policy)
+}
+
+// RotateContext implements the SecretService interface RotateContext function.
+func (s *SecretService) RotateContext(ctx context.Context, path string, policy secrethub.RotatePolicy) (*secrethub.RotateResult, error) {
+	return s.Rotate(path, policy)
+}
+
 // Versions returns a mock of the VersionService interface.
 func (s *SecretService) Versions() secrethub.SecretVersionService {
 	return s.VersionService
@@ -101,3 +202,88 @@ func (w *Writer) Write(path string, data []byte) (*api.SecretVersion, error) {
 	w.ArgData = data
 	return w.ReturnsVersion, w.Err
 }
+
+// IfLatestWriter is a wrapper for the arguments and return values of the mocked WriteIfLatest method.
+type IfLatestWriter struct {
+	ArgPath            string
+	ArgExpectedVersion int
+	ArgData            []byte
+	ReturnsVersion     *api.SecretVersion
+	Err                error
+}
+
+// WriteIfLatest saves the arguments it was called with and returns the mocked response.
+func (w *IfLatestWriter) WriteIfLatest(path string, expectedVersion int, data []byte) (*api.SecretVersion, error) {
+	w.ArgPath = path
+	w.ArgExpectedVersion = expectedVersion
+	w.ArgData = data
+	return w.ReturnsVersion, w.Err
+}
+
+// SecretRotator mocks the Rotate function.
+type SecretRotator struct {
+	ArgPath       string
+	ArgPolicy     secrethub.RotatePolicy
+	ReturnsResult *secrethub.RotateResult
+	Err           error
+}
+
+// Rotate saves the arguments it was called with and returns the mocked response.
+func (r *SecretRotator) Rotate(path string, policy secrethub.RotatePolicy) (*secrethub.RotateResult, error) {
+	r.ArgPath = path
+	r.ArgPolicy = policy
+	return r.ReturnsResult, r.Err
+}
+
+// KeyGenerator mocks the GenerateKey function.
+type KeyGenerator struct {
+	ArgPath        string
+	ArgGenerator   keygen.Generator
+	ArgWritePublic bool
+	ReturnsResult  *secrethub.KeyResult
+	Err            error
+}
+
+// GenerateKey saves the arguments it was called with and returns the mocked response.
+func (g *KeyGenerator) GenerateKey(path string, generator keygen.Generator, writePublic bool) (*secrethub.KeyResult, error) {
+	g.ArgPath = path
+	g.ArgGenerator = generator
+	g.ArgWritePublic = writePublic
+	return g.ReturnsResult, g.Err
+}
+
+// VersionPruner mocks the PruneVersions function.
+type VersionPruner struct {
+	ArgPath       string
+	ArgPolicy     secrethub.RetentionPolicy
+	ReturnsReport *secrethub.PruneReport
+	Err           error
+}
+
+// PruneVersions saves the arguments it was called with and returns the mocked response.
+func (p *VersionPruner) PruneVersions(path string, policy secrethub.RetentionPolicy) (*secrethub.PruneReport, error) {
+	p.ArgPath = path
+	p.ArgPolicy = policy
+	return p.ReturnsReport, p.Err
+}
+
+// SecretWatcher mocks the Watch function.
+type SecretWatcher struct {
+	ArgPaths      []string
+	ArgInterval   time.Duration
+	ArgWithData   bool
+	ReturnsEvents chan secrethub.WatchEvent
+	Err           error
+}
+
+// Watch saves the arguments it was called with and returns the mocked response.
+// Events sent on ReturnsEvents are received by the caller.
+func (w *SecretWatcher) Watch(ctx context.Context, paths []string, interval time.Duration, withData bool) (<-chan secrethub.WatchEvent, error) {
+	w.ArgPaths = paths
+	w.ArgInterval = interval
+	w.ArgWithData = withData
+	if w.Err != nil {
+		return nil, w.Err
+	}
+	return w.ReturnsEvents, nil
+}
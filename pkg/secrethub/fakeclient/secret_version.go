@@ -1,16 +1,25 @@
+//go:build !production
 // +build !production
 
 package fakeclient
 
-import "github.com/secrethub/secrethub-go/internals/api"
+import (
+	"context"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+)
 
 // SecretVersionService can be used to mock a SecretVersionService.
 type SecretVersionService struct {
-	Deleter           SecretVersionDeleter
-	WithDataGetter    WithDataGetter
-	WithoutDataGetter WithoutDataGetter
-	WithDataLister    WithDataLister
-	WithoutDataLister WithoutDataLister
+	Deleter            SecretVersionDeleter
+	Differ             SecretVersionDiffer
+	WithDataGetter     WithDataGetter
+	ManyWithDataGetter ManyWithDataGetter
+	WithoutDataGetter  WithoutDataGetter
+	WithDataLister     WithDataLister
+	WithoutDataLister  WithoutDataLister
+	RollbackWriter     RollbackWriter
 }
 
 // Delete implements the SecretVersionService interface Delete function.
@@ -18,26 +27,81 @@ func (s *SecretVersionService) Delete(path string) error {
 	return s.Deleter.Delete(path)
 }
 
+// DeleteContext implements the SecretVersionService interface DeleteContext function.
+func (s *SecretVersionService) DeleteContext(ctx context.Context, path string) error {
+	return s.Delete(path)
+}
+
+// Diff implements the SecretVersionService interface Diff function.
+func (s *SecretVersionService) Diff(path string, from, to int, redact bool) (*secrethub.VersionDiff, error) {
+	return s.Differ.Diff(path, from, to, redact)
+}
+
+// DiffContext implements the SecretVersionService interface DiffContext function.
+func (s *SecretVersionService) DiffContext(ctx context.Context, path string, from, to int, redact bool) (*secrethub.VersionDiff, error) {
+	return s.Diff(path, from, to, redact)
+}
+
 // GetWithData implements the SecretVersionService interface GetWithData function.
 func (s *SecretVersionService) GetWithData(path string) (*api.SecretVersion, error) {
 	return s.WithDataGetter.GetWithData(path)
 }
 
+// GetWithDataContext implements the SecretVersionService interface GetWithDataContext function.
+func (s *SecretVersionService) GetWithDataContext(ctx context.Context, path string) (*api.SecretVersion, error) {
+	return s.GetWithData(path)
+}
+
+// GetManyWithData implements the SecretVersionService interface GetManyWithData function.
+func (s *SecretVersionService) GetManyWithData(paths []string) []secrethub.SecretVersionResult {
+	return s.ManyWithDataGetter.GetManyWithData(paths)
+}
+
+// GetManyWithDataContext implements the SecretVersionService interface GetManyWithDataContext function.
+func (s *SecretVersionService) GetManyWithDataContext(ctx context.Context, paths []string) []secrethub.SecretVersionResult {
+	return s.GetManyWithData(paths)
+}
+
 // GetWithoutData implements the SecretVersionService interface GetWithoutData function.
 func (s *SecretVersionService) GetWithoutData(path string) (*api.SecretVersion, error) {
 	return s.WithoutDataGetter.GetWithoutData(path)
 }
 
+// GetWithoutDataContext implements the SecretVersionService interface GetWithoutDataContext function.
+func (s *SecretVersionService) GetWithoutDataContext(ctx context.Context, path string) (*api.SecretVersion, error) {
+	return s.GetWithoutData(path)
+}
+
 // ListWithData implements the SecretVersionService interface ListWithData function.
 func (s *SecretVersionService) ListWithData(path string) ([]*api.SecretVersion, error) {
 	return s.WithDataLister.ListWithData(path)
 }
 
+// ListWithDataContext implements the SecretVersionService interface ListWithDataContext function.
+func (s *SecretVersionService) ListWithDataContext(ctx context.Context, path string) ([]*api.SecretVersion, error) {
+	return s.ListWithData(path)
+}
+
 // ListWithoutData implements the SecretVersionService interface ListWithoutData function.
 func (s *SecretVersionService) ListWithoutData(path string) ([]*api.SecretVersion, error) {
 	return s.WithoutDataLister.ListWithoutData(path)
 }
 
+// ListWithoutDataContext implements the SecretVersionService interface ListWithoutDataContext function.
+func (s *SecretVersionService) ListWithoutDataContext(ctx context.Context, path string) ([]*api.SecretVersion, error) {
+	return s.ListWithoutData(path)
+}
+
+// Rollback implements the SecretVersionService interface Rollback function.
+func (s *SecretVersionService) Rollback(path string, version int) (*api.SecretVersion, error) {
+	return s.RollbackWriter.Rollback(path, version)
+}
+
+// RollbackContext implements the SecretVersionService interface RollbackContext function.
+func (s *SecretVersionService) RollbackContext(ctx context.Context, path string, version int) (*api.SecretVersion, error) {
+	return s.Rollback(path, version)
+}
+
 // SecretVersionDeleter mocks the Delete function.
 type SecretVersionDeleter struct {
 	ArgPath string
@@ -63,6 +127,18 @@ func (g *WithDataGetter) GetWithData(path string) (*api.SecretVersion, error) {
 	return g.ReturnsVersion, g.Err
 }
 
+// ManyWithDataGetter mocks the GetManyWithData function.
+type ManyWithDataGetter struct {
+	ArgPaths       []string
+	ReturnsResults []secrethub.SecretVersionResult
+}
+
+// GetManyWithData saves the arguments it was called with and returns the mocked response.
+func (g *ManyWithDataGetter) GetManyWithData(paths []string) []secrethub.SecretVersionResult {
+	g.ArgPaths = paths
+	return g.ReturnsResults
+}
+
 // WithoutDataGetter mocks the GetWithoutData function.
 type WithoutDataGetter struct {
 	ArgPath        string
@@ -101,3 +177,37 @@ func (l *WithoutDataLister) ListWithoutData(path string) ([]*api.SecretVersion,
 	l.ArgPath = path
 	return l.ReturnsVersions, l.Err
 }
+
+// SecretVersionDiffer mocks the Diff function.
+type SecretVersionDiffer struct {
+	ArgPath     string
+	ArgFrom     int
+	ArgTo       int
+	ArgRedact   bool
+	ReturnsDiff *secrethub.VersionDiff
+	Err         error
+}
+
+// Diff saves the arguments it was called with and returns the mocked response.
+func (d *SecretVersionDiffer) Diff(path string, from, to int, redact bool) (*secrethub.VersionDiff, error) {
+	d.ArgPath = path
+	d.ArgFrom = from
+	d.ArgTo = to
+	d.ArgRedact = redact
+	return d.ReturnsDiff, d.Err
+}
+
+// RollbackWriter mocks the Rollback function.
+type RollbackWriter struct {
+	ArgPath        string
+	ArgVersion     int
+	ReturnsVersion *api.SecretVersion
+	Err            error
+}
+
+// Rollback saves the arguments it was called with and returns the mocked response.
+func (w *RollbackWriter) Rollback(path string, version int) (*api.SecretVersion, error) {
+	w.ArgPath = path
+	w.ArgVersion = version
+	return w.ReturnsVersion, w.Err
+}
@@ -3,6 +3,8 @@
 package fakeclient
 
 import (
+	"context"
+
 	"github.com/secrethub/secrethub-go/internals/api"
 	"github.com/secrethub/secrethub-go/pkg/secrethub"
 )
@@ -20,21 +22,41 @@ func (s *ServiceService) Create(path string, description string, credential secr
 	return s.Creater.Create(path, description, credential)
 }
 
+// CreateContext implements the ServiceService interface CreateContext function.
+func (s *ServiceService) CreateContext(ctx context.Context, path string, description string, credential secrethub.Credential) (*api.Service, error) {
+	return s.Create(path, description, credential)
+}
+
 // Delete implements the ServiceService interface Delete function.
 func (s *ServiceService) Delete(id string) (*api.RevokeRepoResponse, error) {
 	return s.Deleter.Delete(id)
 }
 
+// DeleteContext implements the ServiceService interface DeleteContext function.
+func (s *ServiceService) DeleteContext(ctx context.Context, id string) (*api.RevokeRepoResponse, error) {
+	return s.Delete(id)
+}
+
 // Get implements the ServiceService interface Get function.
 func (s *ServiceService) Get(id string) (*api.Service, error) {
 	return s.Getter.Get(id)
 }
 
+// GetContext implements the ServiceService interface GetContext function.
+func (s *ServiceService) GetContext(ctx context.Context, id string) (*api.Service, error) {
+	return s.Get(id)
+}
+
 // List implements the ServiceService interface List function.
 func (s *ServiceService) List(path string) ([]*api.Service, error) {
 	return s.Lister.List(path)
 }
 
+// ListContext implements the ServiceService interface ListContext function.
+func (s *ServiceService) ListContext(ctx context.Context, path string) ([]*api.Service, error) {
+	return s.List(path)
+}
+
 // ServiceCreater mocks the Create function.
 type ServiceCreater struct {
 	ArgPath        string
@@ -2,7 +2,11 @@
 
 package fakeclient
 
-import "github.com/secrethub/secrethub-go/internals/api"
+import (
+	"context"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+)
 
 // UserService is a mock of the UserService interface.
 type UserService struct {
@@ -16,16 +20,31 @@ func (s *UserService) Get(username string) (*api.User, error) {
 	return s.Getter.Get(username)
 }
 
+// GetContext implements the UserService interface GetContext function.
+func (s *UserService) GetContext(ctx context.Context, username string) (*api.User, error) {
+	return s.Get(username)
+}
+
 // Me implements the UserService interface Me function.
 func (s *UserService) Me() (*api.User, error) {
 	return s.MeGetter.Me()
 }
 
+// MeContext implements the UserService interface MeContext function.
+func (s *UserService) MeContext(ctx context.Context) (*api.User, error) {
+	return s.Me()
+}
+
 // Create implements the UserService interface Create function.
 func (s *UserService) Create(username, email, fullName string) (*api.User, error) {
 	return s.UserCreater.Create(username, email, fullName)
 }
 
+// CreateContext implements the UserService interface CreateContext function.
+func (s *UserService) CreateContext(ctx context.Context, username, email, fullName string) (*api.User, error) {
+	return s.Create(username, email, fullName)
+}
+
 // MeGetter is a wrapper for the return values of the mocked MeGetter method.
 type MeGetter struct {
 	ReturnsUser *api.User
@@ -1,9 +1,13 @@
 package secrethub
 
 import (
+	"context"
+	"crypto/tls"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
@@ -11,6 +15,7 @@ import (
 
 	logging "github.com/op/go-logging"
 	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/api/uuid"
 	"github.com/secrethub/secrethub-go/internals/errio"
 )
 
@@ -78,18 +83,53 @@ const (
 	DefaultTimeout = time.Second * 10
 )
 
+// Errors
+var (
+	ErrCanceled         = errClient.Code("canceled").Error("request canceled: the context of the request was canceled")
+	ErrDeadlineExceeded = errClient.Code("deadline_exceeded").Error("request canceled: the deadline of the request's context was exceeded")
+	// ErrCannotReachServer is returned when a request fails without a response from the server,
+	// e.g. because the connection is refused or the DNS lookup fails.
+	ErrCannotReachServer = errClient.Code("cannot_reach_server").ErrorPref("cannot reach the server: %s")
+)
+
 // ClientOptions define client options, overriding the default settings.
 type ClientOptions struct {
 	ServerURL string
 	Timeout   time.Duration
+	// RetryPolicy defines how requests that fail because of a transient
+	// error are retried. When nil, requests are never retried.
+	RetryPolicy *RetryPolicy
+	// Transport is used to make the HTTP requests, e.g. to use a proxy or
+	// to add custom middleware. Defaults to http.DefaultTransport.
+	// When Transport is an *http.Transport, a copy of it is used so that
+	// TLSConfig and PinnedPublicKeys can be applied to it. Other transports
+	// are used as is, ignoring TLSConfig, and cannot be combined with
+	// PinnedPublicKeys: then every request fails with ErrPinningUnsupported.
+	Transport http.RoundTripper
+	// TLSConfig configures the TLS connections to the API, e.g. to trust a
+	// custom CA bundle (RootCAs) or to present client certificates.
+	TLSConfig *tls.Config
+	// PinnedPublicKeys restricts the certificates accepted from the API to those
+	// with a pinned public key somewhere in their chain. Pins are the base64 encoded
+	// SHA-256 hashes of DER encoded SubjectPublicKeyInfos, see PublicKeyPin.
+	// When empty, no pinning is done.
+	PinnedPublicKeys []string
+	// Concurrency is the maximum number of requests that bulk operations,
+	// such as SecretVersionService.GetManyWithData, make in parallel.
+	// Defaults to DefaultConcurrency.
+	Concurrency int
+	// Cache stores the encrypted responses of the API on disk and serves them
+	// when the API cannot be reached. When nil, nothing is cached.
+	Cache *CacheOptions
 }
 
 // httpClient is a raw client for the SecretHub http API.
 type httpClient struct {
-	client  *http.Client
-	signer  auth.Credential
-	base    string // base url
-	version string
+	client      *http.Client
+	signer      auth.Credential
+	retryPolicy *RetryPolicy
+	base        string // base url
+	version     string
 }
 
 // newHTTPClient configures a new httpClient and overrides default values
@@ -97,6 +137,8 @@ type httpClient struct {
 func newHTTPClient(signer auth.Credential, opts *ClientOptions) *httpClient {
 	serverURL := DefaultServerURL
 	timeout := DefaultTimeout
+	var retryPolicy *RetryPolicy
+	var transport http.RoundTripper
 	if opts != nil {
 		if opts.ServerURL != "" {
 			serverURL = opts.ServerURL
@@ -105,6 +147,9 @@ func newHTTPClient(signer auth.Credential, opts *ClientOptions) *httpClient {
 		if opts.Timeout > 0 {
 			timeout = opts.Timeout
 		}
+
+		retryPolicy = opts.RetryPolicy
+		transport = newTransport(opts.Transport, opts.TLSConfig, opts.PinnedPublicKeys)
 	}
 
 	serverURL = strings.TrimSuffix(serverURL, "/")
@@ -112,449 +157,469 @@ func newHTTPClient(signer auth.Credential, opts *ClientOptions) *httpClient {
 
 	return &httpClient{
 		client: &http.Client{
-			Timeout: timeout,
+			Timeout:   timeout,
+			Transport: transport,
 		},
-		signer:  signer,
-		base:    serverURL,
-		version: ClientVersion,
+		signer:      signer,
+		retryPolicy: retryPolicy,
+		base:        serverURL,
+		version:     ClientVersion,
 	}
 }
 
 // ME
 
 // ListMyRepos gets a list of repos from SecretHub
-func (c *httpClient) ListMyRepos() ([]*api.Repo, error) {
+func (c *httpClient) ListMyRepos(ctx context.Context) ([]*api.Repo, error) {
 	out := []*api.Repo{}
 	rawURL := fmt.Sprintf(pathMeRepos, c.base)
-	err := c.get(rawURL, &out)
+	err := c.get(ctx, rawURL, &out)
 	return out, errio.Error(err)
 }
 
-func (c *httpClient) CreateAccountKey(in *api.CreateAccountKeyRequest, fingerprint string) (*api.EncryptedAccountKey, error) {
+func (c *httpClient) CreateAccountKey(ctx context.Context, in *api.CreateAccountKeyRequest, fingerprint string) (*api.EncryptedAccountKey, error) {
 	out := &api.EncryptedAccountKey{}
 	rawURL := fmt.Sprintf(pathCreateAccountKey, c.base, fingerprint)
-	err := c.post(rawURL, http.StatusCreated, in, out)
+	err := c.post(ctx, rawURL, http.StatusCreated, in, out)
 	return out, errio.Error(err)
 }
 
 // GetAccountKey returns the account's intermediate key encrypted with the key identified by key_identifier
-func (c *httpClient) GetAccountKey() (*api.EncryptedAccountKey, error) {
+func (c *httpClient) GetAccountKey(ctx context.Context) (*api.EncryptedAccountKey, error) {
 	out := &api.EncryptedAccountKey{}
 	rawURL := fmt.Sprintf(pathMeKey, c.base)
-	err := c.get(rawURL, out)
+	err := c.get(ctx, rawURL, out)
 	return out, errio.Error(err)
 }
 
 // GetMyUser gets the account's user.
-func (c *httpClient) GetMyUser() (*api.User, error) {
+func (c *httpClient) GetMyUser(ctx context.Context) (*api.User, error) {
 	out := &api.User{}
 	rawURL := fmt.Sprintf(pathMeUser, c.base)
-	err := c.get(rawURL, out)
+	err := c.get(ctx, rawURL, out)
 	return out, errio.Error(err)
 }
 
 // Accounts
 
 // GetAccount returns the account for a name
-func (c *httpClient) GetAccount(name api.AccountName) (*api.Account, error) {
+func (c *httpClient) GetAccount(ctx context.Context, name api.AccountName) (*api.Account, error) {
 	out := &api.Account{}
 	rawURL := fmt.Sprintf(pathAccount, c.base, name)
-	err := c.get(rawURL, out)
+	err := c.get(ctx, rawURL, out)
 	return out, errio.Error(err)
 }
 
 // USERS
 
 // SignupUser creates a new user at SecretHub
-func (c *httpClient) SignupUser(in *api.CreateUserRequest) (*api.User, error) {
+func (c *httpClient) SignupUser(ctx context.Context, in *api.CreateUserRequest) (*api.User, error) {
 	out := &api.User{}
 	rawURL := fmt.Sprintf(pathUsers, c.base)
-	err := c.post(rawURL, http.StatusCreated, in, out)
+	err := c.post(ctx, rawURL, http.StatusCreated, in, out)
 	return out, errio.Error(err)
 }
 
 // GetUser gets a user by its username from SecretHub
-func (c *httpClient) GetUser(username string) (*api.User, error) {
+func (c *httpClient) GetUser(ctx context.Context, username string) (*api.User, error) {
 	out := &api.User{}
 	rawURL := fmt.Sprintf(pathUser, c.base, username)
-	err := c.get(rawURL, out)
+	err := c.get(ctx, rawURL, out)
 	return out, errio.Error(err)
 }
 
 // REPOSITORIES
 
 // GetRepo gets a repo by its namespace and repo name
-func (c *httpClient) GetRepo(namespace, repoName string) (*api.Repo, error) {
+func (c *httpClient) GetRepo(ctx context.Context, namespace, repoName string) (*api.Repo, error) {
 	out := &api.Repo{}
 	rawURL := fmt.Sprintf(pathRepo, c.base, namespace, repoName)
-	err := c.get(rawURL, out)
+	err := c.get(ctx, rawURL, out)
 	return out, errio.Error(err)
 }
 
-func (c *httpClient) ListRepos(namespace string) ([]*api.Repo, error) {
+func (c *httpClient) ListRepos(ctx context.Context, namespace string) ([]*api.Repo, error) {
 	out := []*api.Repo{}
 	rawURL := fmt.Sprintf(pathRepos, c.base, namespace)
-	err := c.get(rawURL, &out)
+	err := c.get(ctx, rawURL, &out)
 	return out, errio.Error(err)
 }
 
 // CreateRepo  creates a new repo at SecretHub
-func (c *httpClient) CreateRepo(namespace string, in *api.CreateRepoRequest) (*api.Repo, error) {
+func (c *httpClient) CreateRepo(ctx context.Context, namespace string, in *api.CreateRepoRequest) (*api.Repo, error) {
 	out := &api.Repo{}
 	rawURL := fmt.Sprintf(pathRepos, c.base, namespace)
-	err := c.post(rawURL, http.StatusCreated, in, out)
+	err := c.post(ctx, rawURL, http.StatusCreated, in, out)
 	return out, errio.Error(err)
 }
 
 // GetRepoKeys retrieves the repo key of the user.
-func (c *httpClient) GetRepoKeys(namespace, repoName string) (*api.RepoKeys, error) {
+func (c *httpClient) GetRepoKeys(ctx context.Context, namespace, repoName string) (*api.RepoKeys, error) {
 	out := &api.RepoKeys{}
 	rawURL := fmt.Sprintf(pathRepoKey, c.base, namespace, repoName)
-	err := c.get(rawURL, out)
+	err := c.get(ctx, rawURL, out)
 	return out, errio.Error(err)
 }
 
 // DeleteRepo deletes a repo
-func (c *httpClient) DeleteRepo(namespace, repoName string) error {
+func (c *httpClient) DeleteRepo(ctx context.Context, namespace, repoName string) error {
 	rawURL := fmt.Sprintf(pathRepo, c.base, namespace, repoName)
-	err := c.delete(rawURL, nil)
+	err := c.delete(ctx, rawURL, nil)
 	return errio.Error(err)
 }
 
 // AuditRepo gets the audit events for a given repo.
-func (c *httpClient) AuditRepo(namespace, repoName string, subjectTypes api.AuditSubjectTypeList) ([]*api.Audit, error) {
+func (c *httpClient) AuditRepo(ctx context.Context, namespace, repoName string, subjectTypes api.AuditSubjectTypeList) ([]*api.Audit, error) {
 	out := []*api.Audit{}
 	rawURL := fmt.Sprintf(pathRepoEvents+"?subject_types=%s", c.base, namespace, repoName, subjectTypes.Join(","))
-	err := c.get(rawURL, &out)
+	err := c.get(ctx, rawURL, &out)
+	return out, errio.Error(err)
+}
+
+// AuditRepoPage gets a page of at most perPage audit events for a given repo,
+// starting after the event with the given ID, or at the first event when it is nil.
+func (c *httpClient) AuditRepoPage(ctx context.Context, namespace, repoName string, subjectTypes api.AuditSubjectTypeList, startingAfter *uuid.UUID, perPage int) ([]*api.Audit, error) {
+	out := []*api.Audit{}
+	rawURL := fmt.Sprintf(pathRepoEvents+"?%s", c.base, namespace, repoName, auditPageQuery(subjectTypes, startingAfter, perPage))
+	err := c.get(ctx, rawURL, &out)
 	return out, errio.Error(err)
 }
 
 // ListRepoAccounts lists the accounts of a repo.
-func (c *httpClient) ListRepoAccounts(namespace, repoName string) ([]*api.Account, error) {
+func (c *httpClient) ListRepoAccounts(ctx context.Context, namespace, repoName string) ([]*api.Account, error) {
 	out := []*api.Account{}
 	rawURL := fmt.Sprintf(pathRepoAccounts, c.base, namespace, repoName)
-	err := c.get(rawURL, &out)
+	err := c.get(ctx, rawURL, &out)
 	return out, errio.Error(err)
 }
 
 // REPO USERS
 
 // InviteRepo adds a user to a repo.
-func (c *httpClient) InviteRepo(namespace, repoName string, in *api.InviteUserRequest) (*api.RepoMember, error) {
+func (c *httpClient) InviteRepo(ctx context.Context, namespace, repoName string, in *api.InviteUserRequest) (*api.RepoMember, error) {
 	out := &api.RepoMember{}
 	rawURL := fmt.Sprintf(pathRepoUsers, c.base, namespace, repoName)
-	err := c.post(rawURL, http.StatusOK, in, out)
+	err := c.post(ctx, rawURL, http.StatusOK, in, out)
 	return out, errio.Error(err)
 }
 
 // GetRepoUser retrieves a user for a repo.
 // If the user is a repo member, then the user is retrieved.
-func (c *httpClient) GetRepoUser(namespace, repoName, username string) (*api.User, error) {
+func (c *httpClient) GetRepoUser(ctx context.Context, namespace, repoName, username string) (*api.User, error) {
 	out := &api.User{}
 	rawURL := fmt.Sprintf(pathRepoUser, c.base, namespace, repoName, username)
-	err := c.get(rawURL, out)
+	err := c.get(ctx, rawURL, out)
 	return out, errio.Error(err)
 }
 
 // RemoveUser removes a user from a repo.
-func (c *httpClient) RemoveUser(namespace, repoName, username string) (*api.RevokeRepoResponse, error) {
+func (c *httpClient) RemoveUser(ctx context.Context, namespace, repoName, username string) (*api.RevokeRepoResponse, error) {
 	out := &api.RevokeRepoResponse{}
 	rawURL := fmt.Sprintf(pathRepoUser, c.base, namespace, repoName, username)
-	err := c.delete(rawURL, out)
+	err := c.delete(ctx, rawURL, out)
 	return out, errio.Error(err)
 }
 
 // ListRepoUsers lists the users of a repo.
-func (c *httpClient) ListRepoUsers(namespace, repoName string) ([]*api.User, error) {
+func (c *httpClient) ListRepoUsers(ctx context.Context, namespace, repoName string) ([]*api.User, error) {
 	out := []*api.User{}
 	rawURL := fmt.Sprintf(pathRepoUsers, c.base, namespace, repoName)
-	err := c.get(rawURL, &out)
+	err := c.get(ctx, rawURL, &out)
 	return out, errio.Error(err)
 }
 
 // Service
 
 // CreateService creates a new service for a repo.
-func (c *httpClient) CreateService(namespace, repoName string, in *api.CreateServiceRequest) (*api.Service, error) {
+func (c *httpClient) CreateService(ctx context.Context, namespace, repoName string, in *api.CreateServiceRequest) (*api.Service, error) {
 	out := &api.Service{}
 	rawURL := fmt.Sprintf(pathServices, c.base, namespace, repoName)
-	err := c.post(rawURL, http.StatusCreated, in, out)
+	err := c.post(ctx, rawURL, http.StatusCreated, in, out)
 	return out, errio.Error(err)
 }
 
 // GetServices retrieves a service.
-func (c *httpClient) GetService(service string) (*api.Service, error) {
+func (c *httpClient) GetService(ctx context.Context, service string) (*api.Service, error) {
 	out := &api.Service{}
 	rawURL := fmt.Sprintf(pathService, c.base, service)
-	err := c.get(rawURL, out)
+	err := c.get(ctx, rawURL, out)
 	return out, errio.Error(err)
 }
 
 // DeleteService deletes an service.
-func (c *httpClient) DeleteService(service string) (*api.RevokeRepoResponse, error) {
+func (c *httpClient) DeleteService(ctx context.Context, service string) (*api.RevokeRepoResponse, error) {
 	out := &api.RevokeRepoResponse{}
 	rawURL := fmt.Sprintf(pathService, c.base, service)
-	err := c.delete(rawURL, out)
+	err := c.delete(ctx, rawURL, out)
 	return out, errio.Error(err)
 }
 
 // ListServices lists the services for a repo.
-func (c *httpClient) ListServices(namespace, repoName string) ([]*api.Service, error) {
+func (c *httpClient) ListServices(ctx context.Context, namespace, repoName string) ([]*api.Service, error) {
 	out := []*api.Service{}
 	rawURL := fmt.Sprintf(pathServices, c.base, namespace, repoName)
-	err := c.get(rawURL, &out)
+	err := c.get(ctx, rawURL, &out)
 	return out, errio.Error(err)
 }
 
 // DIRS
 
 // CreateDir creates a new directory in the repo.
-func (c *httpClient) CreateDir(namespace, repoName string, in *api.CreateDirRequest) (*api.EncryptedDir, error) {
+func (c *httpClient) CreateDir(ctx context.Context, namespace, repoName string, in *api.CreateDirRequest) (*api.EncryptedDir, error) {
 	rawURL := fmt.Sprintf(pathRepoDirs, c.base, namespace, repoName)
 	out := &api.EncryptedDir{}
-	err := c.post(rawURL, http.StatusCreated, in, &out)
+	err := c.post(ctx, rawURL, http.StatusCreated, in, &out)
 	return out, errio.Error(err)
 }
 
 // GetTree gets a directory and all of it subdirs and secrets recursively by blind name.
 // If depth is > 0 then the result is limited to depth
 // If ancestors = true then ancestors are added.
-func (c *httpClient) GetTree(dirBlindName string, depth int, ancestor bool) (*api.EncryptedTree, error) {
+func (c *httpClient) GetTree(ctx context.Context, dirBlindName string, depth int, ancestor bool) (*api.EncryptedTree, error) {
 	rawURL := fmt.Sprintf(pathDir, c.base, dirBlindName)
 	rawURL = fmt.Sprintf(rawURL+"?depth=%d&ancestors=%v", depth, ancestor)
 	out := &api.EncryptedTree{}
-	err := c.get(rawURL, out)
+	err := c.get(ctx, rawURL, out)
 	return out, errio.Error(err)
 }
 
 // ListDirAccounts returns all accounts with read access.
-func (c *httpClient) ListDirAccounts(dirBlindName string) ([]*api.Account, error) {
+func (c *httpClient) ListDirAccounts(ctx context.Context, dirBlindName string) ([]*api.Account, error) {
 	out := []*api.Account{}
 	rawURL := fmt.Sprintf(pathDirAccounts, c.base, dirBlindName)
-	err := c.get(rawURL, &out)
+	err := c.get(ctx, rawURL, &out)
 	return out, errio.Error(err)
 }
 
 // DeleteDir deletes a directory by blind name.
-func (c *httpClient) DeleteDir(dirBlindName string) error {
+func (c *httpClient) DeleteDir(ctx context.Context, dirBlindName string) error {
 	rawURL := fmt.Sprintf(pathDir, c.base, dirBlindName)
-	err := c.delete(rawURL, nil)
+	err := c.delete(ctx, rawURL, nil)
 	return errio.Error(err)
 }
 
 // ACL
 
 // CreateAccessRule creates an AccessRule.
-func (c *httpClient) CreateAccessRule(dirBlindName string, accountName api.AccountName, in *api.CreateAccessRuleRequest) (*api.AccessRule, error) {
+func (c *httpClient) CreateAccessRule(ctx context.Context, dirBlindName string, accountName api.AccountName, in *api.CreateAccessRuleRequest) (*api.AccessRule, error) {
 	out := &api.AccessRule{}
 	rawURL := fmt.Sprintf(pathDirRule, c.base, dirBlindName, accountName)
-	err := c.put(rawURL, http.StatusOK, in, out)
+	err := c.put(ctx, rawURL, http.StatusOK, in, out)
 	return out, errio.Error(err)
 }
 
 // UpdateAccessRule updates an AccessRule.
-func (c *httpClient) UpdateAccessRule(dirBlindName string, accountName api.AccountName, in *api.UpdateAccessRuleRequest) (*api.AccessRule, error) {
+func (c *httpClient) UpdateAccessRule(ctx context.Context, dirBlindName string, accountName api.AccountName, in *api.UpdateAccessRuleRequest) (*api.AccessRule, error) {
 	out := &api.AccessRule{}
 	rawURL := fmt.Sprintf(pathDirRule, c.base, dirBlindName, accountName)
-	err := c.patch(rawURL, http.StatusOK, in, out)
+	err := c.patch(ctx, rawURL, http.StatusOK, in, out)
 	return out, errio.Error(err)
 }
 
 // GetAccessLevel gets an access level for an account.
-func (c *httpClient) GetAccessLevel(dirBlindName string, accountName api.AccountName) (*api.AccessLevel, error) {
+func (c *httpClient) GetAccessLevel(ctx context.Context, dirBlindName string, accountName api.AccountName) (*api.AccessLevel, error) {
 	out := &api.AccessLevel{}
 	rawURL := fmt.Sprintf(pathDirPermission, c.base, dirBlindName, accountName)
-	err := c.get(rawURL, out)
+	err := c.get(ctx, rawURL, out)
 	return out, errio.Error(err)
 }
 
 // GetAccessRule gets an access rule for an account.
-func (c *httpClient) GetAccessRule(dirBlindName string, accountName api.AccountName) (*api.AccessRule, error) {
+func (c *httpClient) GetAccessRule(ctx context.Context, dirBlindName string, accountName api.AccountName) (*api.AccessRule, error) {
 	out := &api.AccessRule{}
 	rawURL := fmt.Sprintf(pathDirRule, c.base, dirBlindName, accountName)
-	err := c.get(rawURL, out)
+	err := c.get(ctx, rawURL, out)
 	return out, errio.Error(err)
 }
 
 // ListAccessRules gets the access rules for a given directory.
-func (c *httpClient) ListAccessRules(dirBlindName string, depth int, withAncestors bool) ([]*api.AccessRule, error) {
+func (c *httpClient) ListAccessRules(ctx context.Context, dirBlindName string, depth int, withAncestors bool) ([]*api.AccessRule, error) {
 	out := []*api.AccessRule{}
 	rawURL := fmt.Sprintf(pathDirRules, c.base, dirBlindName)
 	rawURL = fmt.Sprintf(rawURL+"?depth=%d&ancestors=%v", depth, withAncestors)
-	err := c.get(rawURL, &out)
+	err := c.get(ctx, rawURL, &out)
 	return out, errio.Error(err)
 }
 
 // DeleteAccessRule deletes an access rule for an account.
-func (c *httpClient) DeleteAccessRule(dirBlindName string, accountName api.AccountName) error {
+func (c *httpClient) DeleteAccessRule(ctx context.Context, dirBlindName string, accountName api.AccountName) error {
 	rawURL := fmt.Sprintf(pathDirRule, c.base, dirBlindName, accountName)
-	err := c.delete(rawURL, nil)
+	err := c.delete(ctx, rawURL, nil)
 	return errio.Error(err)
 }
 
 // SECRETS
 
 // CreateSecret writes a new secret.
-func (c httpClient) CreateSecret(namespace, repoName, dirBlindName string, in *api.CreateSecretRequest) (*api.EncryptedSecretVersion, error) {
+func (c httpClient) CreateSecret(ctx context.Context, namespace, repoName, dirBlindName string, in *api.CreateSecretRequest) (*api.EncryptedSecretVersion, error) {
 	rawURL := fmt.Sprintf(pathRepoDirSecrets, c.base, namespace, repoName, dirBlindName)
 	out := &api.EncryptedSecretVersion{}
-	err := c.post(rawURL, http.StatusCreated, in, &out)
+	err := c.post(ctx, rawURL, http.StatusCreated, in, &out)
 	return out, errio.Error(err)
 }
 
 // GetSecret gets a secret by its blind name.
 // Note that this does not include the versions and secret data.
-func (c *httpClient) GetSecret(secretBlindName string) (*api.EncryptedSecret, error) {
+func (c *httpClient) GetSecret(ctx context.Context, secretBlindName string) (*api.EncryptedSecret, error) {
 	out := &api.EncryptedSecret{}
 	rawURL := fmt.Sprintf(pathSecret, c.base, secretBlindName)
-	err := c.get(rawURL, out)
+	err := c.get(ctx, rawURL, out)
 	return out, errio.Error(err)
 }
 
 // CreateSecretVersion creates a new version of an existing secret.
-func (c httpClient) CreateSecretVersion(blindName string, in *api.CreateSecretVersionRequest) (*api.EncryptedSecretVersion, error) {
+func (c httpClient) CreateSecretVersion(ctx context.Context, blindName string, in *api.CreateSecretVersionRequest) (*api.EncryptedSecretVersion, error) {
 	rawURL := fmt.Sprintf(pathSecretVersions, c.base, blindName)
 	out := &api.EncryptedSecretVersion{}
-	err := c.post(rawURL, http.StatusCreated, in, &out)
+	err := c.post(ctx, rawURL, http.StatusCreated, in, &out)
 	return out, errio.Error(err)
 }
 
 // ListSecretVersions lists all versions of a secret by its name.
-func (c *httpClient) ListSecretVersions(secretBlindName string, withData bool) ([]*api.EncryptedSecretVersion, error) {
+func (c *httpClient) ListSecretVersions(ctx context.Context, secretBlindName string, withData bool) ([]*api.EncryptedSecretVersion, error) {
 	out := []*api.EncryptedSecretVersion{}
 	rawURL := fmt.Sprintf(pathSecretVersions+"?encrypted_blob=%t", c.base, secretBlindName, withData)
-	err := c.get(rawURL, &out)
+	err := c.get(ctx, rawURL, &out)
 	return out, errio.Error(err)
 }
 
 // GetSecret gets a single secret by its name.
-func (c *httpClient) GetSecretLatestVersion(secretBlindName string, withData bool) (*api.EncryptedSecretVersion, error) {
+func (c *httpClient) GetSecretLatestVersion(ctx context.Context, secretBlindName string, withData bool) (*api.EncryptedSecretVersion, error) {
 	out := &api.EncryptedSecretVersion{}
 	rawURL := fmt.Sprintf(pathSecret+"?encrypted_blob=%t", c.base, secretBlindName, withData)
-	err := c.get(rawURL, out)
+	err := c.get(ctx, rawURL, out)
 	return out, errio.Error(err)
 }
 
 // GetSecretVersion gets a single version of a secret by its name.
-func (c *httpClient) GetSecretVersion(secretBlindName string, version string, withData bool) (*api.EncryptedSecretVersion, error) {
+func (c *httpClient) GetSecretVersion(ctx context.Context, secretBlindName string, version string, withData bool) (*api.EncryptedSecretVersion, error) {
 	out := &api.EncryptedSecretVersion{}
 	rawURL := fmt.Sprintf(pathSecretVersion+"?encrypted_blob=%t", c.base, secretBlindName, version, withData)
-	err := c.get(rawURL, out)
+	err := c.get(ctx, rawURL, out)
 	return out, errio.Error(err)
 }
 
 // GetCurrentSecretKey gets the secret key currently used for encrypting the secret.
-func (c *httpClient) GetCurrentSecretKey(secretBlindName string) (*api.EncryptedSecretKey, error) {
+func (c *httpClient) GetCurrentSecretKey(ctx context.Context, secretBlindName string) (*api.EncryptedSecretKey, error) {
 	out := &api.EncryptedSecretKey{}
 	rawURL := fmt.Sprintf(pathSecretKey, c.base, secretBlindName)
-	err := c.get(rawURL, out)
+	err := c.get(ctx, rawURL, out)
 	return out, errio.Error(err)
 }
 
 // CreateSecretKey creates a new secret key.
-func (c *httpClient) CreateSecretKey(secretBlindName string, in *api.CreateSecretKeyRequest) (*api.EncryptedSecretKey, error) {
+func (c *httpClient) CreateSecretKey(ctx context.Context, secretBlindName string, in *api.CreateSecretKeyRequest) (*api.EncryptedSecretKey, error) {
 	out := &api.EncryptedSecretKey{}
 	rawURL := fmt.Sprintf(pathSecretKeys, c.base, secretBlindName)
-	err := c.post(rawURL, http.StatusCreated, in, out)
+	err := c.post(ctx, rawURL, http.StatusCreated, in, out)
 	return out, errio.Error(err)
 }
 
 // AuditSecret gets the audit events for a given secret.
-func (c *httpClient) AuditSecret(secretBlindName string, subjectTypes api.AuditSubjectTypeList) ([]*api.Audit, error) {
+func (c *httpClient) AuditSecret(ctx context.Context, secretBlindName string, subjectTypes api.AuditSubjectTypeList) ([]*api.Audit, error) {
 	out := []*api.Audit{}
 	rawURL := fmt.Sprintf(pathSecretEvents+"?subject_types=%s", c.base, secretBlindName, subjectTypes.Join(","))
-	err := c.get(rawURL, &out)
+	err := c.get(ctx, rawURL, &out)
+	return out, errio.Error(err)
+}
+
+// AuditSecretPage gets a page of at most perPage audit events for a given secret,
+// starting after the event with the given ID, or at the first event when it is nil.
+func (c *httpClient) AuditSecretPage(ctx context.Context, secretBlindName string, subjectTypes api.AuditSubjectTypeList, startingAfter *uuid.UUID, perPage int) ([]*api.Audit, error) {
+	out := []*api.Audit{}
+	rawURL := fmt.Sprintf(pathSecretEvents+"?%s", c.base, secretBlindName, auditPageQuery(subjectTypes, startingAfter, perPage))
+	err := c.get(ctx, rawURL, &out)
 	return out, errio.Error(err)
 }
 
 // DeleteSecret deletes a secret.
-func (c *httpClient) DeleteSecret(secretBlindName string) error {
+func (c *httpClient) DeleteSecret(ctx context.Context, secretBlindName string) error {
 	rawURL := fmt.Sprintf(pathSecret, c.base, secretBlindName)
-	err := c.delete(rawURL, nil)
+	err := c.delete(ctx, rawURL, nil)
 	return errio.Error(err)
 }
 
 // DeleteSecretVersion deletes a version of a secret.
-func (c *httpClient) DeleteSecretVersion(secretBlindName string, version string) error {
+func (c *httpClient) DeleteSecretVersion(ctx context.Context, secretBlindName string, version string) error {
 	rawURL := fmt.Sprintf(pathSecretVersion, c.base, secretBlindName, version)
-	err := c.delete(rawURL, nil)
+	err := c.delete(ctx, rawURL, nil)
 	return errio.Error(err)
 }
 
 // ListSecretKeys lists an account's secret keys.
-func (c *httpClient) ListSecretKeys(secretBlindName string) ([]*api.EncryptedSecretKey, error) {
+func (c *httpClient) ListSecretKeys(ctx context.Context, secretBlindName string) ([]*api.EncryptedSecretKey, error) {
 	out := []*api.EncryptedSecretKey{}
 	rawURL := fmt.Sprintf(pathSecretKeys, c.base, secretBlindName)
-	err := c.get(rawURL, &out)
+	err := c.get(ctx, rawURL, &out)
 	return out, errio.Error(err)
 }
 
 // Orgs
 
 // CreateOrg creates an organization.
-func (c *httpClient) CreateOrg(in *api.CreateOrgRequest) (*api.Org, error) {
+func (c *httpClient) CreateOrg(ctx context.Context, in *api.CreateOrgRequest) (*api.Org, error) {
 	out := &api.Org{}
 	rawURL := fmt.Sprintf(pathOrgs, c.base)
-	err := c.post(rawURL, http.StatusCreated, in, out)
+	err := c.post(ctx, rawURL, http.StatusCreated, in, out)
 	return out, errio.Error(err)
 }
 
 // GetOrg gets an organization's details.
-func (c *httpClient) GetOrg(name string) (*api.Org, error) {
+func (c *httpClient) GetOrg(ctx context.Context, name string) (*api.Org, error) {
 	out := &api.Org{}
 	rawURL := fmt.Sprintf(pathOrg, c.base, name)
-	err := c.get(rawURL, out)
+	err := c.get(ctx, rawURL, out)
 	return out, errio.Error(err)
 }
 
 // ListMyOrgs lists the organizations an account is a member of.
-func (c *httpClient) ListMyOrgs() ([]*api.Org, error) {
+func (c *httpClient) ListMyOrgs(ctx context.Context) ([]*api.Org, error) {
 	out := []*api.Org{}
 	rawURL := fmt.Sprintf(pathOrgs, c.base)
-	err := c.get(rawURL, &out)
+	err := c.get(ctx, rawURL, &out)
 	return out, errio.Error(err)
 }
 
 // DeleteOrg permanently deletes an organization and all of its resources.
-func (c *httpClient) DeleteOrg(name string) error {
+func (c *httpClient) DeleteOrg(ctx context.Context, name string) error {
 	rawURL := fmt.Sprintf(pathOrg, c.base, name)
-	err := c.delete(rawURL, nil)
+	err := c.delete(ctx, rawURL, nil)
 	return errio.Error(err)
 }
 
 // ListOrgMembers lists an organization's members.
-func (c *httpClient) ListOrgMembers(name string) ([]*api.OrgMember, error) {
+func (c *httpClient) ListOrgMembers(ctx context.Context, name string) ([]*api.OrgMember, error) {
 	out := []*api.OrgMember{}
 	rawURL := fmt.Sprintf(pathOrgMembers, c.base, name)
-	err := c.get(rawURL, &out)
+	err := c.get(ctx, rawURL, &out)
 	return out, errio.Error(err)
 }
 
 // GetOrgMember gets a  user's organization membership details.
-func (c *httpClient) GetOrgMember(name string, username string) (*api.OrgMember, error) {
+func (c *httpClient) GetOrgMember(ctx context.Context, name string, username string) (*api.OrgMember, error) {
 	out := &api.OrgMember{}
 	rawURL := fmt.Sprintf(pathOrgMember, c.base, name, username)
-	err := c.get(rawURL, out)
+	err := c.get(ctx, rawURL, out)
 	return out, errio.Error(err)
 }
 
 // CreateOrgMember creates a new organization member.
-func (c *httpClient) CreateOrgMember(name string, in *api.CreateOrgMemberRequest) (*api.OrgMember, error) {
+func (c *httpClient) CreateOrgMember(ctx context.Context, name string, in *api.CreateOrgMemberRequest) (*api.OrgMember, error) {
 	out := &api.OrgMember{}
 	rawURL := fmt.Sprintf(pathOrgMembers, c.base, name)
-	err := c.post(rawURL, http.StatusCreated, in, out)
+	err := c.post(ctx, rawURL, http.StatusCreated, in, out)
 	return out, errio.Error(err)
 }
 
-func (c *httpClient) UpdateOrgMember(name string, username string, in *api.UpdateOrgMemberRequest) (*api.OrgMember, error) {
+func (c *httpClient) UpdateOrgMember(ctx context.Context, name string, username string, in *api.UpdateOrgMemberRequest) (*api.OrgMember, error) {
 	out := &api.OrgMember{}
 	rawURL := fmt.Sprintf(pathOrgMember, c.base, name, username)
-	err := c.post(rawURL, http.StatusOK, in, out)
+	err := c.post(ctx, rawURL, http.StatusOK, in, out)
 	return out, errio.Error(err)
 }
 
 // RevokeOrgMember revokes an organization member.
-func (c *httpClient) RevokeOrgMember(name string, username string, opts *api.RevokeOpts) (*api.RevokeOrgResponse, error) {
+func (c *httpClient) RevokeOrgMember(ctx context.Context, name string, username string, opts *api.RevokeOpts) (*api.RevokeOrgResponse, error) {
 	out := &api.RevokeOrgResponse{}
 	rawURL := fmt.Sprintf(pathOrgMember, c.base, name, username)
 	if opts != nil {
@@ -564,72 +629,93 @@ func (c *httpClient) RevokeOrgMember(name string, username string, opts *api.Rev
 		}
 		rawURL = fmt.Sprintf("%s?%s", rawURL, values.Encode())
 	}
-	err := c.delete(rawURL, out)
+	err := c.delete(ctx, rawURL, out)
 	return out, errio.Error(err)
 }
 
 // HELPER METHODS
 
+// auditPageQuery returns the encoded query parameters to request a page of audit events.
+func auditPageQuery(subjectTypes api.AuditSubjectTypeList, startingAfter *uuid.UUID, perPage int) string {
+	values := url.Values{}
+	values.Set("subject_types", subjectTypes.Join(","))
+	values.Set("per_page", strconv.Itoa(perPage))
+	if startingAfter != nil {
+		values.Set("starting_after", startingAfter.ToString())
+	}
+	return values.Encode()
+}
+
 // get is a helper function to make an http GET request.
-func (c *httpClient) get(rawURL string, out interface{}) error {
-	err := c.do(rawURL, "GET", http.StatusOK, nil, out)
+func (c *httpClient) get(ctx context.Context, rawURL string, out interface{}) error {
+	err := c.do(ctx, rawURL, "GET", http.StatusOK, nil, out)
 	return errio.Error(err)
 }
 
 // post is a helper function to make an http POST request
-func (c *httpClient) post(rawURL string, expectedStatus int, in interface{}, out interface{}) error {
-	err := c.do(rawURL, "POST", expectedStatus, in, out)
+func (c *httpClient) post(ctx context.Context, rawURL string, expectedStatus int, in interface{}, out interface{}) error {
+	err := c.do(ctx, rawURL, "POST", expectedStatus, in, out)
 	return errio.Error(err)
 }
 
 // put is a helper function to make an http PUT request.
-func (c *httpClient) put(rawURL string, expectedStatus int, in interface{}, out interface{}) error {
-	err := c.do(rawURL, "PUT", expectedStatus, in, out)
+func (c *httpClient) put(ctx context.Context, rawURL string, expectedStatus int, in interface{}, out interface{}) error {
+	err := c.do(ctx, rawURL, "PUT", expectedStatus, in, out)
 	return errio.Error(err)
 }
 
 // patch is a helper function to make an http PATCH request.
-func (c *httpClient) patch(rawURL string, expectedStatus int, in interface{}, out interface{}) error {
-	err := c.do(rawURL, "PATCH", expectedStatus, in, out)
+func (c *httpClient) patch(ctx context.Context, rawURL string, expectedStatus int, in interface{}, out interface{}) error {
+	err := c.do(ctx, rawURL, "PATCH", expectedStatus, in, out)
 	return errio.Error(err)
 }
 
 // delete is a helper function to make an http DELETE request.
-func (c *httpClient) delete(rawURL string, out interface{}) error {
-	err := c.do(rawURL, "DELETE", http.StatusOK, nil, out)
+func (c *httpClient) delete(ctx context.Context, rawURL string, out interface{}) error {
+	err := c.do(ctx, rawURL, "DELETE", http.StatusOK, nil, out)
 	return errio.Error(err)
 }
 
 // Helper function to make an http request. Parses the url, encodes in as the request body,
 // executes an http request. If the server returns the wrong statuscode, we try to parse
 // the error and return it. If everything went well, it decodes the response body into out.
-func (c *httpClient) do(rawURL string, method string, expectedStatus int, in interface{}, out interface{}) error {
+// The request is bound to ctx, so it is aborted when ctx is canceled or its deadline expires.
+// Requests that fail because of a transient error are retried according to the client's RetryPolicy.
+func (c *httpClient) do(ctx context.Context, rawURL string, method string, expectedStatus int, in interface{}, out interface{}) error {
 	uri, err := url.Parse(rawURL)
 	if err != nil {
 		return errio.Error(err)
 	}
 
-	req, err := http.NewRequest(method, uri.String(), nil)
-	if err != nil {
-		return errio.Error(err)
-	}
-
-	err = encodeRequest(req, in)
-	if err != nil {
-		return errio.Error(err)
-	}
-
-	err = c.signer.AddAuthentication(req)
-	if err != nil {
-		return errio.Error(err)
-	}
+	var resp *http.Response
+	for attempt := 0; ; attempt++ {
+		req, err := c.newRequest(ctx, uri, method, in)
+		if err != nil {
+			return errio.Error(err)
+		}
 
-	req.Header.Set("User-Agent", "SecretHub/"+c.version)
+		resp, err = c.client.Do(req)
+		if ctx.Err() == nil && c.retryPolicy.shouldRetry(attempt, method, resp, err) {
+			backoff, ok := c.retryPolicy.backoff(attempt, resp)
+			if ok {
+				if resp != nil {
+					discardResponse(resp)
+				}
+
+				err = sleep(ctx, backoff)
+				if err != nil {
+					return contextError(ctx, err)
+				}
+				continue
+			}
+		}
 
-	resp, err := c.client.Do(req)
-	if err != nil {
-		return errio.Error(err)
+		if err != nil {
+			return contextError(ctx, err)
+		}
+		break
 	}
+	defer resp.Body.Close()
 
 	if resp.StatusCode == http.StatusUpgradeRequired {
 		return errClient.Code("out_of_date").Errorf(
@@ -646,3 +732,45 @@ func (c *httpClient) do(rawURL string, method string, expectedStatus int, in int
 
 	return nil
 }
+
+// newRequest creates a signed http request. A new request is created for every
+// attempt, so that the signature of a retried request never expires.
+func (c *httpClient) newRequest(ctx context.Context, uri *url.URL, method string, in interface{}) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, uri.String(), nil)
+	if err != nil {
+		return nil, errio.Error(err)
+	}
+
+	err = encodeRequest(req, in)
+	if err != nil {
+		return nil, errio.Error(err)
+	}
+
+	err = c.signer.AddAuthentication(req)
+	if err != nil {
+		return nil, errio.Error(err)
+	}
+
+	req.Header.Set("User-Agent", "SecretHub/"+c.version)
+
+	return req, nil
+}
+
+// contextError returns ErrCanceled or ErrDeadlineExceeded when the given context
+// is done, so callers can distinguish an aborted request from a failing one.
+// Otherwise, any known error wrapped by the transport is unwrapped and returned
+// and all other errors are returned as ErrCannotReachServer.
+func contextError(ctx context.Context, err error) error {
+	switch ctx.Err() {
+	case context.Canceled:
+		return ErrCanceled
+	case context.DeadlineExceeded:
+		return ErrDeadlineExceeded
+	}
+
+	var publicErr errio.PublicError
+	if errors.As(err, &publicErr) {
+		return publicErr
+	}
+	return ErrCannotReachServer(err)
+}
@@ -0,0 +1,77 @@
+package secrethub
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/internals/errio"
+)
+
+func TestDo_ContextDeadlineExceeded(t *testing.T) {
+	// Arrange
+	router, opts, cleanup := setup()
+	defer cleanup()
+
+	client := NewClient(cred1, opts)
+
+	router.Get("/orgs/{org_name}", func(w http.ResponseWriter, r *http.Request) {
+		// Block until the client gives up on the request.
+		<-r.Context().Done()
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	// Act
+	_, err := client.Orgs().GetContext(ctx, "myorg")
+
+	// Assert
+	assert.Equal(t, err, ErrDeadlineExceeded)
+}
+
+func TestDo_ContextCanceled(t *testing.T) {
+	// Arrange
+	router, opts, cleanup := setup()
+	defer cleanup()
+
+	client := NewClient(cred1, opts)
+
+	called := false
+	router.Get("/orgs/{org_name}", func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(&api.Org{})
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// Act
+	_, err := client.Orgs().GetContext(ctx, "myorg")
+
+	// Assert
+	assert.Equal(t, err, ErrCanceled)
+	assert.Equal(t, called, false)
+}
+
+func TestDo_CannotReachServer(t *testing.T) {
+	// Arrange
+	_, opts, cleanup := setup()
+	cleanup()
+
+	client := NewClient(cred1, opts)
+
+	// Act
+	_, err := client.Orgs().Get("myorg")
+
+	// Assert
+	publicErr, ok := err.(errio.PublicError)
+	assert.Equal(t, ok, true)
+	assert.Equal(t, publicErr.Code, "cannot_reach_server")
+	assert.Equal(t, isUnavailable(err), true)
+}
@@ -0,0 +1,94 @@
+package secrethub
+
+import (
+	"context"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/errio"
+	"github.com/secrethub/secrethub-go/pkg/keygen"
+)
+
+// KeyResult is the result of generating key material and writing it to SecretHub.
+type KeyResult struct {
+	// Private is the version of the secret that contains the private part of the key.
+	Private *api.SecretVersion
+	// PublicPath is the path of the secret the public part of the key is written to.
+	// It is empty when the public part is not written.
+	PublicPath string
+	// Public is the version of the secret that contains the public part of the key.
+	// It is nil when the public part is not written.
+	Public *api.SecretVersion
+}
+
+// GenerateKey generates key material with the generator and writes its private part to the
+// secret at the given path. When writePublic is set and the key has a public part, the public
+// part is written to a sibling secret, of which the name is the name of the secret followed
+// by the public suffix of the key, e.g. id_ed25519.pub for an SSH key written to id_ed25519.
+//
+// The private part is written first. When writing the public part fails, the result is
+// returned together with the error.
+func (s secretService) GenerateKey(path string, generator keygen.Generator, writePublic bool) (*KeyResult, error) {
+	return s.GenerateKeyContext(context.Background(), path, generator, writePublic)
+}
+
+// GenerateKeyContext is the same as GenerateKey, but uses the given context for all requests.
+func (s secretService) GenerateKeyContext(ctx context.Context, path string, generator keygen.Generator, writePublic bool) (*KeyResult, error) {
+	secretPath, err := api.NewSecretPath(path)
+	if err != nil {
+		return nil, errio.Error(err)
+	}
+
+	if secretPath.HasVersion() {
+		return nil, ErrCannotWriteToVersion
+	}
+
+	key, err := generator.GenerateKey()
+	if err != nil {
+		return nil, errio.Error(err)
+	}
+
+	var publicPath api.SecretPath
+	if writePublic && len(key.Public) > 0 {
+		publicPath, err = PublicKeyPath(secretPath, key.PublicSuffix)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	private, err := s.WriteContext(ctx, path, key.Private)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &KeyResult{
+		Private: private,
+	}
+
+	if publicPath != "" {
+		public, err := s.WriteContext(ctx, publicPath.Value(), key.Public)
+		if err != nil {
+			return result, err
+		}
+
+		result.PublicPath = publicPath.Value()
+		result.Public = public
+	}
+
+	return result, nil
+}
+
+// PublicKeyPath returns the path of the sibling secret of the secret at the given
+// path that the public part of a generated key with the given suffix is written to.
+func PublicKeyPath(path api.SecretPath, suffix string) (api.SecretPath, error) {
+	parentPath, err := path.GetParentPath()
+	if err != nil {
+		return "", errio.Error(err)
+	}
+
+	publicPath := api.DirPath(parentPath).JoinSecret(path.GetSecret() + suffix)
+	err = publicPath.Validate()
+	if err != nil {
+		return "", errio.Error(err)
+	}
+	return publicPath, nil
+}
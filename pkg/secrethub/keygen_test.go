@@ -0,0 +1,45 @@
+package secrethub
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/assert"
+)
+
+func TestPublicKeyPath(t *testing.T) {
+	cases := map[string]struct {
+		path     api.SecretPath
+		suffix   string
+		expected api.SecretPath
+		err      error
+	}{
+		"repo": {
+			path:     "namespace/repo/id_ed25519",
+			suffix:   ".pub",
+			expected: "namespace/repo/id_ed25519.pub",
+		},
+		"dir": {
+			path:     "namespace/repo/dir/tls",
+			suffix:   ".crt",
+			expected: "namespace/repo/dir/tls.crt",
+		},
+		"name too long": {
+			path:   api.SecretPath("namespace/repo/" + strings.Repeat("a", 32)),
+			suffix: ".pub",
+			err:    api.ErrInvalidSecretName,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			// Act
+			actual, err := PublicKeyPath(tc.path, tc.suffix)
+
+			// Assert
+			assert.Equal(t, err, tc.err)
+			assert.Equal(t, actual, tc.expected)
+		})
+	}
+}
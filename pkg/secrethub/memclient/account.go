@@ -0,0 +1,208 @@
+// +build !production
+
+package memclient
+
+import (
+	"context"
+	"strings"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/api/uuid"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+)
+
+type userService struct {
+	client *Client
+}
+
+// Me gets the account's user if it exists.
+func (s userService) Me() (*api.User, error) {
+	return s.MeContext(context.Background())
+}
+
+// MeContext is the same as Me, but uses the given context.
+func (s userService) MeContext(ctx context.Context) (*api.User, error) {
+	s.client.store.mutex.Lock()
+	defer s.client.store.mutex.Unlock()
+
+	caller, err := s.client.caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !caller.isUser() {
+		return nil, api.ErrNotAUser
+	}
+
+	user := *caller.user
+	return &user, nil
+}
+
+// Create signs up the client as a new user.
+func (s userService) Create(username, email, fullName string) (*api.User, error) {
+	return s.CreateContext(context.Background(), username, email, fullName)
+}
+
+// CreateContext is the same as Create, but uses the given context.
+func (s userService) CreateContext(ctx context.Context, username, email, fullName string) (*api.User, error) {
+	err := api.ValidateUsername(username)
+	if err != nil {
+		return nil, err
+	}
+
+	err = api.ValidateEmail(email)
+	if err != nil {
+		return nil, err
+	}
+
+	err = api.ValidateFullName(fullName)
+	if err != nil {
+		return nil, err
+	}
+
+	store := s.client.store
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	_, err = s.client.caller(ctx)
+	if err == nil {
+		return nil, api.ErrCredentialAlreadyExists
+	} else if err != api.ErrSignatureNotVerified {
+		return nil, err
+	}
+
+	if _, exists := store.accounts[strings.ToLower(username)]; exists {
+		return nil, api.ErrUsernameAlreadyExists
+	}
+	if _, exists := store.orgs[strings.ToLower(username)]; exists {
+		return nil, api.ErrUsernameAlreadyExists
+	}
+	for _, a := range store.accounts {
+		if a.isUser() && strings.EqualFold(a.user.Email, email) {
+			return nil, api.ErrUserEmailAlreadyExists
+		}
+	}
+
+	createdAt := now()
+	accountID := uuid.New()
+	store.accounts[strings.ToLower(username)] = &account{
+		id:          accountID,
+		name:        username,
+		accountType: accountTypeUser,
+		createdAt:   createdAt,
+		user: &api.User{
+			AccountID: accountID,
+			Username:  username,
+			FullName:  fullName,
+			Email:     email,
+			CreatedAt: &createdAt,
+		},
+	}
+	s.client.accountName = username
+
+	user := *store.accounts[strings.ToLower(username)].user
+	return &user, nil
+}
+
+// Get retrieves the user with the given username.
+func (s userService) Get(username string) (*api.User, error) {
+	return s.GetContext(context.Background(), username)
+}
+
+// GetContext is the same as Get, but uses the given context.
+func (s userService) GetContext(ctx context.Context, username string) (*api.User, error) {
+	err := api.ValidateUsername(username)
+	if err != nil {
+		return nil, err
+	}
+
+	s.client.store.mutex.Lock()
+	defer s.client.store.mutex.Unlock()
+
+	_, err = s.client.caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	a, err := s.client.store.lookupUser(username)
+	if err != nil {
+		return nil, err
+	}
+	return a.toUser(), nil
+}
+
+type accountService struct {
+	client *Client
+}
+
+// Get retrieves the user or service account with the given name.
+func (s accountService) Get(name string) (*api.Account, error) {
+	return s.GetContext(context.Background(), name)
+}
+
+// GetContext is the same as Get, but uses the given context.
+func (s accountService) GetContext(ctx context.Context, name string) (*api.Account, error) {
+	accountName, err := api.NewAccountName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	s.client.store.mutex.Lock()
+	defer s.client.store.mutex.Unlock()
+
+	_, err = s.client.caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	a, err := s.client.store.lookupAccount(accountName.String())
+	if err != nil {
+		return nil, err
+	}
+	return a.toAPI(), nil
+}
+
+// Keys returns an account key service.
+func (s accountService) Keys() secrethub.AccountKeyService {
+	return accountKeyService{client: s.client}
+}
+
+// accountKeyService implements the secrethub.AccountKeyService interface.
+// Accounts in the store are never encrypted, so every account has a key.
+type accountKeyService struct {
+	client *Client
+}
+
+// Create creates an account key for the client's credential. As every
+// account already has a key, it fails with api.ErrPrivateKeyAlreadyExists.
+func (s accountKeyService) Create() (*api.EncryptedAccountKey, error) {
+	return s.CreateContext(context.Background())
+}
+
+// CreateContext is the same as Create, but uses the given context.
+func (s accountKeyService) CreateContext(ctx context.Context) (*api.EncryptedAccountKey, error) {
+	s.client.store.mutex.Lock()
+	defer s.client.store.mutex.Unlock()
+
+	_, err := s.client.caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return nil, api.ErrPrivateKeyAlreadyExists
+}
+
+// Exists returns whether an account key exists for the client's credential.
+func (s accountKeyService) Exists() (bool, error) {
+	return s.ExistsContext(context.Background())
+}
+
+// ExistsContext is the same as Exists, but uses the given context.
+func (s accountKeyService) ExistsContext(ctx context.Context) (bool, error) {
+	s.client.store.mutex.Lock()
+	defer s.client.store.mutex.Unlock()
+
+	_, err := s.client.caller(ctx)
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
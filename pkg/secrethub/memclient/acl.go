@@ -0,0 +1,300 @@
+// +build !production
+
+package memclient
+
+import (
+	"context"
+	"sort"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+)
+
+type accessRuleService struct {
+	client *Client
+}
+
+// Delete removes the access rule for the account on the directory at the given path.
+func (s accessRuleService) Delete(path string, accountName string) error {
+	return s.DeleteContext(context.Background(), path, accountName)
+}
+
+// DeleteContext is the same as Delete, but uses the given context.
+func (s accessRuleService) DeleteContext(ctx context.Context, path string, accountName string) error {
+	p, err := api.NewDirPath(path)
+	if err != nil {
+		return err
+	}
+
+	an, err := api.NewAccountName(accountName)
+	if err != nil {
+		return err
+	}
+
+	store := s.client.store
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	caller, err := s.client.caller(ctx)
+	if err != nil {
+		return err
+	}
+
+	d, err := store.lookupDir(caller, p, api.PermissionAdmin)
+	if err != nil {
+		return err
+	}
+
+	a, err := store.lookupAccount(an.String())
+	if err != nil {
+		return err
+	}
+
+	rule, ok := d.rules[*a.id]
+	if !ok {
+		return api.ErrAccessRuleNotFound
+	}
+	if rule.Permission == api.PermissionAdmin && d.isLastRootAdmin(a) {
+		return api.ErrCannotRemoveLastRootAdmin
+	}
+
+	delete(d.rules, *a.id)
+	return nil
+}
+
+// Get retrieves the access rule for the account on the directory at the given path.
+func (s accessRuleService) Get(path string, accountName string) (*api.AccessRule, error) {
+	return s.GetContext(context.Background(), path, accountName)
+}
+
+// GetContext is the same as Get, but uses the given context.
+func (s accessRuleService) GetContext(ctx context.Context, path string, accountName string) (*api.AccessRule, error) {
+	p, err := api.NewDirPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	an, err := api.NewAccountName(accountName)
+	if err != nil {
+		return nil, err
+	}
+
+	store := s.client.store
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	caller, err := s.client.caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	d, err := store.lookupDir(caller, p, api.PermissionRead)
+	if err != nil {
+		return nil, err
+	}
+
+	a, err := store.lookupAccount(an.String())
+	if err != nil {
+		return nil, err
+	}
+
+	rule, ok := d.rules[*a.id]
+	if !ok {
+		return nil, api.ErrAccessRuleNotFound
+	}
+
+	result := *rule
+	return &result, nil
+}
+
+// List retrieves the access rules on the directory at the given path and its
+// subdirectories, up to the given depth. When depth is <0, all subdirectories are
+// included. When ancestors is true, the rules on the parent directories are included too.
+func (s accessRuleService) List(path string, depth int, ancestors bool) ([]*api.AccessRule, error) {
+	return s.ListContext(context.Background(), path, depth, ancestors)
+}
+
+// ListContext is the same as List, but uses the given context.
+func (s accessRuleService) ListContext(ctx context.Context, path string, depth int, ancestors bool) ([]*api.AccessRule, error) {
+	p, err := api.NewDirPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	store := s.client.store
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	caller, err := s.client.caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	d, err := store.lookupDir(caller, p, api.PermissionRead)
+	if err != nil {
+		return nil, err
+	}
+
+	rules := []*api.AccessRule{}
+	if ancestors {
+		for _, ancestor := range d.ancestors() {
+			rules = append(rules, ancestor.copyRules()...)
+		}
+	}
+	d.walk(depth, func(current *dir) {
+		rules = append(rules, current.copyRules()...)
+	})
+
+	sort.Sort(api.SortAccessRules(rules))
+	return rules, nil
+}
+
+// ListLevels lists the access levels of all accounts with access
+// to the directory at the given path.
+func (s accessRuleService) ListLevels(path string) ([]*api.AccessLevel, error) {
+	return s.ListLevelsContext(context.Background(), path)
+}
+
+// ListLevelsContext is the same as ListLevels, but uses the given context.
+func (s accessRuleService) ListLevelsContext(ctx context.Context, path string) ([]*api.AccessLevel, error) {
+	p, err := api.NewDirPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	store := s.client.store
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	caller, err := s.client.caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	d, err := store.lookupDir(caller, p, api.PermissionRead)
+	if err != nil {
+		return nil, err
+	}
+
+	levels := []*api.AccessLevel{}
+	for _, member := range d.repo.sortedMembers() {
+		permission := d.permission(member.account.id)
+		if permission == api.PermissionNone {
+			continue
+		}
+		levels = append(levels, &api.AccessLevel{
+			Account:    member.account.toAPI(),
+			AccountID:  member.account.id,
+			DirID:      d.id,
+			Permission: permission,
+		})
+	}
+	return levels, nil
+}
+
+// Set sets the access rule for the account on the directory at the given path,
+// creating the rule when it does not exist yet.
+func (s accessRuleService) Set(path string, permission api.Permission, accountName string) (*api.AccessRule, error) {
+	return s.SetContext(context.Background(), path, permission, accountName)
+}
+
+// SetContext is the same as Set, but uses the given context.
+func (s accessRuleService) SetContext(ctx context.Context, path string, permission api.Permission, accountName string) (*api.AccessRule, error) {
+	p, err := api.NewDirPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	an, err := api.NewAccountName(accountName)
+	if err != nil {
+		return nil, err
+	}
+
+	store := s.client.store
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	caller, err := s.client.caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	d, err := store.lookupDir(caller, p, api.PermissionRead)
+	if err != nil {
+		return nil, err
+	}
+
+	a, err := store.lookupAccount(an.String())
+	if err != nil {
+		return nil, err
+	}
+
+	if d.permission(caller.id) < api.PermissionAdmin {
+		return nil, api.ErrForbidden
+	}
+
+	rule, exists := d.rules[*a.id]
+	if !exists {
+		if _, isMember := d.repo.members[*a.id]; !isMember {
+			return nil, api.ErrNotMemberOfRepo
+		}
+
+		if permission >= api.PermissionRead {
+			d.grantKeys(a)
+		}
+
+		rule = newAccessRule(d, a, permission)
+		d.rules[*a.id] = rule
+
+		result := *rule
+		return &result, nil
+	}
+
+	if rule.Permission == api.PermissionAdmin && permission < api.PermissionAdmin && d.isLastRootAdmin(a) {
+		return nil, api.ErrCannotRemoveLastRootAdmin
+	}
+	if permission >= api.PermissionRead {
+		d.grantKeys(a)
+	}
+
+	rule.Permission = permission
+	rule.LastChangedAt = now()
+
+	result := *rule
+	return &result, nil
+}
+
+// copyRules returns copies of the access rules on the directory.
+func (d *dir) copyRules() []*api.AccessRule {
+	rules := make([]*api.AccessRule, 0, len(d.rules))
+	for _, rule := range d.rules {
+		result := *rule
+		rules = append(rules, &result)
+	}
+	return rules
+}
+
+// grantKeys gives the account access to the keys of all secrets in the directory
+// and its subdirectories, so its access is taken into account when it is revoked.
+func (d *dir) grantKeys(a *account) {
+	d.walk(-1, func(current *dir) {
+		for _, sec := range current.secrets {
+			for _, key := range sec.keys {
+				key.accounts[*a.id] = true
+			}
+		}
+	})
+}
+
+// isLastRootAdmin returns whether the directory is the root directory of its
+// repository and the account is the only one with an admin rule on it.
+func (d *dir) isLastRootAdmin(a *account) bool {
+	if d.parent != nil {
+		return false
+	}
+	for accountID, rule := range d.rules {
+		if rule.Permission == api.PermissionAdmin && accountID != *a.id {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,129 @@
+// +build !production
+
+// Package memclient provides an in-memory implementation of the secrethub.Client
+// interface, which can be used in tests of code that uses a secrethub.Client.
+//
+// Unlike the stubs in the fakeclient package, the Client keeps track of all
+// repositories, directories, secrets, versions and access rules it is used for
+// and applies the same path rules, permissions and errors as the real client
+// and API. Secrets are stored in memory without encryption.
+//
+//	client, _ := memclient.New("dev1")
+//	_, _ = client.Repos().Create("dev1/repo")
+//	_, _ = client.Secrets().Write("dev1/repo/db_password", []byte("password123"))
+//	version, _ := client.Secrets().Versions().GetWithData("dev1/repo/db_password")
+//
+// Multiple accounts can share the same state by creating their clients from one Store.
+package memclient
+
+import (
+	"context"
+	"strings"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+)
+
+// Client is an in-memory implementation of the secrethub.Client interface.
+// A Client acts on behalf of a single account, which can be a user or a service.
+type Client struct {
+	store *Store
+	// accountName is the name of the account the client acts on behalf of.
+	// It is empty until the client is signed up with Users().Create.
+	accountName string
+}
+
+// New returns a Client for a new Store, signed up as a user with the given username.
+func New(username string) (*Client, error) {
+	client := NewStore().NewClient()
+	_, err := client.Users().Create(username, username+"@example.com", "Test User")
+	if err != nil {
+		return nil, err
+	}
+	return client, nil
+}
+
+// NewClient returns a Client that has not signed up yet. Until it is signed up
+// with Users().Create, all requests fail with api.ErrSignatureNotVerified.
+func (s *Store) NewClient() *Client {
+	return &Client{
+		store: s,
+	}
+}
+
+// ClientFor returns a Client that acts on behalf of the existing user or service account
+// with the given name. When the account does not exist or is removed, all requests fail
+// with api.ErrSignatureNotVerified.
+func (s *Store) ClientFor(accountName string) *Client {
+	return &Client{
+		store:       s,
+		accountName: accountName,
+	}
+}
+
+// Store returns the Store the client uses.
+func (c *Client) Store() *Store {
+	return c.store
+}
+
+// caller returns the account the client acts on behalf of. It returns an
+// error when the context is done or when the account does not exist.
+// It must be called while the mutex of the store is held.
+func (c *Client) caller(ctx context.Context) (*account, error) {
+	switch ctx.Err() {
+	case context.Canceled:
+		return nil, secrethub.ErrCanceled
+	case context.DeadlineExceeded:
+		return nil, secrethub.ErrDeadlineExceeded
+	}
+
+	a, ok := c.store.accounts[strings.ToLower(c.accountName)]
+	if !ok {
+		return nil, api.ErrSignatureNotVerified
+	}
+	return a, nil
+}
+
+// AccessRules implements the secrethub.Client interface.
+func (c *Client) AccessRules() secrethub.AccessRuleService {
+	return accessRuleService{client: c}
+}
+
+// Accounts implements the secrethub.Client interface.
+func (c *Client) Accounts() secrethub.AccountService {
+	return accountService{client: c}
+}
+
+// Dirs implements the secrethub.Client interface.
+func (c *Client) Dirs() secrethub.DirService {
+	return dirService{client: c}
+}
+
+// Orgs implements the secrethub.Client interface.
+func (c *Client) Orgs() secrethub.OrgService {
+	return orgService{client: c}
+}
+
+// Repos implements the secrethub.Client interface.
+func (c *Client) Repos() secrethub.RepoService {
+	return repoService{client: c}
+}
+
+// Secrets implements the secrethub.Client interface.
+func (c *Client) Secrets() secrethub.SecretService {
+	return secretService{client: c}
+}
+
+// Services implements the secrethub.Client interface.
+func (c *Client) Services() secrethub.ServiceService {
+	return serviceService{client: c}
+}
+
+// Users implements the secrethub.Client interface.
+func (c *Client) Users() secrethub.UserService {
+	return userService{client: c}
+}
+
+// Wipe implements the secrethub.Client interface.
+// The Client does not cache any keys, so Wipe does nothing.
+func (c *Client) Wipe() {}
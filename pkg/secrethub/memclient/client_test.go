@@ -0,0 +1,508 @@
+// +build !production
+
+package memclient
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/pkg/keygen"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+)
+
+var _ secrethub.Client = (*Client)(nil)
+
+// newRepo returns a client of a new store, signed up as dev1, with the repository dev1/repo.
+func newRepo(t *testing.T) *Client {
+	client, err := New("dev1")
+	assert.OK(t, err)
+
+	_, err = client.Repos().Create("dev1/repo")
+	assert.OK(t, err)
+
+	return client
+}
+
+func TestClient_Secrets(t *testing.T) {
+	// Arrange
+	client := newRepo(t)
+
+	_, err := client.Dirs().Create("dev1/repo/dir")
+	assert.OK(t, err)
+
+	// Act
+	_, err = client.Secrets().Write("dev1/repo/dir/secret", []byte("first"))
+	assert.OK(t, err)
+
+	_, err = client.Secrets().Write("dev1/repo/Dir/Secret", []byte("second"))
+	assert.OK(t, err)
+
+	latest, err := client.Secrets().Versions().GetWithData("dev1/repo/dir/secret")
+	assert.OK(t, err)
+
+	first, err := client.Secrets().Versions().GetWithData("dev1/repo/dir/secret:1")
+	assert.OK(t, err)
+
+	versions, err := client.Secrets().Versions().ListWithoutData("dev1/repo/dir/secret")
+	assert.OK(t, err)
+
+	tree, err := client.Dirs().GetTree("dev1/repo", -1, false)
+	assert.OK(t, err)
+
+	repo, err := client.Repos().Get("dev1/repo")
+	assert.OK(t, err)
+
+	err = client.Secrets().Delete("dev1/repo/dir/secret")
+	assert.OK(t, err)
+
+	_, errNotFound := client.Secrets().Versions().GetWithData("dev1/repo/dir/secret")
+
+	// Assert
+	assert.Equal(t, latest.Data, []byte("second"))
+	assert.Equal(t, latest.Version, 2)
+	assert.Equal(t, first.Data, []byte("first"))
+	assert.Equal(t, len(versions), 2)
+	assert.Equal(t, tree.DirCount(), 1)
+	assert.Equal(t, tree.SecretCount(), 1)
+	assert.Equal(t, tree.RootDir.Name, "repo")
+	assert.Equal(t, repo.SecretCount, 1)
+	assert.Equal(t, errNotFound, api.ErrSecretNotFound)
+}
+
+func TestSecretService_Write(t *testing.T) {
+	cases := map[string]struct {
+		path     string
+		data     []byte
+		expected error
+	}{
+		"success": {
+			path:     "dev1/repo/secret",
+			data:     []byte("secret"),
+			expected: nil,
+		},
+		"invalid path": {
+			path:     "dev1/repo",
+			data:     []byte("secret"),
+			expected: api.ErrInvalidSecretPath("dev1/repo"),
+		},
+		"version": {
+			path:     "dev1/repo/secret:1",
+			data:     []byte("secret"),
+			expected: secrethub.ErrCannotWriteToVersion,
+		},
+		"empty": {
+			path:     "dev1/repo/secret",
+			data:     []byte{},
+			expected: secrethub.ErrEmptySecret,
+		},
+		"too big": {
+			path:     "dev1/repo/secret",
+			data:     make([]byte, secrethub.MaxSecretSize+1),
+			expected: secrethub.ErrSecretTooBig,
+		},
+		"dir not found": {
+			path:     "dev1/repo/dir/secret",
+			data:     []byte("secret"),
+			expected: api.ErrDirNotFound,
+		},
+		"repo not found": {
+			path:     "dev1/other/secret",
+			data:     []byte("secret"),
+			expected: api.ErrRepoNotFound,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			// Arrange
+			client := newRepo(t)
+
+			// Act
+			_, err := client.Secrets().Write(tc.path, tc.data)
+
+			// Assert
+			assert.Equal(t, err, tc.expected)
+		})
+	}
+}
+
+func TestSecretVersionService_Delete(t *testing.T) {
+	// Arrange
+	client := newRepo(t)
+
+	_, err := client.Secrets().Write("dev1/repo/secret", []byte("first"))
+	assert.OK(t, err)
+
+	_, err = client.Secrets().Write("dev1/repo/secret", []byte("second"))
+	assert.OK(t, err)
+
+	// Act
+	err = client.Secrets().Versions().Delete("dev1/repo/secret:1")
+	assert.OK(t, err)
+
+	errLast := client.Secrets().Versions().Delete("dev1/repo/secret:2")
+	_, errDeleted := client.Secrets().Versions().GetWithData("dev1/repo/secret:1")
+
+	// Assert
+	assert.Equal(t, errLast, api.ErrCannotDeleteLastSecretVersion)
+	assert.Equal(t, errDeleted, api.ErrSecretVersionNotFound)
+}
+
+func TestClient_AccessRules(t *testing.T) {
+	// Arrange
+	dev1 := newRepo(t)
+	dev2 := dev1.Store().NewClient()
+	_, err := dev2.Users().Create("dev2", "dev2@example.com", "Test User")
+	assert.OK(t, err)
+
+	_, err = dev1.Secrets().Write("dev1/repo/secret", []byte("secret"))
+	assert.OK(t, err)
+
+	_, errNotMember := dev1.AccessRules().Set("dev1/repo", api.PermissionRead, "dev2")
+
+	_, err = dev1.Repos().Users().Invite("dev1/repo", "dev2")
+	assert.OK(t, err)
+
+	_, errBeforeRule := dev2.Secrets().Versions().GetWithData("dev1/repo/secret")
+
+	// Act
+	_, err = dev1.AccessRules().Set("dev1/repo", api.PermissionRead, "dev2")
+	assert.OK(t, err)
+
+	read, err := dev2.Secrets().Versions().GetWithData("dev1/repo/secret")
+	assert.OK(t, err)
+
+	_, errWrite := dev2.Secrets().Write("dev1/repo/secret", []byte("other"))
+
+	levels, err := dev1.AccessRules().ListLevels("dev1/repo")
+	assert.OK(t, err)
+
+	errLastAdmin := dev1.AccessRules().Delete("dev1/repo", "dev1")
+
+	revoked, err := dev1.Repos().Users().Revoke("dev1/repo", "dev2")
+	assert.OK(t, err)
+
+	_, errRevoked := dev2.Secrets().Versions().GetWithData("dev1/repo/secret")
+
+	// Assert
+	assert.Equal(t, errNotMember, api.ErrNotMemberOfRepo)
+	assert.Equal(t, errBeforeRule, api.ErrSecretNotFound)
+	assert.Equal(t, read.Data, []byte("secret"))
+	assert.Equal(t, errWrite, api.ErrForbidden)
+	assert.Equal(t, len(levels), 2)
+	assert.Equal(t, errLastAdmin, api.ErrCannotRemoveLastRootAdmin)
+	assert.Equal(t, revoked.RevokedSecretKeyCount, 1)
+	assert.Equal(t, errRevoked, api.ErrRepoNotFound)
+}
+
+func TestClient_Service(t *testing.T) {
+	// Arrange
+	dev1 := newRepo(t)
+
+	_, err := dev1.Secrets().Write("dev1/repo/secret", []byte("secret"))
+	assert.OK(t, err)
+
+	// Act
+	service, err := dev1.Services().Create("dev1/repo", "test service", nil)
+	assert.OK(t, err)
+
+	_, err = dev1.AccessRules().Set("dev1/repo", api.PermissionRead, service.ServiceID)
+	assert.OK(t, err)
+
+	serviceClient := dev1.Store().ClientFor(service.ServiceID)
+	read, err := serviceClient.Secrets().Versions().GetWithData("dev1/repo/secret")
+	assert.OK(t, err)
+
+	_, err = dev1.Services().Delete(service.ServiceID)
+	assert.OK(t, err)
+
+	_, errDeleted := serviceClient.Secrets().Versions().GetWithData("dev1/repo/secret")
+
+	// Assert
+	assert.Equal(t, read.Data, []byte("secret"))
+	assert.Equal(t, errDeleted, api.ErrSignatureNotVerified)
+}
+
+func TestClient_Events(t *testing.T) {
+	// Arrange
+	client := newRepo(t)
+
+	_, err := client.Secrets().Write("dev1/repo/secret", []byte("secret"))
+	assert.OK(t, err)
+
+	_, err = client.Secrets().Versions().GetWithData("dev1/repo/secret")
+	assert.OK(t, err)
+
+	// Act
+	repoEvents, err := client.Repos().ListEvents("dev1/repo", nil)
+	assert.OK(t, err)
+
+	secretEvents, err := client.Secrets().ListEvents("dev1/repo/secret", nil)
+	assert.OK(t, err)
+
+	// Assert
+	assert.Equal(t, len(repoEvents), 4)
+	assert.Equal(t, repoEvents[0].Subject.Type, api.AuditSubjectRepo)
+	assert.Equal(t, len(secretEvents), 3)
+	assert.Equal(t, secretEvents[2].Action, api.AuditActionRead)
+	assert.Equal(t, secretEvents[2].Actor.User.Username, "dev1")
+	assert.Equal(t, secretEvents[2].Subject.SecretVersion.Version, 1)
+}
+
+func TestClient_EventIterator(t *testing.T) {
+	// Arrange
+	client := newRepo(t)
+
+	_, err := client.Secrets().Write("dev1/repo/secret", []byte("secret"))
+	assert.OK(t, err)
+
+	_, err = client.Secrets().Versions().GetWithData("dev1/repo/secret")
+	assert.OK(t, err)
+
+	// Act
+	it := client.Secrets().Events("dev1/repo/secret", secrethub.AuditFilter{
+		Actions: []api.AuditAction{api.AuditActionRead},
+	})
+	event, err := it.Next()
+	assert.OK(t, err)
+	_, errDone := it.Next()
+
+	_, errNotFound := client.Repos().Events("dev1/missing", secrethub.AuditFilter{}).Next()
+
+	// Assert
+	assert.Equal(t, event.Action, api.AuditActionRead)
+	assert.Equal(t, event.Subject.SecretVersion.Version, 1)
+	assert.Equal(t, event.Subject.SecretPath, api.SecretPath("dev1/repo/secret:1"))
+	assert.Equal(t, errDone, secrethub.ErrIteratorDone)
+	assert.Equal(t, errNotFound, api.ErrRepoNotFound)
+}
+
+func TestClient_EventIterator_NoRootDirAccess(t *testing.T) {
+	// Arrange
+	dev1 := newRepo(t)
+	dev2 := dev1.Store().NewClient()
+	_, err := dev2.Users().Create("dev2", "dev2@example.com", "Test User")
+	assert.OK(t, err)
+
+	_, err = dev1.Dirs().Create("dev1/repo/dir")
+	assert.OK(t, err)
+
+	_, err = dev1.Secrets().Write("dev1/repo/dir/secret", []byte("secret"))
+	assert.OK(t, err)
+
+	_, err = dev1.Repos().Users().Invite("dev1/repo", "dev2")
+	assert.OK(t, err)
+
+	_, err = dev1.AccessRules().Set("dev1/repo/dir", api.PermissionRead, "dev2")
+	assert.OK(t, err)
+
+	// Act
+	filter := secrethub.AuditFilter{
+		SubjectTypes: api.AuditSubjectTypeList{api.AuditSubjectSecret},
+		ResolvePaths: true,
+	}
+	repoEvent, err := dev2.Repos().Events("dev1/repo", filter).Next()
+	assert.OK(t, err)
+
+	secretEvent, err := dev2.Secrets().Events("dev1/repo/dir/secret", filter).Next()
+	assert.OK(t, err)
+
+	// Assert
+	assert.Equal(t, repoEvent.Subject.Secret.Name, "secret")
+	assert.Equal(t, repoEvent.Subject.SecretPath, api.SecretPath(""))
+	assert.Equal(t, secretEvent.Subject.SecretPath, api.SecretPath("dev1/repo/dir/secret"))
+}
+
+func TestSecretVersionService_GetManyWithData(t *testing.T) {
+	// Arrange
+	client := newRepo(t)
+
+	_, err := client.Secrets().Write("dev1/repo/secret", []byte("secret"))
+	assert.OK(t, err)
+
+	// Act
+	results := client.Secrets().Versions().GetManyWithData([]string{
+		"dev1/repo/secret",
+		"dev1/repo/missing",
+		"invalid",
+	})
+
+	// Assert
+	assert.Equal(t, len(results), 3)
+	assert.Equal(t, results[0].Version.Data, []byte("secret"))
+	assert.Equal(t, results[1].Err, api.ErrSecretNotFound)
+	assert.Equal(t, results[2].Err, api.ErrInvalidSecretPath("invalid"))
+}
+
+func TestClient_Context(t *testing.T) {
+	// Arrange
+	client := newRepo(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// Act
+	_, err := client.Secrets().WriteContext(ctx, "dev1/repo/secret", []byte("secret"))
+
+	// Assert
+	assert.Equal(t, err, secrethub.ErrCanceled)
+}
+
+func TestClient_NotSignedUp(t *testing.T) {
+	// Arrange
+	client := NewStore().NewClient()
+
+	// Act
+	_, err := client.Users().Me()
+
+	// Assert
+	assert.Equal(t, err, api.ErrSignatureNotVerified)
+}
+
+func TestSecretService_WriteIfLatest(t *testing.T) {
+	cases := map[string]struct {
+		existing        int
+		expectedVersion int
+		expected        error
+		expectedLatest  int
+	}{
+		"create": {
+			existing:        0,
+			expectedVersion: 0,
+			expected:        nil,
+			expectedLatest:  1,
+		},
+		"create existing": {
+			existing:        1,
+			expectedVersion: 0,
+			expected:        api.ErrSecretAlreadyExists,
+			expectedLatest:  1,
+		},
+		"latest": {
+			existing:        2,
+			expectedVersion: 2,
+			expected:        nil,
+			expectedLatest:  3,
+		},
+		"outdated": {
+			existing:        2,
+			expectedVersion: 1,
+			expected:        secrethub.ErrSecretVersionConflict,
+			expectedLatest:  2,
+		},
+		"not found": {
+			existing:        0,
+			expectedVersion: 1,
+			expected:        secrethub.ErrSecretVersionConflict,
+			expectedLatest:  0,
+		},
+		"negative": {
+			existing:        1,
+			expectedVersion: -1,
+			expected:        api.ErrInvalidSecretVersion,
+			expectedLatest:  1,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			// Arrange
+			client := newRepo(t)
+
+			for i := 0; i < tc.existing; i++ {
+				_, err := client.Secrets().Write("dev1/repo/secret", []byte("existing"))
+				assert.OK(t, err)
+			}
+
+			// Act
+			_, err := client.Secrets().WriteIfLatest("dev1/repo/secret", tc.expectedVersion, []byte("new"))
+
+			// Assert
+			assert.Equal(t, err, tc.expected)
+
+			latest := 0
+			secret, err := client.Secrets().Get("dev1/repo/secret")
+			if err == nil {
+				latest = secret.LatestVersion
+			}
+			assert.Equal(t, latest, tc.expectedLatest)
+		})
+	}
+}
+
+func TestSecretService_Rotate(t *testing.T) {
+	// Arrange
+	client := newRepo(t)
+
+	_, err := client.Secrets().Write("dev1/repo/secret", []byte("old"))
+	assert.OK(t, err)
+
+	var hookData []byte
+	policy := secrethub.RotatePolicy{
+		Length: 16,
+		AfterWrite: func(path string, version *api.SecretVersion) error {
+			hookData = version.Data
+			return nil
+		},
+	}
+
+	errHook := errors.New("hook failed")
+	failingPolicy := secrethub.RotatePolicy{
+		AfterWrite: func(path string, version *api.SecretVersion) error {
+			return errHook
+		},
+	}
+
+	// Act
+	result, err := client.Secrets().Rotate("dev1/repo/secret", policy)
+	assert.OK(t, err)
+
+	latest, err := client.Secrets().Versions().GetWithData("dev1/repo/secret")
+	assert.OK(t, err)
+
+	failedResult, errFailed := client.Secrets().Rotate("dev1/repo/secret", failingPolicy)
+
+	_, errNotFound := client.Secrets().Rotate("dev1/repo/missing", policy)
+
+	// Assert
+	assert.Equal(t, result, &secrethub.RotateResult{OldVersion: 1, NewVersion: 2})
+	assert.Equal(t, len(latest.Data), 16)
+	assert.Equal(t, hookData, latest.Data)
+	assert.Equal(t, failedResult, &secrethub.RotateResult{OldVersion: 2, NewVersion: 3})
+	assert.Equal(t, errFailed, errHook)
+	assert.Equal(t, errNotFound, api.ErrSecretNotFound)
+}
+
+func TestSecretService_GenerateKey(t *testing.T) {
+	// Arrange
+	client := newRepo(t)
+
+	// Act
+	sshResult, err := client.Secrets().GenerateKey("dev1/repo/id_ed25519", keygen.SSH{}, true)
+	assert.OK(t, err)
+
+	public, err := client.Secrets().Versions().GetWithData("dev1/repo/id_ed25519.pub")
+	assert.OK(t, err)
+
+	hmacResult, err := client.Secrets().GenerateKey("dev1/repo/jwt", keygen.JWT{Algorithm: keygen.HS256}, true)
+	assert.OK(t, err)
+
+	publicExists, err := client.Secrets().Exists("dev1/repo/jwt.pub")
+	assert.OK(t, err)
+
+	_, errUnsupported := client.Secrets().GenerateKey("dev1/repo/jwt", keygen.JWT{}, true)
+
+	// Assert
+	assert.Equal(t, sshResult.Private.Version, 1)
+	assert.Equal(t, sshResult.PublicPath, "dev1/repo/id_ed25519.pub")
+	assert.Equal(t, sshResult.Public.Version, 1)
+	assert.Equal(t, public.Data[:len("ssh-ed25519 ")], []byte("ssh-ed25519 "))
+	assert.Equal(t, hmacResult.Private.Version, 1)
+	assert.Equal(t, hmacResult.PublicPath, "")
+	assert.Equal(t, hmacResult.Public, (*api.SecretVersion)(nil))
+	assert.Equal(t, publicExists, false)
+	assert.Equal(t, errUnsupported, keygen.ErrUnsupportedAlgorithm)
+}
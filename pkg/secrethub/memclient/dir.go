@@ -0,0 +1,226 @@
+// +build !production
+
+package memclient
+
+import (
+	"context"
+	"strings"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/api/uuid"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+)
+
+type dirService struct {
+	client *Client
+}
+
+// Copy copies the directory at path src with all of its subdirectories and secrets to path dst,
+// which can be in another repository. When the directory at dst already exists, the copied
+// contents are merged into it.
+func (s dirService) Copy(src, dst string, options secrethub.CopyOptions) (*secrethub.CopyResult, error) {
+	return s.CopyContext(context.Background(), src, dst, options)
+}
+
+// CopyContext is the same as Copy, but uses the given context.
+func (s dirService) CopyContext(ctx context.Context, src, dst string, options secrethub.CopyOptions) (*secrethub.CopyResult, error) {
+	return secrethub.ClientSide(s.client).Dirs().CopyContext(ctx, src, dst, options)
+}
+
+// Create creates a directory at a given path.
+func (s dirService) Create(path string) (*api.Dir, error) {
+	return s.CreateContext(context.Background(), path)
+}
+
+// CreateContext is the same as Create, but uses the given context.
+func (s dirService) CreateContext(ctx context.Context, path string) (*api.Dir, error) {
+	p, err := api.NewDirPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	parentPath, err := p.GetParentPath()
+	if err != nil {
+		return nil, err
+	}
+
+	store := s.client.store
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	caller, err := s.client.caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	parent, err := store.lookupDir(caller, api.DirPath(parentPath), api.PermissionWrite)
+	if err != nil {
+		return nil, err
+	}
+
+	name := p.GetDirName()
+	if _, exists := parent.dirs[strings.ToLower(name)]; exists {
+		return nil, api.ErrDirAlreadyExists
+	}
+	if _, exists := parent.secrets[strings.ToLower(name)]; exists {
+		return nil, api.ErrDirAlreadyExists
+	}
+
+	createdAt := now()
+	d := &dir{
+		id:             uuid.New(),
+		name:           name,
+		repo:           parent.repo,
+		parent:         parent,
+		dirs:           make(map[string]*dir),
+		secrets:        make(map[string]*secret),
+		rules:          make(map[uuid.UUID]*api.AccessRule),
+		createdAt:      createdAt,
+		lastModifiedAt: createdAt,
+	}
+	parent.dirs[strings.ToLower(name)] = d
+	parent.lastModifiedAt = createdAt
+
+	return d.toAPI(), nil
+}
+
+// Delete removes the directory at the given path.
+func (s dirService) Delete(path string) error {
+	return s.DeleteContext(context.Background(), path)
+}
+
+// DeleteContext is the same as Delete, but uses the given context.
+func (s dirService) DeleteContext(ctx context.Context, path string) error {
+	p, err := api.NewDirPath(path)
+	if err != nil {
+		return err
+	}
+
+	store := s.client.store
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	caller, err := s.client.caller(ctx)
+	if err != nil {
+		return err
+	}
+
+	d, err := store.lookupDir(caller, p, api.PermissionWrite)
+	if err != nil {
+		return err
+	}
+	if d.parent == nil {
+		return api.ErrCannotRemoveRootDir
+	}
+
+	d.walk(-1, func(current *dir) {
+		for _, sec := range current.sortedSecrets() {
+			store.logEvent(caller, api.AuditActionDelete, d.repo, &event{
+				subjectType:   api.AuditSubjectSecret,
+				subjectSecret: sec,
+			})
+		}
+	})
+
+	store.removeDir(d)
+	return nil
+}
+
+// GetTree retrieves a directory tree at a given path. The contents to the given depth
+// are returned. When depth is <0, the entire directory tree is returned. When ancestors
+// is true, the parent directories of the dir at the given path are also included in the tree.
+func (s dirService) GetTree(path string, depth int, ancestors bool) (*api.Tree, error) {
+	return s.GetTreeContext(context.Background(), path, depth, ancestors)
+}
+
+// GetTreeContext is the same as GetTree, but uses the given context.
+func (s dirService) GetTreeContext(ctx context.Context, path string, depth int, ancestors bool) (*api.Tree, error) {
+	p, err := api.NewDirPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	store := s.client.store
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	caller, err := s.client.caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	d, err := store.lookupDir(caller, p, api.PermissionRead)
+	if err != nil {
+		return nil, err
+	}
+
+	tree := &api.Tree{
+		Dirs:    make(map[uuid.UUID]*api.Dir),
+		Secrets: make(map[uuid.UUID]*api.Secret),
+	}
+
+	var parent *api.Dir
+	if ancestors {
+		for _, ancestor := range d.ancestors() {
+			apiDir := ancestor.toAPI()
+			tree.Dirs[*ancestor.id] = apiDir
+			if parent == nil {
+				tree.RootDir = apiDir
+			} else {
+				parent.SubDirs = append(parent.SubDirs, apiDir)
+			}
+			parent = apiDir
+		}
+	}
+
+	d.walk(depth, func(current *dir) {
+		apiDir := current.toAPI()
+		if current == d && !ancestors {
+			apiDir.ParentID = nil
+		}
+		tree.Dirs[*current.id] = apiDir
+
+		if current == d {
+			if parent == nil {
+				tree.RootDir = apiDir
+			} else {
+				parent.SubDirs = append(parent.SubDirs, apiDir)
+			}
+		} else {
+			tree.Dirs[*current.parent.id].SubDirs = append(tree.Dirs[*current.parent.id].SubDirs, apiDir)
+		}
+
+		for _, sec := range current.sortedSecrets() {
+			apiSecret := sec.toAPI()
+			apiDir.Secrets = append(apiDir.Secrets, apiSecret)
+			tree.Secrets[*sec.id] = apiSecret
+		}
+	})
+
+	if ancestors {
+		// When ancestors are retrieved, the root of the tree is the directory at repo level.
+		// So, the parent path of the tree is the namespace of the path.
+		tree.ParentPath = api.ParentPath(p.GetNamespace())
+	} else {
+		// When ancestors are not retrieved, the root of the tree is the directory at the path.
+		// So, the parent path of the tree is the parent of the directory at the path.
+		tree.ParentPath, err = p.GetParentPath()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return tree, nil
+}
+
+// Move moves the directory at path src, including all of its subdirectories and secrets,
+// to path dst. The source is only deleted after everything has been copied. When the move
+// fails, the directory created at dst is removed again.
+func (s dirService) Move(src, dst string, options secrethub.MoveOptions) (*secrethub.MoveResult, error) {
+	return s.MoveContext(context.Background(), src, dst, options)
+}
+
+// MoveContext is the same as Move, but uses the given context.
+func (s dirService) MoveContext(ctx context.Context, src, dst string, options secrethub.MoveOptions) (*secrethub.MoveResult, error) {
+	return secrethub.ClientSide(s.client).Dirs().MoveContext(ctx, src, dst, options)
+}
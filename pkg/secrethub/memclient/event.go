@@ -0,0 +1,212 @@
+// +build !production
+
+package memclient
+
+import (
+	"time"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/api/uuid"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+)
+
+// event is an audit event. Its subject is rendered when the events are listed,
+// so that subjects that have been removed since are shown as deleted.
+type event struct {
+	id       *uuid.UUID
+	action   api.AuditAction
+	loggedAt time.Time
+	repo     *repo
+	actor    *account
+
+	subjectType    api.AuditSubjectType
+	subjectAccount *account
+	subjectRepo    *repo
+	subjectSecret  *secret
+	subjectVersion *secretVersion
+}
+
+// logEvent records an audit event for an action of the caller in a repository.
+func (s *Store) logEvent(caller *account, action api.AuditAction, r *repo, e *event) {
+	e.id = uuid.New()
+	e.action = action
+	e.loggedAt = now()
+	e.repo = r
+	e.actor = caller
+
+	s.events = append(s.events, e)
+}
+
+// pathResolution is how the paths of secret subjects are set when events are rendered.
+// It follows the client, which knows the path of a secret when its events are listed
+// and otherwise resolves the paths from the tree of the repository, if requested.
+type pathResolution int
+
+const (
+	// noPaths leaves the paths of secrets empty.
+	noPaths pathResolution = iota
+	// treePaths sets the paths of secrets when the account can read the
+	// root directory of the repository, which is needed to get its tree.
+	treePaths
+	// knownPaths always sets the paths of secrets.
+	knownPaths
+)
+
+// setPath returns whether the path of a secret subject of the event is set for the account.
+func (p pathResolution) setPath(e *event, caller *account) bool {
+	switch p {
+	case treePaths:
+		return e.repo.rootDir.permission(caller.id) >= api.PermissionRead
+	case knownPaths:
+		return true
+	default:
+		return false
+	}
+}
+
+// render returns the event as an api.Audit for the given account.
+// It returns false when the subject is a secret the account cannot read.
+func (s *Store) render(e *event, caller *account, paths pathResolution) (*api.Audit, bool) {
+	audit := &api.Audit{
+		EventID:   e.id,
+		Action:    e.action,
+		IPAddress: "127.0.0.1",
+		LoggedAt:  e.loggedAt,
+		Repo:      *e.repo.repo.Trim(),
+	}
+
+	if s.accountExists(e.actor) {
+		if e.actor.isUser() {
+			audit.Actor = *e.actor.user.ToAuditActor()
+		} else {
+			audit.Actor = *e.actor.service.ToAuditActor()
+		}
+	} else {
+		audit.Actor = api.AuditActor{
+			ActorID: e.actor.id,
+			Deleted: true,
+			Type:    api.AuditSubjectAccount,
+		}
+	}
+
+	switch e.subjectType {
+	case api.AuditSubjectUser, api.AuditSubjectService:
+		if !s.accountExists(e.subjectAccount) {
+			audit.Subject = api.AuditSubject{
+				SubjectID: e.subjectAccount.id,
+				Deleted:   true,
+				Type:      api.AuditSubjectAccount,
+			}
+		} else if e.subjectAccount.isUser() {
+			audit.Subject = *e.subjectAccount.user.ToAuditSubject()
+		} else {
+			audit.Subject = *e.subjectAccount.service.ToAuditSubject()
+		}
+	case api.AuditSubjectRepo:
+		audit.Subject = *e.subjectRepo.repo.ToAuditSubject()
+		audit.Subject.Deleted = e.subjectRepo.deleted
+	case api.AuditSubjectSecret:
+		audit.Subject = api.AuditSubject{
+			SubjectID: e.subjectSecret.id,
+			Deleted:   e.subjectSecret.deleted,
+			Type:      api.AuditSubjectSecret,
+		}
+		if e.subjectSecret.deleted {
+			break
+		}
+		if e.subjectSecret.dir.permission(caller.id) < api.PermissionRead {
+			return nil, false
+		}
+		audit.Subject.Secret = e.subjectSecret.toAPI()
+		if paths.setPath(e, caller) {
+			audit.Subject.SecretPath = e.subjectSecret.path()
+		}
+	case api.AuditSubjectSecretVersion:
+		audit.Subject = api.AuditSubject{
+			SubjectID: e.subjectVersion.id,
+			Deleted:   e.subjectVersion.deleted,
+			Type:      api.AuditSubjectSecretVersion,
+		}
+		if e.subjectVersion.deleted {
+			break
+		}
+		if e.subjectVersion.secret.dir.permission(caller.id) < api.PermissionRead {
+			return nil, false
+		}
+		audit.Subject.SecretVersion = e.subjectVersion.toAPI(caller, false)
+		if paths.setPath(e, caller) {
+			audit.Subject.SecretPath = e.subjectVersion.path()
+		}
+	}
+
+	return audit, true
+}
+
+// listEvents returns the events for which match returns true, rendered for the caller
+// and filtered on the given subject types. An empty list of subject types matches all events.
+func (s *Store) listEvents(caller *account, subjectTypes api.AuditSubjectTypeList, paths pathResolution, match func(e *event) bool) []*api.Audit {
+	types := make(map[api.AuditSubjectType]bool)
+	for _, t := range subjectTypes {
+		types[t] = true
+	}
+
+	events := []*api.Audit{}
+	for _, e := range s.events {
+		if !match(e) {
+			continue
+		}
+		if len(types) > 0 && !types[e.subjectType] {
+			continue
+		}
+
+		audit, ok := s.render(e, caller, paths)
+		if ok {
+			events = append(events, audit)
+		}
+	}
+	return events
+}
+
+// eventIterator returns the listed events that match the filter. All events
+// are kept in memory, so they are listed at once instead of page by page.
+// Like the API, it returns api.ErrNotFound when the event of filter.After is not listed.
+type eventIterator struct {
+	events []*api.Audit
+	filter secrethub.AuditFilter
+	err    error
+	index  int
+	// started is set when the events up to filter.After have been skipped.
+	started bool
+}
+
+// Next returns the next event that matches the filter.
+func (it *eventIterator) Next() (*api.Audit, error) {
+	if it.err != nil {
+		return nil, it.err
+	}
+
+	if !it.started && it.filter.After != nil {
+		it.index = -1
+		for i, event := range it.events {
+			if uuid.Equal(event.EventID, it.filter.After) {
+				it.index = i + 1
+				break
+			}
+		}
+		if it.index < 0 {
+			it.err = api.ErrNotFound
+			return nil, it.err
+		}
+	}
+	it.started = true
+
+	for it.index < len(it.events) {
+		event := it.events[it.index]
+		it.index++
+
+		if it.filter.Matches(event) {
+			return event, nil
+		}
+	}
+	return nil, secrethub.ErrIteratorDone
+}
@@ -0,0 +1,488 @@
+// +build !production
+
+package memclient
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/api/uuid"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+)
+
+type orgService struct {
+	client *Client
+}
+
+// Create creates an organization and adds the client's account as an admin member.
+func (s orgService) Create(name string, description string) (*api.Org, error) {
+	return s.CreateContext(context.Background(), name, description)
+}
+
+// CreateContext is the same as Create, but uses the given context.
+func (s orgService) CreateContext(ctx context.Context, name string, description string) (*api.Org, error) {
+	in := &api.CreateOrgRequest{
+		Name:        name,
+		Description: description,
+	}
+
+	err := in.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	store := s.client.store
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	caller, err := s.client.caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if !caller.isUser() {
+		return nil, api.ErrNotAUser
+	}
+	if _, exists := store.orgs[strings.ToLower(name)]; exists {
+		return nil, api.ErrOrgAlreadyExists
+	}
+	if _, exists := store.accounts[strings.ToLower(name)]; exists {
+		return nil, api.ErrNamespaceAlreadyExists
+	}
+
+	o := &org{
+		org: &api.Org{
+			OrgID:       uuid.New(),
+			Name:        name,
+			Description: description,
+			CreatedAt:   now(),
+		},
+		members: make(map[uuid.UUID]*api.OrgMember),
+	}
+	o.members[*caller.id] = newOrgMember(o, caller, api.OrgRoleAdmin)
+	store.orgs[strings.ToLower(name)] = o
+
+	result := *o.org
+	return &result, nil
+}
+
+// Delete permanently deletes an organization and all of its repositories.
+func (s orgService) Delete(name string) error {
+	return s.DeleteContext(context.Background(), name)
+}
+
+// DeleteContext is the same as Delete, but uses the given context.
+func (s orgService) DeleteContext(ctx context.Context, name string) error {
+	err := api.ValidateOrgName(name)
+	if err != nil {
+		return err
+	}
+
+	store := s.client.store
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	caller, err := s.client.caller(ctx)
+	if err != nil {
+		return err
+	}
+
+	o, err := store.lookupOrgAsAdmin(caller, name)
+	if err != nil {
+		return err
+	}
+
+	for _, r := range store.reposInNamespace(o.org.Name) {
+		store.removeRepo(r)
+	}
+	delete(store.orgs, strings.ToLower(o.org.Name))
+
+	return nil
+}
+
+// Get retrieves an organization with its members.
+func (s orgService) Get(name string) (*api.Org, error) {
+	return s.GetContext(context.Background(), name)
+}
+
+// GetContext is the same as Get, but uses the given context.
+func (s orgService) GetContext(ctx context.Context, name string) (*api.Org, error) {
+	err := api.ValidateOrgName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	store := s.client.store
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	caller, err := s.client.caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	o, err := store.lookupOrg(caller, name)
+	if err != nil {
+		return nil, err
+	}
+
+	result := *o.org
+	result.Members = o.listMembers()
+	return &result, nil
+}
+
+// Members returns an OrgMemberService.
+func (s orgService) Members() secrethub.OrgMemberService {
+	return orgMemberService{client: s.client}
+}
+
+// ListMine returns the organizations the client's account is a member of.
+func (s orgService) ListMine() ([]*api.Org, error) {
+	return s.ListMineContext(context.Background())
+}
+
+// ListMineContext is the same as ListMine, but uses the given context.
+func (s orgService) ListMineContext(ctx context.Context) ([]*api.Org, error) {
+	store := s.client.store
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	caller, err := s.client.caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	orgs := []*api.Org{}
+	for _, o := range store.orgs {
+		if _, isMember := o.members[*caller.id]; isMember {
+			result := *o.org
+			orgs = append(orgs, &result)
+		}
+	}
+	sort.Sort(api.SortOrgByName(orgs))
+	return orgs, nil
+}
+
+type orgMemberService struct {
+	client *Client
+}
+
+// Get retrieves a user's organization membership details.
+func (s orgMemberService) Get(org string, username string) (*api.OrgMember, error) {
+	return s.GetContext(context.Background(), org, username)
+}
+
+// GetContext is the same as Get, but uses the given context.
+func (s orgMemberService) GetContext(ctx context.Context, org string, username string) (*api.OrgMember, error) {
+	err := api.ValidateOrgName(org)
+	if err != nil {
+		return nil, err
+	}
+
+	err = api.ValidateUsername(username)
+	if err != nil {
+		return nil, err
+	}
+
+	store := s.client.store
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	caller, err := s.client.caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	o, err := store.lookupOrg(caller, org)
+	if err != nil {
+		return nil, err
+	}
+
+	member, _, err := store.lookupOrgMember(o, username)
+	if err != nil {
+		return nil, err
+	}
+
+	result := *member
+	return &result, nil
+}
+
+// Invite adds a user to an organization.
+func (s orgMemberService) Invite(org string, username string, role string) (*api.OrgMember, error) {
+	return s.InviteContext(context.Background(), org, username, role)
+}
+
+// InviteContext is the same as Invite, but uses the given context.
+func (s orgMemberService) InviteContext(ctx context.Context, org string, username string, role string) (*api.OrgMember, error) {
+	err := api.ValidateOrgName(org)
+	if err != nil {
+		return nil, err
+	}
+
+	in := &api.CreateOrgMemberRequest{
+		Username: username,
+		Role:     role,
+	}
+
+	err = in.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	store := s.client.store
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	caller, err := s.client.caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	o, err := store.lookupOrgAsAdmin(caller, org)
+	if err != nil {
+		return nil, err
+	}
+
+	a, err := store.lookupUser(username)
+	if err != nil {
+		return nil, err
+	}
+	if _, isMember := o.members[*a.id]; isMember {
+		return nil, api.ErrOrgMemberAlreadyExists
+	}
+
+	member := newOrgMember(o, a, role)
+	o.members[*a.id] = member
+
+	result := *member
+	return &result, nil
+}
+
+// List retrieves all members of the given organization.
+func (s orgMemberService) List(org string) ([]*api.OrgMember, error) {
+	return s.ListContext(context.Background(), org)
+}
+
+// ListContext is the same as List, but uses the given context.
+func (s orgMemberService) ListContext(ctx context.Context, org string) ([]*api.OrgMember, error) {
+	err := api.ValidateOrgName(org)
+	if err != nil {
+		return nil, err
+	}
+
+	store := s.client.store
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	caller, err := s.client.caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	o, err := store.lookupOrg(caller, org)
+	if err != nil {
+		return nil, err
+	}
+	return o.listMembers(), nil
+}
+
+// Revoke removes the given user from the organization and from all its repositories.
+// When opts.DryRun is true, only the effect of the revocation is returned.
+func (s orgMemberService) Revoke(org string, username string, opts *api.RevokeOpts) (*api.RevokeOrgResponse, error) {
+	return s.RevokeContext(context.Background(), org, username, opts)
+}
+
+// RevokeContext is the same as Revoke, but uses the given context.
+func (s orgMemberService) RevokeContext(ctx context.Context, org string, username string, opts *api.RevokeOpts) (*api.RevokeOrgResponse, error) {
+	err := api.ValidateOrgName(org)
+	if err != nil {
+		return nil, err
+	}
+
+	err = api.ValidateUsername(username)
+	if err != nil {
+		return nil, err
+	}
+
+	dryRun := opts != nil && opts.DryRun
+
+	store := s.client.store
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	caller, err := s.client.caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	o, err := store.lookupOrgAsAdmin(caller, org)
+	if err != nil {
+		return nil, err
+	}
+
+	member, a, err := store.lookupOrgMember(o, username)
+	if err != nil {
+		return nil, err
+	}
+	if member.Role == api.OrgRoleAdmin && o.adminCount() == 1 {
+		return nil, api.ErrCannotRemoveLastOrgAdmin
+	}
+
+	resp := &api.RevokeOrgResponse{
+		DryRun:       dryRun,
+		Repos:        []*api.RevokeRepoResponse{},
+		StatusCounts: make(map[string]int),
+	}
+	for _, r := range store.reposInNamespace(o.org.Name) {
+		if _, isMember := r.members[*a.id]; !isMember {
+			continue
+		}
+
+		repoResp := store.revokeRepoMember(r, a, dryRun)
+		resp.Repos = append(resp.Repos, repoResp)
+		resp.StatusCounts[repoResp.Status]++
+
+		if !dryRun {
+			store.logEvent(caller, api.AuditActionDelete, r, &event{
+				subjectType:    api.AuditSubjectUser,
+				subjectAccount: a,
+			})
+		}
+	}
+
+	if !dryRun {
+		delete(o.members, *a.id)
+	}
+
+	return resp, nil
+}
+
+// Update updates the role of a member of the organization.
+func (s orgMemberService) Update(org string, username string, role string) (*api.OrgMember, error) {
+	return s.UpdateContext(context.Background(), org, username, role)
+}
+
+// UpdateContext is the same as Update, but uses the given context.
+func (s orgMemberService) UpdateContext(ctx context.Context, org string, username string, role string) (*api.OrgMember, error) {
+	err := api.ValidateOrgName(org)
+	if err != nil {
+		return nil, err
+	}
+
+	err = api.ValidateUsername(username)
+	if err != nil {
+		return nil, err
+	}
+
+	in := &api.UpdateOrgMemberRequest{
+		Role: role,
+	}
+
+	err = in.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	store := s.client.store
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	caller, err := s.client.caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	o, err := store.lookupOrgAsAdmin(caller, org)
+	if err != nil {
+		return nil, err
+	}
+
+	member, _, err := store.lookupOrgMember(o, username)
+	if err != nil {
+		return nil, err
+	}
+	if member.Role == api.OrgRoleAdmin && role != api.OrgRoleAdmin && o.adminCount() == 1 {
+		return nil, api.ErrCannotRemoveLastOrgAdmin
+	}
+
+	member.Role = role
+	member.LastChangedAt = now()
+
+	result := *member
+	return &result, nil
+}
+
+// lookupOrg returns the organization with the given name, when the caller is a member of it.
+func (s *Store) lookupOrg(caller *account, name string) (*org, error) {
+	o, ok := s.orgs[strings.ToLower(name)]
+	if !ok {
+		return nil, api.ErrOrgNotFound
+	}
+	if _, isMember := o.members[*caller.id]; !isMember {
+		return nil, api.ErrOrgNotFound
+	}
+	return o, nil
+}
+
+// lookupOrgAsAdmin returns the organization with the given name, when the caller is an admin of it.
+func (s *Store) lookupOrgAsAdmin(caller *account, name string) (*org, error) {
+	o, err := s.lookupOrg(caller, name)
+	if err != nil {
+		return nil, err
+	}
+	if o.members[*caller.id].Role != api.OrgRoleAdmin {
+		return nil, api.ErrForbidden
+	}
+	return o, nil
+}
+
+// lookupOrgMember returns the membership and the account of the user with the given name.
+func (s *Store) lookupOrgMember(o *org, username string) (*api.OrgMember, *account, error) {
+	a, err := s.lookupUser(username)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	member, ok := o.members[*a.id]
+	if !ok {
+		return nil, nil, api.ErrOrgMemberNotFound
+	}
+	return member, a, nil
+}
+
+// listMembers returns copies of the members of the organization, sorted by username.
+func (o *org) listMembers() []*api.OrgMember {
+	members := []*api.OrgMember{}
+	for _, member := range o.members {
+		result := *member
+		members = append(members, &result)
+	}
+	sort.Sort(api.SortOrgMemberByUsername(members))
+	return members
+}
+
+// adminCount returns the number of admins of the organization.
+func (o *org) adminCount() int {
+	count := 0
+	for _, member := range o.members {
+		if member.Role == api.OrgRoleAdmin {
+			count++
+		}
+	}
+	return count
+}
+
+// newOrgMember creates a membership of the organization for the user account.
+func newOrgMember(o *org, a *account, role string) *api.OrgMember {
+	createdAt := now()
+	return &api.OrgMember{
+		OrgID:         o.org.OrgID,
+		AccountID:     a.id,
+		Role:          role,
+		CreatedAt:     createdAt,
+		LastChangedAt: createdAt,
+		User:          a.toUser(),
+	}
+}
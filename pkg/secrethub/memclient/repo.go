@@ -0,0 +1,547 @@
+// +build !production
+
+package memclient
+
+import (
+	"context"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/api/uuid"
+	"github.com/secrethub/secrethub-go/internals/crypto"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+)
+
+type repoService struct {
+	client *Client
+}
+
+// Backup writes all versions of all secrets in the repository at the given path
+// to w, encrypted with a key derived from the passphrase.
+func (s repoService) Backup(path string, w io.Writer, passphrase []byte) error {
+	return s.BackupContext(context.Background(), path, w, passphrase)
+}
+
+// BackupContext is the same as Backup, but uses the given context.
+func (s repoService) BackupContext(ctx context.Context, path string, w io.Writer, passphrase []byte) error {
+	return secrethub.ClientSide(s.client).Repos().BackupContext(ctx, path, w, passphrase)
+}
+
+// Restore recreates the directories and secrets of the backup in r, which is
+// encrypted with a key derived from the passphrase, in the repository at the given path.
+func (s repoService) Restore(path string, r io.Reader, passphrase []byte) (*secrethub.RestoreResult, error) {
+	return s.RestoreContext(context.Background(), path, r, passphrase)
+}
+
+// RestoreContext is the same as Restore, but uses the given context.
+func (s repoService) RestoreContext(ctx context.Context, path string, r io.Reader, passphrase []byte) (*secrethub.RestoreResult, error) {
+	return secrethub.ClientSide(s.client).Repos().RestoreContext(ctx, path, r, passphrase)
+}
+
+// Create creates a new repo for the given owner and name.
+func (s repoService) Create(path string) (*api.Repo, error) {
+	return s.CreateContext(context.Background(), path)
+}
+
+// CreateContext is the same as Create, but uses the given context.
+func (s repoService) CreateContext(ctx context.Context, path string) (*api.Repo, error) {
+	repoPath, err := api.NewRepoPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	store := s.client.store
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	caller, err := s.client.caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	namespace := repoPath.GetNamespace()
+	if !store.namespaceExists(namespace) {
+		return nil, api.ErrNamespaceNotFound
+	}
+	if !store.canCreateInNamespace(caller, namespace) {
+		return nil, api.ErrForbidden
+	}
+	if _, exists := store.repos[strings.ToLower(repoPath.String())]; exists {
+		return nil, api.ErrRepoAlreadyExists
+	}
+
+	indexKey, err := crypto.GenerateSymmetricKey()
+	if err != nil {
+		return nil, err
+	}
+
+	createdAt := now()
+	r := &repo{
+		repo: &api.Repo{
+			RepoID:         uuid.New(),
+			Owner:          namespace,
+			Name:           repoPath.GetRepo(),
+			CreatedAt:      &createdAt,
+			LastModifiedAt: &createdAt,
+			Status:         api.StatusOK,
+		},
+		indexKey: indexKey,
+		members:  make(map[uuid.UUID]*repoMember),
+	}
+	r.rootDir = &dir{
+		id:             uuid.New(),
+		name:           repoPath.GetRepo(),
+		repo:           r,
+		dirs:           make(map[string]*dir),
+		secrets:        make(map[string]*secret),
+		rules:          make(map[uuid.UUID]*api.AccessRule),
+		createdAt:      createdAt,
+		lastModifiedAt: createdAt,
+	}
+	r.rootDir.rules[*caller.id] = newAccessRule(r.rootDir, caller, api.PermissionAdmin)
+	r.members[*caller.id] = newRepoMember(r, caller)
+
+	store.repos[strings.ToLower(repoPath.String())] = r
+
+	store.logEvent(caller, api.AuditActionCreate, r, &event{
+		subjectType: api.AuditSubjectRepo,
+		subjectRepo: r,
+	})
+
+	return r.toAPI(), nil
+}
+
+// Delete removes the repo with the given path.
+func (s repoService) Delete(path string) error {
+	return s.DeleteContext(context.Background(), path)
+}
+
+// DeleteContext is the same as Delete, but uses the given context.
+func (s repoService) DeleteContext(ctx context.Context, path string) error {
+	repoPath, err := api.NewRepoPath(path)
+	if err != nil {
+		return err
+	}
+
+	store := s.client.store
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	caller, err := s.client.caller(ctx)
+	if err != nil {
+		return err
+	}
+
+	r, err := store.lookupRepo(caller, repoPath)
+	if err != nil {
+		return err
+	}
+	if r.rootDir.permission(caller.id) < api.PermissionAdmin {
+		return api.ErrNoAdminAccess
+	}
+
+	store.removeRepo(r)
+
+	store.logEvent(caller, api.AuditActionDelete, r, &event{
+		subjectType: api.AuditSubjectRepo,
+		subjectRepo: r,
+	})
+
+	return nil
+}
+
+// Get retrieves the repo with the given path.
+func (s repoService) Get(path string) (*api.Repo, error) {
+	return s.GetContext(context.Background(), path)
+}
+
+// GetContext is the same as Get, but uses the given context.
+func (s repoService) GetContext(ctx context.Context, path string) (*api.Repo, error) {
+	repoPath, err := api.NewRepoPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	store := s.client.store
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	caller, err := s.client.caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := store.lookupRepo(caller, repoPath)
+	if err != nil {
+		return nil, err
+	}
+	return r.toAPI(), nil
+}
+
+// List retrieves all repositories in the given namespace the client is a member of.
+func (s repoService) List(namespace string) ([]*api.Repo, error) {
+	return s.ListContext(context.Background(), namespace)
+}
+
+// ListContext is the same as List, but uses the given context.
+func (s repoService) ListContext(ctx context.Context, namespace string) ([]*api.Repo, error) {
+	err := api.ValidateNamespace(namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	store := s.client.store
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	caller, err := s.client.caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !store.namespaceExists(namespace) {
+		return nil, api.ErrNamespaceNotFound
+	}
+
+	repos := []*api.Repo{}
+	for _, r := range store.reposInNamespace(namespace) {
+		if _, isMember := r.members[*caller.id]; isMember {
+			repos = append(repos, r.toAPI())
+		}
+	}
+	return repos, nil
+}
+
+// ListAccounts lists the accounts in the repository.
+func (s repoService) ListAccounts(path string) ([]*api.Account, error) {
+	return s.ListAccountsContext(context.Background(), path)
+}
+
+// ListAccountsContext is the same as ListAccounts, but uses the given context.
+func (s repoService) ListAccountsContext(ctx context.Context, path string) ([]*api.Account, error) {
+	repoPath, err := api.NewRepoPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	store := s.client.store
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	caller, err := s.client.caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := store.lookupRepo(caller, repoPath)
+	if err != nil {
+		return nil, err
+	}
+
+	accounts := []*api.Account{}
+	for _, member := range r.sortedMembers() {
+		accounts = append(accounts, member.account.toAPI())
+	}
+	return accounts, nil
+}
+
+// ListEvents retrieves all audit events for a given repo.
+func (s repoService) ListEvents(path string, subjectTypes api.AuditSubjectTypeList) ([]*api.Audit, error) {
+	return s.ListEventsContext(context.Background(), path, subjectTypes)
+}
+
+// ListEventsContext is the same as ListEvents, but uses the given context.
+func (s repoService) ListEventsContext(ctx context.Context, path string, subjectTypes api.AuditSubjectTypeList) ([]*api.Audit, error) {
+	return s.listEvents(ctx, path, subjectTypes, noPaths)
+}
+
+// listEvents returns the audit events for a given repo, with the paths of secrets set as given.
+func (s repoService) listEvents(ctx context.Context, path string, subjectTypes api.AuditSubjectTypeList, paths pathResolution) ([]*api.Audit, error) {
+	repoPath, err := api.NewRepoPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	store := s.client.store
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	caller, err := s.client.caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := store.lookupRepo(caller, repoPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return store.listEvents(caller, subjectTypes, paths, func(e *event) bool {
+		return e.repo == r
+	}), nil
+}
+
+// Events returns an iterator over the audit events for a given repo that match the filter.
+func (s repoService) Events(path string, filter secrethub.AuditFilter) secrethub.AuditEventIterator {
+	return s.EventsContext(context.Background(), path, filter)
+}
+
+// EventsContext is the same as Events, but uses the given context.
+func (s repoService) EventsContext(ctx context.Context, path string, filter secrethub.AuditFilter) secrethub.AuditEventIterator {
+	paths := noPaths
+	if filter.ResolvePaths {
+		paths = treePaths
+	}
+
+	events, err := s.listEvents(ctx, path, filter.SubjectTypes, paths)
+	return &eventIterator{
+		events: events,
+		filter: filter,
+		err:    err,
+	}
+}
+
+// ListMine retrieves all repositories of which the client is a member.
+func (s repoService) ListMine() ([]*api.Repo, error) {
+	return s.ListMineContext(context.Background())
+}
+
+// ListMineContext is the same as ListMine, but uses the given context.
+func (s repoService) ListMineContext(ctx context.Context) ([]*api.Repo, error) {
+	store := s.client.store
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	caller, err := s.client.caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	repos := []*api.Repo{}
+	for _, r := range store.repos {
+		if _, isMember := r.members[*caller.id]; isMember {
+			repos = append(repos, r.toAPI())
+		}
+	}
+	sort.Slice(repos, func(i, j int) bool {
+		return repos[i].Path() < repos[j].Path()
+	})
+	return repos, nil
+}
+
+// Users returns a RepoUserService that handles operations on users of a repository.
+func (s repoService) Users() secrethub.RepoUserService {
+	return repoUserService{client: s.client}
+}
+
+// Services returns a RepoServiceService that handles operations on services of a repository.
+func (s repoService) Services() secrethub.RepoServiceService {
+	return repoServiceService{client: s.client}
+}
+
+type repoUserService struct {
+	client *Client
+}
+
+// Invite adds a user to a repo.
+func (s repoUserService) Invite(path string, username string) (*api.RepoMember, error) {
+	return s.InviteContext(context.Background(), path, username)
+}
+
+// InviteContext is the same as Invite, but uses the given context.
+func (s repoUserService) InviteContext(ctx context.Context, path string, username string) (*api.RepoMember, error) {
+	repoPath, err := api.NewRepoPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	accountName, err := api.NewAccountName(username)
+	if err != nil {
+		return nil, err
+	}
+	if !accountName.IsUser() {
+		return nil, api.ErrUsernameIsService
+	}
+
+	store := s.client.store
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	caller, err := s.client.caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	invitee, err := store.lookupUser(username)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := store.lookupRepo(caller, repoPath)
+	if err != nil {
+		return nil, err
+	}
+	if r.rootDir.permission(caller.id) < api.PermissionAdmin {
+		return nil, api.ErrNoAdminAccess
+	}
+	if invitee == caller {
+		return nil, api.ErrCannotAddYourself
+	}
+	if _, isMember := r.members[*invitee.id]; isMember {
+		return nil, api.ErrMemberAlreadyExists
+	}
+
+	member := newRepoMember(r, invitee)
+	r.members[*invitee.id] = member
+
+	store.logEvent(caller, api.AuditActionCreate, r, &event{
+		subjectType:    api.AuditSubjectUser,
+		subjectAccount: invitee,
+	})
+
+	result := *member.member
+	return &result, nil
+}
+
+// List lists the users of a repository.
+func (s repoUserService) List(path string) ([]*api.User, error) {
+	return s.ListContext(context.Background(), path)
+}
+
+// ListContext is the same as List, but uses the given context.
+func (s repoUserService) ListContext(ctx context.Context, path string) ([]*api.User, error) {
+	repoPath, err := api.NewRepoPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	store := s.client.store
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	caller, err := s.client.caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := store.lookupRepo(caller, repoPath)
+	if err != nil {
+		return nil, err
+	}
+
+	users := []*api.User{}
+	for _, member := range r.sortedMembers() {
+		if member.account.isUser() {
+			users = append(users, member.account.toUser())
+		}
+	}
+	return users, nil
+}
+
+// Revoke removes a user from a repo.
+func (s repoUserService) Revoke(path string, username string) (*api.RevokeRepoResponse, error) {
+	return s.RevokeContext(context.Background(), path, username)
+}
+
+// RevokeContext is the same as Revoke, but uses the given context.
+func (s repoUserService) RevokeContext(ctx context.Context, path string, username string) (*api.RevokeRepoResponse, error) {
+	repoPath, err := api.NewRepoPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	err = api.ValidateUsername(username)
+	if err != nil {
+		return nil, err
+	}
+
+	store := s.client.store
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	caller, err := s.client.caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := store.lookupRepo(caller, repoPath)
+	if err != nil {
+		return nil, err
+	}
+	if r.rootDir.permission(caller.id) < api.PermissionAdmin {
+		return nil, api.ErrNoAdminAccess
+	}
+
+	user, err := store.lookupUser(username)
+	if err != nil {
+		return nil, err
+	}
+	if _, isMember := r.members[*user.id]; !isMember {
+		return nil, api.ErrRepoMemberNotFound
+	}
+	if user == caller {
+		return nil, api.ErrCannotRemoveYourself
+	}
+
+	resp := store.revokeRepoMember(r, user, false)
+
+	store.logEvent(caller, api.AuditActionDelete, r, &event{
+		subjectType:    api.AuditSubjectUser,
+		subjectAccount: user,
+	})
+
+	return resp, nil
+}
+
+type repoServiceService struct {
+	client *Client
+}
+
+// List lists the services of the given repository.
+func (s repoServiceService) List(path string) ([]*api.Service, error) {
+	return s.ListContext(context.Background(), path)
+}
+
+// ListContext is the same as List, but uses the given context.
+func (s repoServiceService) ListContext(ctx context.Context, path string) ([]*api.Service, error) {
+	return serviceService(s).ListContext(ctx, path)
+}
+
+// reposInNamespace returns the repositories in the namespace, sorted by name.
+func (s *Store) reposInNamespace(namespace string) []*repo {
+	var repos []*repo
+	for _, r := range s.repos {
+		if strings.EqualFold(r.repo.Owner, namespace) {
+			repos = append(repos, r)
+		}
+	}
+	sort.Slice(repos, func(i, j int) bool {
+		return repos[i].repo.Name < repos[j].repo.Name
+	})
+	return repos
+}
+
+// sortedMembers returns the members of the repository, sorted by account name.
+func (r *repo) sortedMembers() []*repoMember {
+	members := make([]*repoMember, 0, len(r.members))
+	for _, member := range r.members {
+		members = append(members, member)
+	}
+	sort.Slice(members, func(i, j int) bool {
+		return members[i].account.name < members[j].account.name
+	})
+	return members
+}
+
+// newRepoMember creates a member of the repository for the account.
+func newRepoMember(r *repo, a *account) *repoMember {
+	return &repoMember{
+		member: &api.RepoMember{
+			RepoID:    r.repo.RepoID,
+			AccountID: a.id,
+			CreatedAt: now(),
+		},
+		account: a,
+	}
+}
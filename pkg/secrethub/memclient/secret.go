@@ -0,0 +1,486 @@
+// +build !production
+
+package memclient
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/api/uuid"
+	"github.com/secrethub/secrethub-go/pkg/keygen"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+)
+
+type secretService struct {
+	client *Client
+}
+
+// Delete removes the secret at the given path.
+func (s secretService) Delete(path string) error {
+	return s.DeleteContext(context.Background(), path)
+}
+
+// DeleteContext is the same as Delete, but uses the given context.
+func (s secretService) DeleteContext(ctx context.Context, path string) error {
+	secretPath, err := api.NewSecretPath(path)
+	if err != nil {
+		return err
+	}
+
+	store := s.client.store
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	caller, err := s.client.caller(ctx)
+	if err != nil {
+		return err
+	}
+
+	sec, err := store.lookupSecret(caller, secretPath, api.PermissionWrite)
+	if err != nil {
+		return err
+	}
+
+	store.removeSecret(sec)
+
+	store.logEvent(caller, api.AuditActionDelete, sec.dir.repo, &event{
+		subjectType:   api.AuditSubjectSecret,
+		subjectSecret: sec,
+	})
+
+	return nil
+}
+
+// Exists returns whether a secret exists on the given path.
+func (s secretService) Exists(path string) (bool, error) {
+	return s.ExistsContext(context.Background(), path)
+}
+
+// ExistsContext is the same as Exists, but uses the given context.
+func (s secretService) ExistsContext(ctx context.Context, path string) (bool, error) {
+	_, err := s.GetContext(ctx, path)
+	if err == api.ErrSecretNotFound {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// GenerateKey generates key material with the generator and writes its private part
+// to the secret at the given path and, when writePublic is set and the key has a public
+// part, its public part to the sibling secret given by secrethub.PublicKeyPath.
+// When writing the public part fails, the result is returned together with the error.
+func (s secretService) GenerateKey(path string, generator keygen.Generator, writePublic bool) (*secrethub.KeyResult, error) {
+	return s.GenerateKeyContext(context.Background(), path, generator, writePublic)
+}
+
+// GenerateKeyContext is the same as GenerateKey, but uses the given context.
+func (s secretService) GenerateKeyContext(ctx context.Context, path string, generator keygen.Generator, writePublic bool) (*secrethub.KeyResult, error) {
+	secretPath, err := api.NewSecretPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if secretPath.HasVersion() {
+		return nil, secrethub.ErrCannotWriteToVersion
+	}
+
+	key, err := generator.GenerateKey()
+	if err != nil {
+		return nil, err
+	}
+
+	var publicPath api.SecretPath
+	if writePublic && len(key.Public) > 0 {
+		publicPath, err = secrethub.PublicKeyPath(secretPath, key.PublicSuffix)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	private, err := s.WriteContext(ctx, path, key.Private)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &secrethub.KeyResult{
+		Private: private,
+	}
+
+	if publicPath != "" {
+		public, err := s.WriteContext(ctx, publicPath.Value(), key.Public)
+		if err != nil {
+			return result, err
+		}
+
+		result.PublicPath = publicPath.Value()
+		result.Public = public
+	}
+
+	return result, nil
+}
+
+// Get retrieves a Secret.
+func (s secretService) Get(path string) (*api.Secret, error) {
+	return s.GetContext(context.Background(), path)
+}
+
+// GetContext is the same as Get, but uses the given context.
+func (s secretService) GetContext(ctx context.Context, path string) (*api.Secret, error) {
+	secretPath, err := api.NewSecretPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	store := s.client.store
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	caller, err := s.client.caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	sec, err := store.lookupSecret(caller, secretPath, api.PermissionRead)
+	if err != nil {
+		return nil, err
+	}
+	return sec.toAPI(), nil
+}
+
+// Write writes the data as a new version of the secret at the given path,
+// creating the secret when it does not exist yet. A new secret key is created
+// when all keys of the secret have been flagged.
+func (s secretService) Write(path string, data []byte) (*api.SecretVersion, error) {
+	return s.WriteContext(context.Background(), path, data)
+}
+
+// WriteContext is the same as Write, but uses the given context.
+func (s secretService) WriteContext(ctx context.Context, path string, data []byte) (*api.SecretVersion, error) {
+	secretPath, err := api.NewSecretPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	err = checkWrite(secretPath, data)
+	if err != nil {
+		return nil, err
+	}
+
+	store := s.client.store
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	caller, err := s.client.caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	sec, err := store.lookupSecret(caller, secretPath, api.PermissionWrite)
+	if err == api.ErrSecretNotFound {
+		return store.createSecret(caller, secretPath, data)
+	} else if err != nil {
+		return nil, err
+	}
+
+	return store.addSecretVersion(caller, sec, data)
+}
+
+// WriteIfLatest is the same as Write, but only writes the data when the latest
+// version of the secret is expectedVersion. Otherwise, secrethub.ErrSecretVersionConflict
+// is returned. When expectedVersion is 0, the secret is only created when it does not
+// exist yet, and api.ErrSecretAlreadyExists is returned when it does.
+// Like the real client, the latest version is checked before and after the write, so a
+// version written concurrently is returned together with secrethub.ErrSecretVersionConflict.
+func (s secretService) WriteIfLatest(path string, expectedVersion int, data []byte) (*api.SecretVersion, error) {
+	return s.WriteIfLatestContext(context.Background(), path, expectedVersion, data)
+}
+
+// WriteIfLatestContext is the same as WriteIfLatest, but uses the given context.
+func (s secretService) WriteIfLatestContext(ctx context.Context, path string, expectedVersion int, data []byte) (*api.SecretVersion, error) {
+	secretPath, err := api.NewSecretPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	err = checkWrite(secretPath, data)
+	if err != nil {
+		return nil, err
+	}
+
+	if expectedVersion < 0 {
+		return nil, api.ErrInvalidSecretVersion
+	}
+
+	exists, err := s.checkLatestVersion(ctx, secretPath, expectedVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	if !exists {
+		return s.create(ctx, secretPath, data)
+	}
+
+	version, err := s.WriteContext(ctx, path, data)
+	if err != nil {
+		return nil, err
+	}
+
+	if version.Version != expectedVersion+1 {
+		return version, secrethub.ErrSecretVersionConflict
+	}
+	return version, nil
+}
+
+// checkLatestVersion returns whether the secret exists and checks that its latest
+// version is the expected version, in the same way as the real client does before it
+// writes a version. The store is unlocked afterwards, so other writes can happen
+// before the version is written.
+func (s secretService) checkLatestVersion(ctx context.Context, secretPath api.SecretPath, expectedVersion int) (bool, error) {
+	store := s.client.store
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	caller, err := s.client.caller(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	sec, err := store.lookupSecret(caller, secretPath, api.PermissionRead)
+	if err == api.ErrSecretNotFound {
+		if expectedVersion != 0 {
+			return false, secrethub.ErrSecretVersionConflict
+		}
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	if expectedVersion == 0 {
+		return true, api.ErrSecretAlreadyExists
+	}
+	if sec.toAPI().LatestVersion != expectedVersion {
+		return true, secrethub.ErrSecretVersionConflict
+	}
+	return true, nil
+}
+
+// create creates the secret at the given path, failing when it already exists.
+func (s secretService) create(ctx context.Context, secretPath api.SecretPath, data []byte) (*api.SecretVersion, error) {
+	store := s.client.store
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	caller, err := s.client.caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = store.lookupSecret(caller, secretPath, api.PermissionRead)
+	if err == nil {
+		return nil, api.ErrSecretAlreadyExists
+	} else if err != api.ErrSecretNotFound {
+		return nil, err
+	}
+
+	return store.createSecret(caller, secretPath, data)
+}
+
+// Rotate replaces the value of an existing secret with a new random value that is
+// generated according to the policy and returns the old and new version numbers.
+// The hook may use the client.
+func (s secretService) Rotate(path string, policy secrethub.RotatePolicy) (*secrethub.RotateResult, error) {
+	return s.RotateContext(context.Background(), path, policy)
+}
+
+// RotateContext is the same as Rotate, but uses the given context.
+func (s secretService) RotateContext(ctx context.Context, path string, policy secrethub.RotatePolicy) (*secrethub.RotateResult, error) {
+	return secrethub.ClientSide(s.client).Secrets().RotateContext(ctx, path, policy)
+}
+
+// ListEvents retrieves all audit events for a given secret.
+// If subjectTypes is left empty, events of all subject types are returned.
+func (s secretService) ListEvents(path string, subjectTypes api.AuditSubjectTypeList) ([]*api.Audit, error) {
+	return s.ListEventsContext(context.Background(), path, subjectTypes)
+}
+
+// ListEventsContext is the same as ListEvents, but uses the given context.
+func (s secretService) ListEventsContext(ctx context.Context, path string, subjectTypes api.AuditSubjectTypeList) ([]*api.Audit, error) {
+	secretPath, err := api.NewSecretPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	store := s.client.store
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	caller, err := s.client.caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	sec, err := store.lookupSecret(caller, secretPath, api.PermissionRead)
+	if err != nil {
+		return nil, err
+	}
+
+	return store.listEvents(caller, subjectTypes, knownPaths, func(e *event) bool {
+		return e.subjectSecret == sec || (e.subjectVersion != nil && e.subjectVersion.secret == sec)
+	}), nil
+}
+
+// Events returns an iterator over the audit events for a given secret that match the filter.
+func (s secretService) Events(path string, filter secrethub.AuditFilter) secrethub.AuditEventIterator {
+	return s.EventsContext(context.Background(), path, filter)
+}
+
+// EventsContext is the same as Events, but uses the given context.
+func (s secretService) EventsContext(ctx context.Context, path string, filter secrethub.AuditFilter) secrethub.AuditEventIterator {
+	events, err := s.ListEventsContext(ctx, path, filter.SubjectTypes)
+	return &eventIterator{
+		events: events,
+		filter: filter,
+		err:    err,
+	}
+}
+
+// Move moves the secret at path src to path dst. The source is only deleted after
+// everything has been copied. When the move fails, the secret created at dst is
+// removed again.
+func (s secretService) Move(src, dst string, options secrethub.MoveOptions) (*secrethub.MoveResult, error) {
+	return s.MoveContext(context.Background(), src, dst, options)
+}
+
+// MoveContext is the same as Move, but uses the given context.
+func (s secretService) MoveContext(ctx context.Context, src, dst string, options secrethub.MoveOptions) (*secrethub.MoveResult, error) {
+	return secrethub.ClientSide(s.client).Secrets().MoveContext(ctx, src, dst, options)
+}
+
+// PruneVersions deletes the versions of the secret at the given path that are not kept according
+// to the policy. When the path is a directory, the versions of all secrets in the directory and
+// its subdirectories are pruned.
+func (s secretService) PruneVersions(path string, policy secrethub.RetentionPolicy) (*secrethub.PruneReport, error) {
+	return s.PruneVersionsContext(context.Background(), path, policy)
+}
+
+// PruneVersionsContext is the same as PruneVersions, but uses the given context.
+func (s secretService) PruneVersionsContext(ctx context.Context, path string, policy secrethub.RetentionPolicy) (*secrethub.PruneReport, error) {
+	return secrethub.ClientSide(s.client).Secrets().PruneVersionsContext(ctx, path, policy)
+}
+
+// Watch polls the latest versions of the secrets at the given paths at the given interval
+// and emits an event on the returned channel for every secret of which the latest version
+// has changed. The first poll emits an event for every secret. The channel is closed when
+// the context is done.
+func (s secretService) Watch(ctx context.Context, paths []string, interval time.Duration, withData bool) (<-chan secrethub.WatchEvent, error) {
+	return secrethub.ClientSide(s.client).Secrets().Watch(ctx, paths, interval, withData)
+}
+
+// Versions returns a SecretVersionService.
+func (s secretService) Versions() secrethub.SecretVersionService {
+	return secretVersionService{client: s.client}
+}
+
+// checkWrite returns an error when the data cannot be written to the path.
+func checkWrite(path api.SecretPath, data []byte) error {
+	if path.HasVersion() {
+		return secrethub.ErrCannotWriteToVersion
+	}
+
+	if len(data) == 0 {
+		return secrethub.ErrEmptySecret
+	}
+
+	if len(data) > secrethub.MaxSecretSize {
+		return secrethub.ErrSecretTooBig
+	}
+
+	return nil
+}
+
+// addSecretVersion adds the data as a new version of the secret,
+// creating a new secret key when all its keys have been flagged.
+func (s *Store) addSecretVersion(caller *account, sec *secret, data []byte) (*api.SecretVersion, error) {
+	key, ok := sec.currentKey()
+	if !ok {
+		var err error
+		key, err = newSecretKey(sec.dir)
+		if err != nil {
+			return nil, err
+		}
+		sec.keys = append(sec.keys, key)
+	}
+
+	return s.createSecretVersion(caller, sec, key, data), nil
+}
+
+// createSecret creates the secret at the given path with the data as its first version.
+// This requires write permission on the directory the secret is created in.
+func (s *Store) createSecret(caller *account, path api.SecretPath, data []byte) (*api.SecretVersion, error) {
+	parentPath, err := path.GetParentPath()
+	if err != nil {
+		return nil, err
+	}
+
+	d, err := s.lookupDir(caller, api.DirPath(parentPath), api.PermissionWrite)
+	if err != nil {
+		return nil, err
+	}
+
+	name := path.GetSecret()
+	if _, exists := d.dirs[strings.ToLower(name)]; exists {
+		return nil, api.ErrSecretAlreadyExists
+	}
+
+	key, err := newSecretKey(d)
+	if err != nil {
+		return nil, err
+	}
+
+	createdAt := now()
+	sec := &secret{
+		id:        uuid.New(),
+		name:      name,
+		dir:       d,
+		keys:      []*secretKey{key},
+		createdAt: createdAt,
+	}
+	d.secrets[strings.ToLower(name)] = sec
+	d.lastModifiedAt = createdAt
+
+	s.logEvent(caller, api.AuditActionCreate, d.repo, &event{
+		subjectType:   api.AuditSubjectSecret,
+		subjectSecret: sec,
+	})
+
+	return s.createSecretVersion(caller, sec, key, data), nil
+}
+
+// createSecretVersion adds the data as a new version of the secret, using the given key.
+func (s *Store) createSecretVersion(caller *account, sec *secret, key *secretKey, data []byte) *api.SecretVersion {
+	number := 1
+	if len(sec.versions) > 0 {
+		number = sec.versions[len(sec.versions)-1].version + 1
+	}
+
+	version := &secretVersion{
+		id:        uuid.New(),
+		secret:    sec,
+		version:   number,
+		key:       key,
+		data:      append([]byte{}, data...),
+		createdAt: now(),
+	}
+	sec.versions = append(sec.versions, version)
+
+	s.logEvent(caller, api.AuditActionCreate, sec.dir.repo, &event{
+		subjectType:    api.AuditSubjectSecretVersion,
+		subjectVersion: version,
+	})
+
+	return version.toAPI(caller, false)
+}
@@ -0,0 +1,253 @@
+// +build !production
+
+package memclient
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+)
+
+type secretVersionService struct {
+	client *Client
+}
+
+// Delete removes a secret version.
+func (s secretVersionService) Delete(path string) error {
+	return s.DeleteContext(context.Background(), path)
+}
+
+// DeleteContext is the same as Delete, but uses the given context.
+func (s secretVersionService) DeleteContext(ctx context.Context, path string) error {
+	secretPath, err := api.NewSecretPath(path)
+	if err != nil {
+		return err
+	}
+
+	version, err := secretPath.GetVersion()
+	if err != nil {
+		return err
+	}
+
+	store := s.client.store
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	caller, err := s.client.caller(ctx)
+	if err != nil {
+		return err
+	}
+
+	sec, err := store.lookupSecret(caller, secretPath, api.PermissionWrite)
+	if err != nil {
+		return err
+	}
+
+	v, err := sec.version(version)
+	if err != nil {
+		return err
+	}
+	if len(sec.versions) == 1 {
+		return api.ErrCannotDeleteLastSecretVersion
+	}
+
+	for i, current := range sec.versions {
+		if current == v {
+			sec.versions = append(sec.versions[:i], sec.versions[i+1:]...)
+			break
+		}
+	}
+	v.deleted = true
+
+	store.logEvent(caller, api.AuditActionDelete, sec.dir.repo, &event{
+		subjectType:    api.AuditSubjectSecretVersion,
+		subjectVersion: v,
+	})
+
+	return nil
+}
+
+// get gets a version of a secret. When the path has no version, the latest version is returned.
+// Reading the data of a version is recorded as an audit event.
+// It must be called while the mutex of the store is held.
+func (s secretVersionService) get(caller *account, path api.SecretPath, withData bool) (*api.SecretVersion, error) {
+	store := s.client.store
+
+	sec, err := store.lookupSecret(caller, path, api.PermissionRead)
+	if err != nil {
+		return nil, err
+	}
+
+	version := "latest"
+	if path.HasVersion() {
+		version, err = path.GetVersion()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	v, err := sec.version(version)
+	if err != nil {
+		return nil, err
+	}
+
+	if withData {
+		store.logEvent(caller, api.AuditActionRead, sec.dir.repo, &event{
+			subjectType:    api.AuditSubjectSecretVersion,
+			subjectVersion: v,
+		})
+	}
+
+	return v.toAPI(caller, withData), nil
+}
+
+// GetWithData gets a secret version, with the sensitive data.
+func (s secretVersionService) GetWithData(path string) (*api.SecretVersion, error) {
+	return s.GetWithDataContext(context.Background(), path)
+}
+
+// GetWithDataContext is the same as GetWithData, but uses the given context.
+func (s secretVersionService) GetWithDataContext(ctx context.Context, path string) (*api.SecretVersion, error) {
+	secretPath, err := api.NewSecretPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	s.client.store.mutex.Lock()
+	defer s.client.store.mutex.Unlock()
+
+	caller, err := s.client.caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.get(caller, secretPath, true)
+}
+
+// GetManyWithData gets multiple secret versions, with the sensitive data.
+// A result is returned for every given path, in the same order as the paths.
+func (s secretVersionService) GetManyWithData(paths []string) []secrethub.SecretVersionResult {
+	return s.GetManyWithDataContext(context.Background(), paths)
+}
+
+// GetManyWithDataContext is the same as GetManyWithData, but uses the given context.
+func (s secretVersionService) GetManyWithDataContext(ctx context.Context, paths []string) []secrethub.SecretVersionResult {
+	results := make([]secrethub.SecretVersionResult, len(paths))
+	for i, path := range paths {
+		results[i].Path = path
+		results[i].Version, results[i].Err = s.GetWithDataContext(ctx, path)
+	}
+	return results
+}
+
+// GetWithoutData gets a secret version, without the sensitive data.
+func (s secretVersionService) GetWithoutData(path string) (*api.SecretVersion, error) {
+	return s.GetWithoutDataContext(context.Background(), path)
+}
+
+// GetWithoutDataContext is the same as GetWithoutData, but uses the given context.
+func (s secretVersionService) GetWithoutDataContext(ctx context.Context, path string) (*api.SecretVersion, error) {
+	secretPath, err := api.NewSecretPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	s.client.store.mutex.Lock()
+	defer s.client.store.mutex.Unlock()
+
+	caller, err := s.client.caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.get(caller, secretPath, false)
+}
+
+// list lists all versions of a secret. Reading the data of
+// the versions is recorded as an audit event for every version.
+func (s secretVersionService) list(ctx context.Context, path string, withData bool) ([]*api.SecretVersion, error) {
+	secretPath, err := api.NewSecretPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	store := s.client.store
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	caller, err := s.client.caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	sec, err := store.lookupSecret(caller, secretPath, api.PermissionRead)
+	if err != nil {
+		return nil, err
+	}
+
+	versions := make([]*api.SecretVersion, 0, len(sec.versions))
+	for _, v := range sec.versions {
+		versions = append(versions, v.toAPI(caller, withData))
+
+		if withData {
+			store.logEvent(caller, api.AuditActionRead, sec.dir.repo, &event{
+				subjectType:    api.AuditSubjectSecretVersion,
+				subjectVersion: v,
+			})
+		}
+	}
+	return versions, nil
+}
+
+// ListWithData lists secret versions, with the sensitive data.
+func (s secretVersionService) ListWithData(path string) ([]*api.SecretVersion, error) {
+	return s.ListWithDataContext(context.Background(), path)
+}
+
+// ListWithDataContext is the same as ListWithData, but uses the given context.
+func (s secretVersionService) ListWithDataContext(ctx context.Context, path string) ([]*api.SecretVersion, error) {
+	return s.list(ctx, path, true)
+}
+
+// ListWithoutData lists secret versions, without the sensitive data.
+func (s secretVersionService) ListWithoutData(path string) ([]*api.SecretVersion, error) {
+	return s.ListWithoutDataContext(context.Background(), path)
+}
+
+// ListWithoutDataContext is the same as ListWithoutData, but uses the given context.
+func (s secretVersionService) ListWithoutDataContext(ctx context.Context, path string) ([]*api.SecretVersion, error) {
+	return s.list(ctx, path, false)
+}
+
+// Diff compares two versions of the secret at the given path. When redact is set,
+// the values of the changes are replaced by secrethub.RedactedValue.
+func (s secretVersionService) Diff(path string, from, to int, redact bool) (*secrethub.VersionDiff, error) {
+	return s.DiffContext(context.Background(), path, from, to, redact)
+}
+
+// DiffContext is the same as Diff, but uses the given context.
+func (s secretVersionService) DiffContext(ctx context.Context, path string, from, to int, redact bool) (*secrethub.VersionDiff, error) {
+	return secrethub.ClientSide(s.client).Secrets().Versions().DiffContext(ctx, path, from, to, redact)
+}
+
+// Rollback writes the value of the given version of the secret at the given path as
+// a new version, which becomes the latest version.
+func (s secretVersionService) Rollback(path string, version int) (*api.SecretVersion, error) {
+	return s.RollbackContext(context.Background(), path, version)
+}
+
+// RollbackContext is the same as Rollback, but uses the given context.
+func (s secretVersionService) RollbackContext(ctx context.Context, path string, version int) (*api.SecretVersion, error) {
+	return secrethub.ClientSide(s.client).Secrets().Versions().RollbackContext(ctx, path, version)
+}
+
+// parseVersion parses a secret version number.
+func parseVersion(version string) (int, error) {
+	number, err := strconv.Atoi(version)
+	if err != nil || number <= 0 {
+		return 0, api.ErrInvalidSecretVersion
+	}
+	return number, nil
+}
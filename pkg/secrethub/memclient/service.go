@@ -0,0 +1,210 @@
+// +build !production
+
+package memclient
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/api/uuid"
+	"github.com/secrethub/secrethub-go/pkg/randchar"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+)
+
+// serviceIDLength is the length of the random part of a service id.
+const serviceIDLength = 12
+
+type serviceService struct {
+	client *Client
+}
+
+// Create creates a new service account for the given repo. The credential is not
+// used, as the Client does not sign its requests. Use Store.ClientFor with the
+// id of the returned service to get a Client that acts on behalf of the service.
+func (s serviceService) Create(path string, description string, credential secrethub.Credential) (*api.Service, error) {
+	return s.CreateContext(context.Background(), path, description, credential)
+}
+
+// CreateContext is the same as Create, but uses the given context.
+func (s serviceService) CreateContext(ctx context.Context, path string, description string, credential secrethub.Credential) (*api.Service, error) {
+	repoPath, err := api.NewRepoPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	err = api.ValidateServiceDescription(description)
+	if err != nil {
+		return nil, err
+	}
+
+	store := s.client.store
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	caller, err := s.client.caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := store.lookupRepo(caller, repoPath)
+	if err != nil {
+		return nil, err
+	}
+	if r.rootDir.permission(caller.id) < api.PermissionAdmin {
+		return nil, api.ErrNoAdminAccess
+	}
+
+	random, err := randchar.NewGenerator(false).Generate(serviceIDLength)
+	if err != nil {
+		return nil, err
+	}
+	serviceID := api.ServiceNamePrefix + strings.ToLower(string(random))
+
+	accountID := uuid.New()
+	a := &account{
+		id:          accountID,
+		name:        serviceID,
+		accountType: accountTypeService,
+		createdAt:   now(),
+		service: &api.Service{
+			AccountID:   accountID,
+			ServiceID:   serviceID,
+			Description: description,
+			CreatedBy:   caller.id,
+		},
+		serviceRepo: r,
+	}
+
+	store.accounts[serviceID] = a
+	r.members[*accountID] = newRepoMember(r, a)
+
+	store.logEvent(caller, api.AuditActionCreate, r, &event{
+		subjectType:    api.AuditSubjectService,
+		subjectAccount: a,
+	})
+
+	return a.toService(), nil
+}
+
+// Delete removes a service account.
+func (s serviceService) Delete(name string) (*api.RevokeRepoResponse, error) {
+	return s.DeleteContext(context.Background(), name)
+}
+
+// DeleteContext is the same as Delete, but uses the given context.
+func (s serviceService) DeleteContext(ctx context.Context, name string) (*api.RevokeRepoResponse, error) {
+	err := api.ValidateServiceID(name)
+	if err != nil {
+		return nil, err
+	}
+
+	store := s.client.store
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	caller, err := s.client.caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	service, err := store.lookupService(caller, name)
+	if err != nil {
+		return nil, err
+	}
+
+	r := service.serviceRepo
+	if r.rootDir.permission(caller.id) < api.PermissionAdmin {
+		return nil, api.ErrNoAdminAccess
+	}
+
+	resp := store.revokeRepoMember(r, service, false)
+	store.removeAccount(service)
+
+	store.logEvent(caller, api.AuditActionDelete, r, &event{
+		subjectType:    api.AuditSubjectService,
+		subjectAccount: service,
+	})
+
+	return resp, nil
+}
+
+// Get retrieves a service account.
+func (s serviceService) Get(name string) (*api.Service, error) {
+	return s.GetContext(context.Background(), name)
+}
+
+// GetContext is the same as Get, but uses the given context.
+func (s serviceService) GetContext(ctx context.Context, name string) (*api.Service, error) {
+	err := api.ValidateServiceID(name)
+	if err != nil {
+		return nil, err
+	}
+
+	store := s.client.store
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	caller, err := s.client.caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	service, err := store.lookupService(caller, name)
+	if err != nil {
+		return nil, err
+	}
+	return service.toService(), nil
+}
+
+// List lists the service accounts of the given repository.
+func (s serviceService) List(path string) ([]*api.Service, error) {
+	return s.ListContext(context.Background(), path)
+}
+
+// ListContext is the same as List, but uses the given context.
+func (s serviceService) ListContext(ctx context.Context, path string) ([]*api.Service, error) {
+	repoPath, err := api.NewRepoPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	store := s.client.store
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	caller, err := s.client.caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := store.lookupRepo(caller, repoPath)
+	if err != nil {
+		return nil, err
+	}
+
+	services := []*api.Service{}
+	for _, member := range r.members {
+		if member.account.serviceRepo == r {
+			services = append(services, member.account.toService())
+		}
+	}
+	sort.Slice(services, func(i, j int) bool {
+		return services[i].ServiceID < services[j].ServiceID
+	})
+	return services, nil
+}
+
+// lookupService returns the service account with the given id,
+// when the caller is a member of the repository of the service.
+func (s *Store) lookupService(caller *account, serviceID string) (*account, error) {
+	service, ok := s.accounts[strings.ToLower(serviceID)]
+	if !ok || service.isUser() {
+		return nil, api.ErrServiceNotFound
+	}
+	if _, isMember := service.serviceRepo.members[*caller.id]; !isMember {
+		return nil, api.ErrServiceNotFound
+	}
+	return service, nil
+}
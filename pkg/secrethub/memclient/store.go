@@ -0,0 +1,593 @@
+// +build !production
+
+package memclient
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/api/uuid"
+	"github.com/secrethub/secrethub-go/internals/crypto"
+)
+
+// Account types
+const (
+	accountTypeUser    = "user"
+	accountTypeService = "service"
+)
+
+// Store holds the state that is shared by all clients created from it.
+// All methods are safe for concurrent use.
+type Store struct {
+	mutex    sync.Mutex
+	accounts map[string]*account
+	orgs     map[string]*org
+	repos    map[string]*repo
+	events   []*event
+}
+
+// NewStore returns a Store without any state.
+func NewStore() *Store {
+	return &Store{
+		accounts: make(map[string]*account),
+		orgs:     make(map[string]*org),
+		repos:    make(map[string]*repo),
+	}
+}
+
+// account is a user or a service account.
+type account struct {
+	id          *uuid.UUID
+	name        string
+	accountType string
+	createdAt   time.Time
+
+	// user is set for user accounts.
+	user *api.User
+	// service and serviceRepo are set for service accounts.
+	service     *api.Service
+	serviceRepo *repo
+}
+
+// toAPI returns the account as an api.Account.
+func (a *account) toAPI() *api.Account {
+	return &api.Account{
+		AccountID:   a.id,
+		Name:        api.AccountName(a.name),
+		AccountType: a.accountType,
+		CreatedAt:   a.createdAt,
+	}
+}
+
+// toUser returns the public details of a user account.
+func (a *account) toUser() *api.User {
+	return a.user.Trim()
+}
+
+// toService returns the details of a service account.
+func (a *account) toService() *api.Service {
+	service := *a.service
+	service.Repo = a.serviceRepo.toAPI()
+	return &service
+}
+
+// isUser returns whether the account is a user account.
+func (a *account) isUser() bool {
+	return a.accountType == accountTypeUser
+}
+
+// org is an organization, whose namespace can contain repositories of its members.
+type org struct {
+	org     *api.Org
+	members map[uuid.UUID]*api.OrgMember
+}
+
+// repo is a repository. A repository has a root directory, which contains
+// all its directories and secrets, and members who can access it.
+type repo struct {
+	repo     *api.Repo
+	indexKey *crypto.SymmetricKey
+	rootDir  *dir
+	members  map[uuid.UUID]*repoMember
+	deleted  bool
+}
+
+// repoMember is an account that is a member of a repository.
+type repoMember struct {
+	member  *api.RepoMember
+	account *account
+}
+
+// toAPI returns the repository as an api.Repo.
+func (r *repo) toAPI() *api.Repo {
+	result := *r.repo
+	result.MemberCount = len(r.members)
+	result.SecretCount = r.rootDir.secretCount()
+	return &result
+}
+
+// path returns the path of the repository.
+func (r *repo) path() api.RepoPath {
+	return r.repo.Path()
+}
+
+// dir is a directory in a repository.
+type dir struct {
+	id             *uuid.UUID
+	name           string
+	repo           *repo
+	parent         *dir
+	dirs           map[string]*dir
+	secrets        map[string]*secret
+	rules          map[uuid.UUID]*api.AccessRule
+	createdAt      time.Time
+	lastModifiedAt time.Time
+}
+
+// path returns the path of the directory.
+func (d *dir) path() api.DirPath {
+	if d.parent == nil {
+		return d.repo.path().GetDirPath()
+	}
+	return d.parent.path().JoinDir(d.name)
+}
+
+// toAPI returns the directory as an api.Dir, without its contents.
+func (d *dir) toAPI() *api.Dir {
+	var parentID *uuid.UUID
+	if d.parent != nil {
+		parentID = d.parent.id
+	}
+
+	blindName, _ := d.path().BlindName(d.repo.indexKey)
+	return &api.Dir{
+		DirID:          d.id,
+		BlindName:      blindName,
+		Name:           d.name,
+		ParentID:       parentID,
+		Status:         api.StatusOK,
+		CreatedAt:      d.createdAt,
+		LastModifiedAt: d.lastModifiedAt,
+	}
+}
+
+// permission returns the permission the account has on the directory,
+// which is the highest permission of all rules on the directory and its parents.
+func (d *dir) permission(accountID *uuid.UUID) api.Permission {
+	permission := api.PermissionNone
+	for current := d; current != nil; current = current.parent {
+		rule, ok := current.rules[*accountID]
+		if ok && rule.Permission > permission {
+			permission = rule.Permission
+		}
+	}
+	return permission
+}
+
+// accounts returns the accounts that have at least read permission on the directory.
+func (d *dir) accounts() []*account {
+	var accounts []*account
+	for _, member := range d.repo.members {
+		if d.permission(member.account.id) >= api.PermissionRead {
+			accounts = append(accounts, member.account)
+		}
+	}
+	sort.Slice(accounts, func(i, j int) bool {
+		return accounts[i].name < accounts[j].name
+	})
+	return accounts
+}
+
+// secretCount returns the number of secrets in the directory and all its subdirectories.
+func (d *dir) secretCount() int {
+	count := len(d.secrets)
+	for _, child := range d.dirs {
+		count += child.secretCount()
+	}
+	return count
+}
+
+// walk calls fn for the directory and all its subdirectories, up to the given depth.
+// A negative depth means there is no maximum depth.
+func (d *dir) walk(depth int, fn func(d *dir)) {
+	d.walkLevel(0, depth, fn)
+}
+
+func (d *dir) walkLevel(level int, depth int, fn func(d *dir)) {
+	fn(d)
+	if depth >= 0 && level >= depth {
+		return
+	}
+	for _, child := range d.sortedDirs() {
+		child.walkLevel(level+1, depth, fn)
+	}
+}
+
+// sortedDirs returns the subdirectories of the directory, sorted by name.
+func (d *dir) sortedDirs() []*dir {
+	dirs := make([]*dir, 0, len(d.dirs))
+	for _, child := range d.dirs {
+		dirs = append(dirs, child)
+	}
+	sort.Slice(dirs, func(i, j int) bool {
+		return dirs[i].name < dirs[j].name
+	})
+	return dirs
+}
+
+// sortedSecrets returns the secrets in the directory, sorted by name.
+func (d *dir) sortedSecrets() []*secret {
+	secrets := make([]*secret, 0, len(d.secrets))
+	for _, sec := range d.secrets {
+		secrets = append(secrets, sec)
+	}
+	sort.Slice(secrets, func(i, j int) bool {
+		return secrets[i].name < secrets[j].name
+	})
+	return secrets
+}
+
+// ancestors returns the parents of the directory, starting at the root directory.
+func (d *dir) ancestors() []*dir {
+	var ancestors []*dir
+	for current := d.parent; current != nil; current = current.parent {
+		ancestors = append([]*dir{current}, ancestors...)
+	}
+	return ancestors
+}
+
+// secret is a secret in a directory.
+type secret struct {
+	id        *uuid.UUID
+	name      string
+	dir       *dir
+	keys      []*secretKey
+	versions  []*secretVersion
+	createdAt time.Time
+	deleted   bool
+}
+
+// path returns the path of the secret.
+func (s *secret) path() api.SecretPath {
+	return s.dir.path().JoinSecret(s.name)
+}
+
+// toAPI returns the secret as an api.Secret.
+func (s *secret) toAPI() *api.Secret {
+	latest := 0
+	if len(s.versions) > 0 {
+		latest = s.versions[len(s.versions)-1].version
+	}
+
+	blindName, _ := s.path().BlindName(s.dir.repo.indexKey)
+	return &api.Secret{
+		SecretID:      s.id,
+		DirID:         s.dir.id,
+		RepoID:        s.dir.repo.repo.RepoID,
+		Name:          s.name,
+		BlindName:     blindName,
+		VersionCount:  len(s.versions),
+		LatestVersion: latest,
+		Status:        api.StatusOK,
+		CreatedAt:     s.createdAt,
+	}
+}
+
+// version returns the version with the given number or "latest".
+func (s *secret) version(version string) (*secretVersion, error) {
+	if strings.ToLower(version) == "latest" {
+		if len(s.versions) == 0 {
+			return nil, api.ErrSecretVersionNotFound
+		}
+		return s.versions[len(s.versions)-1], nil
+	}
+
+	number, err := parseVersion(version)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, v := range s.versions {
+		if v.version == number {
+			return v, nil
+		}
+	}
+	return nil, api.ErrSecretVersionNotFound
+}
+
+// currentKey returns the most recent secret key that has not been flagged.
+func (s *secret) currentKey() (*secretKey, bool) {
+	for i := len(s.keys) - 1; i >= 0; i-- {
+		if s.keys[i].status == api.StatusOK {
+			return s.keys[i], true
+		}
+	}
+	return nil, false
+}
+
+// secretKey is a key used for secret versions. A key is flagged when an
+// account that had access to it is revoked, after which it is no longer used.
+type secretKey struct {
+	id     *uuid.UUID
+	key    *crypto.SymmetricKey
+	status string
+	// accounts contains the accounts that had access to the key.
+	accounts map[uuid.UUID]bool
+}
+
+// secretVersion is a version of a secret.
+type secretVersion struct {
+	id        *uuid.UUID
+	secret    *secret
+	version   int
+	key       *secretKey
+	data      []byte
+	createdAt time.Time
+	deleted   bool
+}
+
+// path returns the path of the secret, followed by a colon and the version number.
+func (v *secretVersion) path() api.SecretPath {
+	return api.SecretPath(v.secret.path().String() + ":" + strconv.Itoa(v.version))
+}
+
+// toAPI returns the version as an api.SecretVersion, with or without the data.
+func (v *secretVersion) toAPI(caller *account, withData bool) *api.SecretVersion {
+	result := &api.SecretVersion{
+		SecretVersionID: v.id,
+		Secret:          v.secret.toAPI(),
+		Version:         v.version,
+		CreatedAt:       v.createdAt,
+		Status:          v.key.status,
+	}
+
+	if withData {
+		result.SecretKey = &api.SecretKey{
+			SecretKeyID: v.key.id,
+			AccountID:   caller.id,
+			Key:         v.key.key,
+		}
+		result.Data = append([]byte{}, v.data...)
+	}
+
+	return result
+}
+
+// lookupAccount returns the account with the given name.
+func (s *Store) lookupAccount(name string) (*account, error) {
+	a, ok := s.accounts[strings.ToLower(name)]
+	if !ok {
+		return nil, api.ErrAccountNotFound
+	}
+	return a, nil
+}
+
+// lookupUser returns the user account with the given username.
+func (s *Store) lookupUser(username string) (*account, error) {
+	a, ok := s.accounts[strings.ToLower(username)]
+	if !ok || !a.isUser() {
+		return nil, api.ErrUserNotFound
+	}
+	return a, nil
+}
+
+// namespaceExists returns whether the namespace is the name of a user or an organization.
+func (s *Store) namespaceExists(namespace string) bool {
+	_, err := s.lookupUser(namespace)
+	if err == nil {
+		return true
+	}
+	_, ok := s.orgs[strings.ToLower(namespace)]
+	return ok
+}
+
+// canCreateInNamespace returns whether the account can create repositories in the namespace.
+func (s *Store) canCreateInNamespace(a *account, namespace string) bool {
+	if strings.EqualFold(a.name, namespace) {
+		return a.isUser()
+	}
+	o, ok := s.orgs[strings.ToLower(namespace)]
+	if !ok {
+		return false
+	}
+	_, isMember := o.members[*a.id]
+	return isMember
+}
+
+// lookupRepo returns the repository at the given path of which the caller is a member.
+func (s *Store) lookupRepo(caller *account, path api.RepoPath) (*repo, error) {
+	r, ok := s.repos[strings.ToLower(path.String())]
+	if !ok {
+		return nil, api.ErrRepoNotFound
+	}
+	if _, isMember := r.members[*caller.id]; !isMember {
+		return nil, api.ErrRepoNotFound
+	}
+	return r, nil
+}
+
+// findDir returns the directory at the given path, regardless of permissions.
+func (s *Store) findDir(caller *account, path api.DirPath) (*dir, error) {
+	r, err := s.lookupRepo(caller, path.GetRepoPath())
+	if err != nil {
+		return nil, err
+	}
+
+	parts := strings.Split(strings.ToLower(path.String()), "/")
+	d := r.rootDir
+	for _, name := range parts[2:] {
+		child, ok := d.dirs[name]
+		if !ok {
+			return nil, api.ErrDirNotFound
+		}
+		d = child
+	}
+	return d, nil
+}
+
+// lookupDir returns the directory at the given path,
+// when the caller has at least the given permission on it.
+func (s *Store) lookupDir(caller *account, path api.DirPath, permission api.Permission) (*dir, error) {
+	d, err := s.findDir(caller, path)
+	if err != nil {
+		return nil, err
+	}
+	if d.permission(caller.id) < api.PermissionRead {
+		return nil, api.ErrDirNotFound
+	}
+	if d.permission(caller.id) < permission {
+		return nil, api.ErrForbidden
+	}
+	return d, nil
+}
+
+// lookupSecret returns the secret at the given path, when the caller
+// has at least the given permission on the directory containing it.
+func (s *Store) lookupSecret(caller *account, path api.SecretPath, permission api.Permission) (*secret, error) {
+	parentPath, err := path.GetParentPath()
+	if err != nil {
+		return nil, err
+	}
+
+	d, err := s.findDir(caller, api.DirPath(parentPath))
+	if err == api.ErrDirNotFound {
+		return nil, api.ErrSecretNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	sec, ok := d.secrets[strings.ToLower(path.GetSecret())]
+	if !ok || d.permission(caller.id) < api.PermissionRead {
+		return nil, api.ErrSecretNotFound
+	}
+	if d.permission(caller.id) < permission {
+		return nil, api.ErrForbidden
+	}
+	return sec, nil
+}
+
+// removeDir removes the directory and all its contents.
+func (s *Store) removeDir(d *dir) {
+	d.walk(-1, func(current *dir) {
+		for _, sec := range current.secrets {
+			s.removeSecret(sec)
+		}
+	})
+	if d.parent != nil {
+		delete(d.parent.dirs, strings.ToLower(d.name))
+	}
+}
+
+// removeSecret removes the secret and all its versions.
+func (s *Store) removeSecret(sec *secret) {
+	sec.deleted = true
+	for _, v := range sec.versions {
+		v.deleted = true
+	}
+	delete(sec.dir.secrets, strings.ToLower(sec.name))
+}
+
+// removeRepo removes the repository with all its contents and service accounts.
+func (s *Store) removeRepo(r *repo) {
+	s.removeDir(r.rootDir)
+	for _, member := range r.members {
+		if member.account.serviceRepo == r {
+			s.removeAccount(member.account)
+		}
+	}
+	r.deleted = true
+	delete(s.repos, strings.ToLower(r.path().String()))
+}
+
+// removeAccount removes the account.
+func (s *Store) removeAccount(a *account) {
+	delete(s.accounts, strings.ToLower(a.name))
+}
+
+// accountExists returns whether the account has not been removed.
+func (s *Store) accountExists(a *account) bool {
+	current, ok := s.accounts[strings.ToLower(a.name)]
+	return ok && current == a
+}
+
+// revokeRepoMember removes the account from the repository and removes all its access rules.
+// All secret keys the account had access to are flagged, so they are no longer used for new
+// versions, and the number of affected keys and versions is returned. On a dry run, only the
+// number of keys and versions that would be affected is returned.
+func (s *Store) revokeRepoMember(r *repo, a *account, dryRun bool) *api.RevokeRepoResponse {
+	resp := &api.RevokeRepoResponse{
+		Namespace: r.repo.Owner,
+		Name:      r.repo.Name,
+		Status:    api.StatusOK,
+	}
+
+	if !dryRun {
+		delete(r.members, *a.id)
+	}
+	r.rootDir.walk(-1, func(d *dir) {
+		if !dryRun {
+			delete(d.rules, *a.id)
+		}
+		for _, sec := range d.secrets {
+			for _, key := range sec.keys {
+				if !key.accounts[*a.id] || key.status == api.StatusFlagged {
+					continue
+				}
+				if !dryRun {
+					key.status = api.StatusFlagged
+				}
+				resp.RevokedSecretKeyCount++
+				for _, v := range sec.versions {
+					if v.key == key {
+						resp.RevokedSecretVersionCount++
+					}
+				}
+			}
+		}
+	})
+
+	return resp
+}
+
+// newSecretKey creates a secret key that the accounts with read access on the directory can use.
+func newSecretKey(d *dir) (*secretKey, error) {
+	key, err := crypto.GenerateSymmetricKey()
+	if err != nil {
+		return nil, err
+	}
+
+	accounts := make(map[uuid.UUID]bool)
+	for _, a := range d.accounts() {
+		accounts[*a.id] = true
+	}
+
+	return &secretKey{
+		id:       uuid.New(),
+		key:      key,
+		status:   api.StatusOK,
+		accounts: accounts,
+	}, nil
+}
+
+// newAccessRule creates an access rule for the account on the directory.
+func newAccessRule(d *dir, a *account, permission api.Permission) *api.AccessRule {
+	createdAt := now()
+	return &api.AccessRule{
+		Account:       a.toAPI(),
+		AccountID:     a.id,
+		DirID:         d.id,
+		RepoID:        d.repo.repo.RepoID,
+		Permission:    permission,
+		CreatedAt:     createdAt,
+		LastChangedAt: createdAt,
+	}
+}
+
+// now returns the current time, as stored by the store.
+func now() time.Time {
+	return time.Now().UTC()
+}
@@ -0,0 +1,393 @@
+package secrethub
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/errio"
+)
+
+// Errors
+var (
+	ErrCannotMoveVersion     = errClient.Code("cannot_move_version").Error("cannot move a single version of a secret, move the secret instead")
+	ErrMoveSameLocation      = errClient.Code("move_same_location").Error("the source and destination of a move are the same")
+	ErrMoveIntoItself        = errClient.Code("move_into_itself").ErrorPref("cannot move directory %s into itself")
+	ErrMoveDestinationExists = errClient.Code("move_destination_exists").ErrorPref("cannot move to %s: a secret or directory already exists at that path")
+	ErrMoveRollbackFailed    = errClient.Code("move_rollback_failed").ErrorPref("%s; undoing the move also failed, remove %s manually: %s")
+)
+
+// MoveOptions configure a move of a secret or directory.
+type MoveOptions struct {
+	// LatestOnly only copies the latest version of every secret instead of all versions.
+	LatestOnly bool
+	// DryRun only computes the changes of the move, without making them.
+	DryRun bool
+}
+
+// MoveResult describes the changes of a move. All paths are absolute.
+type MoveResult struct {
+	// Dirs contains the paths of the directories that are created at the destination, parents first.
+	Dirs []string
+	// Secrets contains the secrets that are copied to the destination.
+	Secrets []MovedSecret
+	// AccessRules contains the access rules of the moved directories that are set on
+	// the directories at the destination, sorted by path and account name.
+	AccessRules []MovedAccessRule
+}
+
+// MovedAccessRule describes an access rule that is set on a directory at the destination.
+type MovedAccessRule struct {
+	// Path is the path of the directory at the destination.
+	Path string
+	// AccountName is the username of the user or the ID of the service the rule applies to.
+	AccountName string
+	// Permission is the permission of the account on the directory.
+	Permission api.Permission
+}
+
+// MovedSecret describes a secret that is copied to its new location.
+type MovedSecret struct {
+	// Src is the path of the secret before the move.
+	Src string
+	// Dst is the path of the secret after the move.
+	Dst string
+	// Versions is the number of versions that are copied. The copied
+	// versions are numbered from 1, so their numbers can differ from the
+	// source when versions of the source have been deleted.
+	Versions int
+}
+
+// Move moves the secret at path src to path dst. All versions, or only the latest version,
+// are copied to dst and encrypted for the accounts that have access to the parent directory
+// of dst. The source is only deleted after everything has been copied. When the move fails,
+// the secret created at dst is removed again.
+func (s secretService) Move(src, dst string, options MoveOptions) (*MoveResult, error) {
+	return s.MoveContext(context.Background(), src, dst, options)
+}
+
+// MoveContext is the same as Move, but uses the given context for all requests.
+func (s secretService) MoveContext(ctx context.Context, src, dst string, options MoveOptions) (*MoveResult, error) {
+	return moveSecret(ctx, clientAdapter{client: s.client}, src, dst, options)
+}
+
+// Move moves the directory at path src, including all of its subdirectories and secrets,
+// to path dst. The access rules on the moved directories are set on the directories at
+// the destination, so the accounts must be members of the destination repository. All
+// versions, or only the latest version, of every secret are copied and encrypted for the
+// accounts that have access to the destination. The source is only deleted after
+// everything has been copied. When the move fails, the directory created at dst is
+// removed again.
+func (s dirService) Move(src, dst string, options MoveOptions) (*MoveResult, error) {
+	return s.MoveContext(context.Background(), src, dst, options)
+}
+
+// MoveContext is the same as Move, but uses the given context for all requests.
+func (s dirService) MoveContext(ctx context.Context, src, dst string, options MoveOptions) (*MoveResult, error) {
+	return moveDir(ctx, clientAdapter{client: s.client}, src, dst, options)
+}
+
+// moveSecret moves the secret at path src to path dst, using the client to copy its
+// versions and delete the source. Nothing is changed when the destination already
+// exists or its parent directory does not exist.
+func moveSecret(ctx context.Context, client Client, src, dst string, options MoveOptions) (*MoveResult, error) {
+	srcPath, err := api.NewSecretPath(src)
+	if err != nil {
+		return nil, errio.Error(err)
+	}
+	if srcPath.HasVersion() {
+		return nil, ErrCannotMoveVersion
+	}
+
+	dstPath, err := api.NewSecretPath(dst)
+	if err != nil {
+		return nil, errio.Error(err)
+	}
+	if dstPath.HasVersion() {
+		return nil, ErrCannotWriteToVersion
+	}
+
+	if strings.EqualFold(srcPath.Value(), dstPath.Value()) {
+		return nil, ErrMoveSameLocation
+	}
+
+	parentPath, err := dstPath.GetParentPath()
+	if err != nil {
+		return nil, errio.Error(err)
+	}
+
+	err = checkMoveDestination(ctx, client, dstPath.Value(), parentPath.String())
+	if err != nil {
+		return nil, err
+	}
+
+	versions, err := readMoveVersions(ctx, client, srcPath.Value(), options)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &MoveResult{
+		Secrets: []MovedSecret{
+			{
+				Src:      srcPath.Value(),
+				Dst:      dstPath.Value(),
+				Versions: len(versions),
+			},
+		},
+	}
+
+	if options.DryRun {
+		return result, nil
+	}
+
+	// The first version only creates the secret when it still does not exist, so that
+	// a secret that is created concurrently at the destination is never rolled back.
+	_, err = client.Secrets().WriteIfLatestContext(ctx, dstPath.Value(), 0, versions[0].Data)
+	if err != nil {
+		return nil, errio.Error(err)
+	}
+
+	rollback := func(err error) error {
+		errRollback := client.Secrets().DeleteContext(ctx, dstPath.Value())
+		if errRollback != nil && errRollback != api.ErrSecretNotFound {
+			return ErrMoveRollbackFailed(err, dstPath.Value(), errRollback)
+		}
+		return err
+	}
+
+	for _, version := range versions[1:] {
+		_, err = client.Secrets().WriteContext(ctx, dstPath.Value(), version.Data)
+		if err != nil {
+			return nil, rollback(errio.Error(err))
+		}
+	}
+
+	err = client.Secrets().DeleteContext(ctx, srcPath.Value())
+	if err != nil {
+		return nil, rollback(errio.Error(err))
+	}
+
+	return result, nil
+}
+
+// moveDir moves the directory at path src to path dst, using the client to create the
+// directories, copy the versions of all secrets and delete the source. Nothing is changed
+// when the destination already exists or its parent directory does not exist.
+func moveDir(ctx context.Context, client Client, src, dst string, options MoveOptions) (*MoveResult, error) {
+	srcPath, err := api.NewDirPath(src)
+	if err != nil {
+		return nil, errio.Error(err)
+	}
+	if srcPath.IsRepoPath() {
+		return nil, api.ErrCannotRemoveRootDir
+	}
+
+	dstPath, err := api.NewDirPath(dst)
+	if err != nil {
+		return nil, errio.Error(err)
+	}
+
+	srcLower := strings.ToLower(srcPath.Value())
+	dstLower := strings.ToLower(dstPath.Value())
+	if srcLower == dstLower {
+		return nil, ErrMoveSameLocation
+	}
+	if strings.HasPrefix(dstLower, srcLower+"/") {
+		return nil, ErrMoveIntoItself(srcPath.Value())
+	}
+	if dstPath.IsRepoPath() {
+		return nil, ErrMoveDestinationExists(dstPath.Value())
+	}
+
+	parentPath, err := dstPath.GetParentPath()
+	if err != nil {
+		return nil, errio.Error(err)
+	}
+
+	err = checkMoveDestination(ctx, client, dstPath.Value(), parentPath.String())
+	if err != nil {
+		return nil, err
+	}
+
+	tree, err := client.Dirs().GetTreeContext(ctx, srcPath.Value(), -1, false)
+	if err != nil {
+		return nil, errio.Error(err)
+	}
+
+	result := &MoveResult{
+		Dirs: []string{dstPath.Value()},
+	}
+	for _, dirPath := range tree.DirPaths() {
+		result.Dirs = append(result.Dirs, dstPath.Value()+"/"+dirPath)
+	}
+
+	result.AccessRules, err = readMoveAccessRules(ctx, client, tree, srcPath, dstPath)
+	if err != nil {
+		return nil, err
+	}
+
+	versions := make(map[string][]*api.SecretVersion)
+	for _, secretPath := range tree.SecretPaths() {
+		secretSrc := srcPath.Value() + "/" + secretPath
+		secretVersions, err := readMoveVersions(ctx, client, secretSrc, options)
+		if err != nil {
+			return nil, err
+		}
+
+		versions[secretSrc] = secretVersions
+		result.Secrets = append(result.Secrets, MovedSecret{
+			Src:      secretSrc,
+			Dst:      dstPath.Value() + "/" + secretPath,
+			Versions: len(secretVersions),
+		})
+	}
+
+	if options.DryRun {
+		return result, nil
+	}
+
+	// The destination is only removed on failure once this move has created it, so that
+	// a directory that is created concurrently at the destination is never rolled back.
+	_, err = client.Dirs().CreateContext(ctx, dstPath.Value())
+	if err != nil {
+		return nil, errio.Error(err)
+	}
+
+	rollback := func(err error) error {
+		errRollback := client.Dirs().DeleteContext(ctx, dstPath.Value())
+		if errRollback != nil && errRollback != api.ErrDirNotFound {
+			return ErrMoveRollbackFailed(err, dstPath.Value(), errRollback)
+		}
+		return err
+	}
+
+	for _, dir := range result.Dirs[1:] {
+		_, err = client.Dirs().CreateContext(ctx, dir)
+		if err != nil {
+			return nil, rollback(errio.Error(err))
+		}
+	}
+
+	// The access rules are set before the secrets are written, so that the secrets
+	// are also encrypted for the accounts that only have access through these rules.
+	for _, rule := range result.AccessRules {
+		_, err = client.AccessRules().SetContext(ctx, rule.Path, rule.Permission, rule.AccountName)
+		if err != nil {
+			return nil, rollback(errio.Error(err))
+		}
+	}
+
+	for _, secret := range result.Secrets {
+		for _, version := range versions[secret.Src] {
+			_, err = client.Secrets().WriteContext(ctx, secret.Dst, version.Data)
+			if err != nil {
+				return nil, rollback(errio.Error(err))
+			}
+		}
+	}
+
+	err = client.Dirs().DeleteContext(ctx, srcPath.Value())
+	if err != nil {
+		return nil, rollback(errio.Error(err))
+	}
+
+	return result, nil
+}
+
+// readMoveAccessRules returns the access rules on the directories in the tree of the directory
+// at srcPath, with the paths of the corresponding directories at dstPath.
+func readMoveAccessRules(ctx context.Context, client Client, tree *api.Tree, srcPath, dstPath api.DirPath) ([]MovedAccessRule, error) {
+	rules, err := client.AccessRules().ListContext(ctx, srcPath.Value(), -1, false)
+	if err != nil {
+		return nil, errio.Error(err)
+	}
+
+	rootPath, err := tree.AbsDirPath(tree.RootDir.DirID)
+	if err != nil {
+		return nil, errio.Error(err)
+	}
+
+	var moved []MovedAccessRule
+	for _, rule := range rules {
+		dirPath, err := tree.AbsDirPath(rule.DirID)
+		if err == api.ErrDirNotFound {
+			// The rule is on a directory that has been created after the tree was retrieved.
+			continue
+		} else if err != nil {
+			return nil, errio.Error(err)
+		}
+
+		moved = append(moved, MovedAccessRule{
+			Path:        dstPath.Value() + strings.TrimPrefix(dirPath.Value(), rootPath.Value()),
+			AccountName: rule.Account.Name.String(),
+			Permission:  rule.Permission,
+		})
+	}
+
+	sort.Slice(moved, func(i, j int) bool {
+		if moved[i].Path != moved[j].Path {
+			return moved[i].Path < moved[j].Path
+		}
+		return moved[i].AccountName < moved[j].AccountName
+	})
+	return moved, nil
+}
+
+// checkMoveDestination returns an error when a secret or directory exists
+// at the destination path or when its parent directory does not exist.
+func checkMoveDestination(ctx context.Context, client Client, path string, parentPath string) error {
+	exists, err := client.Secrets().ExistsContext(ctx, path)
+	if err != nil {
+		return errio.Error(err)
+	}
+	if exists {
+		return ErrMoveDestinationExists(path)
+	}
+
+	_, err = client.Dirs().GetTreeContext(ctx, path, 0, false)
+	if err == nil {
+		return ErrMoveDestinationExists(path)
+	} else if err != api.ErrDirNotFound {
+		return errio.Error(err)
+	}
+
+	_, err = client.Dirs().GetTreeContext(ctx, parentPath, 0, false)
+	if err != nil {
+		return errio.Error(err)
+	}
+	return nil
+}
+
+// readMoveVersions returns the versions of the secret at the given path that are copied
+// by a move, oldest first. The versions only include their data when the move is not a dry run.
+func readMoveVersions(ctx context.Context, client Client, path string, options MoveOptions) ([]*api.SecretVersion, error) {
+	var versions []*api.SecretVersion
+	var err error
+	switch {
+	case options.LatestOnly && options.DryRun:
+		var version *api.SecretVersion
+		version, err = client.Secrets().Versions().GetWithoutDataContext(ctx, path)
+		versions = []*api.SecretVersion{version}
+	case options.LatestOnly:
+		var version *api.SecretVersion
+		version, err = client.Secrets().Versions().GetWithDataContext(ctx, path)
+		versions = []*api.SecretVersion{version}
+	case options.DryRun:
+		versions, err = client.Secrets().Versions().ListWithoutDataContext(ctx, path)
+	default:
+		versions, err = client.Secrets().Versions().ListWithDataContext(ctx, path)
+	}
+	if err != nil {
+		return nil, errio.Error(err)
+	}
+
+	if len(versions) == 0 {
+		return nil, api.ErrSecretVersionNotFound
+	}
+
+	sort.Slice(versions, func(i, j int) bool {
+		return versions[i].Version < versions[j].Version
+	})
+	return versions, nil
+}
@@ -1,6 +1,8 @@
 package secrethub
 
 import (
+	"context"
+
 	"github.com/secrethub/secrethub-go/internals/api"
 	"github.com/secrethub/secrethub-go/internals/errio"
 )
@@ -9,28 +11,41 @@ import (
 type OrgService interface {
 	// Create creates an organization.
 	Create(name string, description string) (*api.Org, error)
+	// CreateContext is the same as Create, but uses the given context for all requests.
+	CreateContext(ctx context.Context, name string, description string) (*api.Org, error)
 	// Delete removes an organization.
 	Delete(name string) error
+	// DeleteContext is the same as Delete, but uses the given context for all requests.
+	DeleteContext(ctx context.Context, name string) error
 	// Get retrieves an organization.
 	Get(name string) (*api.Org, error)
+	// GetContext is the same as Get, but uses the given context for all requests.
+	GetContext(ctx context.Context, name string) (*api.Org, error)
 	// Members returns an OrgMemberService.
 	Members() OrgMemberService
 	// ListMine returns the organizations of the current user.
 	ListMine() ([]*api.Org, error)
+	// ListMineContext is the same as ListMine, but uses the given context for all requests.
+	ListMineContext(ctx context.Context) ([]*api.Org, error)
 }
 
-func newOrgService(client client) OrgService {
+func newOrgService(client *client) OrgService {
 	return orgService{
 		client: client,
 	}
 }
 
 type orgService struct {
-	client client
+	client *client
 }
 
 // Create creates an organization and adds the current account as an admin member.
 func (s orgService) Create(name string, description string) (*api.Org, error) {
+	return s.CreateContext(context.Background(), name, description)
+}
+
+// CreateContext is the same as Create, but uses the given context for all requests.
+func (s orgService) CreateContext(ctx context.Context, name string, description string) (*api.Org, error) {
 	in := &api.CreateOrgRequest{
 		Name:        name,
 		Description: description,
@@ -41,27 +56,37 @@ func (s orgService) Create(name string, description string) (*api.Org, error) {
 		return nil, errio.Error(err)
 	}
 
-	return s.client.httpClient.CreateOrg(in)
+	return s.client.httpClient.CreateOrg(ctx, in)
 }
 
 // Delete permanently deletes an organization and all of its resources.
 func (s orgService) Delete(name string) error {
+	return s.DeleteContext(context.Background(), name)
+}
+
+// DeleteContext is the same as Delete, but uses the given context for all requests.
+func (s orgService) DeleteContext(ctx context.Context, name string) error {
 	err := api.ValidateOrgName(name)
 	if err != nil {
 		return errio.Error(err)
 	}
 
-	return s.client.httpClient.DeleteOrg(name)
+	return s.client.httpClient.DeleteOrg(ctx, name)
 }
 
 // Get retrieves an organization.
 func (s orgService) Get(name string) (*api.Org, error) {
+	return s.GetContext(context.Background(), name)
+}
+
+// GetContext is the same as Get, but uses the given context for all requests.
+func (s orgService) GetContext(ctx context.Context, name string) (*api.Org, error) {
 	err := api.ValidateOrgName(name)
 	if err != nil {
 		return nil, errio.Error(err)
 	}
 
-	return s.client.httpClient.GetOrg(name)
+	return s.client.httpClient.GetOrg(ctx, name)
 }
 
 // Members returns an OrgMemberService.
@@ -71,5 +96,10 @@ func (s orgService) Members() OrgMemberService {
 
 // ListMine returns the organizations of the current user.
 func (s orgService) ListMine() ([]*api.Org, error) {
-	return s.client.httpClient.ListMyOrgs()
+	return s.ListMineContext(context.Background())
+}
+
+// ListMineContext is the same as ListMine, but uses the given context for all requests.
+func (s orgService) ListMineContext(ctx context.Context) ([]*api.Org, error) {
+	return s.client.httpClient.ListMyOrgs(ctx)
 }
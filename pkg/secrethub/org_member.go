@@ -1,6 +1,8 @@
 package secrethub
 
 import (
+	"context"
+
 	"github.com/secrethub/secrethub-go/internals/api"
 	"github.com/secrethub/secrethub-go/internals/errio"
 )
@@ -9,28 +11,43 @@ import (
 type OrgMemberService interface {
 	// Get retrieves a users organization membership details.
 	Get(org string, username string) (*api.OrgMember, error)
+	// GetContext is the same as Get, but uses the given context for all requests.
+	GetContext(ctx context.Context, org string, username string) (*api.OrgMember, error)
 	// Invite invites a user to an organization.
 	Invite(org string, username string, role string) (*api.OrgMember, error)
+	// InviteContext is the same as Invite, but uses the given context for all requests.
+	InviteContext(ctx context.Context, org string, username string, role string) (*api.OrgMember, error)
 	// List retrieves all members of the given organization.
 	List(org string) ([]*api.OrgMember, error)
+	// ListContext is the same as List, but uses the given context for all requests.
+	ListContext(ctx context.Context, org string) ([]*api.OrgMember, error)
 	// Revoke removes the given user from the organization.
 	Revoke(org string, username string, opts *api.RevokeOpts) (*api.RevokeOrgResponse, error)
+	// RevokeContext is the same as Revoke, but uses the given context for all requests.
+	RevokeContext(ctx context.Context, org string, username string, opts *api.RevokeOpts) (*api.RevokeOrgResponse, error)
 	// Update updates the role of a member of the organization.
 	Update(org string, username string, role string) (*api.OrgMember, error)
+	// UpdateContext is the same as Update, but uses the given context for all requests.
+	UpdateContext(ctx context.Context, org string, username string, role string) (*api.OrgMember, error)
 }
 
-func newOrgMemberService(client client) OrgMemberService {
+func newOrgMemberService(client *client) OrgMemberService {
 	return orgMemberService{
 		client: client,
 	}
 }
 
 type orgMemberService struct {
-	client client
+	client *client
 }
 
 // Get retrieves a users organization membership details.
 func (s orgMemberService) Get(org string, username string) (*api.OrgMember, error) {
+	return s.GetContext(context.Background(), org, username)
+}
+
+// GetContext is the same as Get, but uses the given context for all requests.
+func (s orgMemberService) GetContext(ctx context.Context, org string, username string) (*api.OrgMember, error) {
 	err := api.ValidateOrgName(org)
 	if err != nil {
 		return nil, errio.Error(err)
@@ -41,11 +58,16 @@ func (s orgMemberService) Get(org string, username string) (*api.OrgMember, erro
 		return nil, errio.Error(err)
 	}
 
-	return s.client.httpClient.GetOrgMember(org, username)
+	return s.client.httpClient.GetOrgMember(ctx, org, username)
 }
 
 // Invite invites a user to an organization.
 func (s orgMemberService) Invite(org string, username string, role string) (*api.OrgMember, error) {
+	return s.InviteContext(context.Background(), org, username, role)
+}
+
+// InviteContext is the same as Invite, but uses the given context for all requests.
+func (s orgMemberService) InviteContext(ctx context.Context, org string, username string, role string) (*api.OrgMember, error) {
 	err := api.ValidateOrgName(org)
 	if err != nil {
 		return nil, errio.Error(err)
@@ -61,21 +83,31 @@ func (s orgMemberService) Invite(org string, username string, role string) (*api
 		return nil, errio.Error(err)
 	}
 
-	return s.client.httpClient.CreateOrgMember(org, in)
+	return s.client.httpClient.CreateOrgMember(ctx, org, in)
 }
 
 // List retrieves all members of the given organization.
 func (s orgMemberService) List(org string) ([]*api.OrgMember, error) {
+	return s.ListContext(context.Background(), org)
+}
+
+// ListContext is the same as List, but uses the given context for all requests.
+func (s orgMemberService) ListContext(ctx context.Context, org string) ([]*api.OrgMember, error) {
 	err := api.ValidateOrgName(org)
 	if err != nil {
 		return nil, errio.Error(err)
 	}
 
-	return s.client.httpClient.ListOrgMembers(org)
+	return s.client.httpClient.ListOrgMembers(ctx, org)
 }
 
 // Revoke removes the given user from the organization.
 func (s orgMemberService) Revoke(org string, username string, opts *api.RevokeOpts) (*api.RevokeOrgResponse, error) {
+	return s.RevokeContext(context.Background(), org, username, opts)
+}
+
+// RevokeContext is the same as Revoke, but uses the given context for all requests.
+func (s orgMemberService) RevokeContext(ctx context.Context, org string, username string, opts *api.RevokeOpts) (*api.RevokeOrgResponse, error) {
 	err := api.ValidateOrgName(org)
 	if err != nil {
 		return nil, errio.Error(err)
@@ -86,11 +118,16 @@ func (s orgMemberService) Revoke(org string, username string, opts *api.RevokeOp
 		return nil, errio.Error(err)
 	}
 
-	return s.client.httpClient.RevokeOrgMember(org, username, opts)
+	return s.client.httpClient.RevokeOrgMember(ctx, org, username, opts)
 }
 
 // Update updates the role of a member of the organization.
 func (s orgMemberService) Update(org string, username string, role string) (*api.OrgMember, error) {
+	return s.UpdateContext(context.Background(), org, username, role)
+}
+
+// UpdateContext is the same as Update, but uses the given context for all requests.
+func (s orgMemberService) UpdateContext(ctx context.Context, org string, username string, role string) (*api.OrgMember, error) {
 	err := api.ValidateOrgName(org)
 	if err != nil {
 		return nil, errio.Error(err)
@@ -110,5 +147,5 @@ func (s orgMemberService) Update(org string, username string, role string) (*api
 		return nil, errio.Error(err)
 	}
 
-	return s.client.httpClient.UpdateOrgMember(org, username, in)
+	return s.client.httpClient.UpdateOrgMember(ctx, org, username, in)
 }
@@ -0,0 +1,195 @@
+package secrethub
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/errio"
+)
+
+// Errors
+var (
+	ErrEmptyRetentionPolicy   = errClient.Code("empty_retention_policy").Error("a retention policy must keep at least the last versions, the versions newer than a duration or the latest version")
+	ErrInvalidRetentionPolicy = errClient.Code("invalid_retention_policy").Error("the number of versions and the duration of a retention policy cannot be negative")
+)
+
+// RetentionPolicy describes which versions of a secret are kept when pruning.
+// A version is kept when it matches at least one of the rules of the policy and
+// all other versions are deleted. Rules that have their zero value are not used.
+//
+// The latest version of a secret is always kept when all other versions are
+// deleted, because a secret cannot exist without versions.
+type RetentionPolicy struct {
+	// KeepLast is the number of most recent versions that are kept.
+	KeepLast int
+	// KeepNewerThan keeps all versions that have been created less than this duration ago.
+	KeepNewerThan time.Duration
+	// KeepLatest keeps the latest version.
+	KeepLatest bool
+	// DryRun only reports the versions that would be deleted, without deleting them.
+	DryRun bool
+}
+
+// Validate returns an error when the policy has no rules or a negative rule.
+func (p RetentionPolicy) Validate() error {
+	if p.KeepLast < 0 || p.KeepNewerThan < 0 {
+		return ErrInvalidRetentionPolicy
+	}
+	if p.KeepLast == 0 && p.KeepNewerThan == 0 && !p.KeepLatest {
+		return ErrEmptyRetentionPolicy
+	}
+	return nil
+}
+
+// prune returns the version numbers of the given versions that are kept and deleted
+// according to the policy at the given time, both in ascending order.
+func (p RetentionPolicy) prune(versions []*api.SecretVersion, now time.Time) ([]int, []int) {
+	sorted := append([]*api.SecretVersion{}, versions...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Version < sorted[j].Version
+	})
+
+	var kept, deleted []int
+	for i, version := range sorted {
+		fromLatest := len(sorted) - 1 - i
+		keep := fromLatest < p.KeepLast ||
+			(p.KeepNewerThan > 0 && now.Sub(version.CreatedAt) < p.KeepNewerThan) ||
+			(p.KeepLatest && fromLatest == 0)
+
+		if keep {
+			kept = append(kept, version.Version)
+		} else {
+			deleted = append(deleted, version.Version)
+		}
+	}
+
+	if len(kept) == 0 && len(deleted) > 0 {
+		kept = deleted[len(deleted)-1:]
+		deleted = deleted[:len(deleted)-1]
+	}
+	return kept, deleted
+}
+
+// PruneReport describes the versions that have been deleted by pruning.
+type PruneReport struct {
+	// DryRun is true when the versions have not actually been deleted.
+	DryRun bool
+	// Secrets contains the pruned secrets, in the order in which they were pruned.
+	Secrets []PrunedSecret
+}
+
+// PrunedSecret describes the versions of a secret that have been kept and deleted.
+type PrunedSecret struct {
+	// Path is the path of the secret.
+	Path string
+	// Kept contains the numbers of the versions that have been kept.
+	Kept []int
+	// Deleted contains the numbers of the versions that have been deleted.
+	Deleted []int
+}
+
+// Deleted returns the total number of deleted versions.
+func (r *PruneReport) Deleted() int {
+	deleted := 0
+	for _, secret := range r.Secrets {
+		deleted += len(secret.Deleted)
+	}
+	return deleted
+}
+
+// PruneVersions deletes the versions of the secret at the given path that are not kept according
+// to the policy. When the path is a directory, the versions of all secrets in the directory and
+// its subdirectories are pruned.
+func (s secretService) PruneVersions(path string, policy RetentionPolicy) (*PruneReport, error) {
+	return s.PruneVersionsContext(context.Background(), path, policy)
+}
+
+// PruneVersionsContext is the same as PruneVersions, but uses the given context for all requests.
+func (s secretService) PruneVersionsContext(ctx context.Context, path string, policy RetentionPolicy) (*PruneReport, error) {
+	return pruneVersions(ctx, clientAdapter{client: s.client}, path, policy)
+}
+
+// pruneVersions deletes the versions of the secret or of all secrets in the directory at the given
+// path that are not kept according to the policy, using the client to list and delete the versions.
+// Versions are deleted oldest first. When deleting a version fails, the returned report describes the
+// versions that have been deleted before the failure.
+func pruneVersions(ctx context.Context, client Client, path string, policy RetentionPolicy) (*PruneReport, error) {
+	err := policy.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	dirPath, err := api.NewDirPath(path)
+	if err != nil {
+		return nil, errio.Error(err)
+	}
+
+	isSecret := false
+	if !dirPath.IsRepoPath() {
+		isSecret, err = client.Secrets().ExistsContext(ctx, dirPath.Value())
+		if err != nil {
+			return nil, errio.Error(err)
+		}
+	}
+
+	var paths []string
+	if isSecret {
+		paths = []string{dirPath.Value()}
+	} else {
+		tree, err := client.Dirs().GetTreeContext(ctx, dirPath.Value(), -1, false)
+		if err != nil {
+			return nil, errio.Error(err)
+		}
+
+		for _, relativePath := range tree.SecretPaths() {
+			paths = append(paths, dirPath.Value()+"/"+relativePath)
+		}
+	}
+
+	now := time.Now()
+	report := &PruneReport{
+		DryRun: policy.DryRun,
+	}
+	for _, secretPath := range paths {
+		versions, err := client.Secrets().Versions().ListWithoutDataContext(ctx, secretPath)
+		if err != nil {
+			return report, errio.Error(err)
+		}
+
+		kept, deleted := policy.prune(versions, now)
+		pruned := PrunedSecret{
+			Path: secretPath,
+			Kept: kept,
+		}
+
+		if policy.DryRun {
+			pruned.Deleted = deleted
+			report.Secrets = append(report.Secrets, pruned)
+			continue
+		}
+
+		for _, version := range deleted {
+			versionPath, err := api.SecretPath(secretPath).AddVersion(version)
+			if err != nil {
+				return report, errio.Error(err)
+			}
+
+			err = client.Secrets().Versions().DeleteContext(ctx, versionPath.Value())
+			if err == api.ErrCannotDeleteLastSecretVersion {
+				// The other versions have been deleted in the meantime.
+				pruned.Kept = append(pruned.Kept, version)
+				sort.Ints(pruned.Kept)
+				continue
+			} else if err != nil {
+				report.Secrets = append(report.Secrets, pruned)
+				return report, errio.Error(err)
+			}
+			pruned.Deleted = append(pruned.Deleted, version)
+		}
+		report.Secrets = append(report.Secrets, pruned)
+	}
+
+	return report, nil
+}
@@ -0,0 +1,103 @@
+package secrethub
+
+import (
+	"testing"
+	"time"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/assert"
+)
+
+func TestRetentionPolicy_Validate(t *testing.T) {
+	cases := map[string]struct {
+		policy RetentionPolicy
+		err    error
+	}{
+		"keep last": {
+			policy: RetentionPolicy{KeepLast: 1},
+		},
+		"keep newer than": {
+			policy: RetentionPolicy{KeepNewerThan: time.Hour},
+		},
+		"keep latest": {
+			policy: RetentionPolicy{KeepLatest: true},
+		},
+		"empty": {
+			policy: RetentionPolicy{DryRun: true},
+			err:    ErrEmptyRetentionPolicy,
+		},
+		"negative": {
+			policy: RetentionPolicy{KeepLast: -1, KeepLatest: true},
+			err:    ErrInvalidRetentionPolicy,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			// Act
+			err := tc.policy.Validate()
+
+			// Assert
+			assert.Equal(t, err, tc.err)
+		})
+	}
+}
+
+func TestRetentionPolicy_prune(t *testing.T) {
+	now := time.Date(2019, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	// Versions 1 to 5, created 5 to 1 days ago. Version 3 has been deleted.
+	versions := []*api.SecretVersion{
+		{Version: 5, CreatedAt: now.Add(-24 * time.Hour)},
+		{Version: 1, CreatedAt: now.Add(-5 * 24 * time.Hour)},
+		{Version: 2, CreatedAt: now.Add(-4 * 24 * time.Hour)},
+		{Version: 4, CreatedAt: now.Add(-2 * 24 * time.Hour)},
+	}
+
+	cases := map[string]struct {
+		policy          RetentionPolicy
+		expectedKept    []int
+		expectedDeleted []int
+	}{
+		"keep last": {
+			policy:          RetentionPolicy{KeepLast: 2},
+			expectedKept:    []int{4, 5},
+			expectedDeleted: []int{1, 2},
+		},
+		"keep last more than versions": {
+			policy:       RetentionPolicy{KeepLast: 10},
+			expectedKept: []int{1, 2, 4, 5},
+		},
+		"keep newer than": {
+			policy:          RetentionPolicy{KeepNewerThan: 3 * 24 * time.Hour},
+			expectedKept:    []int{4, 5},
+			expectedDeleted: []int{1, 2},
+		},
+		"keep last or newer than": {
+			policy:          RetentionPolicy{KeepLast: 1, KeepNewerThan: 4*24*time.Hour + time.Minute},
+			expectedKept:    []int{2, 4, 5},
+			expectedDeleted: []int{1},
+		},
+		"keep latest": {
+			policy:          RetentionPolicy{KeepLatest: true},
+			expectedKept:    []int{5},
+			expectedDeleted: []int{1, 2, 4},
+		},
+		"nothing matches": {
+			policy:          RetentionPolicy{KeepNewerThan: time.Hour},
+			expectedKept:    []int{5},
+			expectedDeleted: []int{1, 2, 4},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			// Act
+			kept, deleted := tc.policy.prune(versions, now)
+
+			// Assert
+			assert.Equal(t, kept, tc.expectedKept)
+			assert.Equal(t, deleted, tc.expectedDeleted)
+		})
+	}
+}
@@ -1,105 +1,164 @@
 package secrethub
 
 import (
+	"context"
+	"io"
+
 	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/api/uuid"
 	"github.com/secrethub/secrethub-go/internals/crypto"
 	"github.com/secrethub/secrethub-go/internals/errio"
 )
 
 // RepoService handles operations on repositories from SecretHub.
 type RepoService interface {
+	// Backup writes all versions of all secrets in the repository to w, encrypted with a key derived from the passphrase.
+	Backup(path string, w io.Writer, passphrase []byte) error
+	// BackupContext is the same as Backup, but uses the given context for all requests.
+	BackupContext(ctx context.Context, path string, w io.Writer, passphrase []byte) error
 	// Create creates a new repo for the given owner and name.
 	Create(path string) (*api.Repo, error)
+	// CreateContext is the same as Create, but uses the given context for all requests.
+	CreateContext(ctx context.Context, path string) (*api.Repo, error)
 	// Delete removes the repo with the given path.
 	Delete(path string) error
+	// DeleteContext is the same as Delete, but uses the given context for all requests.
+	DeleteContext(ctx context.Context, path string) error
+	// Events returns an iterator that retrieves the audit events for a given repo
+	// one page at a time, only returning the events that match the filter.
+	Events(path string, filter AuditFilter) AuditEventIterator
+	// EventsContext is the same as Events, but uses the given context for all requests.
+	EventsContext(ctx context.Context, path string, filter AuditFilter) AuditEventIterator
 	// Get retrieves the repo with the given path.
 	Get(path string) (*api.Repo, error)
+	// GetContext is the same as Get, but uses the given context for all requests.
+	GetContext(ctx context.Context, path string) (*api.Repo, error)
 	// List retrieves all repositories in the given namespace.
 	List(namespace string) ([]*api.Repo, error)
+	// ListContext is the same as List, but uses the given context for all requests.
+	ListContext(ctx context.Context, namespace string) ([]*api.Repo, error)
 	// ListAccounts lists the accounts in the repository.
 	ListAccounts(path string) ([]*api.Account, error)
+	// ListAccountsContext is the same as ListAccounts, but uses the given context for all requests.
+	ListAccountsContext(ctx context.Context, path string) ([]*api.Account, error)
 	// ListEvents retrieves all audit events for a given repo.
 	ListEvents(path string, subjectTypes api.AuditSubjectTypeList) ([]*api.Audit, error)
+	// ListEventsContext is the same as ListEvents, but uses the given context for all requests.
+	ListEventsContext(ctx context.Context, path string, subjectTypes api.AuditSubjectTypeList) ([]*api.Audit, error)
 	// ListMine retrieves all repositories of the current user.
 	ListMine() ([]*api.Repo, error)
+	// ListMineContext is the same as ListMine, but uses the given context for all requests.
+	ListMineContext(ctx context.Context) ([]*api.Repo, error)
+	// Restore recreates the directories and secrets of an encrypted backup in the repository, creating it when it does not exist.
+	Restore(path string, r io.Reader, passphrase []byte) (*RestoreResult, error)
+	// RestoreContext is the same as Restore, but uses the given context for all requests.
+	RestoreContext(ctx context.Context, path string, r io.Reader, passphrase []byte) (*RestoreResult, error)
 	// Users returns a RepoUserService that handles operations on users of a repository.
 	Users() RepoUserService
 	// Services returns a RepoServiceService that handles operations on services of a repository.
 	Services() RepoServiceService
 }
 
-func newRepoService(client client) RepoService {
+func newRepoService(client *client) RepoService {
 	return repoService{
 		client: client,
 	}
 }
 
 type repoService struct {
-	client client
+	client *client
 }
 
 // Delete removes the repo with the given path.
 func (s repoService) Delete(path string) error {
+	return s.DeleteContext(context.Background(), path)
+}
+
+// DeleteContext is the same as Delete, but uses the given context for all requests.
+func (s repoService) DeleteContext(ctx context.Context, path string) error {
 	repoPath, err := api.NewRepoPath(path)
 	if err != nil {
 		return errio.Error(err)
 	}
 
-	err = s.client.httpClient.DeleteRepo(repoPath.GetNamespaceAndRepoName())
+	err = s.client.httpClient.DeleteRepo(ctx, repoPath.GetNamespace(), repoPath.GetRepo())
 	if err != nil {
 		return errio.Error(err)
 	}
 
+	s.client.repoIndexKeysMutex.Lock()
 	delete(s.client.repoIndexKeys, repoPath)
+	delete(s.client.repoIndexKeyFetches, repoPath)
+	s.client.repoIndexKeysMutex.Unlock()
 
 	return nil
 }
 
 // Get retrieves the repo with the given path.
 func (s repoService) Get(path string) (*api.Repo, error) {
+	return s.GetContext(context.Background(), path)
+}
+
+// GetContext is the same as Get, but uses the given context for all requests.
+func (s repoService) GetContext(ctx context.Context, path string) (*api.Repo, error) {
 	repoPath, err := api.NewRepoPath(path)
 	if err != nil {
 		return nil, errio.Error(err)
 	}
 
-	return s.client.httpClient.GetRepo(repoPath.GetNamespaceAndRepoName())
+	return s.client.httpClient.GetRepo(ctx, repoPath.GetNamespace(), repoPath.GetRepo())
 }
 
 // List retrieves all repositories in the given namespace.
 func (s repoService) List(namespace string) ([]*api.Repo, error) {
+	return s.ListContext(context.Background(), namespace)
+}
+
+// ListContext is the same as List, but uses the given context for all requests.
+func (s repoService) ListContext(ctx context.Context, namespace string) ([]*api.Repo, error) {
 	err := api.ValidateNamespace(namespace)
 	if err != nil {
 		return nil, errio.Error(err)
 	}
 
-	return s.client.httpClient.ListRepos(namespace)
+	return s.client.httpClient.ListRepos(ctx, namespace)
 }
 
 // ListAccounts lists the accounts in the repository.
 func (s repoService) ListAccounts(path string) ([]*api.Account, error) {
+	return s.ListAccountsContext(context.Background(), path)
+}
+
+// ListAccountsContext is the same as ListAccounts, but uses the given context for all requests.
+func (s repoService) ListAccountsContext(ctx context.Context, path string) ([]*api.Account, error) {
 	repoPath, err := api.NewRepoPath(path)
 	if err != nil {
 		return nil, errio.Error(err)
 	}
 
-	return s.client.httpClient.ListRepoAccounts(repoPath.GetNamespaceAndRepoName())
+	return s.client.httpClient.ListRepoAccounts(ctx, repoPath.GetNamespace(), repoPath.GetRepo())
 }
 
 // ListEvents retrieves all audit events for a given repo.
 // If subjectTypes is left empty, the server's default is used.
 func (s repoService) ListEvents(path string, subjectTypes api.AuditSubjectTypeList) ([]*api.Audit, error) {
+	return s.ListEventsContext(context.Background(), path, subjectTypes)
+}
+
+// ListEventsContext is the same as ListEvents, but uses the given context for all requests.
+func (s repoService) ListEventsContext(ctx context.Context, path string, subjectTypes api.AuditSubjectTypeList) ([]*api.Audit, error) {
 	repoPath, err := api.NewRepoPath(path)
 	if err != nil {
 		return nil, errio.Error(err)
 	}
 
 	namespace, repoName := repoPath.GetNamespaceAndRepoName()
-	events, err := s.client.httpClient.AuditRepo(namespace, repoName, subjectTypes)
+	events, err := s.client.httpClient.AuditRepo(ctx, namespace, repoName, subjectTypes)
 	if err != nil {
 		return nil, errio.Error(err)
 	}
 
-	err = s.client.decryptAuditEvents(events...)
+	err = s.client.decryptAuditEvents(ctx, nil, events...)
 	if err != nil {
 		return nil, errio.Error(err)
 	}
@@ -107,24 +166,61 @@ func (s repoService) ListEvents(path string, subjectTypes api.AuditSubjectTypeLi
 	return events, nil
 }
 
+// Events returns an iterator that retrieves the audit events for a given repo one page
+// at a time, only returning the events that match the filter. The subjects of the events
+// are decrypted when they are returned by the iterator.
+func (s repoService) Events(path string, filter AuditFilter) AuditEventIterator {
+	return s.EventsContext(context.Background(), path, filter)
+}
+
+// EventsContext is the same as Events, but uses the given context for all requests.
+func (s repoService) EventsContext(ctx context.Context, path string, filter AuditFilter) AuditEventIterator {
+	repoPath, err := api.NewRepoPath(path)
+	if err != nil {
+		return errAuditEventIterator{err: errio.Error(err)}
+	}
+
+	namespace, repoName := repoPath.GetNamespaceAndRepoName()
+	fetch := func(ctx context.Context, subjectTypes api.AuditSubjectTypeList, startingAfter *uuid.UUID, perPage int) ([]*api.Audit, error) {
+		return s.client.httpClient.AuditRepoPage(ctx, namespace, repoName, subjectTypes, startingAfter, perPage)
+	}
+
+	var paths *auditPathResolver
+	if filter.ResolvePaths {
+		paths = newAuditPathResolver(s.client, repoPath)
+	}
+
+	return newAuditEventIterator(ctx, s.client, paths, fetch, filter)
+}
+
 // ListMine retrieves all repositories of the current user.
 func (s repoService) ListMine() ([]*api.Repo, error) {
-	return s.client.httpClient.ListMyRepos()
+	return s.ListMineContext(context.Background())
+}
+
+// ListMineContext is the same as ListMine, but uses the given context for all requests.
+func (s repoService) ListMineContext(ctx context.Context) ([]*api.Repo, error) {
+	return s.client.httpClient.ListMyRepos(ctx)
 }
 
 // Create creates a new repo for the given owner and name.
 func (s repoService) Create(path string) (*api.Repo, error) {
+	return s.CreateContext(context.Background(), path)
+}
+
+// CreateContext is the same as Create, but uses the given context for all requests.
+func (s repoService) CreateContext(ctx context.Context, path string) (*api.Repo, error) {
 	repoPath, err := api.NewRepoPath(path)
 	if err != nil {
 		return nil, errio.Error(err)
 	}
 
-	account, err := s.client.getMyAccount()
+	account, err := s.client.getMyAccount(ctx)
 	if err != nil {
 		return nil, errio.Error(err)
 	}
 
-	accountKey, err := s.client.getAccountKey()
+	accountKey, err := s.client.getAccountKey(ctx)
 	if err != nil {
 		return nil, errio.Error(err)
 	}
@@ -184,7 +280,7 @@ func (s repoService) Create(path string) (*api.Repo, error) {
 		return nil, errio.Error(err)
 	}
 
-	repo, err := s.client.httpClient.CreateRepo(repoPath.GetNamespace(), in)
+	repo, err := s.client.httpClient.CreateRepo(ctx, repoPath.GetNamespace(), in)
 	if err != nil {
 		return nil, errio.Error(err)
 	}
@@ -203,13 +299,13 @@ func (s repoService) Services() RepoServiceService {
 }
 
 // Creates a new RepoMemberRequests for a given account.
-func (c *client) createRepoMemberRequest(repoPath api.RepoPath, accountPublicKey []byte) (*api.CreateRepoMemberRequest, error) {
-	repoKey, err := c.httpClient.GetRepoKeys(repoPath.GetNamespaceAndRepoName())
+func (c *client) createRepoMemberRequest(ctx context.Context, repoPath api.RepoPath, accountPublicKey []byte) (*api.CreateRepoMemberRequest, error) {
+	repoKey, err := c.httpClient.GetRepoKeys(ctx, repoPath.GetNamespace(), repoPath.GetRepo())
 	if err != nil {
 		return nil, errio.Error(err)
 	}
 
-	accountKey, err := c.getAccountKey()
+	accountKey, err := c.getAccountKey(ctx)
 	if err != nil {
 		return nil, errio.Error(err)
 	}
@@ -235,20 +331,68 @@ func (c *client) createRepoMemberRequest(repoPath api.RepoPath, accountPublicKey
 	}, nil
 }
 
+// repoIndexKeyFetch is a fetch of a repo index key that is in progress.
+// Its key and err are set before done is closed.
+type repoIndexKeyFetch struct {
+	done chan struct{}
+	key  *crypto.SymmetricKey
+	err  error
+}
+
 // getRepoIndexKey retrieves a RepoIndexKey for a repo.
-// These keys are cached in the client.
-func (c *client) getRepoIndexKey(repoPath api.RepoPath) (*crypto.SymmetricKey, error) {
+// These keys are cached in the client. Concurrent callers that miss the cache
+// for the same repo wait for a single fetch. The lock is only held to access
+// the cache, so keys of other repos can be retrieved in the meantime.
+func (c *client) getRepoIndexKey(ctx context.Context, repoPath api.RepoPath) (*crypto.SymmetricKey, error) {
+	c.repoIndexKeysMutex.Lock()
 	repoIndexKey, cached := c.repoIndexKeys[repoPath]
 	if cached {
+		c.repoIndexKeysMutex.Unlock()
 		return repoIndexKey, nil
 	}
 
-	wrappedKey, err := c.httpClient.GetRepoKeys(repoPath.GetNamespaceAndRepoName())
+	fetch, fetching := c.repoIndexKeyFetches[repoPath]
+	if fetching {
+		c.repoIndexKeysMutex.Unlock()
+		select {
+		case <-fetch.done:
+			return fetch.key, fetch.err
+		case <-ctx.Done():
+			return nil, errio.Error(ctx.Err())
+		}
+	}
+
+	fetch = &repoIndexKeyFetch{
+		done: make(chan struct{}),
+	}
+	c.repoIndexKeyFetches[repoPath] = fetch
+	c.repoIndexKeysMutex.Unlock()
+
+	fetch.key, fetch.err = c.fetchRepoIndexKey(ctx, repoPath)
+
+	c.repoIndexKeysMutex.Lock()
+	// The fetch is no longer registered when the keys have been wiped or the repo
+	// has been deleted in the meantime. Then the key must not be cached.
+	if c.repoIndexKeyFetches[repoPath] == fetch {
+		delete(c.repoIndexKeyFetches, repoPath)
+		if fetch.err == nil {
+			c.repoIndexKeys[repoPath] = fetch.key
+		}
+	}
+	c.repoIndexKeysMutex.Unlock()
+	close(fetch.done)
+
+	return fetch.key, fetch.err
+}
+
+// fetchRepoIndexKey fetches the RepoIndexKey of a repo and unwraps it with the account key.
+func (c *client) fetchRepoIndexKey(ctx context.Context, repoPath api.RepoPath) (*crypto.SymmetricKey, error) {
+	wrappedKey, err := c.fetchRepoKeys(ctx, repoPath)
 	if err != nil {
 		return nil, errio.Error(err)
 	}
 
-	accountKey, err := c.getAccountKey()
+	accountKey, err := c.getAccountKey(ctx)
 	if err != nil {
 		return nil, errio.Error(err)
 	}
@@ -258,9 +402,5 @@ func (c *client) getRepoIndexKey(repoPath api.RepoPath) (*crypto.SymmetricKey, e
 		return nil, errio.Error(err)
 	}
 
-	repoIndexKey = crypto.NewSymmetricKey(keyData)
-
-	c.repoIndexKeys[repoPath] = repoIndexKey
-
-	return repoIndexKey, nil
+	return crypto.NewSymmetricKey(keyData), nil
 }
@@ -1,6 +1,8 @@
 package secrethub
 
 import (
+	"context"
+
 	"github.com/secrethub/secrethub-go/internals/api"
 	"github.com/secrethub/secrethub-go/internals/errio"
 )
@@ -9,26 +11,33 @@ import (
 type RepoServiceService interface {
 	// List lists the services of the given repository.
 	List(path string) ([]*api.Service, error)
+	// ListContext is the same as List, but uses the given context for all requests.
+	ListContext(ctx context.Context, path string) ([]*api.Service, error)
 }
 
-func newRepoServiceService(client client) RepoServiceService {
+func newRepoServiceService(client *client) RepoServiceService {
 	return &repoServiceService{
 		client: client,
 	}
 }
 
 type repoServiceService struct {
-	client client
+	client *client
 }
 
 // List lists the services of the given repository.
 func (s repoServiceService) List(path string) ([]*api.Service, error) {
+	return s.ListContext(context.Background(), path)
+}
+
+// ListContext is the same as List, but uses the given context for all requests.
+func (s repoServiceService) ListContext(ctx context.Context, path string) ([]*api.Service, error) {
 	repoPath, err := api.NewRepoPath(path)
 	if err != nil {
 		return nil, errio.Error(err)
 	}
 
-	services, err := s.client.httpClient.ListServices(repoPath.GetNamespaceAndRepoName())
+	services, err := s.client.httpClient.ListServices(ctx, repoPath.GetNamespace(), repoPath.GetRepo())
 	if err != nil {
 		return nil, errio.Error(err)
 	}
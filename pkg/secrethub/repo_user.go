@@ -1,6 +1,8 @@
 package secrethub
 
 import (
+	"context"
+
 	"github.com/secrethub/secrethub-go/internals/api"
 	"github.com/secrethub/secrethub-go/internals/errio"
 )
@@ -9,24 +11,35 @@ import (
 type RepoUserService interface {
 	// Invite invites the user with given username to the repository at the given path.
 	Invite(path string, username string) (*api.RepoMember, error)
+	// InviteContext is the same as Invite, but uses the given context for all requests.
+	InviteContext(ctx context.Context, path string, username string) (*api.RepoMember, error)
 	// List lists the users of the given repository.
 	List(path string) ([]*api.User, error)
+	// ListContext is the same as List, but uses the given context for all requests.
+	ListContext(ctx context.Context, path string) ([]*api.User, error)
 	// Revoke revokes the user with given username from the repository with the given path.
 	Revoke(path string, username string) (*api.RevokeRepoResponse, error)
+	// RevokeContext is the same as Revoke, but uses the given context for all requests.
+	RevokeContext(ctx context.Context, path string, username string) (*api.RevokeRepoResponse, error)
 }
 
-func newRepoUserService(client client) RepoUserService {
+func newRepoUserService(client *client) RepoUserService {
 	return repoUserService{
 		client: client,
 	}
 }
 
 type repoUserService struct {
-	client client
+	client *client
 }
 
 // Invite invites the user with given username to the repository at the given path.
 func (s repoUserService) Invite(path string, username string) (*api.RepoMember, error) {
+	return s.InviteContext(context.Background(), path, username)
+}
+
+// InviteContext is the same as Invite, but uses the given context for all requests.
+func (s repoUserService) InviteContext(ctx context.Context, path string, username string) (*api.RepoMember, error) {
 	repoPath, err := api.NewRepoPath(path)
 	if err != nil {
 		return nil, errio.Error(err)
@@ -40,7 +53,7 @@ func (s repoUserService) Invite(path string, username string) (*api.RepoMember,
 		return nil, api.ErrUsernameIsService
 	}
 
-	account, err := s.client.httpClient.GetAccount(accountName)
+	account, err := s.client.httpClient.GetAccount(ctx, accountName)
 	if err == api.ErrAccountNotFound {
 		// return a more context specific error
 		return nil, api.ErrUserNotFound
@@ -52,7 +65,7 @@ func (s repoUserService) Invite(path string, username string) (*api.RepoMember,
 		return nil, api.ErrAccountNotKeyed
 	}
 
-	createRepoMember, err := s.client.createRepoMemberRequest(repoPath, account.PublicKey)
+	createRepoMember, err := s.client.createRepoMemberRequest(ctx, repoPath, account.PublicKey)
 	if err != nil {
 		return nil, errio.Error(err)
 	}
@@ -62,7 +75,7 @@ func (s repoUserService) Invite(path string, username string) (*api.RepoMember,
 		RepoMember: createRepoMember,
 	}
 
-	repoMember, err := s.client.httpClient.InviteRepo(repoPath.GetNamespace(), repoPath.GetRepo(), in)
+	repoMember, err := s.client.httpClient.InviteRepo(ctx, repoPath.GetNamespace(), repoPath.GetRepo(), in)
 	if err != nil {
 		return nil, errio.Error(err)
 	}
@@ -72,12 +85,17 @@ func (s repoUserService) Invite(path string, username string) (*api.RepoMember,
 
 // List lists the users of the given repository.
 func (s repoUserService) List(path string) ([]*api.User, error) {
+	return s.ListContext(context.Background(), path)
+}
+
+// ListContext is the same as List, but uses the given context for all requests.
+func (s repoUserService) ListContext(ctx context.Context, path string) ([]*api.User, error) {
 	repoPath, err := api.NewRepoPath(path)
 	if err != nil {
 		return nil, errio.Error(err)
 	}
 
-	users, err := s.client.httpClient.ListRepoUsers(repoPath.GetNamespaceAndRepoName())
+	users, err := s.client.httpClient.ListRepoUsers(ctx, repoPath.GetNamespace(), repoPath.GetRepo())
 	if err != nil {
 		return nil, errio.Error(err)
 	}
@@ -87,6 +105,11 @@ func (s repoUserService) List(path string) ([]*api.User, error) {
 
 // Revoke revokes the user with given username from the repository with the given path.
 func (s repoUserService) Revoke(path string, username string) (*api.RevokeRepoResponse, error) {
+	return s.RevokeContext(context.Background(), path, username)
+}
+
+// RevokeContext is the same as Revoke, but uses the given context for all requests.
+func (s repoUserService) RevokeContext(ctx context.Context, path string, username string) (*api.RevokeRepoResponse, error) {
 	repoPath, err := api.NewRepoPath(path)
 	if err != nil {
 		return nil, errio.Error(err)
@@ -97,7 +120,7 @@ func (s repoUserService) Revoke(path string, username string) (*api.RevokeRepoRe
 		return nil, errio.Error(err)
 	}
 
-	resp, err := s.client.httpClient.RemoveUser(repoPath.GetNamespace(), repoPath.GetRepo(), username)
+	resp, err := s.client.httpClient.RemoveUser(ctx, repoPath.GetNamespace(), repoPath.GetRepo(), username)
 	if err != nil {
 		return nil, errio.Error(err)
 	}
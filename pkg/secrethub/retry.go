@@ -0,0 +1,164 @@
+package secrethub
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/secrethub/secrethub-go/internals/errio"
+)
+
+const (
+	// DefaultMaxRetries defines the default number of times a request is retried.
+	DefaultMaxRetries = 3
+	// DefaultMinBackoff defines the default delay before the first retry.
+	DefaultMinBackoff = 250 * time.Millisecond
+	// DefaultMaxBackoff defines the default maximum delay between two retries.
+	DefaultMaxBackoff = 10 * time.Second
+)
+
+// RetryPolicy defines how requests that failed because of a transient error are retried.
+//
+// Only idempotent requests (GET and DELETE) are retried. A request is considered to
+// have failed transiently when the connection to the server failed or when the server
+// responded with a 5xx or 429 (Too Many Requests) status code. The delay between two
+// attempts grows exponentially with the number of attempts and is randomized (jittered)
+// to spread out the load on the server. When the server responds with a Retry-After
+// header, that delay is used instead.
+//
+// Every attempt is signed again, so retried requests never carry an expired signature.
+type RetryPolicy struct {
+	// MaxRetries is the maximum number of times a request is retried
+	// after the first attempt. When MaxRetries <= 0, requests are never retried.
+	MaxRetries int
+	// MinBackoff is the delay before the first retry. The delay is doubled
+	// for every subsequent retry. Defaults to DefaultMinBackoff.
+	MinBackoff time.Duration
+	// MaxBackoff is the maximum delay between two attempts. When the server
+	// asks to wait longer than MaxBackoff with a Retry-After header, the request
+	// is not retried. Defaults to DefaultMaxBackoff.
+	MaxBackoff time.Duration
+}
+
+// DefaultRetryPolicy returns a RetryPolicy with the default settings.
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxRetries: DefaultMaxRetries,
+		MinBackoff: DefaultMinBackoff,
+		MaxBackoff: DefaultMaxBackoff,
+	}
+}
+
+// backoff returns how long to wait before retrying a request that failed on
+// the given attempt, counting from 0. When resp contains a Retry-After header,
+// the delay requested by the server is returned. The second return value is
+// false when the request should not be retried at all.
+func (p *RetryPolicy) backoff(attempt int, resp *http.Response) (time.Duration, bool) {
+	minBackoff := p.MinBackoff
+	if minBackoff <= 0 {
+		minBackoff = DefaultMinBackoff
+	}
+
+	maxBackoff := p.MaxBackoff
+	if maxBackoff <= 0 {
+		maxBackoff = DefaultMaxBackoff
+	}
+	if maxBackoff < minBackoff {
+		maxBackoff = minBackoff
+	}
+
+	if resp != nil {
+		retryAfter, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+		if ok {
+			if retryAfter > maxBackoff {
+				return 0, false
+			}
+			return retryAfter, true
+		}
+	}
+
+	backoff := maxBackoff
+	if attempt < 32 && minBackoff<<uint(attempt) < maxBackoff {
+		backoff = minBackoff << uint(attempt)
+	}
+
+	// Use "equal jitter": wait at least half of the backoff and
+	// a random duration up to the other half.
+	half := backoff / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1)), true
+}
+
+// shouldRetry returns whether a request with the given method that failed
+// on the given attempt, counting from 0, should be retried.
+func (p *RetryPolicy) shouldRetry(attempt int, method string, resp *http.Response, err error) bool {
+	if p == nil || attempt >= p.MaxRetries {
+		return false
+	}
+
+	if method != http.MethodGet && method != http.MethodDelete {
+		return false
+	}
+
+	if err != nil {
+		// Known errors, such as a certificate pin mismatch, are not transient.
+		var publicErr errio.PublicError
+		return !errors.As(err, &publicErr)
+	}
+
+	return resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests
+}
+
+// parseRetryAfter parses the value of a Retry-After header, which is either
+// a number of seconds or an HTTP date. It returns false when the value is
+// empty or cannot be parsed.
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	seconds, err := strconv.Atoi(value)
+	if err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	t, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+
+	d := t.Sub(now)
+	if d < 0 {
+		d = 0
+	}
+	return d, true
+}
+
+// sleep waits for the given duration or until the context is done,
+// whichever happens first. It returns the context's error when the
+// context is done before the duration has passed.
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// discardResponse reads the remainder of the response body and closes it,
+// so the underlying connection can be reused for the next attempt.
+func discardResponse(resp *http.Response) {
+	_, _ = io.Copy(ioutil.Discard, resp.Body)
+	_ = resp.Body.Close()
+}
@@ -0,0 +1,197 @@
+package secrethub
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/assert"
+)
+
+func TestRetry(t *testing.T) {
+	cases := map[string]struct {
+		method          string
+		failures        int
+		failStatus      int
+		retryAfter      string
+		policy          *RetryPolicy
+		expectedCalls   int
+		expectedSuccess bool
+	}{
+		"no policy": {
+			method:          http.MethodGet,
+			failures:        1,
+			failStatus:      http.StatusServiceUnavailable,
+			policy:          nil,
+			expectedCalls:   1,
+			expectedSuccess: false,
+		},
+		"retry GET on 503": {
+			method:          http.MethodGet,
+			failures:        2,
+			failStatus:      http.StatusServiceUnavailable,
+			policy:          &RetryPolicy{MaxRetries: 3, MinBackoff: time.Millisecond, MaxBackoff: 5 * time.Millisecond},
+			expectedCalls:   3,
+			expectedSuccess: true,
+		},
+		"retry DELETE on 429": {
+			method:          http.MethodDelete,
+			failures:        1,
+			failStatus:      http.StatusTooManyRequests,
+			policy:          &RetryPolicy{MaxRetries: 3, MinBackoff: time.Millisecond, MaxBackoff: 5 * time.Millisecond},
+			expectedCalls:   2,
+			expectedSuccess: true,
+		},
+		"max retries exceeded": {
+			method:          http.MethodGet,
+			failures:        5,
+			failStatus:      http.StatusInternalServerError,
+			policy:          &RetryPolicy{MaxRetries: 2, MinBackoff: time.Millisecond, MaxBackoff: 5 * time.Millisecond},
+			expectedCalls:   3,
+			expectedSuccess: false,
+		},
+		"POST is not retried": {
+			method:          http.MethodPost,
+			failures:        1,
+			failStatus:      http.StatusServiceUnavailable,
+			policy:          &RetryPolicy{MaxRetries: 3, MinBackoff: time.Millisecond, MaxBackoff: 5 * time.Millisecond},
+			expectedCalls:   1,
+			expectedSuccess: false,
+		},
+		"4xx is not retried": {
+			method:          http.MethodGet,
+			failures:        1,
+			failStatus:      http.StatusNotFound,
+			policy:          &RetryPolicy{MaxRetries: 3, MinBackoff: time.Millisecond, MaxBackoff: 5 * time.Millisecond},
+			expectedCalls:   1,
+			expectedSuccess: false,
+		},
+		"Retry-After honoured": {
+			method:          http.MethodGet,
+			failures:        1,
+			failStatus:      http.StatusServiceUnavailable,
+			retryAfter:      "0",
+			policy:          &RetryPolicy{MaxRetries: 3, MinBackoff: time.Hour, MaxBackoff: time.Hour},
+			expectedCalls:   2,
+			expectedSuccess: true,
+		},
+		"Retry-After longer than MaxBackoff": {
+			method:          http.MethodGet,
+			failures:        1,
+			failStatus:      http.StatusServiceUnavailable,
+			retryAfter:      "60",
+			policy:          &RetryPolicy{MaxRetries: 3, MinBackoff: time.Millisecond, MaxBackoff: 5 * time.Millisecond},
+			expectedCalls:   1,
+			expectedSuccess: false,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			// Arrange
+			router, opts, cleanup := setup()
+			defer cleanup()
+
+			opts.RetryPolicy = tc.policy
+			client := newHTTPClient(cred1, opts)
+
+			calls := 0
+			router.MethodFunc(tc.method, "/orgs/myorg", func(w http.ResponseWriter, r *http.Request) {
+				calls++
+
+				if r.Header.Get("Authorization") == "" || r.Header.Get("Date") == "" {
+					t.Errorf("attempt %d is not signed", calls)
+				}
+
+				w.Header().Set("Content-Type", "application/json")
+				if calls <= tc.failures {
+					if tc.retryAfter != "" {
+						w.Header().Set("Retry-After", tc.retryAfter)
+					}
+					w.WriteHeader(tc.failStatus)
+					_ = json.NewEncoder(w).Encode(errClient.Code("test").StatusError("failure", tc.failStatus))
+					return
+				}
+
+				w.WriteHeader(http.StatusOK)
+				_ = json.NewEncoder(w).Encode(&api.Org{Name: "myorg"})
+			})
+
+			// Act
+			err := client.do(context.Background(), client.base+"/orgs/myorg", tc.method, http.StatusOK, nil, &api.Org{})
+
+			// Assert
+			assert.Equal(t, calls, tc.expectedCalls)
+			assert.Equal(t, err == nil, tc.expectedSuccess)
+		})
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2019, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	cases := map[string]struct {
+		value    string
+		expected time.Duration
+		ok       bool
+	}{
+		"empty": {
+			value: "",
+			ok:    false,
+		},
+		"seconds": {
+			value:    "120",
+			expected: 2 * time.Minute,
+			ok:       true,
+		},
+		"negative seconds": {
+			value: "-1",
+			ok:    false,
+		},
+		"http date": {
+			value:    "Fri, 01 Mar 2019 12:00:30 GMT",
+			expected: 30 * time.Second,
+			ok:       true,
+		},
+		"http date in the past": {
+			value:    "Fri, 01 Mar 2019 11:00:00 GMT",
+			expected: 0,
+			ok:       true,
+		},
+		"invalid": {
+			value: "soon",
+			ok:    false,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			// Act
+			actual, ok := parseRetryAfter(tc.value, now)
+
+			// Assert
+			assert.Equal(t, ok, tc.ok)
+			assert.Equal(t, actual, tc.expected)
+		})
+	}
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	policy := &RetryPolicy{
+		MaxRetries: 10,
+		MinBackoff: 100 * time.Millisecond,
+		MaxBackoff: time.Second,
+	}
+
+	for attempt := 0; attempt < 10; attempt++ {
+		backoff, ok := policy.backoff(attempt, nil)
+
+		assert.Equal(t, ok, true)
+		if backoff < 0 || backoff > policy.MaxBackoff {
+			t.Errorf("backoff for attempt %d out of bounds: %s", attempt, backoff)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package secrethub
+
+import (
+	"context"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/errio"
+)
+
+// Errors
+var (
+	ErrRollbackToLatest = errClient.Code("rollback_to_latest").ErrorPref("version %d is already the latest version of the secret")
+)
+
+// Rollback writes the value of the given version of the secret at the given path as
+// a new version. Existing versions are never changed. When another version has been
+// written after the latest version was read, ErrSecretVersionConflict is returned and
+// nothing is written. When another version is written at the same time as the new
+// version, the new version is returned together with ErrSecretVersionConflict. It
+// remains a version of the secret, but it is not necessarily the latest version.
+func (s secretVersionService) Rollback(path string, version int) (*api.SecretVersion, error) {
+	return s.RollbackContext(context.Background(), path, version)
+}
+
+// RollbackContext is the same as Rollback, but uses the given context for all requests.
+func (s secretVersionService) RollbackContext(ctx context.Context, path string, version int) (*api.SecretVersion, error) {
+	return rollbackVersion(ctx, clientAdapter{client: s.client}, path, version)
+}
+
+// rollbackVersion writes the value of the given version of the secret at the given path
+// as a new version, using the client to read and write the versions with WriteIfLatest.
+func rollbackVersion(ctx context.Context, client Client, path string, version int) (*api.SecretVersion, error) {
+	secretPath, err := api.NewSecretPath(path)
+	if err != nil {
+		return nil, errio.Error(err)
+	}
+	if secretPath.HasVersion() {
+		return nil, ErrCannotWriteToVersion
+	}
+
+	versionPath, err := secretPath.AddVersion(version)
+	if err != nil {
+		return nil, errio.Error(err)
+	}
+
+	secret, err := client.Secrets().GetContext(ctx, secretPath.Value())
+	if err != nil {
+		return nil, errio.Error(err)
+	}
+	if version == secret.LatestVersion {
+		return nil, ErrRollbackToLatest(version)
+	}
+
+	old, err := client.Secrets().Versions().GetWithDataContext(ctx, versionPath.Value())
+	if err != nil {
+		return nil, errio.Error(err)
+	}
+
+	return client.Secrets().WriteIfLatestContext(ctx, secretPath.Value(), secret.LatestVersion, old.Data)
+}
@@ -0,0 +1,196 @@
+package secrethub
+
+import (
+	"context"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/errio"
+	"github.com/secrethub/secrethub-go/pkg/randchar"
+)
+
+const (
+	// DefaultRotateLength is the length of a rotated secret when the policy does not set one.
+	DefaultRotateLength = 32
+)
+
+// Errors
+var (
+	ErrInvalidRotateLength       = errClient.Code("invalid_rotate_length").Error("the length of a rotated secret must be positive")
+	ErrRotatePolicyUnsatisfiable = errClient.Code("rotate_policy_unsatisfiable").Error("cannot generate a value that meets all requirements of the rotate policy")
+)
+
+// RotatePolicy describes how the new value of a rotated secret is generated.
+// The zero value generates an alphanumeric value of DefaultRotateLength characters.
+type RotatePolicy struct {
+	// Length is the number of characters of the new value.
+	// When it is 0, DefaultRotateLength is used.
+	Length int
+	// Charset contains the characters the new value is generated from.
+	// When it is empty, randchar.Alphanumeric is used.
+	Charset randchar.Charset
+	// Symbols adds randchar.Symbols to the charset.
+	Symbols bool
+	// ExcludeAmbiguous removes characters that are easily confused,
+	// such as 0 and O, from the charset.
+	ExcludeAmbiguous bool
+	// Required contains character classes of which the new value
+	// contains at least one character each, e.g. randchar.Numeric.
+	Required []randchar.Charset
+	// Requirements contains character classes of which the new value contains
+	// at least a minimum number of characters, e.g. at least 2 symbols.
+	Requirements []randchar.Requirement
+	// AfterWrite is an optional hook that is called after the new value has been
+	// written, e.g. to update the system that uses the secret. The given version
+	// includes the data of the new value.
+	AfterWrite RotateHook
+}
+
+// RotateHook is called after the new value of a rotated secret has been written.
+type RotateHook func(path string, version *api.SecretVersion) error
+
+// RotateResult is the result of rotating a secret.
+type RotateResult struct {
+	// OldVersion is the version of the secret that was the latest version before the rotation.
+	OldVersion int
+	// NewVersion is the version that contains the new value of the secret.
+	NewVersion int
+}
+
+// Generate returns a new random value according to the policy.
+// Every value that meets the requirements of the policy is equally likely.
+func (p RotatePolicy) Generate() ([]byte, error) {
+	generator, length, err := p.generator()
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := generator.Generate(length)
+	if err == randchar.ErrPolicyUnsatisfiable {
+		return nil, ErrRotatePolicyUnsatisfiable
+	} else if err != nil {
+		return nil, errio.Error(err)
+	}
+	return value, nil
+}
+
+// Entropy returns the number of bits of entropy of the values generated according to the policy.
+func (p RotatePolicy) Entropy() (float64, error) {
+	generator, length, err := p.generator()
+	if err != nil {
+		return 0, err
+	}
+
+	entropy, err := generator.Entropy(length)
+	if err == randchar.ErrPolicyUnsatisfiable {
+		return 0, ErrRotatePolicyUnsatisfiable
+	} else if err != nil {
+		return 0, errio.Error(err)
+	}
+	return entropy, nil
+}
+
+// generator returns a generator for the policy and the length of the values to generate.
+func (p RotatePolicy) generator() (*randchar.PolicyGenerator, int, error) {
+	length := p.Length
+	if length == 0 {
+		length = DefaultRotateLength
+	}
+	if length < 0 {
+		return nil, 0, ErrInvalidRotateLength
+	}
+
+	charset := p.Charset
+	if len(charset) == 0 {
+		charset = randchar.Alphanumeric
+	}
+	if p.Symbols {
+		charset = charset.Add(randchar.Symbols)
+	}
+
+	requirements := make([]randchar.Requirement, 0, len(p.Required)+len(p.Requirements))
+	for _, class := range p.Required {
+		requirements = append(requirements, randchar.Requirement{
+			Charset: class,
+			Min:     1,
+		})
+	}
+	requirements = append(requirements, p.Requirements...)
+
+	generator, err := randchar.NewPolicyGenerator(randchar.Policy{
+		Charset:          charset,
+		ExcludeAmbiguous: p.ExcludeAmbiguous,
+		Requirements:     requirements,
+	})
+	if err != nil {
+		return nil, 0, errio.Error(err)
+	}
+	return generator, length, nil
+}
+
+// Rotate replaces the value of an existing secret with a new random value that is
+// generated according to the policy and returns the old and new version numbers.
+// The new value is only written when the latest version is still the version that
+// was retrieved, otherwise ErrSecretVersionConflict is returned. When another version
+// is written at the same time as the new value, the new value remains written: the
+// result is then returned together with ErrSecretVersionConflict and the AfterWrite
+// hook is not called. See WriteIfLatest.
+//
+// When the AfterWrite hook of the policy returns an error, the new version has
+// already been written, so the result is returned together with the error.
+// The old version is never deleted; that is up to the caller.
+func (s secretService) Rotate(path string, policy RotatePolicy) (*RotateResult, error) {
+	return s.RotateContext(context.Background(), path, policy)
+}
+
+// RotateContext is the same as Rotate, but uses the given context for all requests.
+func (s secretService) RotateContext(ctx context.Context, path string, policy RotatePolicy) (*RotateResult, error) {
+	return rotateSecret(ctx, clientAdapter{client: s.client}, path, policy)
+}
+
+// rotateSecret rotates the secret at the given path, using the client to read
+// the secret and to write the new value.
+func rotateSecret(ctx context.Context, client Client, path string, policy RotatePolicy) (*RotateResult, error) {
+	secretPath, err := api.NewSecretPath(path)
+	if err != nil {
+		return nil, errio.Error(err)
+	}
+
+	if secretPath.HasVersion() {
+		return nil, ErrCannotWriteToVersion
+	}
+
+	data, err := policy.Generate()
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := client.Secrets().GetContext(ctx, path)
+	if err != nil {
+		return nil, errio.Error(err)
+	}
+
+	version, err := client.Secrets().WriteIfLatestContext(ctx, path, secret.LatestVersion, data)
+	if err == ErrSecretVersionConflict && version != nil {
+		return &RotateResult{
+			OldVersion: secret.LatestVersion,
+			NewVersion: version.Version,
+		}, err
+	} else if err != nil {
+		return nil, err
+	}
+
+	result := &RotateResult{
+		OldVersion: secret.LatestVersion,
+		NewVersion: version.Version,
+	}
+
+	if policy.AfterWrite != nil {
+		version.Data = data
+		err = policy.AfterWrite(path, version)
+		if err != nil {
+			return result, err
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,126 @@
+package secrethub
+
+import (
+	"bytes"
+	"math"
+	"testing"
+
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/pkg/randchar"
+)
+
+func TestRotatePolicy_Entropy(t *testing.T) {
+	// Arrange
+	policy := RotatePolicy{
+		Length:  10,
+		Charset: randchar.Numeric,
+	}
+
+	// Act
+	actual, err := policy.Entropy()
+
+	// Assert
+	assert.OK(t, err)
+	if math.Abs(actual-10*math.Log2(10)) > 1e-9 {
+		t.Errorf("unexpected entropy: %f", actual)
+	}
+}
+
+func TestRotatePolicy_Generate(t *testing.T) {
+	cases := map[string]struct {
+		policy          RotatePolicy
+		expectedLength  int
+		expectedCharset randchar.Charset
+		err             error
+	}{
+		"default": {
+			policy:          RotatePolicy{},
+			expectedLength:  DefaultRotateLength,
+			expectedCharset: randchar.Alphanumeric,
+		},
+		"length": {
+			policy: RotatePolicy{
+				Length: 8,
+			},
+			expectedLength:  8,
+			expectedCharset: randchar.Alphanumeric,
+		},
+		"charset": {
+			policy: RotatePolicy{
+				Charset: randchar.Numeric,
+			},
+			expectedLength:  DefaultRotateLength,
+			expectedCharset: randchar.Numeric,
+		},
+		"symbols": {
+			policy: RotatePolicy{
+				Charset: randchar.Lowercase,
+				Symbols: true,
+			},
+			expectedLength:  DefaultRotateLength,
+			expectedCharset: randchar.Lowercase.Add(randchar.Symbols),
+		},
+		"required": {
+			policy: RotatePolicy{
+				Length:   4,
+				Symbols:  true,
+				Required: []randchar.Charset{randchar.Numeric, randchar.Lowercase, randchar.Uppercase, randchar.Symbols},
+			},
+			expectedLength:  4,
+			expectedCharset: randchar.Alphanumeric.Add(randchar.Symbols),
+		},
+		"exclude ambiguous": {
+			policy: RotatePolicy{
+				Charset:          randchar.Numeric,
+				ExcludeAmbiguous: true,
+			},
+			expectedLength:  DefaultRotateLength,
+			expectedCharset: randchar.Charset("23456789"),
+		},
+		"negative length": {
+			policy: RotatePolicy{
+				Length: -1,
+			},
+			err: ErrInvalidRotateLength,
+		},
+		"required not in charset": {
+			policy: RotatePolicy{
+				Charset:  randchar.Numeric,
+				Required: []randchar.Charset{randchar.Lowercase},
+			},
+			err: ErrRotatePolicyUnsatisfiable,
+		},
+		"more required than length": {
+			policy: RotatePolicy{
+				Length:   1,
+				Required: []randchar.Charset{randchar.Numeric, randchar.Lowercase},
+			},
+			err: ErrRotatePolicyUnsatisfiable,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			// Act
+			actual, err := tc.policy.Generate()
+
+			// Assert
+			assert.Equal(t, err, tc.err)
+			if err != nil {
+				return
+			}
+
+			assert.Equal(t, len(actual), tc.expectedLength)
+			for _, char := range actual {
+				if !tc.expectedCharset.Contains(char) {
+					t.Errorf("unexpected character %q in %s", char, actual)
+				}
+			}
+			for _, class := range tc.policy.Required {
+				if !bytes.ContainsAny(actual, string(class)) {
+					t.Errorf("%s does not contain any of the characters %s", actual, class)
+				}
+			}
+		})
+	}
+}
@@ -1,24 +1,68 @@
 package secrethub
 
 import (
+	"context"
+	"time"
+
 	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/api/uuid"
 	"github.com/secrethub/secrethub-go/internals/errio"
+	"github.com/secrethub/secrethub-go/pkg/keygen"
 )
 
 // SecretService handles operations on secrets from SecretHub.
 type SecretService interface {
 	// Delete removes the secret at the given path.
 	Delete(path string) error
+	// DeleteContext is the same as Delete, but uses the given context for all requests.
+	DeleteContext(ctx context.Context, path string) error
+	// Events returns an iterator that retrieves the audit events for a given secret
+	// one page at a time, only returning the events that match the filter.
+	Events(path string, filter AuditFilter) AuditEventIterator
+	// EventsContext is the same as Events, but uses the given context for all requests.
+	EventsContext(ctx context.Context, path string, filter AuditFilter) AuditEventIterator
 	// Exists returns whether a secret exists on the given path.
 	Exists(path string) (bool, error)
+	// ExistsContext is the same as Exists, but uses the given context for all requests.
+	ExistsContext(ctx context.Context, path string) (bool, error)
+	// GenerateKey generates key material with the generator and writes its private part
+	// to the secret at the given path and, when writePublic is set, its public part to
+	// a sibling secret.
+	GenerateKey(path string, generator keygen.Generator, writePublic bool) (*KeyResult, error)
+	// GenerateKeyContext is the same as GenerateKey, but uses the given context for all requests.
+	GenerateKeyContext(ctx context.Context, path string, generator keygen.Generator, writePublic bool) (*KeyResult, error)
 	// Get retrieves a Secret.
 	Get(path string) (*api.Secret, error)
+	// GetContext is the same as Get, but uses the given context for all requests.
+	GetContext(ctx context.Context, path string) (*api.Secret, error)
 	// ListEvents retrieves all audit events for a given secret.
 	ListEvents(path string, subjectTypes api.AuditSubjectTypeList) ([]*api.Audit, error)
+	// ListEventsContext is the same as ListEvents, but uses the given context for all requests.
+	ListEventsContext(ctx context.Context, path string, subjectTypes api.AuditSubjectTypeList) ([]*api.Audit, error)
+	// Move copies the versions of the secret at path src to path dst and deletes
+	// the source when everything has been copied.
+	Move(src, dst string, options MoveOptions) (*MoveResult, error)
+	// MoveContext is the same as Move, but uses the given context for all requests.
+	MoveContext(ctx context.Context, src, dst string, options MoveOptions) (*MoveResult, error)
+	// PruneVersions deletes the versions of the secret, or of all secrets in the directory,
+	// at the given path that are not kept according to the retention policy.
+	PruneVersions(path string, policy RetentionPolicy) (*PruneReport, error)
+	// PruneVersionsContext is the same as PruneVersions, but uses the given context for all requests.
+	PruneVersionsContext(ctx context.Context, path string, policy RetentionPolicy) (*PruneReport, error)
+
+	// Rotate replaces the value of an existing secret with a new random value that is
+	// generated according to the policy and returns the old and new version numbers.
+	Rotate(path string, policy RotatePolicy) (*RotateResult, error)
+	// RotateContext is the same as Rotate, but uses the given context for all requests.
+	RotateContext(ctx context.Context, path string, policy RotatePolicy) (*RotateResult, error)
 
 	// Versions returns a SecretVersionService.
 	Versions() SecretVersionService
 
+	// Watch polls the latest versions of the secrets at the given paths at the given interval
+	// and emits an event on the returned channel for every change, until the context is done.
+	Watch(ctx context.Context, paths []string, interval time.Duration, withData bool) (<-chan WatchEvent, error)
+
 	// Write encrypts and writes any secret data to SecretHub, always creating
 	// a new secret version for the written data. This ensures secret data is
 	// never overwritten.
@@ -30,31 +74,47 @@ type SecretService interface {
 	// Note that data is encrypted as is. Sanitizing data is the responsibility of the
 	// function caller.
 	Write(path string, data []byte) (*api.SecretVersion, error)
+	// WriteContext is the same as Write, but uses the given context for all requests.
+	WriteContext(ctx context.Context, path string, data []byte) (*api.SecretVersion, error)
+	// WriteIfLatest is the same as Write, but only writes the data when the latest
+	// version of the secret is expectedVersion. Otherwise, ErrSecretVersionConflict
+	// is returned. When expectedVersion is 0, the secret is only created when it
+	// does not exist yet, and api.ErrSecretAlreadyExists is returned when it does.
+	// When another version is written concurrently, the written version is returned
+	// together with ErrSecretVersionConflict and remains a version of the secret.
+	WriteIfLatest(path string, expectedVersion int, data []byte) (*api.SecretVersion, error)
+	// WriteIfLatestContext is the same as WriteIfLatest, but uses the given context for all requests.
+	WriteIfLatestContext(ctx context.Context, path string, expectedVersion int, data []byte) (*api.SecretVersion, error)
 }
 
-func newSecretService(client client) SecretService {
+func newSecretService(client *client) SecretService {
 	return secretService{
 		client: client,
 	}
 }
 
 type secretService struct {
-	client client
+	client *client
 }
 
 // Delete removes the secret at the given path.
 func (s secretService) Delete(path string) error {
+	return s.DeleteContext(context.Background(), path)
+}
+
+// DeleteContext is the same as Delete, but uses the given context for all requests.
+func (s secretService) DeleteContext(ctx context.Context, path string) error {
 	secretPath, err := api.NewSecretPath(path)
 	if err != nil {
 		return errio.Error(err)
 	}
 
-	secretBlindName, err := s.client.convertPathToBlindName(secretPath)
+	secretBlindName, err := s.client.convertPathToBlindName(ctx, secretPath)
 	if err != nil {
 		return errio.Error(err)
 	}
 
-	err = s.client.httpClient.DeleteSecret(secretBlindName)
+	err = s.client.httpClient.DeleteSecret(ctx, secretBlindName)
 	if err != nil {
 		return errio.Error(err)
 	}
@@ -64,17 +124,22 @@ func (s secretService) Delete(path string) error {
 
 // Exists returns whether a secret exists on the given path.
 func (s secretService) Exists(path string) (bool, error) {
+	return s.ExistsContext(context.Background(), path)
+}
+
+// ExistsContext is the same as Exists, but uses the given context for all requests.
+func (s secretService) ExistsContext(ctx context.Context, path string) (bool, error) {
 	secretPath, err := api.NewSecretPath(path)
 	if err != nil {
 		return false, errio.Error(err)
 	}
 
-	blindName, err := s.client.convertPathToBlindName(secretPath)
+	blindName, err := s.client.convertPathToBlindName(ctx, secretPath)
 	if err != nil {
 		return false, errio.Error(err)
 	}
 
-	_, err = s.client.httpClient.GetSecret(blindName)
+	_, err = s.client.httpClient.GetSecret(ctx, blindName)
 	if err == api.ErrSecretNotFound {
 		return false, nil
 	} else if err != nil {
@@ -85,22 +150,27 @@ func (s secretService) Exists(path string) (bool, error) {
 
 // Get retrieves a Secret.
 func (s secretService) Get(path string) (*api.Secret, error) {
+	return s.GetContext(context.Background(), path)
+}
+
+// GetContext is the same as Get, but uses the given context for all requests.
+func (s secretService) GetContext(ctx context.Context, path string) (*api.Secret, error) {
 	secretPath, err := api.NewSecretPath(path)
 	if err != nil {
 		return nil, errio.Error(err)
 	}
 
-	blindName, err := s.client.convertPathToBlindName(secretPath)
+	blindName, err := s.client.convertPathToBlindName(ctx, secretPath)
 	if err != nil {
 		return nil, errio.Error(err)
 	}
 
-	encSecret, err := s.client.httpClient.GetSecret(blindName)
+	encSecret, err := s.client.httpClient.GetSecret(ctx, blindName)
 	if err != nil {
 		return nil, errio.Error(err)
 	}
 
-	accountKey, err := s.client.getAccountKey()
+	accountKey, err := s.client.getAccountKey(ctx)
 	if err != nil {
 		return nil, errio.Error(err)
 	}
@@ -119,28 +189,85 @@ func (s secretService) Get(path string) (*api.Secret, error) {
 // Note that data is encrypted as is. Sanitizing data is the responsibility of the
 // function caller.
 func (s secretService) Write(path string, data []byte) (*api.SecretVersion, error) {
-	secretPath, err := api.NewSecretPath(path)
+	return s.WriteContext(context.Background(), path, data)
+}
+
+// WriteContext is the same as Write, but uses the given context for all requests.
+func (s secretService) WriteContext(ctx context.Context, path string, data []byte) (*api.SecretVersion, error) {
+	secretPath, err := parseWrite(path, data)
 	if err != nil {
+		return nil, err
+	}
+
+	key, err := s.client.getSecretKey(ctx, secretPath)
+	if err == api.ErrSecretNotFound {
+		return s.client.createSecret(ctx, secretPath, data)
+	} else if err == api.ErrNoOKSecretKey {
+		key, err = s.client.createSecretKey(ctx, secretPath)
+		if err != nil {
+			return nil, errio.Error(err)
+		}
+	} else if err != nil {
 		return nil, errio.Error(err)
 	}
 
-	if secretPath.HasVersion() {
-		return nil, ErrCannotWriteToVersion
+	return s.client.createSecretVersion(ctx, secretPath, data, key)
+}
+
+// WriteIfLatest is the same as Write, but only writes the data when the latest
+// version of the secret is expectedVersion. Otherwise, ErrSecretVersionConflict
+// is returned. This can be used to safely update a secret from multiple
+// processes: on a conflict, read the latest version and try again.
+//
+// When expectedVersion is 0, the secret is only created when it does not exist
+// yet, and api.ErrSecretAlreadyExists is returned when it does.
+//
+// The API cannot check the expected version when a version is written, so the
+// latest version is checked both before and after the write. When another process
+// writes a version in between, the written version is not expectedVersion+1. Then
+// the written version is returned together with ErrSecretVersionConflict. The
+// written version remains a version of the secret, so the caller decides whether
+// to keep or delete it.
+func (s secretService) WriteIfLatest(path string, expectedVersion int, data []byte) (*api.SecretVersion, error) {
+	return s.WriteIfLatestContext(context.Background(), path, expectedVersion, data)
+}
+
+// WriteIfLatestContext is the same as WriteIfLatest, but uses the given context for all requests.
+func (s secretService) WriteIfLatestContext(ctx context.Context, path string, expectedVersion int, data []byte) (*api.SecretVersion, error) {
+	secretPath, err := parseWrite(path, data)
+	if err != nil {
+		return nil, err
 	}
 
-	if len(data) == 0 {
-		return nil, ErrEmptySecret
+	if expectedVersion < 0 {
+		return nil, api.ErrInvalidSecretVersion
 	}
 
-	if len(data) > MaxSecretSize {
-		return nil, ErrSecretTooBig
+	blindName, err := s.client.convertPathToBlindName(ctx, secretPath)
+	if err != nil {
+		return nil, errio.Error(err)
 	}
 
-	key, err := s.client.getSecretKey(secretPath)
+	encSecret, err := s.client.httpClient.GetSecret(ctx, blindName)
 	if err == api.ErrSecretNotFound {
-		return s.client.createSecret(secretPath, data)
-	} else if err == api.ErrNoOKSecretKey {
-		key, err = s.client.createSecretKey(secretPath)
+		if expectedVersion != 0 {
+			return nil, ErrSecretVersionConflict
+		}
+		return s.client.createSecret(ctx, secretPath, data)
+	} else if err != nil {
+		return nil, errio.Error(err)
+	}
+
+	if expectedVersion == 0 {
+		return nil, api.ErrSecretAlreadyExists
+	}
+	if encSecret.LatestVersion != expectedVersion {
+		return nil, ErrSecretVersionConflict
+	}
+
+	key, err := s.client.getSecretKey(ctx, secretPath)
+	if err == api.ErrNoOKSecretKey {
+		key, err = s.client.createSecretKey(ctx, secretPath)
 		if err != nil {
 			return nil, errio.Error(err)
 		}
@@ -148,28 +275,63 @@ func (s secretService) Write(path string, data []byte) (*api.SecretVersion, erro
 		return nil, errio.Error(err)
 	}
 
-	return s.client.createSecretVersion(secretPath, data, key)
+	version, err := s.client.createSecretVersion(ctx, secretPath, data, key)
+	if err != nil {
+		return nil, errio.Error(err)
+	}
+
+	if version.Version != expectedVersion+1 {
+		return version, ErrSecretVersionConflict
+	}
+	return version, nil
+}
+
+// parseWrite parses the path of a secret to write to and checks the data can be written.
+func parseWrite(path string, data []byte) (api.SecretPath, error) {
+	secretPath, err := api.NewSecretPath(path)
+	if err != nil {
+		return "", errio.Error(err)
+	}
+
+	if secretPath.HasVersion() {
+		return "", ErrCannotWriteToVersion
+	}
+
+	if len(data) == 0 {
+		return "", ErrEmptySecret
+	}
+
+	if len(data) > MaxSecretSize {
+		return "", ErrSecretTooBig
+	}
+
+	return secretPath, nil
 }
 
 // ListEvents retrieves all audit events for a given secret.
 // If subjectTypes is left empty, the server's default is used.
 func (s secretService) ListEvents(path string, subjectTypes api.AuditSubjectTypeList) ([]*api.Audit, error) {
+	return s.ListEventsContext(context.Background(), path, subjectTypes)
+}
+
+// ListEventsContext is the same as ListEvents, but uses the given context for all requests.
+func (s secretService) ListEventsContext(ctx context.Context, path string, subjectTypes api.AuditSubjectTypeList) ([]*api.Audit, error) {
 	secretPath, err := api.NewSecretPath(path)
 	if err != nil {
 		return nil, errio.Error(err)
 	}
 
-	blindName, err := s.client.convertPathToBlindName(secretPath)
+	blindName, err := s.client.convertPathToBlindName(ctx, secretPath)
 	if err != nil {
 		return nil, errio.Error(err)
 	}
 
-	events, err := s.client.httpClient.AuditSecret(blindName, subjectTypes)
+	events, err := s.client.httpClient.AuditSecret(ctx, blindName, subjectTypes)
 	if err != nil {
 		return nil, errio.Error(err)
 	}
 
-	err = s.client.decryptAuditEvents(events...)
+	err = s.client.decryptAuditEvents(ctx, newSecretAuditPathResolver(secretPath), events...)
 	if err != nil {
 		return nil, errio.Error(err)
 	}
@@ -177,14 +339,43 @@ func (s secretService) ListEvents(path string, subjectTypes api.AuditSubjectType
 	return events, nil
 }
 
+// Events returns an iterator that retrieves the audit events for a given secret one page
+// at a time, only returning the events that match the filter. The subjects of the events
+// are decrypted when they are returned by the iterator.
+func (s secretService) Events(path string, filter AuditFilter) AuditEventIterator {
+	return s.EventsContext(context.Background(), path, filter)
+}
+
+// EventsContext is the same as Events, but uses the given context for all requests.
+func (s secretService) EventsContext(ctx context.Context, path string, filter AuditFilter) AuditEventIterator {
+	secretPath, err := api.NewSecretPath(path)
+	if err != nil {
+		return errAuditEventIterator{err: errio.Error(err)}
+	}
+
+	var blindName string
+	fetch := func(ctx context.Context, subjectTypes api.AuditSubjectTypeList, startingAfter *uuid.UUID, perPage int) ([]*api.Audit, error) {
+		if blindName == "" {
+			var err error
+			blindName, err = s.client.convertPathToBlindName(ctx, secretPath)
+			if err != nil {
+				return nil, errio.Error(err)
+			}
+		}
+		return s.client.httpClient.AuditSecretPage(ctx, blindName, subjectTypes, startingAfter, perPage)
+	}
+
+	return newAuditEventIterator(ctx, s.client, newSecretAuditPathResolver(secretPath), fetch, filter)
+}
+
 // Versions returns a SecretVersionService.
 func (s secretService) Versions() SecretVersionService {
 	return newSecretVersionService(s.client)
 }
 
 // convertsToBlindName will convert a path to a blindname.
-func (c *client) convertPathToBlindName(path api.BlindNamePath) (string, error) {
-	repoIndexKey, err := c.getRepoIndexKey(path.GetRepoPath())
+func (c *client) convertPathToBlindName(ctx context.Context, path api.BlindNamePath) (string, error) {
+	repoIndexKey, err := c.getRepoIndexKey(ctx, path.GetRepoPath())
 	if err != nil {
 		return "", errio.Error(err)
 	}
@@ -1,24 +1,26 @@
 package secrethub
 
 import (
+	"context"
+
 	"github.com/secrethub/secrethub-go/internals/api"
 	"github.com/secrethub/secrethub-go/internals/crypto"
 	"github.com/secrethub/secrethub-go/internals/errio"
 )
 
 // getSecretKey gets the current key for a given secret.
-func (c *client) getSecretKey(secretPath api.SecretPath) (*api.SecretKey, error) {
-	blindName, err := c.convertPathToBlindName(secretPath)
+func (c *client) getSecretKey(ctx context.Context, secretPath api.SecretPath) (*api.SecretKey, error) {
+	blindName, err := c.convertPathToBlindName(ctx, secretPath)
 	if err != nil {
 		return nil, errio.Error(err)
 	}
 
-	encKey, err := c.httpClient.GetCurrentSecretKey(blindName)
+	encKey, err := c.httpClient.GetCurrentSecretKey(ctx, blindName)
 	if err != nil {
 		return nil, errio.Error(err)
 	}
 
-	accountKey, err := c.getAccountKey()
+	accountKey, err := c.getAccountKey(ctx)
 	if err != nil {
 		return nil, errio.Error(err)
 	}
@@ -27,7 +29,7 @@ func (c *client) getSecretKey(secretPath api.SecretPath) (*api.SecretKey, error)
 }
 
 // createSecretKey creates a new secret key for a given secret.
-func (c *client) createSecretKey(secretPath api.SecretPath) (*api.SecretKey, error) {
+func (c *client) createSecretKey(ctx context.Context, secretPath api.SecretPath) (*api.SecretKey, error) {
 	secretKey, err := crypto.GenerateSymmetricKey()
 	if err != nil {
 		return nil, errio.Error(err)
@@ -39,7 +41,7 @@ func (c *client) createSecretKey(secretPath api.SecretPath) (*api.SecretKey, err
 	}
 
 	// Get all accounts that have permission to read the secret.
-	accounts, err := c.ListDirAccounts(parentPath)
+	accounts, err := c.ListDirAccounts(ctx, parentPath)
 	if err != nil {
 		return nil, errio.Error(err)
 	}
@@ -66,17 +68,17 @@ func (c *client) createSecretKey(secretPath api.SecretPath) (*api.SecretKey, err
 		EncryptedFor: encryptedFor,
 	}
 
-	blindName, err := c.convertPathToBlindName(secretPath)
+	blindName, err := c.convertPathToBlindName(ctx, secretPath)
 	if err != nil {
 		return nil, errio.Error(err)
 	}
 
-	resp, err := c.httpClient.CreateSecretKey(blindName, in)
+	resp, err := c.httpClient.CreateSecretKey(ctx, blindName, in)
 	if err != nil {
 		return nil, errio.Error(err)
 	}
 
-	accountKey, err := c.getAccountKey()
+	accountKey, err := c.getAccountKey(ctx)
 	if err != nil {
 		return nil, errio.Error(err)
 	}
@@ -1,6 +1,8 @@
 package secrethub
 
 import (
+	"context"
+
 	"fmt"
 
 	units "github.com/docker/go-units"
@@ -16,37 +18,81 @@ const (
 
 // Errors
 var (
-	ErrSecretTooBig         = errClient.Code("secret_too_big").Error(fmt.Sprintf("maximum size of a secret is %s", units.BytesSize(MaxSecretSize)))
-	ErrEmptySecret          = errClient.Code("empty_secret").Error("secret is empty")
-	ErrCannotWriteToVersion = errClient.Code("cannot_write_version").Error("cannot (over)write a specific secret version, they are append only")
+	ErrSecretTooBig          = errClient.Code("secret_too_big").Error(fmt.Sprintf("maximum size of a secret is %s", units.BytesSize(MaxSecretSize)))
+	ErrEmptySecret           = errClient.Code("empty_secret").Error("secret is empty")
+	ErrCannotWriteToVersion  = errClient.Code("cannot_write_version").Error("cannot (over)write a specific secret version, they are append only")
+	ErrSecretVersionConflict = errClient.Code("secret_version_conflict").Error("the latest version of the secret is not the expected version")
 )
 
 // SecretVersionService handles operations on secret versions from SecretHub.
 type SecretVersionService interface {
 	// Delete removes a secret version.
 	Delete(path string) error
+	// DeleteContext is the same as Delete, but uses the given context for all requests.
+	DeleteContext(ctx context.Context, path string) error
+	// Diff compares two versions of a secret, line by line for text values and key by key
+	// for JSON objects and YAML mappings of scalar values. When redact is set, the values
+	// of the changes are left out.
+	Diff(path string, from, to int, redact bool) (*VersionDiff, error)
+	// DiffContext is the same as Diff, but uses the given context for all requests.
+	DiffContext(ctx context.Context, path string, from, to int, redact bool) (*VersionDiff, error)
 	// GetWithData gets a secret version, with the sensitive data.
 	GetWithData(path string) (*api.SecretVersion, error)
+	// GetWithDataContext is the same as GetWithData, but uses the given context for all requests.
+	GetWithDataContext(ctx context.Context, path string) (*api.SecretVersion, error)
+	// GetManyWithData gets multiple secret versions in parallel, with the sensitive data.
+	// A result is returned for every given path, in the same order as the paths.
+	GetManyWithData(paths []string) []SecretVersionResult
+	// GetManyWithDataContext is the same as GetManyWithData, but uses the given context for all requests.
+	GetManyWithDataContext(ctx context.Context, paths []string) []SecretVersionResult
 	// GetWithoutData gets a secret version, without the sensitive data.
 	GetWithoutData(path string) (*api.SecretVersion, error)
+	// GetWithoutDataContext is the same as GetWithoutData, but uses the given context for all requests.
+	GetWithoutDataContext(ctx context.Context, path string) (*api.SecretVersion, error)
 	// ListWithData lists secret versions, with the sensitive data.
 	ListWithData(path string) ([]*api.SecretVersion, error)
+	// ListWithDataContext is the same as ListWithData, but uses the given context for all requests.
+	ListWithDataContext(ctx context.Context, path string) ([]*api.SecretVersion, error)
 	// ListWithoutData lists secret versions, without the sensitive data.
 	ListWithoutData(path string) ([]*api.SecretVersion, error)
+	// ListWithoutDataContext is the same as ListWithoutData, but uses the given context for all requests.
+	ListWithoutDataContext(ctx context.Context, path string) ([]*api.SecretVersion, error)
+	// Rollback writes the value of an older version of a secret as a new version. Like
+	// WriteIfLatest, it returns ErrSecretVersionConflict when another version is written
+	// concurrently, together with the new version when that has been written.
+	Rollback(path string, version int) (*api.SecretVersion, error)
+	// RollbackContext is the same as Rollback, but uses the given context for all requests.
+	RollbackContext(ctx context.Context, path string, version int) (*api.SecretVersion, error)
+}
+
+// SecretVersionResult is the result of retrieving a single secret version
+// as part of a bulk operation. When retrieving the version failed, Err is set.
+// Cached is true when the version has been served from the cache, because
+// the API could not be reached.
+type SecretVersionResult struct {
+	Path    string
+	Version *api.SecretVersion
+	Cached  bool
+	Err     error
 }
 
-func newSecretVersionService(client client) SecretVersionService {
+func newSecretVersionService(client *client) SecretVersionService {
 	return secretVersionService{
 		client: client,
 	}
 }
 
 type secretVersionService struct {
-	client client
+	client *client
 }
 
 // Delete removes a secret version.
 func (s secretVersionService) Delete(path string) error {
+	return s.DeleteContext(context.Background(), path)
+}
+
+// DeleteContext is the same as Delete, but uses the given context for all requests.
+func (s secretVersionService) DeleteContext(ctx context.Context, path string) error {
 	secretPath, err := api.NewSecretPath(path)
 	if err != nil {
 		return errio.Error(err)
@@ -57,12 +103,12 @@ func (s secretVersionService) Delete(path string) error {
 		return errio.Error(err)
 	}
 
-	secretBlindName, err := s.client.convertPathToBlindName(secretPath)
+	secretBlindName, err := s.client.convertPathToBlindName(ctx, secretPath)
 	if err != nil {
 		return errio.Error(err)
 	}
 
-	err = s.client.httpClient.DeleteSecretVersion(secretBlindName, version)
+	err = s.client.httpClient.DeleteSecretVersion(ctx, secretBlindName, version)
 	if err != nil {
 		return errio.Error(err)
 	}
@@ -71,94 +117,217 @@ func (s secretVersionService) Delete(path string) error {
 }
 
 // get gets a version of a secret. withData specifies whether the encrypted data should be retrieved.
-func (s secretVersionService) get(path api.SecretPath, withData bool) (*api.SecretVersion, error) {
-	blindName, err := s.client.convertPathToBlindName(path)
+func (s secretVersionService) get(ctx context.Context, path api.SecretPath, withData bool) (*api.SecretVersion, error) {
+	version, _, err := s.getCached(ctx, path, withData)
+	return version, err
+}
+
+// getCached is the same as get, but also returns whether the version has been served from the cache.
+func (s secretVersionService) getCached(ctx context.Context, path api.SecretPath, withData bool) (*api.SecretVersion, bool, error) {
+	blindName, err := s.client.convertPathToBlindName(ctx, path)
 	if err != nil {
-		return nil, errio.Error(err)
+		return nil, false, errio.Error(err)
 	}
 
 	var versionParam string
 	if path.HasVersion() {
 		versionParam, err = path.GetVersion()
 		if err != nil {
-			return nil, errio.Error(err)
+			return nil, false, errio.Error(err)
 		}
 	} else {
 		versionParam = "latest"
 	}
 
-	encVersion, err := s.client.httpClient.GetSecretVersion(blindName, versionParam, withData)
+	encVersion, cached, err := s.client.fetchSecretVersion(ctx, path, blindName, versionParam, withData)
 	if err != nil {
-		return nil, errio.Error(err)
+		return nil, false, errio.Error(err)
 	}
 
-	accountKey, err := s.client.getAccountKey()
+	accountKey, err := s.client.getAccountKey(ctx)
 	if err != nil {
-		return nil, errio.Error(err)
+		return nil, false, errio.Error(err)
 	}
 
 	secretVersion, err := encVersion.Decrypt(accountKey)
-	return secretVersion, errio.Error(err)
+	if err != nil {
+		return nil, false, errio.Error(err)
+	}
+	return secretVersion, cached, nil
 }
 
 // GetWithData gets a secret version, with the sensitive data.
 func (s secretVersionService) GetWithData(path string) (*api.SecretVersion, error) {
+	return s.GetWithDataContext(context.Background(), path)
+}
+
+// GetWithDataContext is the same as GetWithData, but uses the given context for all requests.
+func (s secretVersionService) GetWithDataContext(ctx context.Context, path string) (*api.SecretVersion, error) {
 	secretPath, err := api.NewSecretPath(path)
 	if err != nil {
 		return nil, errio.Error(err)
 	}
 
-	return s.get(secretPath, true)
+	return s.get(ctx, secretPath, true)
+}
+
+// GetManyWithData gets multiple secret versions in parallel, with the sensitive data.
+// A result is returned for every given path, in the same order as the paths.
+// The repo index keys needed to derive the blind names are retrieved only
+// once per repository and duplicate paths are only retrieved once.
+// The number of parallel requests is limited by ClientOptions.Concurrency.
+func (s secretVersionService) GetManyWithData(paths []string) []SecretVersionResult {
+	return s.GetManyWithDataContext(context.Background(), paths)
+}
+
+// GetManyWithDataContext is the same as GetManyWithData, but uses the given context for all requests.
+func (s secretVersionService) GetManyWithDataContext(ctx context.Context, paths []string) []SecretVersionResult {
+	results := make([]SecretVersionResult, len(paths))
+
+	// Parse and deduplicate the paths. For every result, indices
+	// contains the index of its path in secretPaths.
+	indices := make([]int, len(paths))
+	unique := make(map[api.SecretPath]int)
+	secretPaths := []api.SecretPath{}
+	repoPaths := []api.RepoPath{}
+	repoIndices := make(map[api.RepoPath]int)
+	for i, path := range paths {
+		results[i].Path = path
+
+		secretPath, err := api.NewSecretPath(path)
+		if err != nil {
+			results[i].Err = errio.Error(err)
+			continue
+		}
+
+		index, ok := unique[secretPath]
+		if !ok {
+			index = len(secretPaths)
+			unique[secretPath] = index
+			secretPaths = append(secretPaths, secretPath)
+		}
+		indices[i] = index
+
+		repoPath := secretPath.GetRepoPath()
+		if _, ok := repoIndices[repoPath]; !ok {
+			repoIndices[repoPath] = len(repoPaths)
+			repoPaths = append(repoPaths, repoPath)
+		}
+	}
+
+	versions := make([]SecretVersionResult, len(secretPaths))
+	if len(secretPaths) > 0 {
+		// Fetch the account key and every repo index key only once,
+		// before the secret versions are retrieved in parallel.
+		_, err := s.client.getAccountKey(ctx)
+		if err != nil {
+			for i := range versions {
+				versions[i].Err = errio.Error(err)
+			}
+		} else {
+			repoErrs := make([]error, len(repoPaths))
+			forEachConcurrently(ctx, len(repoPaths), s.client.concurrency, func(i int) {
+				_, repoErrs[i] = s.client.getRepoIndexKey(ctx, repoPaths[i])
+			})
+
+			started := make([]bool, len(secretPaths))
+			forEachConcurrently(ctx, len(secretPaths), s.client.concurrency, func(i int) {
+				started[i] = true
+
+				repoErr := repoErrs[repoIndices[secretPaths[i].GetRepoPath()]]
+				if repoErr != nil {
+					versions[i].Err = errio.Error(repoErr)
+					return
+				}
+
+				versions[i].Version, versions[i].Cached, versions[i].Err = s.getCached(ctx, secretPaths[i], true)
+			})
+
+			for i := range versions {
+				if !started[i] {
+					versions[i].Err = contextError(ctx, ctx.Err())
+				}
+			}
+		}
+	}
+
+	for i := range results {
+		if results[i].Err != nil {
+			continue
+		}
+
+		version := versions[indices[i]]
+		results[i].Version = version.Version
+		results[i].Cached = version.Cached
+		results[i].Err = version.Err
+	}
+
+	return results
 }
 
 // GetWithoutData gets a secret version, without the sensitive data.
 func (s secretVersionService) GetWithoutData(path string) (*api.SecretVersion, error) {
+	return s.GetWithoutDataContext(context.Background(), path)
+}
+
+// GetWithoutDataContext is the same as GetWithoutData, but uses the given context for all requests.
+func (s secretVersionService) GetWithoutDataContext(ctx context.Context, path string) (*api.SecretVersion, error) {
 	secretPath, err := api.NewSecretPath(path)
 	if err != nil {
 		return nil, errio.Error(err)
 	}
 
-	return s.get(secretPath, false)
+	return s.get(ctx, secretPath, false)
 }
 
-func (s secretVersionService) list(path api.SecretPath, withData bool) ([]*api.SecretVersion, error) {
-	blindName, err := s.client.convertPathToBlindName(path)
+func (s secretVersionService) list(ctx context.Context, path api.SecretPath, withData bool) ([]*api.SecretVersion, error) {
+	blindName, err := s.client.convertPathToBlindName(ctx, path)
 	if err != nil {
 		return nil, errio.Error(err)
 	}
 
-	versions, err := s.client.httpClient.ListSecretVersions(blindName, withData)
+	versions, err := s.client.httpClient.ListSecretVersions(ctx, blindName, withData)
 	if err != nil {
 		return nil, errio.Error(err)
 	}
 
-	return s.client.decryptSecretVersions(versions...)
+	return s.client.decryptSecretVersions(ctx, versions...)
 }
 
 // ListWithData lists secret versions, with the sensitive data.
 func (s secretVersionService) ListWithData(path string) ([]*api.SecretVersion, error) {
+	return s.ListWithDataContext(context.Background(), path)
+}
+
+// ListWithDataContext is the same as ListWithData, but uses the given context for all requests.
+func (s secretVersionService) ListWithDataContext(ctx context.Context, path string) ([]*api.SecretVersion, error) {
 	secretPath, err := api.NewSecretPath(path)
 	if err != nil {
 		return nil, errio.Error(err)
 	}
 
-	return s.list(secretPath, true)
+	return s.list(ctx, secretPath, true)
 }
 
 // ListWithoutData lists secret versions, without the sensitive data.
 func (s secretVersionService) ListWithoutData(path string) ([]*api.SecretVersion, error) {
+	return s.ListWithoutDataContext(context.Background(), path)
+}
+
+// ListWithoutDataContext is the same as ListWithoutData, but uses the given context for all requests.
+func (s secretVersionService) ListWithoutDataContext(ctx context.Context, path string) ([]*api.SecretVersion, error) {
 	secretPath, err := api.NewSecretPath(path)
 	if err != nil {
 		return nil, errio.Error(err)
 	}
 
-	return s.list(secretPath, false)
+	return s.list(ctx, secretPath, false)
 }
 
 // createSecretVersion creates a new version of an existing secret.
 // The provided key should not be a flagged key. When it is,
 // createSecretVersion will return an error.
-func (c *client) createSecretVersion(secretPath api.SecretPath, data []byte, secretKey *api.SecretKey) (*api.SecretVersion, error) {
+func (c *client) createSecretVersion(ctx context.Context, secretPath api.SecretPath, data []byte, secretKey *api.SecretKey) (*api.SecretVersion, error) {
 	var err error
 	encryptedData, err := secretKey.Key.Encrypt(data)
 	if err != nil {
@@ -170,17 +339,17 @@ func (c *client) createSecretVersion(secretPath api.SecretPath, data []byte, sec
 		SecretKeyID:   secretKey.SecretKeyID,
 	}
 
-	blindName, err := c.convertPathToBlindName(secretPath)
+	blindName, err := c.convertPathToBlindName(ctx, secretPath)
 	if err != nil {
 		return nil, errio.Error(err)
 	}
 
-	resp, err := c.httpClient.CreateSecretVersion(blindName, in)
+	resp, err := c.httpClient.CreateSecretVersion(ctx, blindName, in)
 	if err != nil {
 		return nil, errio.Error(err)
 	}
 
-	accountKey, err := c.getAccountKey()
+	accountKey, err := c.getAccountKey(ctx)
 	if err != nil {
 		return nil, errio.Error(err)
 	}
@@ -191,7 +360,7 @@ func (c *client) createSecretVersion(secretPath api.SecretPath, data []byte, sec
 // createSecret creates a new secret, including its first version.
 // It generates a secret key, encrypts the key and the secret name
 // for the accounts that need access to the secret.
-func (c *client) createSecret(secretPath api.SecretPath, data []byte) (*api.SecretVersion, error) {
+func (c *client) createSecret(ctx context.Context, secretPath api.SecretPath, data []byte) (*api.SecretVersion, error) {
 	parentPath, err := secretPath.GetParentPath()
 	if err != nil {
 		return nil, errio.Error(err)
@@ -203,7 +372,7 @@ func (c *client) createSecret(secretPath api.SecretPath, data []byte) (*api.Secr
 	}
 
 	// Get all accounts that have permission to read the secret.
-	accounts, err := c.ListDirAccounts(parentPath)
+	accounts, err := c.ListDirAccounts(ctx, parentPath)
 	if err != nil {
 		return nil, errio.Error(err)
 	}
@@ -223,7 +392,7 @@ func (c *client) createSecret(secretPath api.SecretPath, data []byte) (*api.Secr
 		return nil, errio.Error(err)
 	}
 
-	blindName, err := c.convertPathToBlindName(secretPath)
+	blindName, err := c.convertPathToBlindName(ctx, secretPath)
 	if err != nil {
 		return nil, errio.Error(err)
 	}
@@ -236,17 +405,17 @@ func (c *client) createSecret(secretPath api.SecretPath, data []byte) (*api.Secr
 		EncryptedKeys:  encryptedKeys,
 	}
 
-	parentBlindName, err := c.convertPathToBlindName(parentPath)
+	parentBlindName, err := c.convertPathToBlindName(ctx, parentPath)
 	if err != nil {
 		return nil, errio.Error(err)
 	}
 
-	resp, err := c.httpClient.CreateSecret(secretPath.GetNamespace(), secretPath.GetRepo(), parentBlindName, in)
+	resp, err := c.httpClient.CreateSecret(ctx, secretPath.GetNamespace(), secretPath.GetRepo(), parentBlindName, in)
 	if err != nil {
 		return nil, errio.Error(err)
 	}
 
-	accountKey, err := c.getAccountKey()
+	accountKey, err := c.getAccountKey(ctx)
 	if err != nil {
 		return nil, errio.Error(err)
 	}
@@ -255,8 +424,8 @@ func (c *client) createSecret(secretPath api.SecretPath, data []byte) (*api.Secr
 }
 
 // decryptSecretVersions decrypts EncryptedSecretVersions to a list of SecretVersions
-func (c *client) decryptSecretVersions(encVersions ...*api.EncryptedSecretVersion) ([]*api.SecretVersion, error) {
-	accountKey, err := c.getAccountKey()
+func (c *client) decryptSecretVersions(ctx context.Context, encVersions ...*api.EncryptedSecretVersion) ([]*api.SecretVersion, error) {
+	accountKey, err := c.getAccountKey(ctx)
 	if err != nil {
 		return nil, errio.Error(err)
 	}
@@ -0,0 +1,166 @@
+package secrethub
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/go-chi/chi"
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/api/uuid"
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/internals/crypto"
+)
+
+func TestGetManyWithData(t *testing.T) {
+	// Arrange
+	router, opts, cleanup := setup()
+	defer cleanup()
+
+	opts.Concurrency = 2
+	client := NewClient(cred1, opts)
+
+	accountKey, err := crypto.GenerateRSAPrivateKey(1024)
+	assert.OK(t, err)
+
+	privateKey, err := accountKey.ExportPEM()
+	assert.OK(t, err)
+
+	encryptedPrivateKey, err := cred1.Wrap(privateKey)
+	assert.OK(t, err)
+
+	repoIndexKey, err := crypto.GenerateSymmetricKey()
+	assert.OK(t, err)
+
+	wrappedRepoIndexKey, err := accountKey.Public().WrapBytes(repoIndexKey.Export())
+	assert.OK(t, err)
+
+	secretKey, err := crypto.GenerateSymmetricKey()
+	assert.OK(t, err)
+
+	encryptedSecretKey, err := accountKey.Public().Wrap(secretKey.Export())
+	assert.OK(t, err)
+
+	secrets := map[string]string{
+		"dev1/repo/db_password":     "password123",
+		"dev1/repo/dir/api_key":     "key456",
+		"dev1/repo/dir/api_secret":  "secret789",
+		"dev1/other_repo/something": "",
+	}
+
+	versions := make(map[string]*api.EncryptedSecretVersion)
+	for path, data := range secrets {
+		if data == "" {
+			continue
+		}
+
+		secretPath, err := api.NewSecretPath(path)
+		assert.OK(t, err)
+
+		blindName, err := secretPath.BlindName(repoIndexKey)
+		assert.OK(t, err)
+
+		encryptedName, err := accountKey.Public().Wrap([]byte(secretPath.GetSecret()))
+		assert.OK(t, err)
+
+		encryptedData, err := secretKey.Encrypt([]byte(data))
+		assert.OK(t, err)
+
+		versions[blindName] = &api.EncryptedSecretVersion{
+			SecretVersionID: uuid.New(),
+			Secret: &api.EncryptedSecret{
+				SecretID:      uuid.New(),
+				BlindName:     blindName,
+				EncryptedName: encryptedName,
+				LatestVersion: 1,
+				VersionCount:  1,
+			},
+			Version: 1,
+			SecretKey: &api.EncryptedSecretKey{
+				SecretKeyID:  uuid.New(),
+				EncryptedKey: encryptedSecretKey,
+			},
+			EncryptedData: &encryptedData,
+		}
+	}
+
+	var mutex sync.Mutex
+	calls := make(map[string]int)
+	count := func(r *http.Request) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		calls[r.URL.Path]++
+	}
+
+	router.Get("/me/key", func(w http.ResponseWriter, r *http.Request) {
+		count(r)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(&api.EncryptedAccountKey{
+			Account:             &api.Account{Name: "dev1"},
+			EncryptedPrivateKey: encryptedPrivateKey,
+		})
+	})
+
+	router.Get("/namespaces/{namespace}/repos/{repo}/keys", func(w http.ResponseWriter, r *http.Request) {
+		count(r)
+		if chi.URLParam(r, "repo") != "repo" {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(api.ErrRepoNotFound.StatusCode)
+			_ = json.NewEncoder(w).Encode(api.ErrRepoNotFound)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(&api.RepoKeys{
+			RepoIndexKey: wrappedRepoIndexKey,
+		})
+	})
+
+	router.Get("/secrets/{blind_name}/versions/{version}", func(w http.ResponseWriter, r *http.Request) {
+		count(r)
+		version, ok := versions[chi.URLParam(r, "blind_name")]
+		if !ok {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(api.ErrSecretNotFound.StatusCode)
+			_ = json.NewEncoder(w).Encode(api.ErrSecretNotFound)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(version)
+	})
+
+	paths := []string{
+		"dev1/repo/db_password",
+		"dev1/repo/dir/api_key",
+		"dev1/repo/db_password",
+		"dev1/repo/dir/api_secret",
+		"dev1/repo/does_not_exist",
+		"dev1/other_repo/something",
+		"invalid path",
+	}
+
+	// Act
+	results := client.Secrets().Versions().GetManyWithData(paths)
+
+	// Assert
+	assert.Equal(t, len(results), len(paths))
+	for i, result := range results {
+		assert.Equal(t, result.Path, paths[i])
+	}
+
+	for _, i := range []int{0, 1, 2, 3} {
+		assert.OK(t, results[i].Err)
+		assert.Equal(t, string(results[i].Version.Data), secrets[paths[i]])
+	}
+
+	assert.Equal(t, results[4].Err, api.ErrSecretNotFound)
+	assert.Equal(t, results[5].Err, api.ErrRepoNotFound)
+	assert.Equal(t, results[6].Err != nil, true)
+
+	assert.Equal(t, calls["/me/key"], 1)
+	assert.Equal(t, calls["/namespaces/dev1/repos/repo/keys"], 1)
+	assert.Equal(t, calls["/namespaces/dev1/repos/other_repo/keys"], 1)
+	assert.Equal(t, len(calls), 3+4)
+}
@@ -0,0 +1,192 @@
+// +build !production
+
+package secrethubtest
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/api/uuid"
+	"github.com/secrethub/secrethub-go/internals/auth"
+)
+
+// signupUser creates a user account with the credential in the request.
+// The request must be signed with that credential.
+func (s *Server) signupUser(r *request) (interface{}, error) {
+	in := &api.CreateUserRequest{}
+	err := r.decode(in)
+	if err != nil {
+		return nil, err
+	}
+
+	if in.Credential == nil {
+		return nil, api.ErrInvalidFingerprint
+	}
+
+	if _, exists := s.accountNames[strings.ToLower(in.Username)]; exists {
+		return nil, api.ErrUsernameAlreadyExists
+	}
+	if _, exists := s.orgs[strings.ToLower(in.Username)]; exists {
+		return nil, api.ErrNamespaceAlreadyExists
+	}
+	for _, a := range s.accounts {
+		if a.user != nil && strings.EqualFold(a.user.Email, in.Email) {
+			return nil, api.ErrUserEmailAlreadyExists
+		}
+	}
+	if _, exists := s.credentials[in.Credential.Fingerprint]; exists {
+		return nil, api.ErrCredentialAlreadyExists
+	}
+
+	createdAt := now()
+	c := newCredential(uuid.New(), in.Credential)
+
+	// Verify the request was signed with the credential that is being registered.
+	verifier := auth.NewMethodSignature(singleCredentialGetter{credential: c.credential})
+	_, err = verifier.Verify(r.Request)
+	if err != nil {
+		return nil, err
+	}
+
+	a := &account{
+		id:          c.credential.AccountID,
+		name:        in.Username,
+		accountType: accountTypeUser,
+		createdAt:   createdAt,
+		user: &api.User{
+			AccountID: c.credential.AccountID,
+			Username:  in.Username,
+			FullName:  in.FullName,
+			Email:     in.Email,
+			CreatedAt: &createdAt,
+		},
+	}
+	s.addAccount(a, c)
+
+	return a.user, nil
+}
+
+// getUser returns the public details of a user.
+func (s *Server) getUser(r *request) (interface{}, error) {
+	a, ok := s.accountNames[strings.ToLower(r.param("username"))]
+	if !ok || !a.isUser() {
+		return nil, api.ErrUserNotFound
+	}
+	return a.toUser(), nil
+}
+
+// getMyUser returns the user that made the request, including its private details.
+func (s *Server) getMyUser(r *request) (interface{}, error) {
+	if !r.caller.isUser() {
+		return nil, api.ErrNotAUser
+	}
+	user := *r.caller.user
+	user.PublicKey = r.caller.publicKey
+	return &user, nil
+}
+
+// listMyRepos returns all repositories the caller is a member of.
+func (s *Server) listMyRepos(r *request) (interface{}, error) {
+	repos := []*api.Repo{}
+	for _, repo := range s.repos {
+		if _, isMember := repo.members[*r.caller.id]; isMember {
+			repos = append(repos, repo.toAPI())
+		}
+	}
+	sort.Sort(api.SortRepoByName(repos))
+	return repos, nil
+}
+
+// getMyAccountKey returns the account key of the caller, encrypted for the credential used.
+func (s *Server) getMyAccountKey(r *request) (interface{}, error) {
+	if r.credential.encryptedAccountKey == nil {
+		return nil, api.ErrCredentialNotKeyed
+	}
+	return &api.EncryptedAccountKey{
+		Account:             r.caller.toAPI(),
+		PublicKey:           r.caller.publicKey,
+		EncryptedPrivateKey: *r.credential.encryptedAccountKey,
+		Credential:          r.credential.credential,
+	}, nil
+}
+
+// createAccountKey stores the account key of the caller, encrypted for one of its credentials.
+func (s *Server) createAccountKey(r *request) (interface{}, error) {
+	in := &api.CreateAccountKeyRequest{}
+	err := r.decode(in)
+	if err != nil {
+		return nil, err
+	}
+
+	c, ok := s.credentials[r.param("fingerprint")]
+	if !ok || !uuid.Equal(c.credential.AccountID, r.caller.id) {
+		return nil, api.ErrCredentialNotFound
+	}
+
+	if c.encryptedAccountKey != nil {
+		return nil, api.ErrPrivateKeyAlreadyExists
+	}
+
+	if r.caller.publicKey != nil && string(r.caller.publicKey) != string(in.PublicKey) {
+		return nil, api.ErrPublicAccountKeyConflict
+	}
+
+	r.caller.publicKey = in.PublicKey
+	encryptedKey := in.EncryptedPrivateKey
+	c.encryptedAccountKey = &encryptedKey
+
+	return &api.EncryptedAccountKey{
+		Account:             r.caller.toAPI(),
+		PublicKey:           r.caller.publicKey,
+		EncryptedPrivateKey: encryptedKey,
+		Credential:          c.credential,
+	}, nil
+}
+
+// getAccount returns the account with the given name.
+func (s *Server) getAccount(r *request) (interface{}, error) {
+	a, err := s.lookupAccount(r.param("account_name"))
+	if err != nil {
+		return nil, err
+	}
+	return a.toAPI(), nil
+}
+
+// addAccount adds an account with its first credential to the server.
+func (s *Server) addAccount(a *account, c *credential) {
+	s.accounts[*a.id] = a
+	s.accountNames[strings.ToLower(a.name)] = a
+	s.credentials[c.credential.Fingerprint] = c
+	if a.service != nil {
+		s.services[strings.ToLower(a.service.ServiceID)] = a
+	}
+}
+
+// newCredential creates a credential for the account with the given id.
+func newCredential(accountID *uuid.UUID, in *api.CreateCredentialRequest) *credential {
+	return &credential{
+		credential: &api.Credential{
+			AccountID:   accountID,
+			Type:        in.Type,
+			CreatedAt:   now(),
+			Fingerprint: in.Fingerprint,
+			Name:        in.Name,
+			Verifier:    in.Verifier,
+		},
+	}
+}
+
+// singleCredentialGetter returns a single credential, which is
+// used to verify requests signed with a credential that is not yet registered.
+type singleCredentialGetter struct {
+	credential *api.Credential
+}
+
+// GetCredential returns the credential when it has the given fingerprint.
+func (g singleCredentialGetter) GetCredential(fingerprint string) (*api.Credential, error) {
+	if fingerprint != g.credential.Fingerprint {
+		return nil, api.ErrCredentialNotFound
+	}
+	return g.credential, nil
+}
@@ -0,0 +1,421 @@
+// +build !production
+
+package secrethubtest
+
+import (
+	"sort"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/api/uuid"
+	"github.com/secrethub/secrethub-go/internals/crypto"
+)
+
+// createDir creates a directory in a repository. This requires write
+// permission on the parent directory.
+func (s *Server) createDir(r *request) (interface{}, error) {
+	in := &api.CreateDirRequest{}
+	err := r.decode(in)
+	if err != nil {
+		return nil, err
+	}
+
+	repo, err := s.lookupRepo(r.caller, r.param("namespace"), r.param("repo_name"))
+	if err != nil {
+		return nil, err
+	}
+
+	parent, ok := s.dirs[in.ParentBlindName]
+	if !ok || parent.repo != repo || parent.permission(r.caller.id) < api.PermissionRead {
+		return nil, api.ErrParentDirNotFound
+	}
+	if parent.permission(r.caller.id) < api.PermissionWrite {
+		return nil, api.ErrForbidden
+	}
+	if s.blindNameExists(in.BlindName) {
+		return nil, api.ErrDirAlreadyExists
+	}
+
+	names := make(map[uuid.UUID]crypto.CiphertextRSA)
+	encryptedFor := make(map[uuid.UUID]bool)
+	for _, name := range in.EncryptedNames {
+		names[*name.AccountID] = name.EncryptedName
+		encryptedFor[*name.AccountID] = true
+	}
+	err = checkEncryptedFor(parent, encryptedFor)
+	if err != nil {
+		return nil, err
+	}
+
+	createdAt := now()
+	d := &dir{
+		id:             uuid.New(),
+		blindName:      in.BlindName,
+		repo:           repo,
+		parent:         parent,
+		names:          names,
+		dirs:           make(map[uuid.UUID]*dir),
+		secrets:        make(map[uuid.UUID]*secret),
+		rules:          make(map[uuid.UUID]*api.AccessRule),
+		createdAt:      createdAt,
+		lastModifiedAt: createdAt,
+	}
+	parent.dirs[*d.id] = d
+	parent.lastModifiedAt = createdAt
+	s.dirs[d.blindName] = d
+
+	encryptedDir, _ := d.encrypted(r.caller.id)
+	return encryptedDir, nil
+}
+
+// getTree returns a directory with its subdirectories and secrets, up to the depth
+// given in the query. When the ancestors query parameter is true, the parents of the
+// directory are included as well.
+func (s *Server) getTree(r *request) (interface{}, error) {
+	d, err := s.lookupDir(r.caller, r.param("dir_blind_name"), api.PermissionRead)
+	if err != nil {
+		return nil, err
+	}
+
+	depth, err := r.queryInt("depth", -1)
+	if err != nil {
+		return nil, err
+	}
+
+	tree := &api.EncryptedTree{
+		Directories: make(map[uuid.UUID]*api.EncryptedDir),
+		Secrets:     []*api.EncryptedSecret{},
+	}
+
+	d.walk(depth, func(current *dir, _ int) {
+		encryptedDir, ok := current.encrypted(r.caller.id)
+		if !ok {
+			return
+		}
+		tree.Directories[*current.id] = encryptedDir
+
+		for _, sec := range current.secrets {
+			encryptedSecret, ok := sec.encrypted(r.caller.id)
+			if ok {
+				tree.Secrets = append(tree.Secrets, encryptedSecret)
+			}
+		}
+	})
+
+	if r.queryBool("ancestors") {
+		for _, ancestor := range d.ancestors() {
+			encryptedDir, ok := ancestor.encrypted(r.caller.id)
+			if !ok {
+				return nil, api.ErrDirNotFound
+			}
+			tree.Directories[*ancestor.id] = encryptedDir
+		}
+	} else {
+		// The requested directory is the root of the returned tree.
+		tree.Directories[*d.id].ParentID = nil
+	}
+
+	return tree, nil
+}
+
+// deleteDir removes a directory and all its contents. This requires write
+// permission on the directory. The root directory of a repository cannot be removed.
+func (s *Server) deleteDir(r *request) (interface{}, error) {
+	d, err := s.lookupDir(r.caller, r.param("dir_blind_name"), api.PermissionWrite)
+	if err != nil {
+		return nil, err
+	}
+	if d.parent == nil {
+		return nil, api.ErrCannotRemoveRootDir
+	}
+
+	d.walk(-1, func(current *dir, _ int) {
+		for _, sec := range current.secrets {
+			s.logEvent(r, api.AuditActionDelete, d.repo, &event{
+				subjectType:   api.AuditSubjectSecret,
+				subjectSecret: sec,
+			})
+		}
+	})
+
+	s.removeDir(d)
+	return nil, nil
+}
+
+// listDirAccounts returns the accounts that have read access on a directory.
+func (s *Server) listDirAccounts(r *request) (interface{}, error) {
+	d, err := s.lookupDir(r.caller, r.param("dir_blind_name"), api.PermissionRead)
+	if err != nil {
+		return nil, err
+	}
+
+	accounts := []*api.Account{}
+	for _, a := range d.accounts() {
+		accounts = append(accounts, a.toAPI())
+	}
+	return accounts, nil
+}
+
+// getAccessLevel returns the permission an account has on a directory.
+func (s *Server) getAccessLevel(r *request) (interface{}, error) {
+	d, err := s.lookupDir(r.caller, r.param("dir_blind_name"), api.PermissionRead)
+	if err != nil {
+		return nil, err
+	}
+
+	a, err := s.lookupRepoAccount(d.repo, r.param("account_name"))
+	if err != nil {
+		return nil, err
+	}
+
+	return &api.AccessLevel{
+		Account:    a.toAPI(),
+		AccountID:  a.id,
+		DirID:      d.id,
+		Permission: d.permission(a.id),
+	}, nil
+}
+
+// listAccessRules returns the access rules on a directory and its subdirectories,
+// up to the depth given in the query. When the ancestors query parameter is true,
+// the access rules on the parents of the directory are included as well.
+func (s *Server) listAccessRules(r *request) (interface{}, error) {
+	d, err := s.lookupDir(r.caller, r.param("dir_blind_name"), api.PermissionRead)
+	if err != nil {
+		return nil, err
+	}
+
+	depth, err := r.queryInt("depth", -1)
+	if err != nil {
+		return nil, err
+	}
+
+	rules := []*api.AccessRule{}
+	if r.queryBool("ancestors") {
+		for _, ancestor := range d.ancestors() {
+			for _, rule := range ancestor.rules {
+				rules = append(rules, rule)
+			}
+		}
+	}
+	d.walk(depth, func(current *dir, _ int) {
+		for _, rule := range current.rules {
+			rules = append(rules, rule)
+		}
+	})
+
+	sort.Sort(api.SortAccessRules(rules))
+	return rules, nil
+}
+
+// getAccessRule returns the access rule of an account on a directory.
+func (s *Server) getAccessRule(r *request) (interface{}, error) {
+	d, err := s.lookupDir(r.caller, r.param("dir_blind_name"), api.PermissionRead)
+	if err != nil {
+		return nil, err
+	}
+
+	a, err := s.lookupAccount(r.param("account_name"))
+	if err != nil {
+		return nil, err
+	}
+
+	rule, ok := d.rules[*a.id]
+	if !ok {
+		return nil, api.ErrAccessRuleNotFound
+	}
+	return rule, nil
+}
+
+// createAccessRule creates an access rule for an account on a directory. This requires
+// admin permission on the directory. When the account does not yet have read access
+// on the directory, the request must contain the names of the directory, all its
+// subdirectories and all its secrets, together with the secret keys, encrypted for
+// the account.
+func (s *Server) createAccessRule(r *request) (interface{}, error) {
+	in := &api.CreateAccessRuleRequest{}
+	err := r.decode(in)
+	if err != nil {
+		return nil, err
+	}
+
+	d, err := s.lookupDir(r.caller, r.param("dir_blind_name"), api.PermissionAdmin)
+	if err != nil {
+		return nil, err
+	}
+
+	a, err := s.lookupRepoAccount(d.repo, r.param("account_name"))
+	if err != nil {
+		return nil, err
+	}
+	if _, exists := d.rules[*a.id]; exists {
+		return nil, api.ErrAccessRuleAlreadyExists
+	}
+
+	if d.permission(a.id) < api.PermissionRead && in.Permission >= api.PermissionRead {
+		err = s.grantAccess(d, a, in)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	rule := newAccessRule(d, a, in.Permission)
+	d.rules[*a.id] = rule
+	return rule, nil
+}
+
+// grantAccess stores the names and keys in the request for the account, after
+// checking the request covers every directory and secret in the directory.
+func (s *Server) grantAccess(d *dir, a *account, in *api.CreateAccessRuleRequest) error {
+	dirNames := make(map[uuid.UUID]crypto.CiphertextRSA)
+	for _, encryptedDir := range in.EncryptedDirs {
+		if !uuid.Equal(encryptedDir.AccountID, a.id) {
+			return api.ErrInvalidAccountID
+		}
+		dirNames[*encryptedDir.NodeID] = encryptedDir.EncryptedName
+	}
+
+	secretNames := make(map[uuid.UUID]crypto.CiphertextRSA)
+	secretKeys := make(map[uuid.UUID][]api.SecretKeyMemberRequest)
+	for _, encryptedSecret := range in.EncryptedSecrets {
+		if !uuid.Equal(encryptedSecret.Name.AccountID, a.id) {
+			return api.ErrInvalidAccountID
+		}
+		secretNames[*encryptedSecret.Name.NodeID] = encryptedSecret.Name.EncryptedName
+		secretKeys[*encryptedSecret.Name.NodeID] = encryptedSecret.Keys
+	}
+
+	var missing bool
+	d.walk(-1, func(current *dir, _ int) {
+		if _, ok := dirNames[*current.id]; !ok {
+			missing = true
+		}
+		for _, sec := range current.secrets {
+			if _, ok := secretNames[*sec.id]; !ok {
+				missing = true
+			}
+		}
+	})
+	if missing {
+		return api.ErrNotEncryptedForAccounts
+	}
+
+	// The names of the parent directories are stored too,
+	// so the account can resolve the full path of the directory.
+	for _, current := range d.ancestors() {
+		if name, ok := dirNames[*current.id]; ok {
+			current.names[*a.id] = name
+		}
+	}
+
+	d.walk(-1, func(current *dir, _ int) {
+		current.names[*a.id] = dirNames[*current.id]
+		for _, sec := range current.secrets {
+			sec.names[*a.id] = secretNames[*sec.id]
+			for _, key := range secretKeys[*sec.id] {
+				secretKey, ok := sec.key(key.SecretKeyID)
+				if ok {
+					secretKey.keys[*a.id] = key.EncryptedKey
+				}
+			}
+		}
+	})
+
+	return nil
+}
+
+// updateAccessRule changes the permission of an existing access rule.
+// This requires admin permission on the directory.
+func (s *Server) updateAccessRule(r *request) (interface{}, error) {
+	in := &api.UpdateAccessRuleRequest{}
+	err := r.decode(in)
+	if err != nil {
+		return nil, err
+	}
+
+	d, err := s.lookupDir(r.caller, r.param("dir_blind_name"), api.PermissionAdmin)
+	if err != nil {
+		return nil, err
+	}
+
+	a, err := s.lookupAccount(r.param("account_name"))
+	if err != nil {
+		return nil, err
+	}
+
+	rule, ok := d.rules[*a.id]
+	if !ok {
+		return nil, api.ErrAccessRuleNotFound
+	}
+	if rule.Permission == api.PermissionAdmin && in.Permission < api.PermissionAdmin && d.isLastRootAdmin(a) {
+		return nil, api.ErrCannotRemoveLastRootAdmin
+	}
+
+	rule.Permission = in.Permission
+	rule.LastChangedAt = now()
+	return rule, nil
+}
+
+// deleteAccessRule removes the access rule of an account on a directory.
+// This requires admin permission on the directory.
+func (s *Server) deleteAccessRule(r *request) (interface{}, error) {
+	d, err := s.lookupDir(r.caller, r.param("dir_blind_name"), api.PermissionAdmin)
+	if err != nil {
+		return nil, err
+	}
+
+	a, err := s.lookupAccount(r.param("account_name"))
+	if err != nil {
+		return nil, err
+	}
+
+	rule, ok := d.rules[*a.id]
+	if !ok {
+		return nil, api.ErrAccessRuleNotFound
+	}
+	if rule.Permission == api.PermissionAdmin && d.isLastRootAdmin(a) {
+		return nil, api.ErrCannotRemoveLastRootAdmin
+	}
+
+	delete(d.rules, *a.id)
+	return nil, nil
+}
+
+// isLastRootAdmin returns whether the directory is the root directory of its
+// repository and the account is the only one with an admin rule on it.
+func (d *dir) isLastRootAdmin(a *account) bool {
+	if d.parent != nil {
+		return false
+	}
+	for accountID, rule := range d.rules {
+		if rule.Permission == api.PermissionAdmin && accountID != *a.id {
+			return false
+		}
+	}
+	return true
+}
+
+// lookupRepoAccount returns the account with the given name when it is a member of the repository.
+func (s *Server) lookupRepoAccount(repo *repo, name string) (*account, error) {
+	a, err := s.lookupAccount(name)
+	if err != nil {
+		return nil, err
+	}
+	if _, isMember := repo.members[*a.id]; !isMember {
+		return nil, api.ErrNotMemberOfRepo
+	}
+	return a, nil
+}
+
+// newAccessRule creates an access rule for the account on the directory.
+func newAccessRule(d *dir, a *account, permission api.Permission) *api.AccessRule {
+	createdAt := now()
+	return &api.AccessRule{
+		Account:       a.toAPI(),
+		AccountID:     a.id,
+		DirID:         d.id,
+		RepoID:        d.repo.repo.RepoID,
+		Permission:    permission,
+		CreatedAt:     createdAt,
+		LastChangedAt: createdAt,
+	}
+}
@@ -0,0 +1,193 @@
+// +build !production
+
+package secrethubtest
+
+import (
+	"net"
+	"strings"
+	"time"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/api/uuid"
+)
+
+// event is an audit event. Its subject is rendered for the account that
+// requests the event, because secret names are encrypted for every account.
+type event struct {
+	id        *uuid.UUID
+	action    api.AuditAction
+	ipAddress string
+	loggedAt  time.Time
+	repo      *repo
+	actor     *account
+
+	subjectType    api.AuditSubjectType
+	subjectAccount *account
+	subjectRepo    *repo
+	subjectSecret  *secret
+	subjectVersion *secretVersion
+	subjectKey     *secretKey
+}
+
+// logEvent records an audit event for an action of the caller in a repository.
+func (s *Server) logEvent(r *request, action api.AuditAction, repo *repo, e *event) {
+	e.id = uuid.New()
+	e.action = action
+	e.loggedAt = now()
+	e.repo = repo
+	e.actor = r.caller
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err == nil {
+		e.ipAddress = host
+	}
+
+	s.events = append(s.events, e)
+}
+
+// accountExists returns whether the account has not been removed from the server.
+func (s *Server) accountExists(a *account) bool {
+	_, ok := s.accounts[*a.id]
+	return ok
+}
+
+// render returns the event as an api.Audit for the given account.
+// It returns false when the subject of the event cannot be shown to the account,
+// because the name of the secret is not encrypted for the account.
+func (s *Server) render(e *event, caller *account) (*api.Audit, bool) {
+	audit := &api.Audit{
+		EventID:   e.id,
+		Action:    e.action,
+		IPAddress: e.ipAddress,
+		LoggedAt:  e.loggedAt,
+		Repo:      *e.repo.repo.Trim(),
+	}
+
+	if s.accountExists(e.actor) {
+		if e.actor.isUser() {
+			audit.Actor = *e.actor.user.ToAuditActor()
+		} else {
+			audit.Actor = *e.actor.service.ToAuditActor()
+		}
+	} else {
+		audit.Actor = api.AuditActor{
+			ActorID: e.actor.id,
+			Deleted: true,
+			Type:    api.AuditSubjectAccount,
+		}
+	}
+
+	switch e.subjectType {
+	case api.AuditSubjectUser, api.AuditSubjectService:
+		if !s.accountExists(e.subjectAccount) {
+			audit.Subject = api.AuditSubject{
+				SubjectID: e.subjectAccount.id,
+				Deleted:   true,
+				Type:      api.AuditSubjectAccount,
+			}
+		} else if e.subjectAccount.isUser() {
+			audit.Subject = *e.subjectAccount.user.ToAuditSubject()
+		} else {
+			audit.Subject = *e.subjectAccount.service.ToAuditSubject()
+		}
+	case api.AuditSubjectRepo:
+		audit.Subject = *e.subjectRepo.repo.ToAuditSubject()
+		audit.Subject.Deleted = e.subjectRepo.deleted
+	case api.AuditSubjectSecret:
+		if e.subjectSecret.deleted {
+			audit.Subject = api.AuditSubject{
+				SubjectID: e.subjectSecret.id,
+				Deleted:   true,
+				Type:      api.AuditSubjectSecret,
+			}
+			break
+		}
+		encryptedSecret, ok := e.subjectSecret.encrypted(caller.id)
+		if !ok {
+			return nil, false
+		}
+		audit.Subject = *encryptedSecret.ToAuditSubject()
+	case api.AuditSubjectSecretVersion:
+		if e.subjectVersion.deleted {
+			audit.Subject = api.AuditSubject{
+				SubjectID: e.subjectVersion.id,
+				Deleted:   true,
+				Type:      api.AuditSubjectSecretVersion,
+			}
+			break
+		}
+		encryptedVersion, ok := e.subjectVersion.encrypted(caller.id, false)
+		if !ok {
+			return nil, false
+		}
+		audit.Subject = *encryptedVersion.ToAuditSubject()
+	case api.AuditSubjectSecretKey:
+		audit.Subject = api.AuditSubject{
+			SubjectID: e.subjectKey.id,
+			Type:      api.AuditSubjectSecretKey,
+		}
+	}
+
+	return audit, true
+}
+
+// listEvents returns the events for which match returns true, rendered for the caller
+// and filtered on the subject types given in the subject_types query parameter.
+// When the per_page query parameter is set, at most that many events are returned,
+// starting after the event with the ID given in the starting_after query parameter.
+// When that event is not listed, api.ErrNotFound is returned.
+func (s *Server) listEvents(r *request, match func(e *event) bool) ([]*api.Audit, error) {
+	perPage, err := r.queryInt("per_page", 0)
+	if err != nil || perPage < 0 {
+		return nil, api.ErrBadRequest
+	}
+
+	var startingAfter *uuid.UUID
+	if value := r.URL.Query().Get("starting_after"); value != "" {
+		startingAfter, err = uuid.FromString(value)
+		if err != nil {
+			return nil, api.ErrBadRequest
+		}
+	}
+
+	subjectTypes := make(map[api.AuditSubjectType]bool)
+	for _, t := range strings.Split(r.URL.Query().Get("subject_types"), ",") {
+		if t != "" {
+			subjectTypes[api.AuditSubjectType(t)] = true
+		}
+	}
+
+	events := []*api.Audit{}
+	for _, e := range s.events {
+		if !match(e) {
+			continue
+		}
+		if len(subjectTypes) > 0 && !subjectTypes[e.subjectType] {
+			continue
+		}
+
+		audit, ok := s.render(e, r.caller)
+		if ok {
+			events = append(events, audit)
+		}
+	}
+
+	if startingAfter != nil {
+		start := -1
+		for i, audit := range events {
+			if uuid.Equal(audit.EventID, startingAfter) {
+				start = i + 1
+				break
+			}
+		}
+		if start < 0 {
+			return nil, api.ErrNotFound
+		}
+		events = events[start:]
+	}
+
+	if perPage > 0 && len(events) > perPage {
+		events = events[:perPage]
+	}
+	return events, nil
+}
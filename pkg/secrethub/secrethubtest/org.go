@@ -0,0 +1,296 @@
+// +build !production
+
+package secrethubtest
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/api/uuid"
+)
+
+// createOrg creates an organization of which the caller becomes an admin.
+func (s *Server) createOrg(r *request) (interface{}, error) {
+	in := &api.CreateOrgRequest{}
+	err := r.decode(in)
+	if err != nil {
+		return nil, err
+	}
+
+	if !r.caller.isUser() {
+		return nil, api.ErrNotAUser
+	}
+	if _, exists := s.orgs[strings.ToLower(in.Name)]; exists {
+		return nil, api.ErrOrgAlreadyExists
+	}
+	if _, exists := s.accountNames[strings.ToLower(in.Name)]; exists {
+		return nil, api.ErrNamespaceAlreadyExists
+	}
+
+	o := &org{
+		org: &api.Org{
+			OrgID:       uuid.New(),
+			Name:        in.Name,
+			Description: in.Description,
+			CreatedAt:   now(),
+		},
+		members: make(map[uuid.UUID]*api.OrgMember),
+	}
+	o.members[*r.caller.id] = newOrgMember(o, r.caller, api.OrgRoleAdmin)
+	s.orgs[strings.ToLower(in.Name)] = o
+
+	return o.org, nil
+}
+
+// listMyOrgs returns the organizations the caller is a member of.
+func (s *Server) listMyOrgs(r *request) (interface{}, error) {
+	orgs := []*api.Org{}
+	for _, o := range s.orgs {
+		if _, isMember := o.members[*r.caller.id]; isMember {
+			orgs = append(orgs, o.org)
+		}
+	}
+	sort.Sort(api.SortOrgByName(orgs))
+	return orgs, nil
+}
+
+// getOrg returns an organization with its members.
+func (s *Server) getOrg(r *request) (interface{}, error) {
+	o, err := s.lookupOrg(r.caller, r.param("org_name"))
+	if err != nil {
+		return nil, err
+	}
+
+	result := *o.org
+	result.Members = o.listMembers()
+	return &result, nil
+}
+
+// deleteOrg removes an organization with all its repositories.
+// This requires the caller to be an admin of the organization.
+func (s *Server) deleteOrg(r *request) (interface{}, error) {
+	o, err := s.lookupOrgAsAdmin(r.caller, r.param("org_name"))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, repo := range s.reposInNamespace(o.org.Name) {
+		s.removeRepo(repo)
+	}
+	delete(s.orgs, strings.ToLower(o.org.Name))
+
+	return nil, nil
+}
+
+// listOrgMembers returns the members of an organization.
+func (s *Server) listOrgMembers(r *request) (interface{}, error) {
+	o, err := s.lookupOrg(r.caller, r.param("org_name"))
+	if err != nil {
+		return nil, err
+	}
+	return o.listMembers(), nil
+}
+
+// createOrgMember adds a user to an organization.
+// This requires the caller to be an admin of the organization.
+func (s *Server) createOrgMember(r *request) (interface{}, error) {
+	in := &api.CreateOrgMemberRequest{}
+	err := r.decode(in)
+	if err != nil {
+		return nil, err
+	}
+
+	o, err := s.lookupOrgAsAdmin(r.caller, r.param("org_name"))
+	if err != nil {
+		return nil, err
+	}
+
+	a, ok := s.accountNames[strings.ToLower(in.Username)]
+	if !ok || !a.isUser() {
+		return nil, api.ErrUserNotFound
+	}
+	if _, isMember := o.members[*a.id]; isMember {
+		return nil, api.ErrOrgMemberAlreadyExists
+	}
+
+	member := newOrgMember(o, a, in.Role)
+	o.members[*a.id] = member
+	return member, nil
+}
+
+// getOrgMember returns a member of an organization.
+func (s *Server) getOrgMember(r *request) (interface{}, error) {
+	o, err := s.lookupOrg(r.caller, r.param("org_name"))
+	if err != nil {
+		return nil, err
+	}
+
+	member, _, err := s.lookupOrgMember(o, r.param("username"))
+	if err != nil {
+		return nil, err
+	}
+	return member, nil
+}
+
+// updateOrgMember changes the role of a member of an organization.
+// This requires the caller to be an admin of the organization.
+func (s *Server) updateOrgMember(r *request) (interface{}, error) {
+	in := &api.UpdateOrgMemberRequest{}
+	err := r.decode(in)
+	if err != nil {
+		return nil, err
+	}
+
+	o, err := s.lookupOrgAsAdmin(r.caller, r.param("org_name"))
+	if err != nil {
+		return nil, err
+	}
+
+	member, _, err := s.lookupOrgMember(o, r.param("username"))
+	if err != nil {
+		return nil, err
+	}
+	if member.Role == api.OrgRoleAdmin && in.Role != api.OrgRoleAdmin && o.adminCount() == 1 {
+		return nil, api.ErrCannotRemoveLastOrgAdmin
+	}
+
+	member.Role = in.Role
+	member.LastChangedAt = now()
+	return member, nil
+}
+
+// revokeOrgMember removes a member from an organization and from all repositories
+// of the organization. This requires the caller to be an admin of the organization.
+// When the dry_run query parameter is true, only the effect of the revocation is returned.
+func (s *Server) revokeOrgMember(r *request) (interface{}, error) {
+	opts := &api.RevokeOpts{}
+	opts.Unmarshal(r.URL.Query())
+
+	o, err := s.lookupOrgAsAdmin(r.caller, r.param("org_name"))
+	if err != nil {
+		return nil, err
+	}
+
+	member, a, err := s.lookupOrgMember(o, r.param("username"))
+	if err != nil {
+		return nil, err
+	}
+	if member.Role == api.OrgRoleAdmin && o.adminCount() == 1 {
+		return nil, api.ErrCannotRemoveLastOrgAdmin
+	}
+
+	resp := &api.RevokeOrgResponse{
+		DryRun:       opts.DryRun,
+		Repos:        []*api.RevokeRepoResponse{},
+		StatusCounts: make(map[string]int),
+	}
+	for _, repo := range s.reposInNamespace(o.org.Name) {
+		if _, isMember := repo.members[*a.id]; !isMember {
+			continue
+		}
+
+		repoResp := s.revokeRepoMember(repo, a, opts.DryRun)
+		resp.Repos = append(resp.Repos, repoResp)
+		resp.StatusCounts[repoResp.Status]++
+
+		if !opts.DryRun {
+			s.logEvent(r, api.AuditActionDelete, repo, &event{
+				subjectType:    api.AuditSubjectUser,
+				subjectAccount: a,
+			})
+		}
+	}
+
+	if !opts.DryRun {
+		delete(o.members, *a.id)
+	}
+
+	return resp, nil
+}
+
+// lookupOrg returns the organization with the given name, when the caller is a member of it.
+func (s *Server) lookupOrg(caller *account, name string) (*org, error) {
+	o, ok := s.orgs[strings.ToLower(name)]
+	if !ok {
+		return nil, api.ErrOrgNotFound
+	}
+	if _, isMember := o.members[*caller.id]; !isMember {
+		return nil, api.ErrOrgNotFound
+	}
+	return o, nil
+}
+
+// lookupOrgAsAdmin returns the organization with the given name, when the caller is an admin of it.
+func (s *Server) lookupOrgAsAdmin(caller *account, name string) (*org, error) {
+	o, err := s.lookupOrg(caller, name)
+	if err != nil {
+		return nil, err
+	}
+	if o.members[*caller.id].Role != api.OrgRoleAdmin {
+		return nil, api.ErrForbidden
+	}
+	return o, nil
+}
+
+// lookupOrgMember returns the membership and the account of the user with the given name.
+func (s *Server) lookupOrgMember(o *org, username string) (*api.OrgMember, *account, error) {
+	a, ok := s.accountNames[strings.ToLower(username)]
+	if !ok || !a.isUser() {
+		return nil, nil, api.ErrUserNotFound
+	}
+
+	member, ok := o.members[*a.id]
+	if !ok {
+		return nil, nil, api.ErrOrgMemberNotFound
+	}
+	return member, a, nil
+}
+
+// reposInNamespace returns the repositories in the namespace, sorted by name.
+func (s *Server) reposInNamespace(namespace string) []*repo {
+	var repos []*repo
+	for _, repo := range s.repos {
+		if strings.EqualFold(repo.namespace, namespace) {
+			repos = append(repos, repo)
+		}
+	}
+	sort.Slice(repos, func(i, j int) bool {
+		return repos[i].repo.Name < repos[j].repo.Name
+	})
+	return repos
+}
+
+// listMembers returns the members of the organization, sorted by username.
+func (o *org) listMembers() []*api.OrgMember {
+	members := []*api.OrgMember{}
+	for _, member := range o.members {
+		members = append(members, member)
+	}
+	sort.Sort(api.SortOrgMemberByUsername(members))
+	return members
+}
+
+// adminCount returns the number of admins of the organization.
+func (o *org) adminCount() int {
+	count := 0
+	for _, member := range o.members {
+		if member.Role == api.OrgRoleAdmin {
+			count++
+		}
+	}
+	return count
+}
+
+// newOrgMember creates a membership of the organization for the user account.
+func newOrgMember(o *org, a *account, role string) *api.OrgMember {
+	createdAt := now()
+	return &api.OrgMember{
+		OrgID:         o.org.OrgID,
+		AccountID:     a.id,
+		Role:          role,
+		CreatedAt:     createdAt,
+		LastChangedAt: createdAt,
+		User:          a.toUser(),
+	}
+}
@@ -0,0 +1,311 @@
+// +build !production
+
+package secrethubtest
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/api/uuid"
+	"github.com/secrethub/secrethub-go/internals/crypto"
+)
+
+// createRepo creates a repository with a root directory, of which the caller
+// becomes a member with admin permission on the root directory.
+func (s *Server) createRepo(r *request) (interface{}, error) {
+	in := &api.CreateRepoRequest{}
+	err := r.decode(in)
+	if err != nil {
+		return nil, err
+	}
+
+	namespace := r.param("namespace")
+	if !s.namespaceExists(namespace) {
+		return nil, api.ErrNamespaceNotFound
+	}
+	if !s.canCreateInNamespace(r.caller, namespace) {
+		return nil, api.ErrForbidden
+	}
+	if _, exists := s.repos[repoKey(namespace, in.Name)]; exists {
+		return nil, api.ErrRepoAlreadyExists
+	}
+	if s.blindNameExists(in.RootDir.BlindName) {
+		return nil, api.ErrDirAlreadyExists
+	}
+
+	names := make(map[uuid.UUID]crypto.CiphertextRSA)
+	for _, name := range in.RootDir.EncryptedNames {
+		names[*name.AccountID] = name.EncryptedName
+	}
+	if _, ok := names[*r.caller.id]; !ok {
+		return nil, api.ErrNotEncryptedForAccounts
+	}
+
+	createdAt := now()
+	repo := &repo{
+		repo: &api.Repo{
+			RepoID:         uuid.New(),
+			Owner:          namespace,
+			Name:           in.Name,
+			CreatedAt:      &createdAt,
+			LastModifiedAt: &createdAt,
+			Status:         api.StatusOK,
+		},
+		namespace: namespace,
+		members:   make(map[uuid.UUID]*repoMember),
+	}
+
+	repo.rootDir = &dir{
+		id:             uuid.New(),
+		blindName:      in.RootDir.BlindName,
+		repo:           repo,
+		names:          names,
+		dirs:           make(map[uuid.UUID]*dir),
+		secrets:        make(map[uuid.UUID]*secret),
+		rules:          make(map[uuid.UUID]*api.AccessRule),
+		createdAt:      createdAt,
+		lastModifiedAt: createdAt,
+	}
+	repo.rootDir.rules[*r.caller.id] = newAccessRule(repo.rootDir, r.caller, api.PermissionAdmin)
+
+	repo.members[*r.caller.id] = newRepoMember(repo, r.caller, in.RepoMember)
+
+	s.repos[repoKey(namespace, in.Name)] = repo
+	s.dirs[repo.rootDir.blindName] = repo.rootDir
+
+	s.logEvent(r, api.AuditActionCreate, repo, &event{
+		subjectType: api.AuditSubjectRepo,
+		subjectRepo: repo,
+	})
+
+	return repo.toAPI(), nil
+}
+
+// listRepos returns the repositories in a namespace of which the caller is a member.
+func (s *Server) listRepos(r *request) (interface{}, error) {
+	namespace := r.param("namespace")
+	if !s.namespaceExists(namespace) {
+		return nil, api.ErrNamespaceNotFound
+	}
+
+	repos := []*api.Repo{}
+	for _, repo := range s.repos {
+		if !strings.EqualFold(repo.namespace, namespace) {
+			continue
+		}
+		if _, isMember := repo.members[*r.caller.id]; isMember {
+			repos = append(repos, repo.toAPI())
+		}
+	}
+	sort.Sort(api.SortRepoByName(repos))
+	return repos, nil
+}
+
+// getRepo returns a repository.
+func (s *Server) getRepo(r *request) (interface{}, error) {
+	repo, err := s.lookupRepo(r.caller, r.param("namespace"), r.param("repo_name"))
+	if err != nil {
+		return nil, err
+	}
+	return repo.toAPI(), nil
+}
+
+// deleteRepo removes a repository. This requires admin permission on the root directory.
+func (s *Server) deleteRepo(r *request) (interface{}, error) {
+	repo, err := s.lookupRepo(r.caller, r.param("namespace"), r.param("repo_name"))
+	if err != nil {
+		return nil, err
+	}
+	if repo.rootDir.permission(r.caller.id) < api.PermissionAdmin {
+		return nil, api.ErrNoAdminAccess
+	}
+
+	s.removeRepo(repo)
+
+	s.logEvent(r, api.AuditActionDelete, repo, &event{
+		subjectType: api.AuditSubjectRepo,
+		subjectRepo: repo,
+	})
+
+	return nil, nil
+}
+
+// getRepoKeys returns the repository keys of the caller.
+func (s *Server) getRepoKeys(r *request) (interface{}, error) {
+	repo, err := s.lookupRepo(r.caller, r.param("namespace"), r.param("repo_name"))
+	if err != nil {
+		return nil, err
+	}
+	return repo.members[*r.caller.id].keys, nil
+}
+
+// listRepoAccounts returns the accounts that are a member of a repository.
+func (s *Server) listRepoAccounts(r *request) (interface{}, error) {
+	repo, err := s.lookupRepo(r.caller, r.param("namespace"), r.param("repo_name"))
+	if err != nil {
+		return nil, err
+	}
+
+	accounts := []*api.Account{}
+	for _, member := range repo.members {
+		accounts = append(accounts, member.account.toAPI())
+	}
+	sort.Slice(accounts, func(i, j int) bool {
+		return accounts[i].Name < accounts[j].Name
+	})
+	return accounts, nil
+}
+
+// listRepoEvents returns the audit events of a repository.
+func (s *Server) listRepoEvents(r *request) (interface{}, error) {
+	repo, err := s.lookupRepo(r.caller, r.param("namespace"), r.param("repo_name"))
+	if err != nil {
+		return nil, err
+	}
+
+	return s.listEvents(r, func(e *event) bool {
+		return e.repo == repo
+	})
+}
+
+// listRepoUsers returns the users that are a member of a repository.
+func (s *Server) listRepoUsers(r *request) (interface{}, error) {
+	repo, err := s.lookupRepo(r.caller, r.param("namespace"), r.param("repo_name"))
+	if err != nil {
+		return nil, err
+	}
+
+	users := []*api.User{}
+	for _, member := range repo.members {
+		if member.account.isUser() {
+			users = append(users, member.account.toUser())
+		}
+	}
+	sort.Slice(users, func(i, j int) bool {
+		return users[i].Username < users[j].Username
+	})
+	return users, nil
+}
+
+// getRepoUser returns a user that is a member of a repository.
+func (s *Server) getRepoUser(r *request) (interface{}, error) {
+	repo, err := s.lookupRepo(r.caller, r.param("namespace"), r.param("repo_name"))
+	if err != nil {
+		return nil, err
+	}
+
+	member, err := s.lookupRepoUser(repo, r.param("username"))
+	if err != nil {
+		return nil, err
+	}
+	return member.account.toUser(), nil
+}
+
+// inviteRepoUser adds a user to a repository. This requires admin
+// permission on the root directory of the repository.
+func (s *Server) inviteRepoUser(r *request) (interface{}, error) {
+	in := &api.InviteUserRequest{}
+	err := r.decode(in)
+	if err != nil {
+		return nil, err
+	}
+
+	repo, err := s.lookupRepo(r.caller, r.param("namespace"), r.param("repo_name"))
+	if err != nil {
+		return nil, err
+	}
+	if repo.rootDir.permission(r.caller.id) < api.PermissionAdmin {
+		return nil, api.ErrNoAdminAccess
+	}
+
+	invitee, ok := s.accounts[*in.AccountID]
+	if !ok || !invitee.isUser() {
+		return nil, api.ErrUserNotFound
+	}
+	if invitee.publicKey == nil {
+		return nil, api.ErrAccountNotKeyed
+	}
+	if uuid.Equal(invitee.id, r.caller.id) {
+		return nil, api.ErrCannotAddYourself
+	}
+	if _, isMember := repo.members[*invitee.id]; isMember {
+		return nil, api.ErrMemberAlreadyExists
+	}
+
+	member := newRepoMember(repo, invitee, in.RepoMember)
+	repo.members[*invitee.id] = member
+
+	s.logEvent(r, api.AuditActionCreate, repo, &event{
+		subjectType:    api.AuditSubjectUser,
+		subjectAccount: invitee,
+	})
+
+	return member.member, nil
+}
+
+// removeRepoUser removes a user from a repository. This requires admin
+// permission on the root directory of the repository.
+func (s *Server) removeRepoUser(r *request) (interface{}, error) {
+	repo, err := s.lookupRepo(r.caller, r.param("namespace"), r.param("repo_name"))
+	if err != nil {
+		return nil, err
+	}
+	if repo.rootDir.permission(r.caller.id) < api.PermissionAdmin {
+		return nil, api.ErrNoAdminAccess
+	}
+
+	member, err := s.lookupRepoUser(repo, r.param("username"))
+	if err != nil {
+		return nil, err
+	}
+	if uuid.Equal(member.account.id, r.caller.id) {
+		return nil, api.ErrCannotRemoveYourself
+	}
+
+	resp := s.revokeRepoMember(repo, member.account, false)
+
+	s.logEvent(r, api.AuditActionDelete, repo, &event{
+		subjectType:    api.AuditSubjectUser,
+		subjectAccount: member.account,
+	})
+
+	return resp, nil
+}
+
+// lookupRepoUser returns the member of the repository that is the user with the given name.
+func (s *Server) lookupRepoUser(repo *repo, username string) (*repoMember, error) {
+	a, ok := s.accountNames[strings.ToLower(username)]
+	if !ok || !a.isUser() {
+		return nil, api.ErrUserNotFound
+	}
+
+	member, ok := repo.members[*a.id]
+	if !ok {
+		return nil, api.ErrRepoMemberNotFound
+	}
+	return member, nil
+}
+
+// blindNameExists returns whether a directory or secret with the blind name exists.
+func (s *Server) blindNameExists(blindName string) bool {
+	_, isDir := s.dirs[blindName]
+	_, isSecret := s.secrets[blindName]
+	return isDir || isSecret
+}
+
+// newRepoMember creates a member of the repository for the account.
+func newRepoMember(repo *repo, a *account, in *api.CreateRepoMemberRequest) *repoMember {
+	return &repoMember{
+		member: &api.RepoMember{
+			RepoID:    repo.repo.RepoID,
+			AccountID: a.id,
+			CreatedAt: now(),
+		},
+		account: a,
+		keys: &api.RepoKeys{
+			RepoEncryptionKey: in.RepoEncryptionKey,
+			RepoIndexKey:      in.RepoIndexKey,
+		},
+	}
+}
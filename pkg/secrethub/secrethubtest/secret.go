@@ -0,0 +1,384 @@
+// +build !production
+
+package secrethubtest
+
+import (
+	"strconv"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/api/uuid"
+	"github.com/secrethub/secrethub-go/internals/crypto"
+)
+
+// createSecret creates a secret with its first version and secret key in a directory.
+// This requires write permission on the directory. The name and the key must be
+// encrypted for every account that has read access on the directory.
+func (s *Server) createSecret(r *request) (interface{}, error) {
+	in := &api.CreateSecretRequest{}
+	err := r.decode(in)
+	if err != nil {
+		return nil, err
+	}
+
+	repo, err := s.lookupRepo(r.caller, r.param("namespace"), r.param("repo_name"))
+	if err != nil {
+		return nil, err
+	}
+
+	d, err := s.lookupDir(r.caller, r.param("dir_blind_name"), api.PermissionWrite)
+	if err != nil {
+		return nil, err
+	}
+	if d.repo != repo {
+		return nil, api.ErrDirNotFound
+	}
+	if s.blindNameExists(in.BlindName) {
+		return nil, api.ErrSecretAlreadyExists
+	}
+
+	names := make(map[uuid.UUID]crypto.CiphertextRSA)
+	encryptedFor := make(map[uuid.UUID]bool)
+	for _, name := range in.EncryptedNames {
+		names[*name.AccountID] = name.EncryptedName
+		encryptedFor[*name.AccountID] = true
+	}
+	err = checkEncryptedFor(d, encryptedFor)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make(map[uuid.UUID]crypto.CiphertextRSA)
+	for _, key := range in.EncryptedKeys {
+		keys[*key.AccountID] = key.EncryptedKey
+	}
+
+	createdAt := now()
+	sec := &secret{
+		id:        uuid.New(),
+		blindName: in.BlindName,
+		dir:       d,
+		names:     names,
+		createdAt: createdAt,
+	}
+	key := &secretKey{
+		id:     uuid.New(),
+		status: api.StatusOK,
+		keys:   keys,
+	}
+	sec.keys = []*secretKey{key}
+
+	version := &secretVersion{
+		id:        uuid.New(),
+		secret:    sec,
+		version:   1,
+		key:       key,
+		data:      in.EncryptedData,
+		createdAt: createdAt,
+	}
+	sec.versions = []*secretVersion{version}
+
+	d.secrets[*sec.id] = sec
+	d.lastModifiedAt = createdAt
+	s.secrets[sec.blindName] = sec
+
+	s.logEvent(r, api.AuditActionCreate, repo, &event{
+		subjectType:   api.AuditSubjectSecret,
+		subjectSecret: sec,
+	})
+	s.logEvent(r, api.AuditActionCreate, repo, &event{
+		subjectType:    api.AuditSubjectSecretVersion,
+		subjectVersion: version,
+	})
+
+	encryptedVersion, _ := version.encrypted(r.caller.id, false)
+	return encryptedVersion, nil
+}
+
+// getSecret returns a secret. When the encrypted_blob query parameter is set,
+// the latest version of the secret is returned instead, with the encrypted data
+// when the parameter is true.
+func (s *Server) getSecret(r *request) (interface{}, error) {
+	sec, err := s.lookupSecret(r.caller, r.param("secret_blind_name"), api.PermissionRead)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.URL.Query().Get("encrypted_blob") != "" {
+		return s.readVersion(r, sec, "latest")
+	}
+
+	encryptedSecret, ok := sec.encrypted(r.caller.id)
+	if !ok {
+		return nil, api.ErrSecretNotFound
+	}
+	return encryptedSecret, nil
+}
+
+// deleteSecret removes a secret and all its versions. This requires
+// write permission on the directory containing the secret.
+func (s *Server) deleteSecret(r *request) (interface{}, error) {
+	sec, err := s.lookupSecret(r.caller, r.param("secret_blind_name"), api.PermissionWrite)
+	if err != nil {
+		return nil, err
+	}
+
+	s.removeSecret(sec)
+
+	s.logEvent(r, api.AuditActionDelete, sec.dir.repo, &event{
+		subjectType:   api.AuditSubjectSecret,
+		subjectSecret: sec,
+	})
+
+	return nil, nil
+}
+
+// listSecretVersions returns all versions of a secret, with the encrypted
+// data when the encrypted_blob query parameter is true.
+func (s *Server) listSecretVersions(r *request) (interface{}, error) {
+	sec, err := s.lookupSecret(r.caller, r.param("secret_blind_name"), api.PermissionRead)
+	if err != nil {
+		return nil, err
+	}
+
+	withData := r.queryBool("encrypted_blob")
+
+	versions := []*api.EncryptedSecretVersion{}
+	for _, v := range sec.versions {
+		encryptedVersion, ok := v.encrypted(r.caller.id, withData)
+		if !ok {
+			return nil, api.ErrSecretNotFound
+		}
+		versions = append(versions, encryptedVersion)
+
+		if withData {
+			s.logEvent(r, api.AuditActionRead, sec.dir.repo, &event{
+				subjectType:    api.AuditSubjectSecretVersion,
+				subjectVersion: v,
+			})
+		}
+	}
+	return versions, nil
+}
+
+// createSecretVersion adds a version to a secret. This requires write permission
+// on the directory containing the secret. The data must be encrypted with a secret
+// key of the secret that has not been flagged.
+func (s *Server) createSecretVersion(r *request) (interface{}, error) {
+	in := &api.CreateSecretVersionRequest{}
+	err := r.decode(in)
+	if err != nil {
+		return nil, err
+	}
+
+	sec, err := s.lookupSecret(r.caller, r.param("secret_blind_name"), api.PermissionWrite)
+	if err != nil {
+		return nil, err
+	}
+
+	key, ok := sec.key(in.SecretKeyID)
+	if !ok {
+		return nil, api.ErrSecretKeyNotFound
+	}
+	if key.status == api.StatusFlagged {
+		return nil, api.ErrSecretKeyFlagged
+	}
+
+	number := 1
+	if len(sec.versions) > 0 {
+		number = sec.versions[len(sec.versions)-1].version + 1
+	}
+
+	version := &secretVersion{
+		id:        uuid.New(),
+		secret:    sec,
+		version:   number,
+		key:       key,
+		data:      in.EncryptedData,
+		createdAt: now(),
+	}
+	sec.versions = append(sec.versions, version)
+
+	s.logEvent(r, api.AuditActionCreate, sec.dir.repo, &event{
+		subjectType:    api.AuditSubjectSecretVersion,
+		subjectVersion: version,
+	})
+
+	encryptedVersion, ok := version.encrypted(r.caller.id, false)
+	if !ok {
+		return nil, api.ErrSecretNotFound
+	}
+	return encryptedVersion, nil
+}
+
+// getSecretVersion returns a version of a secret, with the encrypted
+// data when the encrypted_blob query parameter is true.
+func (s *Server) getSecretVersion(r *request) (interface{}, error) {
+	sec, err := s.lookupSecret(r.caller, r.param("secret_blind_name"), api.PermissionRead)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.readVersion(r, sec, r.param("version"))
+}
+
+// readVersion returns a version of the secret encrypted for the caller, with the
+// encrypted data when the encrypted_blob query parameter is true. Reading the
+// encrypted data is recorded as an audit event.
+func (s *Server) readVersion(r *request, sec *secret, version string) (*api.EncryptedSecretVersion, error) {
+	v, err := sec.version(version)
+	if err != nil {
+		return nil, err
+	}
+
+	withData := r.queryBool("encrypted_blob")
+	encryptedVersion, ok := v.encrypted(r.caller.id, withData)
+	if !ok {
+		return nil, api.ErrSecretNotFound
+	}
+
+	if withData {
+		s.logEvent(r, api.AuditActionRead, sec.dir.repo, &event{
+			subjectType:    api.AuditSubjectSecretVersion,
+			subjectVersion: v,
+		})
+	}
+
+	return encryptedVersion, nil
+}
+
+// deleteSecretVersion removes a version of a secret. This requires write permission
+// on the directory containing the secret. The last version of a secret cannot be removed.
+func (s *Server) deleteSecretVersion(r *request) (interface{}, error) {
+	sec, err := s.lookupSecret(r.caller, r.param("secret_blind_name"), api.PermissionWrite)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := sec.version(r.param("version"))
+	if err != nil {
+		return nil, err
+	}
+	if len(sec.versions) == 1 {
+		return nil, api.ErrCannotDeleteLastSecretVersion
+	}
+
+	for i, current := range sec.versions {
+		if current == v {
+			sec.versions = append(sec.versions[:i], sec.versions[i+1:]...)
+			break
+		}
+	}
+	v.deleted = true
+
+	s.logEvent(r, api.AuditActionDelete, sec.dir.repo, &event{
+		subjectType:    api.AuditSubjectSecretVersion,
+		subjectVersion: v,
+	})
+
+	return nil, nil
+}
+
+// getCurrentSecretKey returns the most recent secret key of a secret that has not been flagged.
+func (s *Server) getCurrentSecretKey(r *request) (interface{}, error) {
+	sec, err := s.lookupSecret(r.caller, r.param("secret_blind_name"), api.PermissionRead)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := len(sec.keys) - 1; i >= 0; i-- {
+		key := sec.keys[i]
+		if key.status != api.StatusOK {
+			continue
+		}
+
+		encryptedKey, ok := key.encrypted(r.caller.id)
+		if !ok {
+			return nil, api.ErrSecretKeyNotFound
+		}
+		return encryptedKey, nil
+	}
+	return nil, api.ErrNoOKSecretKey
+}
+
+// listSecretKeys returns all secret keys of a secret that are encrypted for the caller.
+func (s *Server) listSecretKeys(r *request) (interface{}, error) {
+	sec, err := s.lookupSecret(r.caller, r.param("secret_blind_name"), api.PermissionRead)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := []*api.EncryptedSecretKey{}
+	for _, key := range sec.keys {
+		encryptedKey, ok := key.encrypted(r.caller.id)
+		if ok {
+			keys = append(keys, encryptedKey)
+		}
+	}
+	return keys, nil
+}
+
+// createSecretKey adds a secret key to a secret. This requires write permission on the
+// directory containing the secret. The key must be encrypted for every account that has
+// read access on the directory.
+func (s *Server) createSecretKey(r *request) (interface{}, error) {
+	in := &api.CreateSecretKeyRequest{}
+	err := r.decode(in)
+	if err != nil {
+		return nil, err
+	}
+
+	sec, err := s.lookupSecret(r.caller, r.param("secret_blind_name"), api.PermissionWrite)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make(map[uuid.UUID]crypto.CiphertextRSA)
+	encryptedFor := make(map[uuid.UUID]bool)
+	for _, key := range in.EncryptedFor {
+		keys[*key.AccountID] = key.EncryptedKey
+		encryptedFor[*key.AccountID] = true
+	}
+	err = checkEncryptedFor(sec.dir, encryptedFor)
+	if err != nil {
+		return nil, err
+	}
+
+	key := &secretKey{
+		id:     uuid.New(),
+		status: api.StatusOK,
+		keys:   keys,
+	}
+	sec.keys = append(sec.keys, key)
+
+	s.logEvent(r, api.AuditActionCreate, sec.dir.repo, &event{
+		subjectType: api.AuditSubjectSecretKey,
+		subjectKey:  key,
+	})
+
+	encryptedKey, _ := key.encrypted(r.caller.id)
+	return encryptedKey, nil
+}
+
+// listSecretEvents returns the audit events of a secret and its versions.
+func (s *Server) listSecretEvents(r *request) (interface{}, error) {
+	sec, err := s.lookupSecret(r.caller, r.param("secret_blind_name"), api.PermissionRead)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.listEvents(r, func(e *event) bool {
+		if e.subjectSecret == sec {
+			return true
+		}
+		return e.subjectVersion != nil && e.subjectVersion.secret == sec
+	})
+}
+
+// parseVersion parses a secret version number, which must be positive.
+func parseVersion(version string) (int, error) {
+	number, err := strconv.Atoi(version)
+	if err != nil || number <= 0 {
+		return 0, api.ErrInvalidSecretVersion
+	}
+	return number, nil
+}
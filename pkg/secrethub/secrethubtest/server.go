@@ -0,0 +1,333 @@
+// +build !production
+
+// Package secrethubtest provides an in-memory implementation of the SecretHub API,
+// so that code using a secrethub.Client can be tested end-to-end without a network
+// connection to the real API.
+//
+// The Server keeps track of all accounts, repositories, directories, secrets, keys,
+// versions, access rules and audit events in memory, verifies the signature of every
+// request and enforces the same permissions as the real API:
+//
+//	server := secrethubtest.NewServer()
+//	defer server.Close()
+//
+//	credential, _ := secrethub.GenerateCredential()
+//	client := secrethub.NewClient(credential, server.ClientOptions())
+//	_, _ = client.Users().Create("dev1", "dev1@example.com", "Developer One")
+//	_, _ = client.Repos().Create("dev1/repo")
+//	_, _ = client.Secrets().Write("dev1/repo/db_password", []byte("password123"))
+//
+// Note that the server does not implement billing or rate limiting.
+package secrethubtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/api/uuid"
+	"github.com/secrethub/secrethub-go/internals/auth"
+	"github.com/secrethub/secrethub-go/internals/errio"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+)
+
+// Errors
+var (
+	errServer = errio.Namespace("secrethubtest")
+)
+
+// Server is an in-memory implementation of the SecretHub API, served over HTTP.
+// All methods are safe for concurrent use.
+type Server struct {
+	server        *httptest.Server
+	authenticator auth.Authenticator
+
+	mutex        sync.Mutex
+	accounts     map[uuid.UUID]*account
+	accountNames map[string]*account
+	credentials  map[string]*credential
+	orgs         map[string]*org
+	repos        map[string]*repo
+	dirs         map[string]*dir
+	secrets      map[string]*secret
+	services     map[string]*account
+	events       []*event
+}
+
+// NewServer starts and returns a new Server without any state.
+// The caller should call Close when finished, to shut it down.
+func NewServer() *Server {
+	s := &Server{
+		accounts:     make(map[uuid.UUID]*account),
+		accountNames: make(map[string]*account),
+		credentials:  make(map[string]*credential),
+		orgs:         make(map[string]*org),
+		repos:        make(map[string]*repo),
+		dirs:         make(map[string]*dir),
+		secrets:      make(map[string]*secret),
+		services:     make(map[string]*account),
+	}
+	s.authenticator = auth.NewAuthenticator(auth.NewMethodSignature(credentialGetter{server: s}))
+
+	router := chi.NewRouter()
+	router.Route("/v1", s.routes)
+
+	s.server = httptest.NewServer(router)
+	return s
+}
+
+// URL returns the base URL of the server, which can be used as ClientOptions.ServerURL.
+func (s *Server) URL() string {
+	return s.server.URL
+}
+
+// ClientOptions returns the options with which a secrethub.Client connects to the server.
+func (s *Server) ClientOptions() *secrethub.ClientOptions {
+	return &secrethub.ClientOptions{
+		ServerURL: s.server.URL,
+	}
+}
+
+// Close shuts down the server and blocks until all outstanding requests have completed.
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+// routes registers the handlers for all routes of the API.
+func (s *Server) routes(r chi.Router) {
+	r.Post("/users", s.handlePublic(http.StatusCreated, s.signupUser))
+	r.Get("/users/{username}", s.handle(http.StatusOK, s.getUser))
+
+	r.Get("/me/user", s.handle(http.StatusOK, s.getMyUser))
+	r.Get("/me/repos", s.handle(http.StatusOK, s.listMyRepos))
+	r.Get("/me/key", s.handle(http.StatusOK, s.getMyAccountKey))
+	r.Post("/me/credentials/{fingerprint}/key", s.handle(http.StatusCreated, s.createAccountKey))
+	r.Get("/account/{account_name}", s.handle(http.StatusOK, s.getAccount))
+
+	r.Route("/namespaces/{namespace}/repos", func(r chi.Router) {
+		r.Get("/", s.handle(http.StatusOK, s.listRepos))
+		r.Post("/", s.handle(http.StatusCreated, s.createRepo))
+		r.Route("/{repo_name}", func(r chi.Router) {
+			r.Get("/", s.handle(http.StatusOK, s.getRepo))
+			r.Delete("/", s.handle(http.StatusOK, s.deleteRepo))
+			r.Get("/keys", s.handle(http.StatusOK, s.getRepoKeys))
+			r.Get("/accounts", s.handle(http.StatusOK, s.listRepoAccounts))
+			r.Get("/events", s.handle(http.StatusOK, s.listRepoEvents))
+			r.Post("/dirs", s.handle(http.StatusCreated, s.createDir))
+			r.Post("/dirs/{dir_blind_name}/secrets", s.handle(http.StatusCreated, s.createSecret))
+			r.Get("/users", s.handle(http.StatusOK, s.listRepoUsers))
+			r.Post("/users", s.handle(http.StatusOK, s.inviteRepoUser))
+			r.Get("/users/{username}", s.handle(http.StatusOK, s.getRepoUser))
+			r.Delete("/users/{username}", s.handle(http.StatusOK, s.removeRepoUser))
+			r.Get("/services", s.handle(http.StatusOK, s.listServices))
+			r.Post("/services", s.handle(http.StatusCreated, s.createService))
+		})
+	})
+
+	r.Get("/services/{service_id}", s.handle(http.StatusOK, s.getService))
+	r.Delete("/services/{service_id}", s.handle(http.StatusOK, s.deleteService))
+
+	r.Route("/dirs/{dir_blind_name}", func(r chi.Router) {
+		r.Get("/", s.handle(http.StatusOK, s.getTree))
+		r.Delete("/", s.handle(http.StatusOK, s.deleteDir))
+		r.Get("/accounts", s.handle(http.StatusOK, s.listDirAccounts))
+		r.Get("/permissions/{account_name}", s.handle(http.StatusOK, s.getAccessLevel))
+		r.Get("/rules", s.handle(http.StatusOK, s.listAccessRules))
+		r.Get("/rules/{account_name}", s.handle(http.StatusOK, s.getAccessRule))
+		r.Put("/rules/{account_name}", s.handle(http.StatusOK, s.createAccessRule))
+		r.Patch("/rules/{account_name}", s.handle(http.StatusOK, s.updateAccessRule))
+		r.Delete("/rules/{account_name}", s.handle(http.StatusOK, s.deleteAccessRule))
+	})
+
+	r.Route("/secrets/{secret_blind_name}", func(r chi.Router) {
+		r.Get("/", s.handle(http.StatusOK, s.getSecret))
+		r.Delete("/", s.handle(http.StatusOK, s.deleteSecret))
+		r.Get("/versions", s.handle(http.StatusOK, s.listSecretVersions))
+		r.Post("/versions", s.handle(http.StatusCreated, s.createSecretVersion))
+		r.Get("/versions/{version}", s.handle(http.StatusOK, s.getSecretVersion))
+		r.Delete("/versions/{version}", s.handle(http.StatusOK, s.deleteSecretVersion))
+		r.Get("/key", s.handle(http.StatusOK, s.getCurrentSecretKey))
+		r.Get("/keys", s.handle(http.StatusOK, s.listSecretKeys))
+		r.Post("/keys", s.handle(http.StatusCreated, s.createSecretKey))
+		r.Get("/events", s.handle(http.StatusOK, s.listSecretEvents))
+	})
+
+	r.Route("/orgs", func(r chi.Router) {
+		r.Get("/", s.handle(http.StatusOK, s.listMyOrgs))
+		r.Post("/", s.handle(http.StatusCreated, s.createOrg))
+		r.Get("/{org_name}", s.handle(http.StatusOK, s.getOrg))
+		r.Delete("/{org_name}", s.handle(http.StatusOK, s.deleteOrg))
+		r.Get("/{org_name}/members", s.handle(http.StatusOK, s.listOrgMembers))
+		r.Post("/{org_name}/members", s.handle(http.StatusCreated, s.createOrgMember))
+		r.Get("/{org_name}/members/{username}", s.handle(http.StatusOK, s.getOrgMember))
+		r.Post("/{org_name}/members/{username}", s.handle(http.StatusOK, s.updateOrgMember))
+		r.Delete("/{org_name}/members/{username}", s.handle(http.StatusOK, s.revokeOrgMember))
+	})
+}
+
+// request is an incoming request to the server.
+type request struct {
+	*http.Request
+	// caller is the authenticated account that made the request.
+	// It is nil for requests to public routes.
+	caller *account
+	// credential is the credential the request was signed with.
+	// It is nil for requests to public routes.
+	credential *credential
+	body       []byte
+}
+
+// param returns the value of the URL parameter with the given key.
+func (r *request) param(key string) string {
+	return chi.URLParam(r.Request, key)
+}
+
+// queryInt returns the value of the query parameter with the given key as an integer,
+// or the default value when the parameter is not set.
+func (r *request) queryInt(key string, defaultValue int) (int, error) {
+	value := r.URL.Query().Get(key)
+	if value == "" {
+		return defaultValue, nil
+	}
+	i, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, api.ErrBadRequest
+	}
+	return i, nil
+}
+
+// queryBool returns whether the query parameter with the given key is set to true.
+func (r *request) queryBool(key string) bool {
+	return strings.ToLower(r.URL.Query().Get(key)) == "true"
+}
+
+// validator is implemented by request types that can validate themselves.
+type validator interface {
+	Validate() error
+}
+
+// decode decodes the body of the request into in and validates it when possible.
+func (r *request) decode(in interface{}) error {
+	err := json.Unmarshal(r.body, in)
+	if err != nil {
+		return api.ErrBadRequest
+	}
+
+	v, ok := in.(validator)
+	if ok {
+		return v.Validate()
+	}
+	return nil
+}
+
+// handlerFunc handles a request and returns the response body to return.
+type handlerFunc func(r *request) (interface{}, error)
+
+// handle returns an http.HandlerFunc that authenticates the request, calls the
+// handler with exclusive access to the state of the server and writes the response
+// with the given status code, or the returned error.
+func (s *Server) handle(status int, fn handlerFunc) http.HandlerFunc {
+	return s.serve(status, true, fn)
+}
+
+// handlePublic is the same as handle, but does not authenticate the request.
+func (s *Server) handlePublic(status int, fn handlerFunc) http.HandlerFunc {
+	return s.serve(status, false, fn)
+}
+
+func (s *Server) serve(status int, authenticate bool, fn handlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			writeError(w, api.ErrBadRequest)
+			return
+		}
+		r.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+		req := &request{
+			Request: r,
+			body:    body,
+		}
+
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+
+		if authenticate {
+			result, err := s.authenticator.Verify(r)
+			if err != nil {
+				writeError(w, err)
+				return
+			}
+
+			req.credential = s.credentials[result.Fingerprint]
+			req.caller = s.accounts[*result.AccountID]
+			if req.credential == nil || req.caller == nil {
+				writeError(w, api.ErrSignatureNotVerified)
+				return
+			}
+		}
+
+		out, err := fn(req)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		if out == nil {
+			w.WriteHeader(status)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_ = json.NewEncoder(w).Encode(out)
+	}
+}
+
+// writeError writes an error response in the format of the SecretHub API.
+func writeError(w http.ResponseWriter, err error) {
+	var statusErr errio.PublicStatusError
+	switch e := err.(type) {
+	case errio.PublicStatusError:
+		statusErr = e
+	case errio.PublicError:
+		statusErr = errio.PublicStatusError{
+			PublicError: e,
+			StatusCode:  http.StatusBadRequest,
+		}
+	default:
+		statusErr = errServer.Code("unexpected").StatusError(err.Error(), http.StatusInternalServerError)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusErr.StatusCode)
+	_ = json.NewEncoder(w).Encode(statusErr)
+}
+
+// credentialGetter looks up credentials for the authenticator.
+// It is only called while the mutex of the server is held.
+type credentialGetter struct {
+	server *Server
+}
+
+// GetCredential returns the credential with the given fingerprint.
+func (g credentialGetter) GetCredential(fingerprint string) (*api.Credential, error) {
+	credential, ok := g.server.credentials[fingerprint]
+	if !ok {
+		return nil, api.ErrCredentialNotFound
+	}
+	return credential.credential, nil
+}
+
+// now returns the current time, as stored by the server.
+func now() time.Time {
+	return time.Now().UTC()
+}
@@ -0,0 +1,308 @@
+// +build !production
+
+package secrethubtest
+
+import (
+	"crypto/tls"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/internals/auth"
+	"github.com/secrethub/secrethub-go/pkg/keygen"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+)
+
+// newUser signs up a user on the server and returns a client authenticated as the user.
+func newUser(t *testing.T, server *Server, username string) secrethub.Client {
+	credential, err := secrethub.GenerateCredential()
+	assert.OK(t, err)
+
+	client := secrethub.NewClient(credential, server.ClientOptions())
+	_, err = client.Users().Create(username, username+"@example.com", "Test User")
+	assert.OK(t, err)
+
+	return client
+}
+
+// roundTripperFunc adapts a function to the http.RoundTripper interface.
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestServer_Secrets(t *testing.T) {
+	// Arrange
+	server := NewServer()
+	defer server.Close()
+
+	client := newUser(t, server, "dev1")
+
+	_, err := client.Repos().Create("dev1/repo")
+	assert.OK(t, err)
+
+	_, err = client.Dirs().Create("dev1/repo/dir")
+	assert.OK(t, err)
+
+	// Act
+	_, err = client.Secrets().Write("dev1/repo/dir/secret", []byte("first"))
+	assert.OK(t, err)
+
+	_, err = client.Secrets().Write("dev1/repo/dir/secret", []byte("second"))
+	assert.OK(t, err)
+
+	latest, err := client.Secrets().Versions().GetWithData("dev1/repo/dir/secret")
+	assert.OK(t, err)
+
+	first, err := client.Secrets().Versions().GetWithData("dev1/repo/dir/secret:1")
+	assert.OK(t, err)
+
+	versions, err := client.Secrets().Versions().ListWithoutData("dev1/repo/dir/secret")
+	assert.OK(t, err)
+
+	tree, err := client.Dirs().GetTree("dev1/repo", -1, false)
+	assert.OK(t, err)
+
+	repo, err := client.Repos().Get("dev1/repo")
+	assert.OK(t, err)
+
+	err = client.Secrets().Delete("dev1/repo/dir/secret")
+	assert.OK(t, err)
+
+	_, errNotFound := client.Secrets().Versions().GetWithData("dev1/repo/dir/secret")
+
+	// Assert
+	assert.Equal(t, latest.Data, []byte("second"))
+	assert.Equal(t, latest.Version, 2)
+	assert.Equal(t, first.Data, []byte("first"))
+	assert.Equal(t, len(versions), 2)
+	assert.Equal(t, tree.DirCount(), 1)
+	assert.Equal(t, tree.SecretCount(), 1)
+	assert.Equal(t, repo.SecretCount, 1)
+	assert.Equal(t, errNotFound, api.ErrSecretNotFound)
+}
+
+func TestServer_WriteIfLatest(t *testing.T) {
+	// Arrange
+	server := NewServer()
+	defer server.Close()
+
+	credential, err := secrethub.GenerateCredential()
+	assert.OK(t, err)
+
+	client := secrethub.NewClient(credential, server.ClientOptions())
+	_, err = client.Users().Create("dev1", "dev1@example.com", "Test User")
+	assert.OK(t, err)
+
+	_, err = client.Repos().Create("dev1/repo")
+	assert.OK(t, err)
+
+	// With racingClient, the other client writes a version after the latest version
+	// has been checked and before the new version is written.
+	other := secrethub.NewClient(credential, server.ClientOptions())
+
+	racing := true
+	opts := server.ClientOptions()
+	opts.Transport = roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		if racing && req.Method == http.MethodPost && strings.HasSuffix(req.URL.Path, "/versions") {
+			racing = false
+			_, err := other.Secrets().Write("dev1/repo/secret", []byte("concurrent"))
+			assert.OK(t, err)
+		}
+		return http.DefaultTransport.RoundTrip(req)
+	})
+	racingClient := secrethub.NewClient(credential, opts)
+
+	// Act
+	created, err := client.Secrets().WriteIfLatest("dev1/repo/secret", 0, []byte("first"))
+	assert.OK(t, err)
+
+	_, errExists := client.Secrets().WriteIfLatest("dev1/repo/secret", 0, []byte("other"))
+
+	updated, err := client.Secrets().WriteIfLatest("dev1/repo/secret", 1, []byte("second"))
+	assert.OK(t, err)
+
+	_, errConflict := client.Secrets().WriteIfLatest("dev1/repo/secret", 1, []byte("other"))
+
+	raced, errRaced := racingClient.Secrets().WriteIfLatest("dev1/repo/secret", 2, []byte("third"))
+
+	latest, err := client.Secrets().Versions().GetWithData("dev1/repo/secret")
+	assert.OK(t, err)
+
+	// Assert
+	assert.Equal(t, created.Version, 1)
+	assert.Equal(t, errExists, api.ErrSecretAlreadyExists)
+	assert.Equal(t, updated.Version, 2)
+	assert.Equal(t, errConflict, secrethub.ErrSecretVersionConflict)
+	assert.Equal(t, errRaced, secrethub.ErrSecretVersionConflict)
+	assert.Equal(t, raced.Version, 4)
+	assert.Equal(t, latest.Data, []byte("third"))
+}
+
+func TestServer_GenerateKey(t *testing.T) {
+	// Arrange
+	server := NewServer()
+	defer server.Close()
+
+	client := newUser(t, server, "dev1")
+
+	_, err := client.Repos().Create("dev1/repo")
+	assert.OK(t, err)
+
+	generator := keygen.X509{
+		Type:       keygen.KeyTypeECDSA,
+		CommonName: "example.com",
+		SelfSigned: true,
+	}
+
+	// Act
+	result, err := client.Secrets().GenerateKey("dev1/repo/tls", generator, true)
+	assert.OK(t, err)
+
+	private, err := client.Secrets().Versions().GetWithData("dev1/repo/tls")
+	assert.OK(t, err)
+
+	cert, err := client.Secrets().Versions().GetWithData("dev1/repo/tls.crt")
+	assert.OK(t, err)
+
+	// Assert
+	assert.Equal(t, result.Private.Version, 1)
+	assert.Equal(t, result.PublicPath, "dev1/repo/tls.crt")
+	assert.Equal(t, result.Public.Version, 1)
+
+	_, err = tls.X509KeyPair(cert.Data, private.Data)
+	assert.OK(t, err)
+}
+
+func TestServer_AccessRules(t *testing.T) {
+	// Arrange
+	server := NewServer()
+	defer server.Close()
+
+	dev1 := newUser(t, server, "dev1")
+	dev2 := newUser(t, server, "dev2")
+
+	_, err := dev1.Repos().Create("dev1/repo")
+	assert.OK(t, err)
+
+	_, err = dev1.Secrets().Write("dev1/repo/secret", []byte("secret"))
+	assert.OK(t, err)
+
+	_, err = dev1.Repos().Users().Invite("dev1/repo", "dev2")
+	assert.OK(t, err)
+
+	_, errBeforeRule := dev2.Secrets().Versions().GetWithData("dev1/repo/secret")
+
+	// Act
+	_, err = dev1.AccessRules().Set("dev1/repo", api.PermissionRead, "dev2")
+	assert.OK(t, err)
+
+	read, err := dev2.Secrets().Versions().GetWithData("dev1/repo/secret")
+	assert.OK(t, err)
+
+	_, errWrite := dev2.Secrets().Write("dev1/repo/secret", []byte("other"))
+
+	levels, err := dev1.AccessRules().ListLevels("dev1/repo")
+	assert.OK(t, err)
+
+	// Assert
+	assert.Equal(t, errBeforeRule, api.ErrSecretNotFound)
+	assert.Equal(t, read.Data, []byte("secret"))
+	assert.Equal(t, errWrite, api.ErrForbidden)
+	assert.Equal(t, len(levels), 2)
+}
+
+func TestServer_Service(t *testing.T) {
+	// Arrange
+	server := NewServer()
+	defer server.Close()
+
+	dev1 := newUser(t, server, "dev1")
+
+	_, err := dev1.Repos().Create("dev1/repo")
+	assert.OK(t, err)
+
+	_, err = dev1.Secrets().Write("dev1/repo/secret", []byte("secret"))
+	assert.OK(t, err)
+
+	credential, err := secrethub.GenerateCredential()
+	assert.OK(t, err)
+
+	// Act
+	service, err := dev1.Services().Create("dev1/repo", "test service", credential)
+	assert.OK(t, err)
+
+	_, err = dev1.AccessRules().Set("dev1/repo", api.PermissionRead, service.ServiceID)
+	assert.OK(t, err)
+
+	serviceClient := secrethub.NewClient(credential, server.ClientOptions())
+	read, err := serviceClient.Secrets().Versions().GetWithData("dev1/repo/secret")
+	assert.OK(t, err)
+
+	_, err = dev1.Services().Delete(service.ServiceID)
+	assert.OK(t, err)
+
+	_, errDeleted := serviceClient.Secrets().Versions().GetWithData("dev1/repo/secret")
+
+	// Assert
+	assert.Equal(t, read.Data, []byte("secret"))
+	assert.Equal(t, errDeleted, api.ErrSignatureNotVerified)
+}
+
+func TestServer_Events(t *testing.T) {
+	// Arrange
+	server := NewServer()
+	defer server.Close()
+
+	client := newUser(t, server, "dev1")
+
+	_, err := client.Repos().Create("dev1/repo")
+	assert.OK(t, err)
+
+	_, err = client.Secrets().Write("dev1/repo/secret", []byte("secret"))
+	assert.OK(t, err)
+
+	_, err = client.Secrets().Versions().GetWithData("dev1/repo/secret")
+	assert.OK(t, err)
+
+	// Act
+	repoEvents, err := client.Repos().ListEvents("dev1/repo", nil)
+	assert.OK(t, err)
+
+	secretEvents, err := client.Secrets().ListEvents("dev1/repo/secret", nil)
+	assert.OK(t, err)
+
+	// Assert
+	assert.Equal(t, len(repoEvents), 4)
+	assert.Equal(t, repoEvents[0].Subject.Type, api.AuditSubjectRepo)
+	assert.Equal(t, len(secretEvents), 3)
+	assert.Equal(t, secretEvents[2].Action, api.AuditActionRead)
+	assert.Equal(t, secretEvents[2].Actor.User.Username, "dev1")
+}
+
+func TestServer_Authentication(t *testing.T) {
+	// Arrange
+	server := NewServer()
+	defer server.Close()
+
+	_ = newUser(t, server, "dev1")
+
+	credential, err := secrethub.GenerateCredential()
+	assert.OK(t, err)
+
+	unknown := secrethub.NewClient(credential, server.ClientOptions())
+
+	// Act
+	_, errUnknown := unknown.Users().Me()
+
+	resp, err := http.Get(server.URL() + "/v1/me/user")
+	assert.OK(t, err)
+	_ = resp.Body.Close()
+
+	// Assert
+	assert.Equal(t, errUnknown, api.ErrSignatureNotVerified)
+	assert.Equal(t, resp.StatusCode, auth.ErrNoAuthHeader.StatusCode)
+}
@@ -0,0 +1,138 @@
+// +build !production
+
+package secrethubtest
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/api/uuid"
+	"github.com/secrethub/secrethub-go/internals/crypto"
+	"github.com/secrethub/secrethub-go/pkg/randchar"
+)
+
+// serviceIDLength is the length of the random part of a service id.
+const serviceIDLength = 12
+
+// createService creates a service account that is a member of a repository.
+// This requires admin permission on the root directory of the repository.
+func (s *Server) createService(r *request) (interface{}, error) {
+	in := &api.CreateServiceRequest{}
+	err := r.decode(in)
+	if err != nil {
+		return nil, err
+	}
+
+	repo, err := s.lookupRepo(r.caller, r.param("namespace"), r.param("repo_name"))
+	if err != nil {
+		return nil, err
+	}
+	if repo.rootDir.permission(r.caller.id) < api.PermissionAdmin {
+		return nil, api.ErrNoAdminAccess
+	}
+	if _, exists := s.credentials[in.Credential.Fingerprint]; exists {
+		return nil, api.ErrCredentialAlreadyExists
+	}
+
+	random, err := randchar.NewGenerator(false).Generate(serviceIDLength)
+	if err != nil {
+		return nil, err
+	}
+	serviceID := api.ServiceNamePrefix + strings.ToLower(string(random))
+
+	accountID := uuid.New()
+	a := &account{
+		id:          accountID,
+		name:        serviceID,
+		accountType: accountTypeService,
+		publicKey:   in.AccountKey.PublicKey,
+		createdAt:   now(),
+		service: &api.Service{
+			AccountID:   accountID,
+			ServiceID:   serviceID,
+			Description: in.Description,
+			CreatedBy:   r.caller.id,
+		},
+		serviceRepo: repo,
+	}
+
+	c := newCredential(accountID, in.Credential)
+	encryptedKey := crypto.CiphertextRSAAES(in.AccountKey.EncryptedPrivateKey)
+	c.encryptedAccountKey = &encryptedKey
+
+	s.addAccount(a, c)
+	repo.members[*accountID] = newRepoMember(repo, a, in.RepoMember)
+
+	s.logEvent(r, api.AuditActionCreate, repo, &event{
+		subjectType:    api.AuditSubjectService,
+		subjectAccount: a,
+	})
+
+	return a.toService(), nil
+}
+
+// listServices returns the service accounts of a repository.
+func (s *Server) listServices(r *request) (interface{}, error) {
+	repo, err := s.lookupRepo(r.caller, r.param("namespace"), r.param("repo_name"))
+	if err != nil {
+		return nil, err
+	}
+
+	services := []*api.Service{}
+	for _, member := range repo.members {
+		if member.account.serviceRepo == repo {
+			services = append(services, member.account.toService())
+		}
+	}
+	sort.Slice(services, func(i, j int) bool {
+		return services[i].ServiceID < services[j].ServiceID
+	})
+	return services, nil
+}
+
+// getService returns a service account of a repository the caller is a member of.
+func (s *Server) getService(r *request) (interface{}, error) {
+	service, err := s.lookupService(r.caller, r.param("service_id"))
+	if err != nil {
+		return nil, err
+	}
+	return service.toService(), nil
+}
+
+// deleteService removes a service account and revokes its access. This requires
+// admin permission on the root directory of the repository of the service.
+func (s *Server) deleteService(r *request) (interface{}, error) {
+	service, err := s.lookupService(r.caller, r.param("service_id"))
+	if err != nil {
+		return nil, err
+	}
+
+	repo := service.serviceRepo
+	if repo.rootDir.permission(r.caller.id) < api.PermissionAdmin {
+		return nil, api.ErrNoAdminAccess
+	}
+
+	resp := s.revokeRepoMember(repo, service, false)
+	s.removeAccount(service)
+
+	s.logEvent(r, api.AuditActionDelete, repo, &event{
+		subjectType:    api.AuditSubjectService,
+		subjectAccount: service,
+	})
+
+	return resp, nil
+}
+
+// lookupService returns the service account with the given id,
+// when the caller is a member of the repository of the service.
+func (s *Server) lookupService(caller *account, serviceID string) (*account, error) {
+	service, ok := s.services[strings.ToLower(serviceID)]
+	if !ok {
+		return nil, api.ErrServiceNotFound
+	}
+	if _, isMember := service.serviceRepo.members[*caller.id]; !isMember {
+		return nil, api.ErrServiceNotFound
+	}
+	return service, nil
+}
@@ -0,0 +1,523 @@
+// +build !production
+
+package secrethubtest
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/api/uuid"
+	"github.com/secrethub/secrethub-go/internals/crypto"
+)
+
+// Account types
+const (
+	accountTypeUser    = "user"
+	accountTypeService = "service"
+)
+
+// account is a user or a service account.
+type account struct {
+	id          *uuid.UUID
+	name        string
+	accountType string
+	publicKey   []byte
+	createdAt   time.Time
+
+	// user is set for user accounts.
+	user *api.User
+	// service and serviceRepo are set for service accounts.
+	service     *api.Service
+	serviceRepo *repo
+}
+
+// toAPI returns the account as an api.Account.
+func (a *account) toAPI() *api.Account {
+	return &api.Account{
+		AccountID:   a.id,
+		Name:        api.AccountName(a.name),
+		PublicKey:   a.publicKey,
+		AccountType: a.accountType,
+		CreatedAt:   a.createdAt,
+	}
+}
+
+// toUser returns the public details of a user account.
+func (a *account) toUser() *api.User {
+	user := a.user.Trim()
+	user.PublicKey = a.publicKey
+	return user
+}
+
+// toService returns the details of a service account.
+func (a *account) toService() *api.Service {
+	service := *a.service
+	service.Repo = a.serviceRepo.toAPI()
+	return &service
+}
+
+// isUser returns whether the account is a user account.
+func (a *account) isUser() bool {
+	return a.accountType == accountTypeUser
+}
+
+// credential is used by an account to authenticate and to encrypt its account key.
+type credential struct {
+	credential *api.Credential
+	// encryptedAccountKey is the account key encrypted for the credential.
+	// It is nil until the account key has been created.
+	encryptedAccountKey *crypto.CiphertextRSAAES
+}
+
+// org is an organization, whose namespace can contain repositories of its members.
+type org struct {
+	org     *api.Org
+	members map[uuid.UUID]*api.OrgMember
+}
+
+// repo is a repository. A repository has a root directory, which contains all
+// its directories and secrets, and members who have the repository keys.
+type repo struct {
+	repo      *api.Repo
+	namespace string
+	rootDir   *dir
+	members   map[uuid.UUID]*repoMember
+	deleted   bool
+}
+
+// repoMember is an account that has access to the keys of a repository.
+type repoMember struct {
+	member  *api.RepoMember
+	account *account
+	keys    *api.RepoKeys
+}
+
+// toAPI returns the repository as an api.Repo.
+func (r *repo) toAPI() *api.Repo {
+	result := *r.repo
+	result.MemberCount = len(r.members)
+	result.SecretCount = r.rootDir.secretCount()
+	return &result
+}
+
+// path returns the path of the repository.
+func (r *repo) path() string {
+	return r.namespace + "/" + r.repo.Name
+}
+
+// dir is a directory in a repository. Its name is encrypted for every
+// account that has read access to it.
+type dir struct {
+	id             *uuid.UUID
+	blindName      string
+	repo           *repo
+	parent         *dir
+	names          map[uuid.UUID]crypto.CiphertextRSA
+	dirs           map[uuid.UUID]*dir
+	secrets        map[uuid.UUID]*secret
+	rules          map[uuid.UUID]*api.AccessRule
+	createdAt      time.Time
+	lastModifiedAt time.Time
+}
+
+// encrypted returns the directory encrypted for the given account.
+// It returns false when the name is not encrypted for the account.
+func (d *dir) encrypted(accountID *uuid.UUID) (*api.EncryptedDir, bool) {
+	name, ok := d.names[*accountID]
+	if !ok {
+		return nil, false
+	}
+
+	var parentID *uuid.UUID
+	if d.parent != nil {
+		parentID = d.parent.id
+	}
+
+	return &api.EncryptedDir{
+		DirID:          d.id,
+		BlindName:      d.blindName,
+		EncryptedName:  name,
+		ParentID:       parentID,
+		Status:         api.StatusOK,
+		CreatedAt:      d.createdAt,
+		LastModifiedAt: d.lastModifiedAt,
+	}, true
+}
+
+// permission returns the permission the account has on the directory,
+// which is the highest permission of all rules on the directory and its parents.
+func (d *dir) permission(accountID *uuid.UUID) api.Permission {
+	permission := api.PermissionNone
+	for current := d; current != nil; current = current.parent {
+		rule, ok := current.rules[*accountID]
+		if ok && rule.Permission > permission {
+			permission = rule.Permission
+		}
+	}
+	return permission
+}
+
+// accounts returns the accounts that have at least read permission on the directory.
+func (d *dir) accounts() []*account {
+	var accounts []*account
+	for _, member := range d.repo.members {
+		if d.permission(member.account.id) >= api.PermissionRead {
+			accounts = append(accounts, member.account)
+		}
+	}
+	sort.Slice(accounts, func(i, j int) bool {
+		return accounts[i].name < accounts[j].name
+	})
+	return accounts
+}
+
+// secretCount returns the number of secrets in the directory and all its subdirectories.
+func (d *dir) secretCount() int {
+	count := len(d.secrets)
+	for _, child := range d.dirs {
+		count += child.secretCount()
+	}
+	return count
+}
+
+// walk calls fn for the directory and all its subdirectories, up to the given depth.
+// A negative depth means there is no maximum depth.
+func (d *dir) walk(depth int, fn func(d *dir, level int)) {
+	d.walkLevel(0, depth, fn)
+}
+
+func (d *dir) walkLevel(level int, depth int, fn func(d *dir, level int)) {
+	fn(d, level)
+	if depth >= 0 && level >= depth {
+		return
+	}
+	for _, child := range d.dirs {
+		child.walkLevel(level+1, depth, fn)
+	}
+}
+
+// ancestors returns the parents of the directory, starting at the root directory.
+func (d *dir) ancestors() []*dir {
+	var ancestors []*dir
+	for current := d.parent; current != nil; current = current.parent {
+		ancestors = append([]*dir{current}, ancestors...)
+	}
+	return ancestors
+}
+
+// secret is a secret in a directory. Its name and keys are encrypted
+// for every account that has read access to it.
+type secret struct {
+	id        *uuid.UUID
+	blindName string
+	dir       *dir
+	names     map[uuid.UUID]crypto.CiphertextRSA
+	keys      []*secretKey
+	versions  []*secretVersion
+	createdAt time.Time
+	deleted   bool
+}
+
+// encrypted returns the secret encrypted for the given account.
+// It returns false when the name is not encrypted for the account.
+func (s *secret) encrypted(accountID *uuid.UUID) (*api.EncryptedSecret, bool) {
+	name, ok := s.names[*accountID]
+	if !ok {
+		return nil, false
+	}
+
+	latest := 0
+	if len(s.versions) > 0 {
+		latest = s.versions[len(s.versions)-1].version
+	}
+
+	return &api.EncryptedSecret{
+		SecretID:      s.id,
+		DirID:         s.dir.id,
+		RepoID:        s.dir.repo.repo.RepoID,
+		EncryptedName: name,
+		BlindName:     s.blindName,
+		VersionCount:  len(s.versions),
+		LatestVersion: latest,
+		Status:        api.StatusOK,
+		CreatedAt:     s.createdAt,
+	}, true
+}
+
+// version returns the version with the given number or "latest".
+func (s *secret) version(version string) (*secretVersion, error) {
+	if strings.ToLower(version) == "latest" {
+		if len(s.versions) == 0 {
+			return nil, api.ErrSecretVersionNotFound
+		}
+		return s.versions[len(s.versions)-1], nil
+	}
+
+	number, err := parseVersion(version)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, v := range s.versions {
+		if v.version == number {
+			return v, nil
+		}
+	}
+	return nil, api.ErrSecretVersionNotFound
+}
+
+// key returns the secret key with the given id.
+func (s *secret) key(id *uuid.UUID) (*secretKey, bool) {
+	for _, key := range s.keys {
+		if uuid.Equal(key.id, id) {
+			return key, true
+		}
+	}
+	return nil, false
+}
+
+// secretKey is a key used to encrypt secret versions. The key is
+// encrypted for every account that has read access to the secret.
+type secretKey struct {
+	id     *uuid.UUID
+	status string
+	keys   map[uuid.UUID]crypto.CiphertextRSA
+}
+
+// encrypted returns the key encrypted for the given account.
+// It returns false when the key is not encrypted for the account.
+func (k *secretKey) encrypted(accountID *uuid.UUID) (*api.EncryptedSecretKey, bool) {
+	key, ok := k.keys[*accountID]
+	if !ok {
+		return nil, false
+	}
+	return &api.EncryptedSecretKey{
+		SecretKeyID:  k.id,
+		AccountID:    accountID,
+		EncryptedKey: key,
+	}, true
+}
+
+// secretVersion is a version of a secret, encrypted with a secret key.
+type secretVersion struct {
+	id        *uuid.UUID
+	secret    *secret
+	version   int
+	key       *secretKey
+	data      crypto.CiphertextAES
+	createdAt time.Time
+	deleted   bool
+}
+
+// encrypted returns the version encrypted for the given account, with or without
+// the encrypted data. It returns false when the secret or its key are not encrypted
+// for the account.
+func (v *secretVersion) encrypted(accountID *uuid.UUID, withData bool) (*api.EncryptedSecretVersion, bool) {
+	encryptedSecret, ok := v.secret.encrypted(accountID)
+	if !ok {
+		return nil, false
+	}
+
+	result := &api.EncryptedSecretVersion{
+		SecretVersionID: v.id,
+		Secret:          encryptedSecret,
+		Version:         v.version,
+		CreatedAt:       v.createdAt,
+		Status:          v.key.status,
+	}
+
+	if withData {
+		key, ok := v.key.encrypted(accountID)
+		if !ok {
+			return nil, false
+		}
+		data := v.data
+		result.SecretKey = key
+		result.EncryptedData = &data
+	}
+
+	return result, true
+}
+
+// namespaceExists returns whether the namespace is the name of a user or an organization.
+func (s *Server) namespaceExists(namespace string) bool {
+	a, ok := s.accountNames[strings.ToLower(namespace)]
+	if ok && a.isUser() {
+		return true
+	}
+	_, ok = s.orgs[strings.ToLower(namespace)]
+	return ok
+}
+
+// canCreateInNamespace returns whether the account can create repositories in the namespace.
+func (s *Server) canCreateInNamespace(a *account, namespace string) bool {
+	if strings.EqualFold(a.name, namespace) {
+		return a.isUser()
+	}
+	o, ok := s.orgs[strings.ToLower(namespace)]
+	if !ok {
+		return false
+	}
+	_, isMember := o.members[*a.id]
+	return isMember
+}
+
+// lookupRepo returns the repository at the given path of which the caller is a member.
+func (s *Server) lookupRepo(caller *account, namespace, name string) (*repo, error) {
+	r, ok := s.repos[repoKey(namespace, name)]
+	if !ok {
+		return nil, api.ErrRepoNotFound
+	}
+	if _, isMember := r.members[*caller.id]; !isMember {
+		return nil, api.ErrRepoNotFound
+	}
+	return r, nil
+}
+
+// lookupDir returns the directory with the given blind name,
+// when the caller has at least the given permission on it.
+func (s *Server) lookupDir(caller *account, blindName string, permission api.Permission) (*dir, error) {
+	d, ok := s.dirs[blindName]
+	if !ok {
+		return nil, api.ErrDirNotFound
+	}
+	if d.permission(caller.id) < permission {
+		if permission == api.PermissionRead {
+			return nil, api.ErrDirNotFound
+		}
+		return nil, api.ErrForbidden
+	}
+	return d, nil
+}
+
+// lookupSecret returns the secret with the given blind name, when the caller
+// has at least the given permission on the directory containing it.
+func (s *Server) lookupSecret(caller *account, blindName string, permission api.Permission) (*secret, error) {
+	sec, ok := s.secrets[blindName]
+	if !ok {
+		return nil, api.ErrSecretNotFound
+	}
+	if sec.dir.permission(caller.id) < permission {
+		if permission == api.PermissionRead {
+			return nil, api.ErrSecretNotFound
+		}
+		return nil, api.ErrForbidden
+	}
+	return sec, nil
+}
+
+// lookupAccount returns the account with the given name.
+func (s *Server) lookupAccount(name string) (*account, error) {
+	a, ok := s.accountNames[strings.ToLower(name)]
+	if !ok {
+		return nil, api.ErrAccountNotFound
+	}
+	return a, nil
+}
+
+// removeDir removes the directory and all its contents from the server.
+func (s *Server) removeDir(d *dir) {
+	for _, child := range d.dirs {
+		s.removeDir(child)
+	}
+	for _, sec := range d.secrets {
+		s.removeSecret(sec)
+	}
+	delete(s.dirs, d.blindName)
+	if d.parent != nil {
+		delete(d.parent.dirs, *d.id)
+	}
+}
+
+// removeSecret removes the secret and all its versions from the server.
+func (s *Server) removeSecret(sec *secret) {
+	sec.deleted = true
+	for _, v := range sec.versions {
+		v.deleted = true
+	}
+	delete(s.secrets, sec.blindName)
+	delete(sec.dir.secrets, *sec.id)
+}
+
+// removeRepo removes the repository with all its contents and service accounts from the server.
+func (s *Server) removeRepo(r *repo) {
+	s.removeDir(r.rootDir)
+	for _, member := range r.members {
+		if member.account.serviceRepo == r {
+			s.removeAccount(member.account)
+		}
+	}
+	r.deleted = true
+	delete(s.repos, repoKey(r.namespace, r.repo.Name))
+}
+
+// removeAccount removes the account and its credentials from the server.
+func (s *Server) removeAccount(a *account) {
+	for fingerprint, c := range s.credentials {
+		if uuid.Equal(c.credential.AccountID, a.id) {
+			delete(s.credentials, fingerprint)
+		}
+	}
+	if a.service != nil {
+		delete(s.services, strings.ToLower(a.service.ServiceID))
+	}
+	delete(s.accounts, *a.id)
+	delete(s.accountNames, strings.ToLower(a.name))
+}
+
+// revokeRepoMember removes the account from the repository and removes all its access rules.
+// All secret keys the account had access to are flagged, so they are no longer used for new
+// versions, and the number of affected keys and versions is returned. On a dry run, only the
+// number of keys and versions that would be affected is returned.
+func (s *Server) revokeRepoMember(r *repo, a *account, dryRun bool) *api.RevokeRepoResponse {
+	resp := &api.RevokeRepoResponse{
+		Namespace: r.namespace,
+		Name:      r.repo.Name,
+		Status:    api.StatusOK,
+	}
+
+	if !dryRun {
+		delete(r.members, *a.id)
+	}
+	r.rootDir.walk(-1, func(d *dir, _ int) {
+		if !dryRun {
+			delete(d.rules, *a.id)
+		}
+		for _, sec := range d.secrets {
+			for _, key := range sec.keys {
+				if _, ok := key.keys[*a.id]; !ok || key.status == api.StatusFlagged {
+					continue
+				}
+				if !dryRun {
+					key.status = api.StatusFlagged
+				}
+				resp.RevokedSecretKeyCount++
+				for _, v := range sec.versions {
+					if v.key == key {
+						resp.RevokedSecretVersionCount++
+					}
+				}
+			}
+		}
+	})
+
+	return resp
+}
+
+// checkEncryptedFor returns an error when the given account ids do not
+// include every account that has read access to the directory.
+func checkEncryptedFor(d *dir, accountIDs map[uuid.UUID]bool) error {
+	for _, a := range d.accounts() {
+		if !accountIDs[*a.id] {
+			return api.ErrNotEncryptedForAccounts
+		}
+	}
+	return nil
+}
+
+// repoKey returns the key of a repository in the repos map of the server.
+func repoKey(namespace, name string) string {
+	return strings.ToLower(namespace + "/" + name)
+}
@@ -1,6 +1,8 @@
 package secrethub
 
 import (
+	"context"
+
 	"github.com/secrethub/secrethub-go/internals/api"
 	"github.com/secrethub/secrethub-go/internals/errio"
 )
@@ -9,26 +11,39 @@ import (
 type ServiceService interface {
 	// Create creates a new service account for the given repo.
 	Create(path string, description string, credential Credential) (*api.Service, error)
+	// CreateContext is the same as Create, but uses the given context for all requests.
+	CreateContext(ctx context.Context, path string, description string, credential Credential) (*api.Service, error)
 	// Delete removes a service account by name.
 	Delete(name string) (*api.RevokeRepoResponse, error)
+	// DeleteContext is the same as Delete, but uses the given context for all requests.
+	DeleteContext(ctx context.Context, name string) (*api.RevokeRepoResponse, error)
 	// Get retrieves a service account by name.
 	Get(name string) (*api.Service, error)
+	// GetContext is the same as Get, but uses the given context for all requests.
+	GetContext(ctx context.Context, name string) (*api.Service, error)
 	// List lists all service accounts in a given repository.
 	List(path string) ([]*api.Service, error)
+	// ListContext is the same as List, but uses the given context for all requests.
+	ListContext(ctx context.Context, path string) ([]*api.Service, error)
 }
 
-func newServiceService(client client) ServiceService {
+func newServiceService(client *client) ServiceService {
 	return serviceService{
 		client: client,
 	}
 }
 
 type serviceService struct {
-	client client
+	client *client
 }
 
 // Create creates a new service account for the given repo.
 func (s serviceService) Create(path string, description string, credential Credential) (*api.Service, error) {
+	return s.CreateContext(context.Background(), path, description, credential)
+}
+
+// CreateContext is the same as Create, but uses the given context for all requests.
+func (s serviceService) CreateContext(ctx context.Context, path string, description string, credential Credential) (*api.Service, error) {
 	repoPath, err := api.NewRepoPath(path)
 	if err != nil {
 		return nil, errio.Error(err)
@@ -54,7 +69,7 @@ func (s serviceService) Create(path string, description string, credential Crede
 		return nil, errio.Error(err)
 	}
 
-	serviceRepoMemberRequest, err := s.client.createRepoMemberRequest(repoPath, accountKeyRequest.PublicKey)
+	serviceRepoMemberRequest, err := s.client.createRepoMemberRequest(ctx, repoPath, accountKeyRequest.PublicKey)
 	if err != nil {
 		return nil, errio.Error(err)
 	}
@@ -71,7 +86,7 @@ func (s serviceService) Create(path string, description string, credential Crede
 		return nil, errio.Error(err)
 	}
 
-	service, err := s.client.httpClient.CreateService(repoPath.GetNamespace(), repoPath.GetRepo(), in)
+	service, err := s.client.httpClient.CreateService(ctx, repoPath.GetNamespace(), repoPath.GetRepo(), in)
 	if err != nil {
 		return nil, errio.Error(err)
 	}
@@ -81,12 +96,17 @@ func (s serviceService) Create(path string, description string, credential Crede
 
 // Delete removes a service account.
 func (s serviceService) Delete(name string) (*api.RevokeRepoResponse, error) {
+	return s.DeleteContext(context.Background(), name)
+}
+
+// DeleteContext is the same as Delete, but uses the given context for all requests.
+func (s serviceService) DeleteContext(ctx context.Context, name string) (*api.RevokeRepoResponse, error) {
 	err := api.ValidateServiceID(name)
 	if err != nil {
 		return nil, errio.Error(err)
 	}
 
-	resp, err := s.client.httpClient.DeleteService(name)
+	resp, err := s.client.httpClient.DeleteService(ctx, name)
 	if err != nil {
 		return nil, errio.Error(err)
 	}
@@ -96,16 +116,26 @@ func (s serviceService) Delete(name string) (*api.RevokeRepoResponse, error) {
 
 // Get retrieves a service account.
 func (s serviceService) Get(name string) (*api.Service, error) {
+	return s.GetContext(context.Background(), name)
+}
+
+// GetContext is the same as Get, but uses the given context for all requests.
+func (s serviceService) GetContext(ctx context.Context, name string) (*api.Service, error) {
 	err := api.ValidateServiceID(name)
 	if err != nil {
 		return nil, errio.Error(err)
 	}
 
-	return s.client.httpClient.GetService(name)
+	return s.client.httpClient.GetService(ctx, name)
 }
 
 // List is an alias of the RepoServiceService List function.
 func (s serviceService) List(path string) ([]*api.Service, error) {
+	return s.ListContext(context.Background(), path)
+}
+
+// ListContext is the same as List, but uses the given context for all requests.
+func (s serviceService) ListContext(ctx context.Context, path string) ([]*api.Service, error) {
 	repoServiceService := newRepoServiceService(s.client)
-	return repoServiceService.List(path)
+	return repoServiceService.ListContext(ctx, path)
 }
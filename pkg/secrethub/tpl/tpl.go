@@ -0,0 +1,309 @@
+// Package tpl renders templates that contain references to secrets,
+// such as configuration files.
+//
+// A template contains placeholders of the form
+//
+//	{{ <namespace>/<repo>[/<dir-path>]/<secret>[:<version>] }}
+//
+// that are replaced by the value of the referenced secret. When no version
+// is given, the latest version of the secret is used. A placeholder can contain
+// variables of the form ${name}, which are substituted before the secret is
+// retrieved. This way, a single template can be used for multiple environments:
+//
+//	password: {{ acme/app/${env}/db_password }}
+package tpl
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/errio"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+)
+
+const (
+	placeholderStart = "{{"
+	placeholderEnd   = "}}"
+	variableStart    = "${"
+	variableEnd      = "}"
+
+	// FileMode is the file mode with which rendered templates are written:
+	// readable and writable by the owner only.
+	FileMode os.FileMode = 0600
+)
+
+// Errors
+var (
+	errTpl = errio.Namespace("template")
+
+	ErrUnterminatedPlaceholder = errTpl.Code("unterminated_placeholder").ErrorPref("placeholder at line %d, column %d is not closed with }}")
+	ErrEmptyPlaceholder        = errTpl.Code("empty_placeholder").ErrorPref("placeholder at line %d, column %d is empty")
+	ErrUnterminatedVariable    = errTpl.Code("unterminated_variable").ErrorPref("variable in placeholder at line %d, column %d is not closed with }")
+	ErrInvalidVariableName     = errTpl.Code("invalid_variable_name").ErrorPref("variable name %q in placeholder at line %d, column %d must only contain letters, digits and underscores and cannot start with a digit")
+	ErrUndefinedVariable       = errTpl.Code("undefined_variable").ErrorPref("variable %s in placeholder at line %d, column %d is not defined")
+	ErrInvalidSecretPath       = errTpl.Code("invalid_secret_path").ErrorPref("placeholder at line %d, column %d does not contain a valid secret path: %s")
+	ErrSecretNotResolved       = errTpl.Code("secret_not_resolved").ErrorPref("cannot retrieve secret %s for placeholder at line %d, column %d: %s")
+)
+
+var whitelistVariableName = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// Template is a parsed template.
+type Template struct {
+	nodes []node
+}
+
+// node is either a piece of text or a placeholder.
+type node struct {
+	text        string
+	placeholder *placeholder
+}
+
+// placeholder is a reference to a secret, of which the path can contain variables.
+type placeholder struct {
+	// parts contains the literal parts of the path, alternated with variable names.
+	// Even indices contain literals and odd indices contain variable names.
+	parts  []string
+	line   int
+	column int
+}
+
+// Parse parses a template.
+func Parse(raw []byte) (*Template, error) {
+	t := &Template{}
+
+	s := string(raw)
+	offset := 0
+	for {
+		start := strings.Index(s[offset:], placeholderStart)
+		if start == -1 {
+			t.appendText(s[offset:])
+			return t, nil
+		}
+		start += offset
+		t.appendText(s[offset:start])
+
+		line, column := position(s, start)
+
+		end := strings.Index(s[start:], placeholderEnd)
+		if end == -1 {
+			return nil, ErrUnterminatedPlaceholder(line, column)
+		}
+		end += start
+
+		p, err := parsePlaceholder(s[start+len(placeholderStart):end], line, column)
+		if err != nil {
+			return nil, err
+		}
+		t.nodes = append(t.nodes, node{placeholder: p})
+
+		offset = end + len(placeholderEnd)
+	}
+}
+
+// appendText adds a text node to the template, unless the text is empty.
+func (t *Template) appendText(text string) {
+	if text != "" {
+		t.nodes = append(t.nodes, node{text: text})
+	}
+}
+
+// parsePlaceholder parses the contents of a placeholder, found at the given position.
+func parsePlaceholder(contents string, line, column int) (*placeholder, error) {
+	contents = strings.TrimSpace(contents)
+	if contents == "" {
+		return nil, ErrEmptyPlaceholder(line, column)
+	}
+
+	p := &placeholder{
+		line:   line,
+		column: column,
+	}
+
+	for {
+		start := strings.Index(contents, variableStart)
+		if start == -1 {
+			p.parts = append(p.parts, contents)
+			return p, nil
+		}
+
+		end := strings.Index(contents[start:], variableEnd)
+		if end == -1 {
+			return nil, ErrUnterminatedVariable(line, column)
+		}
+		end += start
+
+		name := contents[start+len(variableStart) : end]
+		if !whitelistVariableName.MatchString(name) {
+			return nil, ErrInvalidVariableName(name, line, column)
+		}
+
+		p.parts = append(p.parts, contents[:start], name)
+		contents = contents[end+len(variableEnd):]
+	}
+}
+
+// path returns the secret path of the placeholder with the given variables substituted.
+func (p placeholder) path(vars map[string]string) (api.SecretPath, error) {
+	var path strings.Builder
+	for i, part := range p.parts {
+		if i%2 == 0 {
+			path.WriteString(part)
+			continue
+		}
+
+		value, ok := vars[part]
+		if !ok {
+			return "", ErrUndefinedVariable(part, p.line, p.column)
+		}
+		path.WriteString(value)
+	}
+
+	secretPath, err := api.NewSecretPath(path.String())
+	if err != nil {
+		return "", ErrInvalidSecretPath(p.line, p.column, err)
+	}
+	return secretPath, nil
+}
+
+// Variables returns the names of all variables used in the template, without duplicates.
+func (t *Template) Variables() []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, n := range t.nodes {
+		if n.placeholder == nil {
+			continue
+		}
+		for i := 1; i < len(n.placeholder.parts); i += 2 {
+			name := n.placeholder.parts[i]
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	return names
+}
+
+// Paths returns the paths of all secrets referenced by the template with the
+// given variables substituted. Every path is validated, so this can be used to
+// check a template and its variables without retrieving any secrets.
+func (t *Template) Paths(vars map[string]string) ([]api.SecretPath, error) {
+	var paths []api.SecretPath
+	for _, n := range t.nodes {
+		if n.placeholder == nil {
+			continue
+		}
+
+		path, err := n.placeholder.path(vars)
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
+// Render replaces every placeholder in the template by the value of the secret
+// it references, after substituting the given variables. All paths are validated
+// before any secret is retrieved, and all secrets are retrieved in a single batch.
+func (t *Template) Render(versions secrethub.SecretVersionService, vars map[string]string) ([]byte, error) {
+	return t.RenderContext(context.Background(), versions, vars)
+}
+
+// RenderContext is the same as Render, but uses the given context for all requests.
+func (t *Template) RenderContext(ctx context.Context, versions secrethub.SecretVersionService, vars map[string]string) ([]byte, error) {
+	paths, err := t.Paths(vars)
+	if err != nil {
+		return nil, err
+	}
+
+	rawPaths := make([]string, len(paths))
+	for i, path := range paths {
+		rawPaths[i] = path.Value()
+	}
+
+	var results []secrethub.SecretVersionResult
+	if len(rawPaths) > 0 {
+		results = versions.GetManyWithDataContext(ctx, rawPaths)
+	}
+
+	var buf bytes.Buffer
+	i := 0
+	for _, n := range t.nodes {
+		if n.placeholder == nil {
+			buf.WriteString(n.text)
+			continue
+		}
+
+		result := results[i]
+		if result.Err != nil {
+			return nil, ErrSecretNotResolved(result.Path, n.placeholder.line, n.placeholder.column, result.Err)
+		}
+		buf.Write(result.Version.Data)
+		i++
+	}
+
+	return buf.Bytes(), nil
+}
+
+// RenderFile renders the template and writes the result to the file with the given
+// name. The file is written with FileMode and replaced atomically, so it never
+// contains a partially rendered template and is never readable by others.
+func (t *Template) RenderFile(versions secrethub.SecretVersionService, vars map[string]string, filename string) error {
+	return t.RenderFileContext(context.Background(), versions, vars, filename)
+}
+
+// RenderFileContext is the same as RenderFile, but uses the given context for all requests.
+func (t *Template) RenderFileContext(ctx context.Context, versions secrethub.SecretVersionService, vars map[string]string, filename string) error {
+	rendered, err := t.RenderContext(ctx, versions, vars)
+	if err != nil {
+		return err
+	}
+
+	return writeFile(filename, rendered)
+}
+
+// writeFile atomically writes data to the file with the given name, with FileMode.
+func writeFile(filename string, data []byte) error {
+	// ioutil.TempFile creates the file with mode 0600, so the data is never readable by others.
+	tmp, err := ioutil.TempFile(filepath.Dir(filename), "."+filepath.Base(filename)+".tmp")
+	if err != nil {
+		return errio.Error(err)
+	}
+	defer func() {
+		// Removing fails once the file has been renamed, which is fine.
+		_ = os.Remove(tmp.Name())
+	}()
+
+	_, err = tmp.Write(data)
+	if err != nil {
+		_ = tmp.Close()
+		return errio.Error(err)
+	}
+
+	err = tmp.Chmod(FileMode)
+	if err != nil {
+		_ = tmp.Close()
+		return errio.Error(err)
+	}
+
+	err = tmp.Close()
+	if err != nil {
+		return errio.Error(err)
+	}
+
+	return errio.Error(os.Rename(tmp.Name(), filename))
+}
+
+// position returns the line and column, both counting from 1, of the given offset in s.
+func position(s string, offset int) (int, int) {
+	line := strings.Count(s[:offset], "\n") + 1
+	column := offset - strings.LastIndex(s[:offset], "\n")
+	return line, column
+}
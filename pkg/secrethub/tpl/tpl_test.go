@@ -0,0 +1,189 @@
+package tpl
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+)
+
+// versionService returns the secrets in a map and records the paths of every batch.
+type versionService struct {
+	secrethub.SecretVersionService
+	secrets map[string]string
+	batches [][]string
+}
+
+func (s *versionService) GetManyWithDataContext(ctx context.Context, paths []string) []secrethub.SecretVersionResult {
+	s.batches = append(s.batches, paths)
+
+	results := make([]secrethub.SecretVersionResult, len(paths))
+	for i, path := range paths {
+		results[i].Path = path
+		data, ok := s.secrets[path]
+		if !ok {
+			results[i].Err = api.ErrSecretNotFound
+			continue
+		}
+		results[i].Version = &api.SecretVersion{Data: []byte(data)}
+	}
+	return results
+}
+
+func TestParse(t *testing.T) {
+	cases := map[string]struct {
+		raw               string
+		expectedVariables []string
+		err               error
+	}{
+		"no placeholders": {
+			raw: "foo: bar\n",
+		},
+		"placeholders": {
+			raw:               "user: {{ acme/app/${env}/db_user }}\npassword: {{acme/app/${env}/db_password:2}}\n",
+			expectedVariables: []string{"env"},
+		},
+		"multiple variables": {
+			raw:               "{{ ${org}/${repo}/${env}/key }}",
+			expectedVariables: []string{"org", "repo", "env"},
+		},
+		"unterminated placeholder": {
+			raw: "foo: bar\npassword: {{ acme/app/db_password\n",
+			err: ErrUnterminatedPlaceholder(2, 11),
+		},
+		"empty placeholder": {
+			raw: "password: {{  }}",
+			err: ErrEmptyPlaceholder(1, 11),
+		},
+		"unterminated variable": {
+			raw: "{{ acme/app/${env/db_password }}",
+			err: ErrUnterminatedVariable(1, 1),
+		},
+		"invalid variable name": {
+			raw: "{{ acme/app/${1env}/db_password }}",
+			err: ErrInvalidVariableName("1env", 1, 1),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			// Act
+			tpl, err := Parse([]byte(tc.raw))
+
+			// Assert
+			assert.Equal(t, err, tc.err)
+			if tc.err == nil {
+				assert.Equal(t, tpl.Variables(), tc.expectedVariables)
+			}
+		})
+	}
+}
+
+func TestRender(t *testing.T) {
+	secrets := map[string]string{
+		"acme/app/dev/db_user":       "dev_user",
+		"acme/app/dev/db_password:2": "dev_password",
+		"acme/app/prd/db_user":       "prd_user",
+	}
+
+	cases := map[string]struct {
+		raw             string
+		vars            map[string]string
+		expected        string
+		expectedBatches [][]string
+		err             error
+	}{
+		"no placeholders": {
+			raw:      "foo: bar\n",
+			expected: "foo: bar\n",
+		},
+		"placeholders": {
+			raw:      "user: {{ acme/app/${env}/db_user }}\npassword: {{acme/app/${env}/db_password:2}}\n",
+			vars:     map[string]string{"env": "dev"},
+			expected: "user: dev_user\npassword: dev_password\n",
+			expectedBatches: [][]string{
+				{"acme/app/dev/db_user", "acme/app/dev/db_password:2"},
+			},
+		},
+		"undefined variable": {
+			raw: "user: {{ acme/app/${env}/db_user }}",
+			err: ErrUndefinedVariable("env", 1, 7),
+		},
+		"invalid path is not requested": {
+			raw:  "user: {{ acme/app/prd/db_user }}\npassword: {{ acme/${env} }}",
+			vars: map[string]string{"env": "prd"},
+			err:  ErrInvalidSecretPath(2, 11, api.ErrInvalidSecretPath("acme/prd")),
+		},
+		"secret not found": {
+			raw:  "password: {{ acme/app/${env}/db_password }}",
+			vars: map[string]string{"env": "prd"},
+			err:  ErrSecretNotResolved("acme/app/prd/db_password", 1, 11, api.ErrSecretNotFound),
+			expectedBatches: [][]string{
+				{"acme/app/prd/db_password"},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			// Arrange
+			tpl, err := Parse([]byte(tc.raw))
+			assert.OK(t, err)
+
+			versions := &versionService{
+				secrets: secrets,
+			}
+
+			// Act
+			actual, err := tpl.Render(versions, tc.vars)
+
+			// Assert
+			assert.Equal(t, err, tc.err)
+			assert.Equal(t, versions.batches, tc.expectedBatches)
+			if tc.err == nil {
+				assert.Equal(t, string(actual), tc.expected)
+			}
+		})
+	}
+}
+
+func TestRenderFile(t *testing.T) {
+	// Arrange
+	dir, err := ioutil.TempDir("", "secrethub-tpl")
+	assert.OK(t, err)
+	defer os.RemoveAll(dir)
+
+	filename := filepath.Join(dir, "config.yml")
+	err = ioutil.WriteFile(filename, []byte("old"), 0644)
+	assert.OK(t, err)
+
+	tpl, err := Parse([]byte("password: {{ acme/app/db_password }}\n"))
+	assert.OK(t, err)
+
+	versions := &versionService{
+		secrets: map[string]string{"acme/app/db_password": "password123"},
+	}
+
+	// Act
+	err = tpl.RenderFile(versions, nil, filename)
+
+	// Assert
+	assert.OK(t, err)
+
+	actual, err := ioutil.ReadFile(filename)
+	assert.OK(t, err)
+	assert.Equal(t, string(actual), "password: password123\n")
+
+	info, err := os.Stat(filename)
+	assert.OK(t, err)
+	assert.Equal(t, info.Mode().Perm(), FileMode)
+
+	files, err := ioutil.ReadDir(dir)
+	assert.OK(t, err)
+	assert.Equal(t, len(files), 1)
+}
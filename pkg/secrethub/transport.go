@@ -0,0 +1,129 @@
+package secrethub
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"net/http"
+)
+
+// Errors
+var (
+	ErrCertificatePinMismatch = errClient.Code("certificate_pin_mismatch").Error("the certificate presented by the server does not match any of the pinned public keys")
+	ErrPinningUnsupported     = errClient.Code("pinning_unsupported").Error("public keys can only be pinned when the transport is an *http.Transport")
+)
+
+// newTransport returns the http.RoundTripper to use for requests to the API.
+//
+// When the given transport is an *http.Transport, or when no transport is given,
+// a copy of it is configured with the TLS configuration and pinned public keys,
+// so that pins are verified during the TLS handshake. Any other transport is used
+// as is. Pins cannot be verified before a request is sent with such a transport,
+// so then every request fails with ErrPinningUnsupported.
+func newTransport(transport http.RoundTripper, tlsConfig *tls.Config, pins []string) http.RoundTripper {
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	base, ok := transport.(*http.Transport)
+	if !ok {
+		if len(pins) == 0 {
+			return transport
+		}
+		return errRoundTripper{err: ErrPinningUnsupported}
+	}
+
+	base = base.Clone()
+	if tlsConfig != nil {
+		base.TLSClientConfig = tlsConfig.Clone()
+	}
+
+	if len(pins) > 0 {
+		if base.TLSClientConfig == nil {
+			base.TLSClientConfig = &tls.Config{}
+		}
+
+		pinner := newPinner(pins)
+		verifyConnection := base.TLSClientConfig.VerifyConnection
+		base.TLSClientConfig.VerifyConnection = func(cs tls.ConnectionState) error {
+			if verifyConnection != nil {
+				err := verifyConnection(cs)
+				if err != nil {
+					return err
+				}
+			}
+			return pinner.verify(cs)
+		}
+	}
+
+	return base
+}
+
+// pinner verifies a TLS connection against a set of pinned public keys.
+type pinner struct {
+	pins map[string]bool
+}
+
+// newPinner creates a pinner for the given pins. A pin is the base64 (standard
+// encoding) SHA-256 hash of a DER encoded SubjectPublicKeyInfo, as used by HPKP.
+func newPinner(pins []string) pinner {
+	p := pinner{
+		pins: make(map[string]bool, len(pins)),
+	}
+	for _, pin := range pins {
+		p.pins[pin] = true
+	}
+	return p
+}
+
+// verify returns ErrCertificatePinMismatch when none of the certificates of
+// the connection contain a pinned public key. When the chain has been verified,
+// only certificates in the verified chains are considered.
+func (p pinner) verify(cs tls.ConnectionState) error {
+	if len(cs.VerifiedChains) > 0 {
+		for _, chain := range cs.VerifiedChains {
+			if p.matches(chain) {
+				return nil
+			}
+		}
+		return ErrCertificatePinMismatch
+	}
+
+	if p.matches(cs.PeerCertificates) {
+		return nil
+	}
+	return ErrCertificatePinMismatch
+}
+
+// matches returns whether any of the certificates contains a pinned public key.
+func (p pinner) matches(certs []*x509.Certificate) bool {
+	for _, cert := range certs {
+		if p.pins[PublicKeyPin(cert)] {
+			return true
+		}
+	}
+	return false
+}
+
+// PublicKeyPin returns the pin of a certificate's public key that can be used
+// in ClientOptions.PinnedPublicKeys. The pin is the base64 (standard encoding)
+// SHA-256 hash of the certificate's DER encoded SubjectPublicKeyInfo.
+func PublicKeyPin(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// errRoundTripper is an http.RoundTripper that only returns an error,
+// e.g. because the transport has been configured incorrectly.
+type errRoundTripper struct {
+	err error
+}
+
+// RoundTrip returns the error of the round tripper.
+func (t errRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		_ = req.Body.Close()
+	}
+	return nil, t.err
+}
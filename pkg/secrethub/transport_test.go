@@ -0,0 +1,101 @@
+package secrethub
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/assert"
+)
+
+// roundTripperFunc adapts a function to the http.RoundTripper interface.
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestPinnedPublicKeys(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(&api.Org{Name: "myorg"})
+	}))
+	defer server.Close()
+
+	rootCAs := x509.NewCertPool()
+	rootCAs.AddCert(server.Certificate())
+
+	cases := map[string]struct {
+		pins      []string
+		transport func() http.RoundTripper
+		err       error
+	}{
+		"no pins": {
+			pins: nil,
+			err:  nil,
+		},
+		"matching pin": {
+			pins: []string{"bm90IGEgcGlu", PublicKeyPin(server.Certificate())},
+			err:  nil,
+		},
+		"mismatching pin": {
+			pins: []string{"bm90IGEgcGlu"},
+			err:  ErrCertificatePinMismatch,
+		},
+		"middleware without pins": {
+			pins: nil,
+			transport: func() http.RoundTripper {
+				return roundTripperFunc(server.Client().Transport.RoundTrip)
+			},
+			err: nil,
+		},
+		"pin with middleware": {
+			pins: []string{PublicKeyPin(server.Certificate())},
+			transport: func() http.RoundTripper {
+				return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+					t.Error("request sent with a transport that cannot verify the pins")
+					return server.Client().Transport.RoundTrip(req)
+				})
+			},
+			err: ErrPinningUnsupported,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			// Arrange
+			opts := &ClientOptions{
+				ServerURL:        server.URL,
+				TLSConfig:        &tls.Config{RootCAs: rootCAs},
+				PinnedPublicKeys: tc.pins,
+			}
+			if tc.transport != nil {
+				opts.Transport = tc.transport()
+			}
+
+			client := NewClient(cred1, opts)
+
+			// Act
+			_, err := client.Orgs().Get("myorg")
+
+			// Assert
+			assert.Equal(t, err, tc.err)
+		})
+	}
+}
+
+func TestNewTransport_DoesNotModifyGivenTransport(t *testing.T) {
+	// Arrange
+	base := &http.Transport{}
+
+	// Act
+	transport := newTransport(base, &tls.Config{ServerName: "example.com"}, []string{"bm90IGEgcGlu"})
+
+	// Assert
+	assert.Equal(t, base.TLSClientConfig == nil || base.TLSClientConfig.VerifyConnection == nil, true)
+	assert.Equal(t, transport.(*http.Transport).TLSClientConfig.ServerName, "example.com")
+}
@@ -1,6 +1,8 @@
 package secrethub
 
 import (
+	"context"
+
 	"github.com/secrethub/secrethub-go/internals/api"
 	"github.com/secrethub/secrethub-go/internals/crypto"
 	"github.com/secrethub/secrethub-go/internals/errio"
@@ -10,29 +12,45 @@ import (
 type UserService interface {
 	// Me gets the account's user if it exists.
 	Me() (*api.User, error)
+	// MeContext is the same as Me, but uses the given context for all requests.
+	MeContext(ctx context.Context) (*api.User, error)
 	// Create creates a new user at SecretHub.
 	Create(username, email, fullName string) (*api.User, error)
+	// CreateContext is the same as Create, but uses the given context for all requests.
+	CreateContext(ctx context.Context, username, email, fullName string) (*api.User, error)
 	// Get a user by their username.
 	Get(username string) (*api.User, error)
+	// GetContext is the same as Get, but uses the given context for all requests.
+	GetContext(ctx context.Context, username string) (*api.User, error)
 }
 
-func newUserService(client client) UserService {
+func newUserService(client *client) UserService {
 	return userService{
 		client: client,
 	}
 }
 
 type userService struct {
-	client client
+	client *client
 }
 
 // Me gets the account's user if it exists.
 func (s userService) Me() (*api.User, error) {
-	return s.client.httpClient.GetMyUser()
+	return s.MeContext(context.Background())
+}
+
+// MeContext is the same as Me, but uses the given context for all requests.
+func (s userService) MeContext(ctx context.Context) (*api.User, error) {
+	return s.client.httpClient.GetMyUser(ctx)
 }
 
 // Create creates a new user at SecretHub.
 func (s userService) Create(username, email, fullName string) (*api.User, error) {
+	return s.CreateContext(context.Background(), username, email, fullName)
+}
+
+// CreateContext is the same as Create, but uses the given context for all requests.
+func (s userService) CreateContext(ctx context.Context, username, email, fullName string) (*api.User, error) {
 	err := api.ValidateUsername(username)
 	if err != nil {
 		return nil, errio.Error(err)
@@ -53,10 +71,10 @@ func (s userService) Create(username, email, fullName string) (*api.User, error)
 		return nil, errio.Error(err)
 	}
 
-	return s.create(username, email, fullName, accountKey)
+	return s.create(ctx, username, email, fullName, accountKey)
 }
 
-func (s userService) create(username, email, fullName string, accountKey crypto.RSAPrivateKey) (*api.User, error) {
+func (s userService) create(ctx context.Context, username, email, fullName string, accountKey crypto.RSAPrivateKey) (*api.User, error) {
 	credentialRequest, err := s.client.createCredentialRequest(s.client.credential)
 	if err != nil {
 		return nil, errio.Error(err)
@@ -74,12 +92,12 @@ func (s userService) create(username, email, fullName string, accountKey crypto.
 		Credential: credentialRequest,
 	}
 
-	user, err := s.client.httpClient.SignupUser(userRequest)
+	user, err := s.client.httpClient.SignupUser(ctx, userRequest)
 	if err != nil {
 		return nil, errio.Error(err)
 	}
 
-	accountKeyResponse, err := s.client.createAccountKey(accountKey)
+	accountKeyResponse, err := s.client.createAccountKey(ctx, accountKey)
 	if err != nil {
 		return nil, err
 	}
@@ -91,12 +109,17 @@ func (s userService) create(username, email, fullName string, accountKey crypto.
 
 // Get retrieves the user with the given username from SecretHub.
 func (s userService) Get(username string) (*api.User, error) {
+	return s.GetContext(context.Background(), username)
+}
+
+// GetContext is the same as Get, but uses the given context for all requests.
+func (s userService) GetContext(ctx context.Context, username string) (*api.User, error) {
 	err := api.ValidateUsername(username)
 	if err != nil {
 		return nil, errio.Error(err)
 	}
 
-	user, err := s.client.httpClient.GetUser(username)
+	user, err := s.client.httpClient.GetUser(ctx, username)
 	if err != nil {
 		return nil, errio.Error(err)
 	}
@@ -105,7 +128,7 @@ func (s userService) Get(username string) (*api.User, error) {
 }
 
 // createAccountKey adds the account key for the clients credential.
-func (c *client) createAccountKey(accountKey crypto.RSAPrivateKey) (*api.EncryptedAccountKey, error) {
+func (c *client) createAccountKey(ctx context.Context, accountKey crypto.RSAPrivateKey) (*api.EncryptedAccountKey, error) {
 	accountKeyRequest, err := c.createAccountKeyRequest(c.credential, accountKey)
 	if err != nil {
 		return nil, errio.Error(err)
@@ -121,7 +144,7 @@ func (c *client) createAccountKey(accountKey crypto.RSAPrivateKey) (*api.Encrypt
 		return nil, err
 	}
 
-	result, err := c.httpClient.CreateAccountKey(accountKeyRequest, fingerprint)
+	result, err := c.httpClient.CreateAccountKey(ctx, accountKeyRequest, fingerprint)
 	if err != nil {
 		return nil, errio.Error(err)
 	}
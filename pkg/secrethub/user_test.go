@@ -1,6 +1,7 @@
 package secrethub
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -89,7 +90,7 @@ func TestSignup(t *testing.T) {
 	})
 
 	// Act
-	actual, err := userService.create(username, email, fullName, accountKey)
+	actual, err := userService.create(context.Background(), username, email, fullName, accountKey)
 
 	// Assert
 	assert.OK(t, err)
@@ -119,7 +120,7 @@ func TestSignup_AlreadyExists(t *testing.T) {
 	assert.OK(t, err)
 
 	// Act
-	_, err = userService.create("dev1", "dev1@testing.com", "Developer Uno", key)
+	_, err = userService.create(context.Background(), "dev1", "dev1@testing.com", "Developer Uno", key)
 
 	// Assert
 	assert.Equal(t, err, expected)
@@ -139,7 +140,7 @@ func TestSignup_InvalidArgument(t *testing.T) {
 	assert.OK(t, err)
 
 	// Act
-	_, err = userService.create("invalidname$#@%%", "dev1@testing.com", "Developer Uno", key)
+	_, err = userService.create(context.Background(), "invalidname$#@%%", "dev1@testing.com", "Developer Uno", key)
 
 	// Assert
 	assert.Equal(t, err, api.ErrInvalidUsername)
@@ -0,0 +1,198 @@
+package secrethub
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/errio"
+)
+
+const (
+	// DefaultWatchInterval is the interval at which watched secrets are polled
+	// when no interval is given.
+	DefaultWatchInterval = 30 * time.Second
+	// MaxWatchBackoff is the maximum delay before a watched secret of which polling
+	// failed is polled again, unless the interval is longer.
+	MaxWatchBackoff = 5 * time.Minute
+)
+
+// Errors
+var (
+	ErrNoWatchPaths       = errClient.Code("no_watch_paths").Error("at least one secret path to watch is required")
+	ErrCannotWatchVersion = errClient.Code("cannot_watch_version").Error("cannot watch a specific version of a secret, watch the secret instead")
+)
+
+// WatchEvent is emitted when the latest version of a watched secret has changed
+// or when polling the secret failed.
+type WatchEvent struct {
+	// Path is the path of the watched secret, as it was given to Watch.
+	Path string
+	// Previous is the number of the latest version before the change.
+	// It is 0 for the first event of a secret.
+	Previous int
+	// Version is the new latest version. It only contains the data of the
+	// version when the secrets are watched with their data.
+	Version *api.SecretVersion
+	// Err is set when polling the secret failed. The secret is polled
+	// again after a delay that grows with every consecutive failure.
+	Err error
+}
+
+// Watch polls the latest versions of the secrets at the given paths at the given interval
+// and emits an event on the returned channel for every secret of which the latest version
+// has changed. The first poll emits an event for every secret. When withData is set, the
+// events contain the data of the new versions. The channel is closed when the context is done.
+func (s secretService) Watch(ctx context.Context, paths []string, interval time.Duration, withData bool) (<-chan WatchEvent, error) {
+	return watchSecrets(ctx, clientAdapter{client: s.client}, paths, interval, withData)
+}
+
+// watchedSecret is a secret that is polled for changes
+// on behalf of all paths that refer to the secret.
+type watchedSecret struct {
+	path     string
+	paths    []string
+	version  int
+	failures int
+	next     time.Time
+}
+
+// watchSecrets polls the latest versions of the secrets at the given paths, using the client,
+// and emits events on the returned channel for every change. Paths that only differ in case
+// refer to the same secret, which is polled only once, and the repo index key that is needed
+// to poll the secrets of a repository is only retrieved once. When the interval is 0,
+// DefaultWatchInterval is used. When polling a secret fails, an event with the error is
+// emitted and the delay before the next poll of the secret is doubled, up to MaxWatchBackoff.
+func watchSecrets(ctx context.Context, client Client, paths []string, interval time.Duration, withData bool) (<-chan WatchEvent, error) {
+	if len(paths) == 0 {
+		return nil, ErrNoWatchPaths
+	}
+	if interval <= 0 {
+		interval = DefaultWatchInterval
+	}
+
+	var secrets []*watchedSecret
+	indices := make(map[string]int)
+	for _, path := range paths {
+		secretPath, err := api.NewSecretPath(path)
+		if err != nil {
+			return nil, errio.Error(err)
+		}
+		if secretPath.HasVersion() {
+			return nil, ErrCannotWatchVersion
+		}
+
+		key := strings.ToLower(secretPath.Value())
+		index, ok := indices[key]
+		if !ok {
+			index = len(secrets)
+			indices[key] = index
+			secrets = append(secrets, &watchedSecret{path: secretPath.Value()})
+		}
+		secrets[index].paths = append(secrets[index].paths, path)
+	}
+
+	events := make(chan WatchEvent)
+	go func() {
+		defer close(events)
+
+		timer := time.NewTimer(0)
+		defer timer.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-timer.C:
+			}
+
+			now := time.Now()
+			for _, secret := range secrets {
+				if now.Before(secret.next) {
+					continue
+				}
+
+				for _, event := range secret.poll(ctx, client, withData, interval, now) {
+					select {
+					case events <- event:
+					case <-ctx.Done():
+						return
+					}
+				}
+			}
+
+			timer.Reset(interval)
+		}
+	}()
+
+	return events, nil
+}
+
+// poll retrieves the latest version of the secret and returns the events for all of its paths.
+func (s *watchedSecret) poll(ctx context.Context, client Client, withData bool, interval time.Duration, now time.Time) []WatchEvent {
+	version, err := client.Secrets().Versions().GetWithoutDataContext(ctx, s.path)
+	if err == nil && withData && version.Version != s.version {
+		var versionPath api.SecretPath
+		versionPath, err = api.SecretPath(s.path).AddVersion(version.Version)
+		if err == nil {
+			version, err = client.Secrets().Versions().GetWithDataContext(ctx, versionPath.Value())
+		}
+	}
+
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		s.failures++
+		s.next = now.Add(watchBackoff(interval, s.failures))
+
+		events := make([]WatchEvent, len(s.paths))
+		for i, path := range s.paths {
+			events[i] = WatchEvent{
+				Path:     path,
+				Previous: s.version,
+				Err:      errio.Error(err),
+			}
+		}
+		return events
+	}
+
+	s.failures = 0
+	s.next = time.Time{}
+	if version.Version == s.version {
+		return nil
+	}
+
+	previous := s.version
+	s.version = version.Version
+
+	events := make([]WatchEvent, len(s.paths))
+	for i, path := range s.paths {
+		events[i] = WatchEvent{
+			Path:     path,
+			Previous: previous,
+			Version:  version,
+		}
+	}
+	return events
+}
+
+// watchBackoff returns the delay before a secret of which the given number of
+// consecutive polls failed is polled again.
+func watchBackoff(interval time.Duration, failures int) time.Duration {
+	max := MaxWatchBackoff
+	if interval > max {
+		max = interval
+	}
+
+	backoff := interval
+	for i := 0; i < failures && backoff < max; i++ {
+		backoff *= 2
+	}
+	if backoff > max {
+		backoff = max
+	}
+	return backoff
+}
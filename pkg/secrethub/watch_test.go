@@ -0,0 +1,47 @@
+package secrethub
+
+import (
+	"testing"
+	"time"
+
+	"github.com/secrethub/secrethub-go/internals/assert"
+)
+
+func TestWatchBackoff(t *testing.T) {
+	cases := map[string]struct {
+		interval time.Duration
+		failures int
+		expected time.Duration
+	}{
+		"first failure": {
+			interval: time.Second,
+			failures: 1,
+			expected: 2 * time.Second,
+		},
+		"third failure": {
+			interval: time.Second,
+			failures: 3,
+			expected: 8 * time.Second,
+		},
+		"maximum": {
+			interval: time.Minute,
+			failures: 10,
+			expected: MaxWatchBackoff,
+		},
+		"interval longer than maximum": {
+			interval: time.Hour,
+			failures: 2,
+			expected: time.Hour,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			// Act
+			actual := watchBackoff(tc.interval, tc.failures)
+
+			// Assert
+			assert.Equal(t, actual, tc.expected)
+		})
+	}
+}